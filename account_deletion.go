@@ -0,0 +1,438 @@
+package nakama
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/hako/durafmt"
+
+	"github.com/nakamauwu/nakama/web"
+)
+
+const (
+	accountDeletionCodeTTL     = time.Hour * 2
+	accountDeletionGracePeriod = time.Hour * 24 * 30
+	accountDeletionRequests    = 3
+	accountDeletionWindow      = time.Hour
+	accountDeletionCooldown    = time.Minute
+	// purgedAccountsBatch is how many accounts are purged per job run.
+	purgedAccountsBatch = 20
+)
+
+var (
+	// ErrInvalidAccountDeletionCode denotes an invalid account deletion code; that is not uuid.
+	ErrInvalidAccountDeletionCode = InvalidArgumentError("invalid account deletion code")
+	// ErrAccountDeletionCodeNotFound denotes a not found or expired account deletion code.
+	ErrAccountDeletionCodeNotFound = NotFoundError("account deletion code not found")
+	// ErrTooManyAccountDeletionRequests denotes that too many account deletion
+	// emails were requested by the same user in a short period of time.
+	ErrTooManyAccountDeletionRequests = ResourceExhaustedError("too many account deletion requests")
+)
+
+// RequestAccountDeletion sends an email to the authenticated user
+// with a link to confirm the deletion of their account.
+func (s *Service) RequestAccountDeletion(ctx context.Context) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	var email string
+	query := "SELECT email FROM users WHERE id = $1"
+	err := s.DB.QueryRowContext(ctx, query, uid).Scan(&email)
+	if err == sql.ErrNoRows {
+		return ErrUserGone
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not sql query select user email: %w", err)
+	}
+
+	err = s.takeRateLimits(ctx, ErrTooManyAccountDeletionRequests, rateLimit{
+		key:      "account_deletion:user:" + uid,
+		limit:    accountDeletionRequests,
+		window:   accountDeletionWindow,
+		cooldown: accountDeletionCooldown,
+	})
+	if err != nil {
+		return err
+	}
+
+	var code string
+	query = "INSERT INTO account_deletion_codes (user_id) VALUES ($1) RETURNING code"
+	err = s.DB.QueryRowContext(ctx, query, uid).Scan(&code)
+	if isForeignKeyViolation(err) {
+		return ErrUserGone
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not sql insert account deletion code: %w", err)
+	}
+
+	// See transport/http/handler.go
+	// GET /account_deletion must exist.
+	link := cloneURL(s.Origin)
+	link.Path = "/account_deletion"
+	q := link.Query()
+	q.Set("code", code)
+	link.RawQuery = q.Encode()
+
+	s.accountDeletionTmplOncer.Do(func() {
+		var text []byte
+		text, err = web.TemplateFiles.ReadFile("template/mail/account-deletion.html.tmpl")
+		if err != nil {
+			err = fmt.Errorf("could not read account deletion template file: %w", err)
+			return
+		}
+
+		s.accountDeletionTmpl, err = template.
+			New("mail/account-deletion.html").
+			Funcs(template.FuncMap{
+				"human_duration": func(d time.Duration) string {
+					return durafmt.Parse(d).LimitFirstN(1).String()
+				},
+			}).
+			Parse(string(text))
+		if err != nil {
+			err = fmt.Errorf("could not parse account deletion mail template: %w", err)
+			return
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	err = s.accountDeletionTmpl.Execute(&b, map[string]interface{}{
+		"Origin":      s.Origin,
+		"Link":        link,
+		"TTL":         accountDeletionCodeTTL,
+		"GracePeriod": accountDeletionGracePeriod,
+	})
+	if err != nil {
+		return fmt.Errorf("could not execute account deletion mail template: %w", err)
+	}
+
+	err = s.Sender.Send(email, "Delete your Nakama account", b.String(), link.String())
+	if err != nil {
+		return fmt.Errorf("could not send account deletion email: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmAccountDeletion with the code sent by email.
+// The account gets logged out everywhere and is scheduled to be purged
+// after a grace period. Logging in again before that cancels the deletion.
+// It returns the time at which the account will be purged.
+func (s *Service) ConfirmAccountDeletion(ctx context.Context, code string) (time.Time, error) {
+	var scheduledAt time.Time
+	if !reUUID.MatchString(code) {
+		return scheduledAt, ErrInvalidAccountDeletionCode
+	}
+
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var uid string
+		var createdAt time.Time
+		query := "DELETE FROM account_deletion_codes WHERE code = $1 RETURNING user_id, created_at"
+		err := tx.QueryRowContext(ctx, query, code).Scan(&uid, &createdAt)
+		if err == sql.ErrNoRows {
+			return ErrAccountDeletionCodeNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql delete account deletion code: %w", err)
+		}
+
+		if time.Since(createdAt) > accountDeletionCodeTTL {
+			return ErrAccountDeletionCodeNotFound
+		}
+
+		query = `
+			UPDATE users SET deletion_scheduled_at = $1
+			WHERE id = $2
+			RETURNING deletion_scheduled_at`
+		err = tx.QueryRowContext(ctx, query, time.Now().Add(accountDeletionGracePeriod), uid).Scan(&scheduledAt)
+		if err == sql.ErrNoRows {
+			return ErrUserGone
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql schedule account deletion: %w", err)
+		}
+
+		for _, table := range []string{"account_deletion_codes", "sessions", "personal_access_tokens", "oauth_access_tokens"} {
+			query = "DELETE FROM " + table + " WHERE user_id = $1"
+			if _, err := tx.ExecContext(ctx, query, uid); err != nil {
+				return fmt.Errorf("could not sql delete user %s: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return scheduledAt, nil
+}
+
+// purgeDeletedAccounts whose grace period is over.
+func (s *Service) purgeDeletedAccounts(ctx context.Context) (int64, error) {
+	query := "SELECT id FROM users WHERE deletion_scheduled_at <= now() LIMIT $1"
+	rows, err := s.DB.QueryContext(ctx, query, purgedAccountsBatch)
+	if err != nil {
+		return 0, fmt.Errorf("could not sql query select deleted accounts: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return 0, fmt.Errorf("could not sql scan deleted account: %w", err)
+		}
+
+		uu = append(uu, uid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("could not iterate deleted account rows: %w", err)
+	}
+
+	var n int64
+	for _, uid := range uu {
+		purged, err := s.purgeAccount(ctx, uid)
+		if err != nil {
+			return n, err
+		}
+
+		if purged {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// purgeAccount deletes the user along with everything it owns.
+// Stored files are queued for the orphaned files job,
+// and counters and reactions on other users, posts and comments are decremented.
+// It does nothing if the deletion was canceled in the meantime.
+func (s *Service) purgeAccount(ctx context.Context, uid string) (bool, error) {
+	var purged bool
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		purged = false
+
+		var avatar, cover sql.NullString
+		query := `
+			SELECT avatar, cover FROM users
+			WHERE id = $1 AND deletion_scheduled_at <= now()
+			FOR UPDATE`
+		err := tx.QueryRowContext(ctx, query, uid).Scan(&avatar, &cover)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select deleted account: %w", err)
+		}
+
+		var media []string
+		query = "SELECT unnest(media) FROM posts WHERE user_id = $1 AND media IS NOT NULL"
+		rows, err := tx.QueryContext(ctx, query, uid)
+		if err != nil {
+			return fmt.Errorf("could not sql query select deleted account media: %w", err)
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return fmt.Errorf("could not sql scan deleted account media: %w", err)
+			}
+
+			media = append(media, name)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not iterate deleted account media rows: %w", err)
+		}
+
+		if avatar.Valid {
+			if err := s.orphanFiles(ctx, tx, AvatarsBucket, avatar.String); err != nil {
+				return err
+			}
+		}
+
+		if cover.Valid {
+			if err := s.orphanFiles(ctx, tx, CoversBucket, cover.String); err != nil {
+				return err
+			}
+		}
+
+		if err := s.orphanFiles(ctx, tx, MediaBucket, media...); err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO orphaned_files (bucket, name)
+			SELECT $1, file_name FROM data_exports
+			WHERE user_id = $2 AND file_name IS NOT NULL
+			ON CONFLICT (bucket, name) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, DataExportsBucket, uid); err != nil {
+			return fmt.Errorf("could not sql insert orphaned data export files: %w", err)
+		}
+
+		counters := []struct {
+			what  string
+			query string
+		}{
+			{"followees followers count", `
+				UPDATE users SET followers_count = followers_count - 1
+				WHERE id IN (SELECT followee_id FROM follows WHERE follower_id = $1) AND id != $1`},
+			{"followers followees count", `
+				UPDATE users SET followees_count = followees_count - 1
+				WHERE id IN (SELECT follower_id FROM follows WHERE followee_id = $1) AND id != $1`},
+			{"commented posts comments count", `
+				UPDATE posts SET comments_count = posts.comments_count - c.count
+				FROM (
+					SELECT post_id, count(*) AS count FROM comments
+					WHERE user_id = $1
+					GROUP BY post_id
+				) AS c
+				WHERE posts.id = c.post_id AND posts.user_id != $1`},
+		}
+		for _, c := range counters {
+			if _, err := tx.ExecContext(ctx, c.query, uid); err != nil {
+				return fmt.Errorf("could not sql decrement %s: %w", c.what, err)
+			}
+		}
+
+		if err := decrementUserReactions(ctx, tx, uid, "posts", "post_reactions", "post_id"); err != nil {
+			return err
+		}
+
+		if err := decrementUserReactions(ctx, tx, uid, "comments", "comment_reactions", "comment_id"); err != nil {
+			return err
+		}
+
+		// the rest of the user rows cascade.
+		query = "DELETE FROM users WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, uid); err != nil {
+			return fmt.Errorf("could not sql delete user: %w", err)
+		}
+
+		purged = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return purged, nil
+}
+
+// cancelAccountDeletion of the given user, if any was scheduled.
+func cancelAccountDeletion(ctx context.Context, tx *sql.Tx, userID string) error {
+	query := "UPDATE users SET deletion_scheduled_at = NULL WHERE id = $1 AND deletion_scheduled_at IS NOT NULL"
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("could not sql cancel account deletion: %w", err)
+	}
+
+	return nil
+}
+
+// decrementUserReactions takes out the reactions of the given user
+// from the reactions totals of someone else's posts or comments,
+// before the reaction rows themselves cascade.
+func decrementUserReactions(ctx context.Context, tx *sql.Tx, uid, table, reactionsTable, idColumn string) error {
+	query := fmt.Sprintf(`
+		SELECT %[1]s.id, %[1]s.reactions, r.user_reactions
+		FROM (
+			SELECT %[3]s
+			, json_agg(json_build_object('reaction', reaction, 'type', type)) AS user_reactions
+			FROM %[2]s
+			WHERE user_id = $1
+			GROUP BY %[3]s
+		) AS r
+		INNER JOIN %[1]s ON %[1]s.id = r.%[3]s
+		WHERE %[1]s.user_id != $1`, table, reactionsTable, idColumn)
+	rows, err := tx.QueryContext(ctx, query, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql query select deleted account %s: %w", reactionsTable, err)
+	}
+
+	defer rows.Close()
+
+	type reacted struct {
+		id               string
+		rawReactions     []byte
+		rawUserReactions []byte
+	}
+	var rr []reacted
+	for rows.Next() {
+		var r reacted
+		if err := rows.Scan(&r.id, &r.rawReactions, &r.rawUserReactions); err != nil {
+			return fmt.Errorf("could not sql scan deleted account %s: %w", reactionsTable, err)
+		}
+
+		rr = append(rr, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not iterate deleted account %s rows: %w", reactionsTable, err)
+	}
+
+	for _, r := range rr {
+		var reactions []Reaction
+		if r.rawReactions != nil {
+			if err := json.Unmarshal(r.rawReactions, &reactions); err != nil {
+				return fmt.Errorf("could not json unmarshall %s reactions: %w", table, err)
+			}
+		}
+
+		var userReactions []userReaction
+		if err := json.Unmarshal(r.rawUserReactions, &userReactions); err != nil {
+			return fmt.Errorf("could not json unmarshall user %s: %w", reactionsTable, err)
+		}
+
+		raw, err := json.Marshal(withoutUserReactions(reactions, userReactions))
+		if err != nil {
+			return fmt.Errorf("could not json marshall %s reactions: %w", table, err)
+		}
+
+		query := fmt.Sprintf("UPDATE %s SET reactions = $1 WHERE id = $2", table)
+		if _, err := tx.ExecContext(ctx, query, raw, r.id); err != nil {
+			return fmt.Errorf("could not sql update %s reactions: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// withoutUserReactions decrements the count of each reaction the user did,
+// dropping the ones that reach zero.
+func withoutUserReactions(reactions []Reaction, userReactions []userReaction) []Reaction {
+	out := []Reaction{}
+	for _, r := range reactions {
+		for _, ur := range userReactions {
+			if r.Type == ur.Type && r.Reaction == ur.Reaction && r.Count > 0 {
+				r.Count--
+			}
+		}
+
+		if r.Count != 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package nakama
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_RequestAccountDeletion(t *testing.T) {
+	svc := &Service{}
+	err := svc.RequestAccountDeletion(context.Background())
+	testutil.WantEq(t, ErrUnauthenticated, err, "error")
+}
+
+func TestService_ConfirmAccountDeletion(t *testing.T) {
+	svc := &Service{}
+	for _, code := range []string{"", "nope", "00000000-0000-0000-0000"} {
+		_, err := svc.ConfirmAccountDeletion(context.Background(), code)
+		testutil.WantEq(t, ErrInvalidAccountDeletionCode, err, "error")
+	}
+}
+
+func Test_withoutUserReactions(t *testing.T) {
+	reactions := []Reaction{
+		{Type: "emoji", Reaction: "👍", Count: 2},
+		{Type: "emoji", Reaction: "❤️", Count: 1},
+		{Type: "emoji", Reaction: "😂", Count: 3},
+	}
+	userReactions := []userReaction{
+		{Type: "emoji", Reaction: "👍"},
+		{Type: "emoji", Reaction: "❤️"},
+	}
+	got := withoutUserReactions(reactions, userReactions)
+	testutil.WantEq(t, []Reaction{
+		{Type: "emoji", Reaction: "👍", Count: 1},
+		{Type: "emoji", Reaction: "😂", Count: 3},
+	}, got, "reactions")
+
+	testutil.WantEq(t, []Reaction{}, withoutUserReactions(nil, userReactions), "no reactions")
+}
+
+func TestService_purgeAccount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping account purge integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	deleted := createTestUser(t)
+	other := createTestUser(t)
+
+	mustExec := func(what, query string, args ...interface{}) {
+		t.Helper()
+		_, err := testDB.ExecContext(ctx, query, args...)
+		testutil.WantEq(t, nil, err, what+" error")
+	}
+
+	mustExec("update deleted user", "UPDATE users SET avatar = 'avatar.png', deletion_scheduled_at = now() - INTERVAL '1 second' WHERE id = $1", deleted.ID)
+	mustExec("insert follows", "INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2), ($2, $1)", deleted.ID, other.ID)
+	mustExec("update other user counts", "UPDATE users SET followers_count = 1, followees_count = 1 WHERE id = $1", other.ID)
+
+	var deletedPostID, otherPostID, otherCommentID string
+	err := testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content, media) VALUES ($1, 'mine', ARRAY['media.gif']) RETURNING id", deleted.ID).Scan(&deletedPostID)
+	testutil.WantEq(t, nil, err, "insert deleted user post error")
+
+	err = testDB.QueryRowContext(ctx, `
+		INSERT INTO posts (user_id, content, comments_count, reactions)
+		VALUES ($1, 'theirs', 2, '[{"type":"emoji","reaction":"👍","count":2}]')
+		RETURNING id`, other.ID).Scan(&otherPostID)
+	testutil.WantEq(t, nil, err, "insert other user post error")
+
+	err = testDB.QueryRowContext(ctx, `
+		INSERT INTO comments (user_id, post_id, content, reactions)
+		VALUES ($1, $2, 'theirs', '[{"type":"emoji","reaction":"👍","count":1}]')
+		RETURNING id`, other.ID, otherPostID).Scan(&otherCommentID)
+	testutil.WantEq(t, nil, err, "insert other user comment error")
+
+	mustExec("insert deleted user comment", "INSERT INTO comments (user_id, post_id, content) VALUES ($1, $2, 'mine')", deleted.ID, otherPostID)
+	mustExec("insert post reactions", "INSERT INTO post_reactions (user_id, post_id, type, reaction) VALUES ($1, $3, 'emoji', '👍'), ($2, $3, 'emoji', '👍')", deleted.ID, other.ID, otherPostID)
+	mustExec("insert comment reaction", "INSERT INTO comment_reactions (user_id, comment_id, type, reaction) VALUES ($1, $2, 'emoji', '👍')", deleted.ID, otherCommentID)
+	mustExec("insert data export", "INSERT INTO data_exports (user_id, status, file_name) VALUES ($1, 'ready', 'export.zip')", deleted.ID)
+
+	purged, err := svc.purgeAccount(ctx, deleted.ID)
+	testutil.WantEq(t, nil, err, "purge error")
+	testutil.WantEq(t, true, purged, "purged")
+
+	var followersCount, followeesCount int
+	err = testDB.QueryRowContext(ctx, "SELECT followers_count, followees_count FROM users WHERE id = $1", other.ID).Scan(&followersCount, &followeesCount)
+	testutil.WantEq(t, nil, err, "select other user counts error")
+	testutil.WantEq(t, 0, followersCount, "followers count")
+	testutil.WantEq(t, 0, followeesCount, "followees count")
+
+	var commentsCount int
+	var rawPostReactions, rawCommentReactions []byte
+	err = testDB.QueryRowContext(ctx, "SELECT comments_count, reactions FROM posts WHERE id = $1", otherPostID).Scan(&commentsCount, &rawPostReactions)
+	testutil.WantEq(t, nil, err, "select other user post error")
+	testutil.WantEq(t, 1, commentsCount, "comments count")
+
+	var postReactions []Reaction
+	err = json.Unmarshal(rawPostReactions, &postReactions)
+	testutil.WantEq(t, nil, err, "unmarshall post reactions error")
+	testutil.WantEq(t, []Reaction{{Type: "emoji", Reaction: "👍", Count: 1}}, postReactions, "post reactions")
+
+	err = testDB.QueryRowContext(ctx, "SELECT reactions FROM comments WHERE id = $1", otherCommentID).Scan(&rawCommentReactions)
+	testutil.WantEq(t, nil, err, "select other user comment error")
+
+	var commentReactions []Reaction
+	err = json.Unmarshal(rawCommentReactions, &commentReactions)
+	testutil.WantEq(t, nil, err, "unmarshall comment reactions error")
+	testutil.WantEq(t, 0, len(commentReactions), "comment reactions")
+
+	for _, f := range []struct{ bucket, name string }{
+		{AvatarsBucket, "avatar.png"},
+		{MediaBucket, "media.gif"},
+		{MediaBucket, "media.poster.png"},
+		{DataExportsBucket, "export.zip"},
+	} {
+		var queued bool
+		err = testDB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM orphaned_files WHERE bucket = $1 AND name = $2)", f.bucket, f.name).Scan(&queued)
+		testutil.WantEq(t, nil, err, "select orphaned file error")
+		testutil.WantEq(t, true, queued, f.bucket+"/"+f.name+" queued")
+	}
+
+	for _, c := range []struct{ what, query, id string }{
+		{"user", "SELECT count(*) FROM users WHERE id = $1", deleted.ID},
+		{"posts", "SELECT count(*) FROM posts WHERE user_id = $1", deleted.ID},
+		{"comments", "SELECT count(*) FROM comments WHERE user_id = $1", deleted.ID},
+		{"follows", "SELECT count(*) FROM follows WHERE follower_id = $1 OR followee_id = $1", deleted.ID},
+		{"post reactions", "SELECT count(*) FROM post_reactions WHERE user_id = $1", deleted.ID},
+		{"data exports", "SELECT count(*) FROM data_exports WHERE user_id = $1", deleted.ID},
+	} {
+		var n int
+		err = testDB.QueryRowContext(ctx, c.query, c.id).Scan(&n)
+		testutil.WantEq(t, nil, err, "count "+c.what+" error")
+		testutil.WantEq(t, 0, n, c.what+" left")
+	}
+
+	purged, err = svc.purgeAccount(ctx, deleted.ID)
+	testutil.WantEq(t, nil, err, "purge again error")
+	testutil.WantEq(t, false, purged, "purged again")
+}
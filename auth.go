@@ -3,7 +3,10 @@ package nakama
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"html/template"
@@ -21,9 +24,25 @@ import (
 // KeyAuthUserID to use in context.
 const KeyAuthUserID = ctxkey("auth_user_id")
 
+// KeyAuthSessionID to use in context.
+const KeyAuthSessionID = ctxkey("auth_session_id")
+
+// KeyUserAgent to use in context.
+// It is recorded on the sessions so users can tell their devices apart.
+const KeyUserAgent = ctxkey("user_agent")
+
+// KeyClientIP to use in context.
+const KeyClientIP = ctxkey("client_ip")
+
 const (
+	magicLinksPerEmail       = 5
+	magicLinksPerIP          = 20
+	magicLinkWindow          = time.Hour
+	magicLinkCooldown        = time.Minute
 	emailVerificationCodeTTL = time.Hour * 2
-	authTokenTTL             = time.Hour * 24 * 14
+	accessTokenTTL           = time.Minute * 15
+	refreshTokenTTL          = time.Hour * 24 * 14
+	refreshTokenLen          = 32
 )
 
 var (
@@ -40,21 +59,47 @@ var (
 	ErrInvalidVerificationCode = InvalidArgumentError("invalid verification code")
 	// ErrVerificationCodeNotFound denotes a not found verification code.
 	ErrVerificationCodeNotFound = NotFoundError("verification code not found")
+	// ErrInvalidRefreshToken denotes an invalid or unknown refresh token.
+	ErrInvalidRefreshToken = UnauthenticatedError("invalid refresh token")
+	// ErrTooManyMagicLinks denotes that too many magic links were requested
+	// for the same email address or from the same IP.
+	ErrTooManyMagicLinks = ResourceExhaustedError("too many magic links")
+	// ErrRefreshTokenReused denotes that an already used refresh token was presented again.
+	// The whole session it belongs to gets revoked since it could have been stolen.
+	ErrRefreshTokenReused = UnauthenticatedError("refresh token reused")
 )
 
 type ctxkey string
 
 // TokenOutput response.
+// Token is a short-lived access token to authenticate requests.
+// RefreshToken can be exchanged just once for a new pair of tokens.
 type TokenOutput struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expiresAt"`
+	Token                 string    `json:"token"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
 }
 
 // AuthOutput response.
+// When the user has two-factor authentication enabled,
+// no tokens are issued yet; SecondFactorChallengeID must be passed
+// to VerifySecondFactor along with a code to complete the login.
 type AuthOutput struct {
-	User      User      `json:"user"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expiresAt"`
+	User User `json:"user"`
+	TokenOutput
+	SecondFactorRequired    bool   `json:"secondFactorRequired"`
+	SecondFactorChallengeID string `json:"secondFactorChallengeID,omitempty"`
+}
+
+// Auth identifies who is behind a token.
+// Scopes is only set for personal access tokens
+// and access tokens issued to OAuth apps,
+// which are not bound to any session.
+type Auth struct {
+	UserID    string
+	SessionID string
+	Scopes    []string
 }
 
 type SendMagicLink struct {
@@ -101,15 +146,39 @@ func (s *Service) SendMagicLink(ctx context.Context, in SendMagicLink) error {
 		}
 	}
 
+	limits := []rateLimit{{
+		key:      "magic_link:email:" + in.Email,
+		limit:    magicLinksPerEmail,
+		window:   magicLinkWindow,
+		cooldown: magicLinkCooldown,
+	}}
+	if ip, ok := ctx.Value(KeyClientIP).(string); ok {
+		limits = append(limits, rateLimit{
+			key:    "magic_link:ip:" + ip,
+			limit:  magicLinksPerIP,
+			window: magicLinkWindow,
+		})
+	}
+
+	err = s.takeRateLimits(ctx, ErrTooManyMagicLinks, limits...)
+	if err != nil {
+		return err
+	}
+
+	loginCode, err := genLoginCode()
+	if err != nil {
+		return err
+	}
+
 	var row *sql.Row
 
 	if in.UpdateEmail {
 		uid, _ := ctx.Value(KeyAuthUserID).(string)
-		query := "INSERT INTO email_verification_codes (user_id, email) VALUES ($1, $2) RETURNING code"
-		row = s.DB.QueryRowContext(ctx, query, uid, in.Email)
+		query := "INSERT INTO email_verification_codes (user_id, email, login_code) VALUES ($1, $2, $3) RETURNING code"
+		row = s.DB.QueryRowContext(ctx, query, uid, in.Email, loginCode)
 	} else {
-		query := "INSERT INTO email_verification_codes (email) VALUES ($1) RETURNING code"
-		row = s.DB.QueryRowContext(ctx, query, in.Email)
+		query := "INSERT INTO email_verification_codes (email, login_code) VALUES ($1, $2) RETURNING code"
+		row = s.DB.QueryRowContext(ctx, query, in.Email, loginCode)
 	}
 
 	var code string
@@ -173,6 +242,7 @@ func (s *Service) SendMagicLink(ctx context.Context, in SendMagicLink) error {
 		"UpdateEmail": in.UpdateEmail,
 		"Origin":      s.Origin,
 		"MagicLink":   magicLink,
+		"LoginCode":   loginCode,
 		"TTL":         emailVerificationCodeTTL,
 	})
 	if err != nil {
@@ -185,7 +255,8 @@ func (s *Service) SendMagicLink(ctx context.Context, in SendMagicLink) error {
 	} else {
 		subject = "Login to Nakama"
 	}
-	err = s.Sender.Send(in.Email, subject, b.String(), magicLink.String())
+	text := fmt.Sprintf("%s\n\nOr type this code: %s", magicLink, loginCode)
+	err = s.Sender.Send(in.Email, subject, b.String(), text)
 	if err != nil {
 		return fmt.Errorf("could not send magic link: %w", err)
 	}
@@ -250,71 +321,90 @@ func (s *Service) VerifyMagicLink(ctx context.Context, email, code string, usern
 			return ErrExpiredToken
 		}
 
-		// not login but update email.
-		if userID.Valid {
-			query := "UPDATE users SET email = $1 WHERE id = $2 RETURNING id, username, avatar"
-			row = tx.QueryRowContext(ctx, query, email, userID.String)
-		} else {
-			query := "SELECT id, username, avatar FROM users WHERE email = $1"
-			row = tx.QueryRowContext(ctx, query, email)
+		auth.User, err = s.verifiedEmailUser(ctx, tx, email, userID, username)
+		return err
+	})
+	if err != nil {
+		return auth, err
+	}
+
+	auth, err = s.login(ctx, auth.User)
+	if err != nil {
+		return auth, err
+	}
+
+	go func() {
+		_, err := s.DB.Exec("DELETE FROM email_verification_codes WHERE email = $1 AND code = $2", email, code)
+		if err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not delete verification code: %w", err))
+			return
 		}
+	}()
 
-		var avatar sql.NullString
-		err = row.Scan(&auth.User.ID, &auth.User.Username, &avatar)
-		if err == sql.ErrNoRows {
-			if username == nil {
-				return ErrUserNotFound
-			}
+	return auth, nil
+}
 
-			query := "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id"
-			row := tx.QueryRowContext(ctx, query, email, username)
-			err := row.Scan(&auth.User.ID)
-			if isUniqueViolation(err) {
-				if strings.Contains(err.Error(), "email") {
-					return ErrEmailTaken
-				}
+// verifiedEmailUser is the user that just verified the given email address.
+// When userID is set, the user is updating its email address instead.
+// If there is no user with that email, a new one is created with the given username.
+func (s *Service) verifiedEmailUser(ctx context.Context, tx *sql.Tx, email string, userID sql.NullString, username *string) (User, error) {
+	var u User
+	var row *sql.Row
 
-				if strings.Contains(err.Error(), "username") {
-					return ErrUsernameTaken
-				}
-			}
+	// not login but update email.
+	if userID.Valid {
+		query := "UPDATE users SET email = $1 WHERE id = $2 RETURNING id, username, avatar"
+		row = tx.QueryRowContext(ctx, query, email, userID.String)
+	} else {
+		query := "SELECT id, username, avatar FROM users WHERE email = $1"
+		row = tx.QueryRowContext(ctx, query, email)
+	}
 
-			if err != nil {
-				return fmt.Errorf("could not sql insert user at magic link: %w", err)
-			}
+	var avatar sql.NullString
+	err := row.Scan(&u.ID, &u.Username, &avatar)
+	if err == sql.ErrNoRows {
+		if username == nil {
+			return u, ErrUserNotFound
+		}
 
-			auth.User.Username = *username
+		reserved, err := usernameReserved(ctx, tx, *username, "")
+		if err != nil {
+			return u, err
+		}
 
-			return nil
+		if reserved {
+			return u, ErrUsernameTaken
+		}
+
+		query := "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id"
+		row := tx.QueryRowContext(ctx, query, email, username)
+		err = row.Scan(&u.ID)
+		if isUniqueViolation(err) {
+			if strings.Contains(err.Error(), "email") {
+				return u, ErrEmailTaken
+			}
+
+			if strings.Contains(err.Error(), "username") {
+				return u, ErrUsernameTaken
+			}
 		}
 
 		if err != nil {
-			return fmt.Errorf("could not sql query select user from verification code email: %w", err)
+			return u, fmt.Errorf("could not sql insert user at magic link: %w", err)
 		}
 
-		auth.User.AvatarURL = s.avatarURL(avatar)
+		u.Username = *username
 
-		return nil
-	})
-	if err != nil {
-		return auth, err
+		return u, nil
 	}
 
-	auth.ExpiresAt = time.Now().Add(authTokenTTL)
-	auth.Token, err = s.codec().EncodeToString(auth.User.ID)
 	if err != nil {
-		return auth, fmt.Errorf("could not create auth token: %w", err)
+		return u, fmt.Errorf("could not sql query select user from verification code email: %w", err)
 	}
 
-	go func() {
-		_, err := s.DB.Exec("DELETE FROM email_verification_codes WHERE email = $1 AND code = $2", email, code)
-		if err != nil {
-			_ = s.Logger.Log("error", fmt.Errorf("could not delete verification code: %w", err))
-			return
-		}
-	}()
+	u.AvatarURL = s.avatarURL(avatar)
 
-	return auth, nil
+	return u, nil
 }
 
 func isVerificationCodeExpired(t time.Time) bool {
@@ -352,44 +442,60 @@ func (s *Service) DevLogin(ctx context.Context, email string) (AuthOutput, error
 
 	out.User.AvatarURL = s.avatarURL(avatar)
 
-	out.Token, err = s.codec().EncodeToString(out.User.ID)
+	out.TokenOutput, err = s.issueToken(ctx, out.User.ID)
 	if err != nil {
-		return out, fmt.Errorf("could not create token: %w", err)
+		return out, err
 	}
 
-	out.ExpiresAt = time.Now().Add(authTokenTTL)
-
 	return out, nil
 }
 
-// AuthUserIDFromToken decodes the token into a user ID.
-func (s *Service) AuthUserIDFromToken(token string) (string, error) {
-	uid, err := s.codec().DecodeToString(token)
+// AuthFromToken decodes the token into the session it belongs to
+// and checks that session is still active.
+// Personal access tokens and access tokens issued to OAuth apps are accepted too.
+func (s *Service) AuthFromToken(ctx context.Context, token string) (Auth, error) {
+	if isPersonalAccessToken(token) {
+		return s.personalAccessTokenAuth(ctx, token)
+	}
+
+	if isOAuthAccessToken(token) {
+		return s.oauthAccessTokenAuth(ctx, token)
+	}
+
+	var auth Auth
+	sid, err := s.codec().DecodeToString(token)
 	if err != nil {
 		if errors.Is(err, branca.ErrInvalidToken) || errors.Is(err, branca.ErrInvalidTokenVersion) {
-			return "", ErrInvalidToken
+			return auth, ErrInvalidToken
 		}
 
 		if _, ok := err.(*branca.ErrExpiredToken); ok {
-			return "", ErrExpiredToken
+			return auth, ErrExpiredToken
 		}
 
 		// check branca unexported/internal chacha20poly1305 error for invalid key.
 		if strings.HasSuffix(err.Error(), "authentication failed") {
-			return "", ErrUnauthenticated
+			return auth, ErrUnauthenticated
 		}
 
-		return "", fmt.Errorf("could not decode token: %w", err)
+		return auth, fmt.Errorf("could not decode token: %w", err)
+	}
+
+	if !reUUID.MatchString(sid) {
+		return auth, ErrInvalidToken
 	}
 
-	if !reUUID.MatchString(uid) {
-		return "", ErrInvalidUserID
+	auth.UserID, err = s.sessionUserID(ctx, sid)
+	if err != nil {
+		return auth, err
 	}
 
-	return uid, nil
+	auth.SessionID = sid
+
+	return auth, nil
 }
 
-// AuthUser is the current authenticated user.
+// AuthUser is the current authenticated user, along with its role.
 func (s *Service) AuthUser(ctx context.Context) (User, error) {
 	var u User
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
@@ -397,30 +503,207 @@ func (s *Service) AuthUser(ctx context.Context) (User, error) {
 		return u, ErrUnauthenticated
 	}
 
-	return s.userByID(ctx, uid)
+	u, err := s.userByID(ctx, uid)
+	if err != nil {
+		return u, err
+	}
+
+	u.Role, err = userRole(ctx, s.DB, uid)
+	if err != nil {
+		return u, err
+	}
+
+	return u, nil
 }
 
-// Token to authenticate requests.
-func (s *Service) Token(ctx context.Context) (TokenOutput, error) {
+// RefreshToken exchanges a refresh token for a new access token.
+// Refresh tokens rotate, so a new one is issued as well
+// and the given one cannot be used again.
+// Presenting an already used refresh token revokes the whole session.
+func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (TokenOutput, error) {
 	var out TokenOutput
-	uid, ok := ctx.Value(KeyAuthUserID).(string)
+
+	hash, ok := hashRefreshToken(refreshToken)
 	if !ok {
-		return out, ErrUnauthenticated
+		return out, ErrInvalidRefreshToken
 	}
 
-	var err error
-	out.Token, err = s.codec().EncodeToString(uid)
+	var sid string
+	var reused bool
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		reused = false
+
+		var usedAt sql.NullTime
+		var expiresAt time.Time
+		query := `
+			SELECT refresh_tokens.session_id, refresh_tokens.used_at, sessions.expires_at
+			FROM refresh_tokens
+			INNER JOIN sessions ON refresh_tokens.session_id = sessions.id
+			WHERE refresh_tokens.token_hash = $1
+			FOR UPDATE`
+		row := tx.QueryRowContext(ctx, query, hash)
+		err := row.Scan(&sid, &usedAt, &expiresAt)
+		if err == sql.ErrNoRows {
+			return ErrInvalidRefreshToken
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select refresh token: %w", err)
+		}
+
+		if usedAt.Valid {
+			reused = true
+			query := "DELETE FROM sessions WHERE id = $1"
+			_, err := tx.ExecContext(ctx, query, sid)
+			if err != nil {
+				return fmt.Errorf("could not sql delete session with reused refresh token: %w", err)
+			}
+
+			return nil
+		}
+
+		if !expiresAt.After(time.Now()) {
+			return ErrExpiredToken
+		}
+
+		query = "UPDATE refresh_tokens SET used_at = now() WHERE token_hash = $1"
+		_, err = tx.ExecContext(ctx, query, hash)
+		if err != nil {
+			return fmt.Errorf("could not sql update refresh token usage: %w", err)
+		}
+
+		out.RefreshTokenExpiresAt = time.Now().Add(refreshTokenTTL)
+		query = "UPDATE sessions SET expires_at = $1 WHERE id = $2"
+		_, err = tx.ExecContext(ctx, query, out.RefreshTokenExpiresAt, sid)
+		if err != nil {
+			return fmt.Errorf("could not sql update session expiration: %w", err)
+		}
+
+		out.RefreshToken, err = insertRefreshToken(ctx, tx, sid)
+		return err
+	})
 	if err != nil {
-		return out, fmt.Errorf("could not create token: %w", err)
+		return out, err
+	}
+
+	if reused {
+		return out, ErrRefreshTokenReused
 	}
 
-	out.ExpiresAt = time.Now().Add(authTokenTTL)
+	out.Token, out.ExpiresAt, err = s.accessToken(sid)
+	if err != nil {
+		return out, err
+	}
 
 	return out, nil
 }
 
+// login issues a new token for the given user.
+// Unless it has two-factor authentication enabled,
+// in which case a second factor challenge gets started instead.
+func (s *Service) login(ctx context.Context, u User) (AuthOutput, error) {
+	out := AuthOutput{User: u}
+
+	challengeID, required, err := s.createSecondFactorChallenge(ctx, u.ID)
+	if err != nil {
+		return out, err
+	}
+
+	if required {
+		out.SecondFactorRequired = true
+		out.SecondFactorChallengeID = challengeID
+		return out, nil
+	}
+
+	out.TokenOutput, err = s.issueToken(ctx, u.ID)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// issueToken starts a new session for the given user
+// and issues its first pair of access and refresh tokens.
+// Suspended users are not allowed to log in.
+// Logging in cancels any pending account deletion.
+func (s *Service) issueToken(ctx context.Context, userID string) (TokenOutput, error) {
+	var out TokenOutput
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		if err := checkNotSuspended(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if err := cancelAccountDeletion(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		var sid string
+		var err error
+		sid, out.RefreshTokenExpiresAt, err = createSession(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		out.RefreshToken, err = insertRefreshToken(ctx, tx, sid)
+		if err != nil {
+			return err
+		}
+
+		out.Token, out.ExpiresAt, err = s.accessToken(sid)
+		return err
+	})
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// accessToken encodes the session ID into a short-lived token.
+func (s *Service) accessToken(sessionID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(accessTokenTTL)
+	token, err := s.codec().EncodeToString(sessionID)
+	if err != nil {
+		return "", expiresAt, fmt.Errorf("could not create auth token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// insertRefreshToken generates a new refresh token for the given session.
+// Only its hash gets stored.
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, sessionID string) (string, error) {
+	b := make([]byte, refreshTokenLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash := sha256.Sum256(b)
+	query := "INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)"
+	_, err := tx.ExecContext(ctx, query, hash[:], sessionID)
+	if err != nil {
+		return "", fmt.Errorf("could not sql insert refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// hashRefreshToken decodes the given refresh token and returns its hash
+// as stored in the database.
+func hashRefreshToken(token string) ([]byte, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != refreshTokenLen {
+		return nil, false
+	}
+
+	hash := sha256.Sum256(b)
+	return hash[:], true
+}
+
 func (s *Service) codec() *branca.Branca {
 	cdc := branca.NewBranca(s.TokenKey)
-	cdc.SetTTL(uint32(authTokenTTL.Seconds()))
+	cdc.SetTTL(uint32(accessTokenTTL.Seconds()))
 	return cdc
 }
@@ -69,6 +69,8 @@ func TestService_SendMagicLink(t *testing.T) {
 			},
 		}
 
+		// own email so the rate limit hit does not affect the next tests.
+		email := testutil.RandStr(t, 10) + "@example.org"
 		err := svc.SendMagicLink(ctx, SendMagicLink{Email: email, RedirectURI: redirectURI})
 		testutil.WantEq(t, fmt.Errorf("could not send magic link: %w", errInternal), err, "error")
 	})
@@ -96,5 +98,14 @@ func TestService_SendMagicLink(t *testing.T) {
 		testutil.WantEq(t, "Login to Nakama", call.Subject, "sender send-subject")
 		testutil.WantEq(t, "text/html; charset=utf-8", http.DetectContentType([]byte(call.HTML)), "sender send-subject content type")
 		t.Logf("\nmagic link text:\n%s\n\n", call.Text)
+
+		t.Run("cooldown", func(t *testing.T) {
+			err := svc.SendMagicLink(ctx, SendMagicLink{Email: email, RedirectURI: redirectURI})
+			testutil.WantEq(t, true, errors.Is(err, ErrTooManyMagicLinks), "too many magic links")
+
+			var retryErr *RetryAfterError
+			testutil.WantEq(t, true, errors.As(err, &retryErr) && retryErr.RetryAfter > 0, "retry after")
+			testutil.WantEq(t, 1, len(senderMock.SendCalls()), "calls length")
+		})
 	})
 }
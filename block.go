@@ -0,0 +1,236 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+var (
+	// ErrForbiddenBlock denotes a forbidden block. Like blocking yourself.
+	ErrForbiddenBlock = PermissionDeniedError("forbidden block")
+	// ErrBlocked denotes an interaction between two users
+	// where one of them has blocked the other.
+	ErrBlocked = PermissionDeniedError("blocked")
+)
+
+// ToggleBlockOutput response.
+type ToggleBlockOutput struct {
+	Blocking bool `json:"blocking"`
+}
+
+// queryRower is either a *sql.DB or a *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// ToggleBlock of the user with the given username.
+// Blocking a user makes both of you unfollow each other
+// and drops follow requests between you.
+// From then on, neither of you can see nor interact with the other.
+func (s *Service) ToggleBlock(ctx context.Context, username string) (ToggleBlockOutput, error) {
+	var out ToggleBlockOutput
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return out, ErrInvalidUsername
+	}
+
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var blockedID string
+		query := "SELECT id FROM users WHERE username = $1"
+		err := tx.QueryRowContext(ctx, query, username).Scan(&blockedID)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select user id from username: %w", err)
+		}
+
+		if blockedID == uid {
+			return ErrForbiddenBlock
+		}
+
+		query = "DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2"
+		res, err := tx.ExecContext(ctx, query, uid, blockedID)
+		if err != nil {
+			return fmt.Errorf("could not sql delete block: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get deleted block rows affected: %w", err)
+		}
+
+		if n != 0 {
+			out.Blocking = false
+			return nil
+		}
+
+		query = "INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1, $2)"
+		if _, err := tx.ExecContext(ctx, query, uid, blockedID); err != nil {
+			return fmt.Errorf("could not sql insert block: %w", err)
+		}
+
+		if err := unfollow(ctx, tx, uid, blockedID); err != nil {
+			return err
+		}
+
+		if err := unfollow(ctx, tx, blockedID, uid); err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM follow_requests
+			WHERE (follower_id = $1 AND followee_id = $2)
+				OR (follower_id = $2 AND followee_id = $1)`
+		if _, err := tx.ExecContext(ctx, query, uid, blockedID); err != nil {
+			return fmt.Errorf("could not sql delete follow requests: %w", err)
+		}
+
+		out.Blocking = true
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// Blocks from the authenticated user in ascending order with forward pagination.
+func (s *Service) Blocks(ctx context.Context, first uint64, after *string) (UserProfiles, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var afterUsername string
+	if after != nil {
+		var err error
+		afterUsername, err = decodeSimpleCursor(*after)
+		if err != nil || !ValidUsername(afterUsername) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT users.username
+		, users.avatar
+		, users.cover
+		, users.followers_count
+		, users.followees_count
+		FROM blocks
+		INNER JOIN users ON blocks.blocked_id = users.id
+		WHERE blocks.blocker_id = @uid
+		{{ if .afterUsername }}AND username > @afterUsername{{ end }}
+		ORDER BY username ASC
+		LIMIT @first`, map[string]interface{}{
+		"uid":           uid,
+		"first":         first,
+		"afterUsername": afterUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build blocks sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query select blocks: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu UserProfiles
+	for rows.Next() {
+		var u UserProfile
+		var avatar, cover sql.NullString
+		err := rows.Scan(
+			&u.Username,
+			&avatar,
+			&cover,
+			&u.FollowersCount,
+			&u.FolloweesCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan block: %w", err)
+		}
+
+		u.Blocking = true
+		u.AvatarURL = s.avatarURL(avatar)
+		u.CoverURL = s.coverURL(cover)
+		uu = append(uu, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate block rows: %w", err)
+	}
+
+	return uu, nil
+}
+
+// unfollow removes the follow between the two users, if any,
+// and decrements their counters accordingly.
+func unfollow(ctx context.Context, tx *sql.Tx, followerID, followeeID string) error {
+	query := "DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2"
+	res, err := tx.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("could not delete follow: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted follow rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	query = "UPDATE users SET followees_count = followees_count - 1 WHERE id = $1"
+	if _, err = tx.ExecContext(ctx, query, followerID); err != nil {
+		return fmt.Errorf("could not decrement followees count: %w", err)
+	}
+
+	query = "UPDATE users SET followers_count = followers_count - 1 WHERE id = $1"
+	if _, err = tx.ExecContext(ctx, query, followeeID); err != nil {
+		return fmt.Errorf("could not decrement followers count: %w", err)
+	}
+
+	return nil
+}
+
+// blocked tells whether there is a block between the two users; either way.
+func blocked(ctx context.Context, db queryRower, userID, otherUserID string) (bool, error) {
+	var ok bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+				OR (blocker_id = $2 AND blocked_id = $1)
+		)`
+	if err := db.QueryRowContext(ctx, query, userID, otherUserID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("could not sql query select block existence: %w", err)
+	}
+
+	return ok, nil
+}
+
+// notBlocked gives a condition to use inside buildQuery,
+// that filters out rows from users in a block with @uid; either way.
+func notBlocked(userIDColumn string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM blocks
+		WHERE (blocks.blocker_id = @uid AND blocks.blocked_id = %[1]s)
+			OR (blocks.blocker_id = %[1]s AND blocks.blocked_id = @uid)
+	)`, userIDColumn)
+}
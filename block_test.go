@@ -0,0 +1,80 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_ToggleBlock(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := svc.ToggleBlock(context.Background(), "someone")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		_, err := svc.ToggleBlock(ctx, "@nope")
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("unfollows_both_ways", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping blocks integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		blocker := createTestUser(t)
+		blockedUser := createTestUser(t)
+
+		_, err := testDB.ExecContext(ctx, `
+			INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2), ($2, $1)`, blocker.ID, blockedUser.ID)
+		testutil.WantEq(t, nil, err, "insert follows error")
+
+		_, err = testDB.ExecContext(ctx, `
+			UPDATE users SET followers_count = 1, followees_count = 1 WHERE id IN ($1, $2)`, blocker.ID, blockedUser.ID)
+		testutil.WantEq(t, nil, err, "update counts error")
+
+		blockerCtx := context.WithValue(ctx, KeyAuthUserID, blocker.ID)
+		out, err := svc.ToggleBlock(blockerCtx, blockedUser.Username)
+		testutil.WantEq(t, nil, err, "toggle block error")
+		testutil.WantEq(t, true, out.Blocking, "blocking")
+
+		var follows int
+		err = testDB.QueryRowContext(ctx, `
+			SELECT count(*) FROM follows WHERE follower_id IN ($1, $2)`, blocker.ID, blockedUser.ID).Scan(&follows)
+		testutil.WantEq(t, nil, err, "count follows error")
+		testutil.WantEq(t, 0, follows, "follows left")
+
+		var followersCount, followeesCount int
+		err = testDB.QueryRowContext(ctx, `
+			SELECT followers_count, followees_count FROM users WHERE id = $1`, blockedUser.ID).Scan(&followersCount, &followeesCount)
+		testutil.WantEq(t, nil, err, "select counts error")
+		testutil.WantEq(t, 0, followersCount, "followers count")
+		testutil.WantEq(t, 0, followeesCount, "followees count")
+
+		blockedCtx := context.WithValue(ctx, KeyAuthUserID, blockedUser.ID)
+		_, err = svc.ToggleFollow(blockedCtx, blocker.Username)
+		testutil.WantEq(t, ErrBlocked, err, "follow error")
+
+		out, err = svc.ToggleBlock(blockerCtx, blockedUser.Username)
+		testutil.WantEq(t, nil, err, "toggle unblock error")
+		testutil.WantEq(t, false, out.Blocking, "blocking")
+	})
+}
+
+func TestService_Blocks(t *testing.T) {
+	svc := &Service{}
+
+	_, err := svc.Blocks(context.Background(), 0, nil)
+	testutil.WantEq(t, ErrUnauthenticated, err, "error")
+
+	ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+	_, err = svc.Blocks(ctx, 0, ptrString("nope"))
+	testutil.WantEq(t, ErrInvalidCursor, err, "error")
+}
@@ -80,14 +80,20 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		githubClientSecret  = os.Getenv("GITHUB_CLIENT_SECRET")
 		googleClientID      = os.Getenv("GOOGLE_CLIENT_ID")
 		googleClientSecret  = os.Getenv("GOOGLE_CLIENT_SECRET")
+		oidcProviders       = os.Getenv("OIDC_PROVIDERS")
 		disabledDevLogin, _ = strconv.ParseBool(os.Getenv("DISABLE_DEV_LOGIN"))
 		allowedOrigins      = os.Getenv("ALLOWED_ORIGINS")
+		trustedProxies, _   = strconv.Atoi(env("TRUSTED_PROXIES", "0"))
 		vapidPrivateKey     = os.Getenv("VAPID_PRIVATE_KEY")
 		vapidPublicKey      = os.Getenv("VAPID_PUBLIC_KEY")
 	)
 
 	fs := flag.NewFlagSet("nakama", flag.ExitOnError)
 	fs.Usage = func() {
+		fmt.Println("Usage: nakama [flags] [command]")
+		fmt.Println("\nCommands:")
+		fmt.Println("  set-role <username> <user|moderator|admin>\n    \tAssign a role to a user")
+		fmt.Println("\nFlags:")
 		fs.PrintDefaults()
 		fmt.Println("\nDon't forget to set TOKEN_KEY, and SENDGRID_API_KEY or SMTP_USERNAME and SMTP_PASSWORD for real usage.")
 	}
@@ -106,8 +112,10 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 	fs.StringVar(&cookieBlockKey, "cookie-block-key", cookieBlockKey, "Cookie block key. 16, 24, or 32 bytes")
 	fs.StringVar(&githubClientID, "github-client-id", githubClientID, "GitHub client ID")
 	fs.StringVar(&googleClientID, "google-client-id", googleClientID, "Google client ID")
+	fs.StringVar(&oidcProviders, "oidc-providers", oidcProviders, "Comma separated list of generic OpenID Connect provider names. Each one configured with OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET and optionally OIDC_<NAME>_SCOPES")
 	fs.BoolVar(&disabledDevLogin, "disable-dev-login", disabledDevLogin, "Disable development login endpoint")
 	fs.StringVar(&allowedOrigins, "allowed-origins", allowedOrigins, "Comma separated list of allowed origins")
+	fs.IntVar(&trustedProxies, "trusted-proxies", trustedProxies, "Number of trusted reverse proxies in front of this server. X-Forwarded-For is ignored when 0")
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("could not parse flags: %w", err)
 	}
@@ -145,6 +153,14 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		}
 	}
 
+	switch cmd := fs.Arg(0); cmd {
+	case "":
+	case "set-role":
+		return setRole(ctx, db, fs.Args()[1:])
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
 	natsConn, err := nats.Connect(natsURL)
 	if err != nil {
 		return fmt.Errorf("could not connect to NATS server: %w", err)
@@ -201,7 +217,7 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		store = &fsstorage.Store{Root: filepath.Join(wd, "web", "static", "img")}
 	}
 
-	var svc transport.Service = &nakama.Service{
+	service := &nakama.Service{
 		Logger:           logger,
 		DB:               db,
 		Sender:           sender,
@@ -218,12 +234,22 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		VAPIDPublicKey:   vapidPublicKey,
 	}
 
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		service.RunBackgroundJobs(ctx)
+	}()
+
+	var svc transport.Service = service
+
 	var promHandler http.Handler
 	{
 		promHandler = promhttp.Handler()
 		svc = &transport.ServiceWithInstrumentation{Next: svc}
 	}
 
+	svc = &transport.ServiceWithScopes{Next: svc}
+
 	var oauthProviders []httptransport.OauthProvider
 	if githubClientID != "" && githubClientSecret != "" {
 		oauthProviders = append(oauthProviders, httptransport.OauthProvider{
@@ -239,34 +265,42 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		})
 	}
 	if googleClientID != "" && googleClientSecret != "" {
-		provider, err := oidc.NewProvider(ctx, "https://accounts.google.com")
+		provider, err := oidcProvider(ctx, origin, "google", "https://accounts.google.com", googleClientID, googleClientSecret, nil)
 		if err != nil {
-			return fmt.Errorf("setup google oidc: %w", err)
+			return err
 		}
 
-		oauthProviders = append(oauthProviders, httptransport.OauthProvider{
-			Name: "google",
-			Config: &oauth2.Config{
-				ClientID:     googleClientID,
-				ClientSecret: googleClientSecret,
-				RedirectURL:  origin.String() + "/api/google_auth/callback",
-				Endpoint:     provider.Endpoint(),
-				Scopes: []string{
-					oidc.ScopeOpenID,
-					"profile",
-					"email",
-				},
-			},
-			IDTokenVerifier: provider.Verifier(&oidc.Config{
-				ClientID: googleClientID,
-			}),
-		})
+		oauthProviders = append(oauthProviders, provider)
+	}
+	for _, name := range strings.Split(oidcProviders, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		var scopes []string
+		if s := os.Getenv(prefix + "SCOPES"); s != "" {
+			scopes = strings.Split(s, ",")
+		}
+		provider, err := oidcProvider(ctx, origin, name,
+			os.Getenv(prefix+"ISSUER"),
+			os.Getenv(prefix+"CLIENT_ID"),
+			os.Getenv(prefix+"CLIENT_SECRET"),
+			scopes,
+		)
+		if err != nil {
+			return err
+		}
+
+		_ = logger.Log("oidc_provider", name)
+		oauthProviders = append(oauthProviders, provider)
 	}
 	cookieCodec := securecookie.New(
 		[]byte(cookieHashKey),
 		[]byte(cookieBlockKey),
 	)
-	h := httptransport.New(svc, oauthProviders, origin, log.With(logger, "component", "http"), store, cookieCodec, promHandler, embedStaticFiles)
+	h := httptransport.New(svc, oauthProviders, origin, log.With(logger, "component", "http"), store, cookieCodec, promHandler, embedStaticFiles, trustedProxies)
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", port),
 		Handler:           h,
@@ -287,6 +321,14 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 		defer cancelShutdown()
 		if err := server.Shutdown(ctxShutdown); err != nil {
 			errs <- fmt.Errorf("could not shutdown server: %w", err)
+			return
+		}
+
+		select {
+		case <-jobsDone:
+		case <-ctxShutdown.Done():
+			errs <- fmt.Errorf("could not stop background jobs: %w", ctxShutdown.Err())
+			return
 		}
 
 		errs <- nil
@@ -302,6 +344,63 @@ func run(ctx context.Context, logger log.Logger, args []string) error {
 	return <-errs
 }
 
+// oidcProvider discovers the OpenID Connect provider at the given issuer URL.
+// Scopes default to openid, profile and email.
+func oidcProvider(ctx context.Context, origin *url.URL, name, issuer, clientID, clientSecret string, scopes []string) (httptransport.OauthProvider, error) {
+	var out httptransport.OauthProvider
+	if !nakama.ValidProviderName(name) {
+		return out, fmt.Errorf("invalid oidc provider name %q", name)
+	}
+
+	if issuer == "" || clientID == "" || clientSecret == "" {
+		return out, fmt.Errorf("missing issuer, client ID or client secret for %s oidc provider", name)
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return out, fmt.Errorf("setup %s oidc: %w", name, err)
+	}
+
+	if len(scopes) == 0 {
+		scopes = []string{
+			oidc.ScopeOpenID,
+			"profile",
+			"email",
+		}
+	}
+
+	return httptransport.OauthProvider{
+		Name: name,
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  origin.String() + "/api/" + name + "_auth/callback",
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		IDTokenVerifier: provider.Verifier(&oidc.Config{
+			ClientID: clientID,
+		}),
+	}, nil
+}
+
+// setRole assigns a role to the user with the given username.
+// Usage: nakama set-role <username> <user|moderator|admin>.
+func setRole(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: nakama set-role <username> <user|moderator|admin>")
+	}
+
+	username, role := args[0], nakama.Role(args[1])
+	service := &nakama.Service{DB: db}
+	if err := service.SetUserRole(ctx, username, role); err != nil {
+		return fmt.Errorf("could not set user role: %w", err)
+	}
+
+	fmt.Printf("%s is now %s\n", username, role)
+	return nil
+}
+
 func env(key, fallbackValue string) string {
 	s, ok := os.LookupEnv(key)
 	if !ok {
@@ -23,6 +23,8 @@ var (
 	// ErrCommentNotFound denotes a not found comment.
 	ErrCommentNotFound     = NotFoundError("comment not found")
 	ErrUpdateCommentDenied = PermissionDeniedError("update comment denied")
+	// ErrDeleteCommentDenied denotes that the user is not allowed to delete someone else's comment.
+	ErrDeleteCommentDenied = PermissionDeniedError("delete comment denied")
 )
 
 // Comment model.
@@ -66,10 +68,41 @@ func (s *Service) CreateComment(ctx context.Context, postID string, content stri
 	tags := collectTags(content)
 
 	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
-		query := `
+		var postUserID string
+		query := "SELECT user_id FROM posts WHERE id = $1"
+		err := tx.QueryRowContext(ctx, query, postID).Scan(&postUserID)
+		if err == sql.ErrNoRows {
+			return ErrPostNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select post user id: %w", err)
+		}
+
+		visible, err := postVisibleByID(ctx, tx, uid, postID)
+		if err != nil {
+			return err
+		}
+
+		if !visible {
+			return ErrPostNotFound
+		}
+
+		if postUserID != uid {
+			isBlocked, err := blocked(ctx, tx, uid, postUserID)
+			if err != nil {
+				return err
+			}
+
+			if isBlocked {
+				return ErrBlocked
+			}
+		}
+
+		query = `
 			INSERT INTO comments (user_id, post_id, content) VALUES ($1, $2, $3)
 			RETURNING id, created_at`
-		err := tx.QueryRowContext(ctx, query, uid, postID, content).Scan(&c.ID, &c.CreatedAt)
+		err = tx.QueryRowContext(ctx, query, uid, postID, content).Scan(&c.ID, &c.CreatedAt)
 		if isForeignKeyViolation(err) {
 			return ErrPostNotFound
 		}
@@ -189,6 +222,11 @@ func (s *Service) Comments(ctx context.Context, postID string, last uint64, befo
 		) AS reactions ON reactions.user_id = @uid AND reactions.comment_id = comments.id
 		{{end}}
 		WHERE comments.post_id = @postID
+		AND (comments.hidden_at IS NULL{{if .auth}} OR comments.user_id = @uid{{end}})
+		AND EXISTS (
+			SELECT 1 FROM posts WHERE posts.id = comments.post_id AND `+visiblePost()+`
+		)
+		{{if .auth}}AND `+notBlocked("comments.user_id")+`{{end}}
 		{{ if and .beforeCommentID .beforeCreatedAt }}
 			AND comments.created_at <= @beforeCreatedAt
 			AND (
@@ -270,13 +308,23 @@ func (s *Service) Comments(ctx context.Context, postID string, last uint64, befo
 }
 
 // CommentStream to receive comments in realtime.
+// The post must be visible to the user, same as with Comments.
 func (s *Service) CommentStream(ctx context.Context, postID string) (<-chan Comment, error) {
 	if !reUUID.MatchString(postID) {
 		return nil, ErrInvalidPostID
 	}
 
-	cc := make(chan Comment)
 	uid, auth := ctx.Value(KeyAuthUserID).(string)
+	visible, err := postVisibleByID(ctx, s.DB, uid, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !visible {
+		return nil, ErrPostNotFound
+	}
+
+	cc := make(chan Comment)
 	unsub, err := s.PubSub.Sub(commentTopic(postID), func(data []byte) {
 		go func(r io.Reader) {
 			var c Comment
@@ -290,6 +338,18 @@ func (s *Service) CommentStream(ctx context.Context, postID string) (<-chan Comm
 				return
 			}
 
+			if auth {
+				isBlocked, err := blocked(ctx, s.DB, uid, c.UserID)
+				if err != nil {
+					_ = s.Logger.Log("error", err)
+					return
+				}
+
+				if isBlocked {
+					return
+				}
+			}
+
 			cc <- c
 		}(bytes.NewReader(data))
 	})
@@ -378,6 +438,8 @@ func (s *Service) UpdateComment(ctx context.Context, in UpdateComment) (UpdatedC
 	})
 }
 
+// DeleteComment from the authenticated user.
+// Moderators and admins can delete the comments of users with a lower role.
 func (s *Service) DeleteComment(ctx context.Context, commentID string) error {
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
 	if !ok {
@@ -389,10 +451,10 @@ func (s *Service) DeleteComment(ctx context.Context, commentID string) error {
 	}
 
 	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
-		var postID string
-		query := "SELECT post_id FROM comments WHERE id = $1 AND user_id = $2"
-		row := tx.QueryRowContext(ctx, query, commentID, uid)
-		err := row.Scan(&postID)
+		var postID, authorID string
+		query := "SELECT post_id, user_id FROM comments WHERE id = $1"
+		row := tx.QueryRowContext(ctx, query, commentID)
+		err := row.Scan(&postID, &authorID)
 		if err == sql.ErrNoRows {
 			return ErrCommentNotFound
 		}
@@ -401,6 +463,12 @@ func (s *Service) DeleteComment(ctx context.Context, commentID string) error {
 			return fmt.Errorf("could not sql query select comment to delete post id: %w", err)
 		}
 
+		if authorID != uid {
+			if err := authorizeOver(ctx, tx, uid, authorID, permDeleteAnyComment, ErrDeleteCommentDenied); err != nil {
+				return err
+			}
+		}
+
 		query = "DELETE FROM comments WHERE id = $1"
 		_, err = tx.ExecContext(ctx, query, commentID)
 		if err != nil {
@@ -446,8 +514,9 @@ func (s *Service) ToggleCommentReaction(ctx context.Context, commentID string, i
 
 		var rawReactions []byte
 		var rawUserReactions []byte
+		var commentUserID, postID string
 		query := `
-			SELECT comments.reactions, reactions.user_reactions
+			SELECT comments.user_id, comments.post_id, comments.reactions, reactions.user_reactions
 			FROM comments
 			LEFT JOIN (
 				SELECT user_id
@@ -458,7 +527,7 @@ func (s *Service) ToggleCommentReaction(ctx context.Context, commentID string, i
 			) AS reactions ON reactions.user_id = $1 AND reactions.comment_id = comments.id
 			WHERE comments.id = $2`
 		row := tx.QueryRowContext(ctx, query, uid, commentID)
-		err := row.Scan(&rawReactions, &rawUserReactions)
+		err := row.Scan(&commentUserID, &postID, &rawReactions, &rawUserReactions)
 		if err == sql.ErrNoRows {
 			return ErrCommentNotFound
 		}
@@ -467,6 +536,26 @@ func (s *Service) ToggleCommentReaction(ctx context.Context, commentID string, i
 			return fmt.Errorf("could not sql scan comment and user reactions: %w", err)
 		}
 
+		visible, err := postVisibleByID(ctx, tx, uid, postID)
+		if err != nil {
+			return err
+		}
+
+		if !visible {
+			return ErrCommentNotFound
+		}
+
+		if commentUserID != uid {
+			isBlocked, err := blocked(ctx, tx, uid, commentUserID)
+			if err != nil {
+				return err
+			}
+
+			if isBlocked {
+				return ErrBlocked
+			}
+		}
+
 		var reactions []Reaction
 		if rawReactions != nil {
 			err = json.Unmarshal(rawReactions, &reactions)
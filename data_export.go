@@ -0,0 +1,521 @@
+package nakama
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/hako/durafmt"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+
+	"github.com/nakamauwu/nakama/storage"
+	"github.com/nakamauwu/nakama/web"
+)
+
+// DataExportsBucket is where data export archives are stored.
+const DataExportsBucket = "exports"
+
+const (
+	dataExportTTL      = time.Hour * 24 * 7
+	dataExportTokenLen = 32
+	dataExportsBatch   = 5
+	// dataExportStaleAfter is how long an export can be building
+	// before another run takes it over;
+	// as it may have been interrupted by a restart.
+	dataExportStaleAfter = time.Minute * 30
+)
+
+// Data export statuses.
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+var (
+	// ErrDataExportInProgress denotes that the user already has an export being built.
+	ErrDataExportInProgress = AlreadyExistsError("data export in progress")
+	// ErrDataExportNotFound denotes a not found or expired data export.
+	ErrDataExportNotFound = NotFoundError("data export not found")
+)
+
+// DataExport of all the data of a user.
+type DataExport struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+// dataExportFiles are the JSON files included in the archive.
+// Each query takes the user ID and gives back a single JSON value.
+var dataExportFiles = []struct {
+	name  string
+	query string
+}{
+	{"profile.json", `
+		SELECT json_build_object(
+			'id', id,
+			'email', email,
+			'username', username,
+			'avatar', avatar,
+			'cover', cover,
+			'bio', bio,
+			'waifu', waifu,
+			'husbando', husbando,
+			'followersCount', followers_count,
+			'followeesCount', followees_count,
+			'createdAt', created_at
+		) FROM users WHERE id = $1`},
+	{"posts.json", `
+		SELECT COALESCE(json_agg(json_build_object(
+			'id', id,
+			'content', content,
+			'media', media,
+			'spoilerOf', spoiler_of,
+			'nsfw', nsfw,
+			'createdAt', created_at,
+			'updatedAt', updated_at
+		) ORDER BY created_at), '[]') FROM posts WHERE user_id = $1`},
+	{"comments.json", `
+		SELECT COALESCE(json_agg(json_build_object(
+			'id', id,
+			'postID', post_id,
+			'content', content,
+			'createdAt', created_at
+		) ORDER BY created_at), '[]') FROM comments WHERE user_id = $1`},
+	{"reactions.json", `
+		SELECT json_build_object(
+			'posts', (
+				SELECT COALESCE(json_agg(json_build_object(
+					'postID', post_id,
+					'type', type,
+					'reaction', reaction
+				)), '[]') FROM post_reactions WHERE user_id = $1
+			),
+			'comments', (
+				SELECT COALESCE(json_agg(json_build_object(
+					'commentID', comment_id,
+					'type', type,
+					'reaction', reaction
+				)), '[]') FROM comment_reactions WHERE user_id = $1
+			)
+		)`},
+	{"follows.json", `
+		SELECT json_build_object(
+			'followers', (
+				SELECT COALESCE(json_agg(users.username ORDER BY users.username), '[]')
+				FROM follows
+				INNER JOIN users ON follows.follower_id = users.id
+				WHERE follows.followee_id = $1
+			),
+			'followees', (
+				SELECT COALESCE(json_agg(users.username ORDER BY users.username), '[]')
+				FROM follows
+				INNER JOIN users ON follows.followee_id = users.id
+				WHERE follows.follower_id = $1
+			)
+		)`},
+	{"notifications.json", `
+		SELECT COALESCE(json_agg(json_build_object(
+			'id', id,
+			'actors', ARRAY(
+				SELECT users.username FROM unnest(actor_ids) WITH ORDINALITY AS actor (id, i)
+				INNER JOIN users ON users.id = actor.id
+				ORDER BY actor.i
+			),
+			'type', type,
+			'postID', post_id,
+			'readAt', read_at,
+			'issuedAt', issued_at
+		) ORDER BY issued_at), '[]') FROM notifications WHERE user_id = $1`},
+}
+
+// RequestDataExport of all the data of the authenticated user.
+// The archive is built in the background,
+// and a download link is sent by email once it is ready.
+func (s *Service) RequestDataExport(ctx context.Context) (DataExport, error) {
+	var out DataExport
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	query := `
+		INSERT INTO data_exports (user_id, status) VALUES ($1, $2)
+		RETURNING id, created_at`
+	err := s.DB.QueryRowContext(ctx, query, uid, DataExportStatusPending).Scan(&out.ID, &out.CreatedAt)
+	if isUniqueViolation(err) {
+		return out, ErrDataExportInProgress
+	}
+
+	if isForeignKeyViolation(err) {
+		return out, ErrUserGone
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql insert data export: %w", err)
+	}
+
+	out.Status = DataExportStatusPending
+
+	return out, nil
+}
+
+// DataExportFile opens the archive of a ready data export
+// given the token from the download link.
+// The caller must close the file.
+func (s *Service) DataExportFile(ctx context.Context, token string) (*storage.File, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != dataExportTokenLen {
+		return nil, ErrInvalidToken
+	}
+
+	hash := sha256.Sum256(b)
+
+	var fileName string
+	query := `
+		SELECT file_name FROM data_exports
+		WHERE token_hash = $1 AND status = $2 AND expires_at > now()`
+	err = s.DB.QueryRowContext(ctx, query, hash[:], DataExportStatusReady).Scan(&fileName)
+	if err == sql.ErrNoRows {
+		return nil, ErrDataExportNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select data export: %w", err)
+	}
+
+	f, err := s.Store.Open(ctx, DataExportsBucket, fileName)
+	if isFileNotFound(err) {
+		return nil, ErrDataExportNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open data export file: %w", err)
+	}
+
+	return f, nil
+}
+
+// buildDataExports builds pending data exports,
+// and deletes the expired and failed ones along with their archives.
+func (s *Service) buildDataExports(ctx context.Context) (int64, error) {
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		query := `
+			DELETE FROM data_exports
+			WHERE expires_at < now() OR (status = $1 AND completed_at < $2)
+			RETURNING file_name`
+		rows, err := tx.QueryContext(ctx, query, DataExportStatusFailed, time.Now().Add(-dataExportTTL))
+		if err != nil {
+			return fmt.Errorf("could not sql delete expired data exports: %w", err)
+		}
+
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name sql.NullString
+			if err := rows.Scan(&name); err != nil {
+				return fmt.Errorf("could not sql scan expired data export: %w", err)
+			}
+
+			if name.Valid {
+				names = append(names, name.String)
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not iterate expired data export rows: %w", err)
+		}
+
+		return s.orphanFiles(ctx, tx, DataExportsBucket, names...)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for n < dataExportsBatch {
+		var exportID, uid string
+		query := `
+			UPDATE data_exports SET started_at = now()
+			WHERE id = (
+				SELECT id FROM data_exports
+				WHERE status = $1 AND (started_at IS NULL OR started_at < $2)
+				ORDER BY created_at
+				LIMIT 1
+			)
+			RETURNING id, user_id`
+		err := s.DB.QueryRowContext(ctx, query, DataExportStatusPending, time.Now().Add(-dataExportStaleAfter)).Scan(&exportID, &uid)
+		if err == sql.ErrNoRows {
+			return n, nil
+		}
+
+		if err != nil {
+			return n, fmt.Errorf("could not sql claim pending data export: %w", err)
+		}
+
+		if err := s.buildDataExport(ctx, exportID, uid); err != nil {
+			// leave it for a retry if it was just interrupted.
+			if ctx.Err() != nil {
+				return n, err
+			}
+
+			query := `
+				UPDATE data_exports SET
+					status = $1
+					, file_name = NULL
+					, token_hash = NULL
+					, completed_at = now()
+					, expires_at = NULL
+				WHERE id = $2`
+			if _, err := s.DB.ExecContext(ctx, query, DataExportStatusFailed, exportID); err != nil {
+				_ = s.Logger.Log("error", fmt.Errorf("could not sql mark data export as failed: %w", err))
+			}
+
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// buildDataExport archives the user data into the store,
+// marks the export as ready and emails the download link.
+func (s *Service) buildDataExport(ctx context.Context, exportID, userID string) error {
+	var email string
+	query := "SELECT email FROM users WHERE id = $1"
+	if err := s.DB.QueryRowContext(ctx, query, userID).Scan(&email); err != nil {
+		return fmt.Errorf("could not sql query select data export user email: %w", err)
+	}
+
+	// the archive is built on disk as it can get as big as every media file the user uploaded.
+	tmp, err := os.CreateTemp("", "nakama-data-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("could not create data export temp file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.writeDataExport(ctx, tmp, userID); err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("could not get data export size: %w", err)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("could not rewind data export temp file: %w", err)
+	}
+
+	fileName, err := gonanoid.New()
+	if err != nil {
+		return fmt.Errorf("could not generate data export filename: %w", err)
+	}
+
+	fileName += ".zip"
+
+	err = s.Store.StoreFrom(ctx, DataExportsBucket, fileName, tmp, size, storage.StoreWithContentType("application/zip"))
+	if err != nil {
+		return fmt.Errorf("could not store data export file: %w", err)
+	}
+
+	token := make([]byte, dataExportTokenLen)
+	if _, err := rand.Read(token); err != nil {
+		go s.deleteFile(DataExportsBucket, fileName)
+		return fmt.Errorf("could not generate data export token: %w", err)
+	}
+
+	hash := sha256.Sum256(token)
+	expiresAt := time.Now().Add(dataExportTTL)
+	query = `
+		UPDATE data_exports SET
+			status = $1
+			, file_name = $2
+			, token_hash = $3
+			, completed_at = now()
+			, expires_at = $4
+		WHERE id = $5`
+	_, err = s.DB.ExecContext(ctx, query, DataExportStatusReady, fileName, hash[:], expiresAt, exportID)
+	if err != nil {
+		go s.deleteFile(DataExportsBucket, fileName)
+		return fmt.Errorf("could not sql update data export as ready: %w", err)
+	}
+
+	// See transport/http/handler.go
+	// GET /api/data_export must exist.
+	link := cloneURL(s.Origin)
+	link.Path = "/api/data_export"
+	q := link.Query()
+	q.Set("token", base64.RawURLEncoding.EncodeToString(token))
+	link.RawQuery = q.Encode()
+
+	if err := s.sendDataExportLink(email, link); err != nil {
+		go s.deleteFile(DataExportsBucket, fileName)
+		return err
+	}
+
+	return nil
+}
+
+// writeDataExport zips the user data as JSON files
+// along with the original avatar, cover and post media.
+func (s *Service) writeDataExport(ctx context.Context, w io.Writer, userID string) error {
+	zw := zip.NewWriter(w)
+
+	for _, f := range dataExportFiles {
+		var raw []byte
+		if err := s.DB.QueryRowContext(ctx, f.query, userID).Scan(&raw); err != nil {
+			return fmt.Errorf("could not sql query select data export %s: %w", f.name, err)
+		}
+
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, raw, "", "  "); err != nil {
+			return fmt.Errorf("could not json indent data export %s: %w", f.name, err)
+		}
+
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("could not create data export %s: %w", f.name, err)
+		}
+
+		if _, err := indented.WriteTo(fw); err != nil {
+			return fmt.Errorf("could not write data export %s: %w", f.name, err)
+		}
+	}
+
+	type file struct{ bucket, name string }
+	var files []file
+
+	query := `
+		SELECT $2, avatar FROM users WHERE id = $1 AND avatar IS NOT NULL
+		UNION ALL
+		SELECT $3, cover FROM users WHERE id = $1 AND cover IS NOT NULL
+		UNION ALL
+		SELECT $4, unnest(media) FROM posts WHERE user_id = $1 AND media IS NOT NULL`
+	rows, err := s.DB.QueryContext(ctx, query, userID, AvatarsBucket, CoversBucket, MediaBucket)
+	if err != nil {
+		return fmt.Errorf("could not sql query select data export files: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var f file
+		if err := rows.Scan(&f.bucket, &f.name); err != nil {
+			return fmt.Errorf("could not sql scan data export file: %w", err)
+		}
+
+		files = append(files, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not iterate data export file rows: %w", err)
+	}
+
+	for _, f := range files {
+		if err := s.copyDataExportFile(ctx, zw, f.bucket, f.name); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("could not close data export zip: %w", err)
+	}
+
+	return nil
+}
+
+// copyDataExportFile from the store into the zip, under a directory named after the bucket.
+// Files already gone from the store are skipped.
+func (s *Service) copyDataExportFile(ctx context.Context, zw *zip.Writer, bucket, name string) error {
+	f, err := s.Store.Open(ctx, bucket, name)
+	if isFileNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not open data export file %s/%s: %w", bucket, name, err)
+	}
+
+	defer f.Close()
+
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     path.Join(bucket, name),
+		Method:   zip.Store, // images are already compressed.
+		Modified: f.LastModified,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create data export file %s/%s: %w", bucket, name, err)
+	}
+
+	if _, err := io.Copy(fw, f); err != nil {
+		return fmt.Errorf("could not copy data export file %s/%s: %w", bucket, name, err)
+	}
+
+	return nil
+}
+
+func (s *Service) sendDataExportLink(email string, link fmt.Stringer) error {
+	var err error
+	s.dataExportTmplOncer.Do(func() {
+		var text []byte
+		text, err = web.TemplateFiles.ReadFile("template/mail/data-export.html.tmpl")
+		if err != nil {
+			err = fmt.Errorf("could not read data export template file: %w", err)
+			return
+		}
+
+		s.dataExportTmpl, err = template.
+			New("mail/data-export.html").
+			Funcs(template.FuncMap{
+				"human_duration": func(d time.Duration) string {
+					return durafmt.Parse(d).LimitFirstN(1).String()
+				},
+			}).
+			Parse(string(text))
+		if err != nil {
+			err = fmt.Errorf("could not parse data export mail template: %w", err)
+			return
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	err = s.dataExportTmpl.Execute(&b, map[string]interface{}{
+		"Origin": s.Origin,
+		"Link":   link,
+		"TTL":    dataExportTTL,
+	})
+	if err != nil {
+		return fmt.Errorf("could not execute data export mail template: %w", err)
+	}
+
+	err = s.Sender.Send(email, "Your Nakama data export", b.String(), link.String())
+	if err != nil {
+		return fmt.Errorf("could not send data export email: %w", err)
+	}
+
+	return nil
+}
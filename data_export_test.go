@@ -0,0 +1,70 @@
+package nakama
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	fsstorage "github.com/nakamauwu/nakama/storage/fs"
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_RequestDataExport(t *testing.T) {
+	svc := &Service{}
+	_, err := svc.RequestDataExport(context.Background())
+	testutil.WantEq(t, ErrUnauthenticated, err, "error")
+}
+
+func TestService_DataExportFile(t *testing.T) {
+	svc := &Service{}
+	for _, token := range []string{"", "nope", "c2hvcnQ"} {
+		_, err := svc.DataExportFile(context.Background(), token)
+		testutil.WantEq(t, ErrInvalidToken, err, "error")
+	}
+}
+
+func TestService_writeDataExport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping data export integration test in short mode")
+	}
+
+	ctx := context.Background()
+	store := &fsstorage.Store{Root: t.TempDir()}
+	svc := &Service{DB: testDB, Store: store}
+
+	username := "export_" + testutil.RandStr(t, 8)
+	var uid string
+	row := testDB.QueryRowContext(ctx, "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id", username+"@example.org", username)
+	err := row.Scan(&uid)
+	testutil.WantEq(t, nil, err, "insert user error")
+
+	media := testutil.RandStr(t, 10) + ".png"
+	err = store.Store(ctx, MediaBucket, media, []byte("nakama"))
+	testutil.WantEq(t, nil, err, "store media error")
+
+	_, err = testDB.ExecContext(ctx, "INSERT INTO posts (user_id, content, media) VALUES ($1, 'hi', ARRAY[$2])", uid, media)
+	testutil.WantEq(t, nil, err, "insert post error")
+
+	var b bytes.Buffer
+	err = svc.writeDataExport(ctx, &b, uid)
+	testutil.WantEq(t, nil, err, "write data export error")
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	testutil.WantEq(t, nil, err, "zip reader error")
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+
+	testutil.WantEq(t, []string{
+		"profile.json",
+		"posts.json",
+		"comments.json",
+		"reactions.json",
+		"follows.json",
+		"notifications.json",
+		"media/" + media,
+	}, names, "zip file names")
+}
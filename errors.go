@@ -1,6 +1,9 @@
 package nakama
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var ErrInvalidArgument = errors.New("invalid argument")
 
@@ -98,3 +101,33 @@ func (e GoneError) Error() string {
 func (e GoneError) Unwrap() error {
 	return ErrGone
 }
+
+// -----------------------------------------------------------------------------
+
+var ErrResourceExhausted = errors.New("resource exhausted")
+
+type ResourceExhaustedError string
+
+func (e ResourceExhaustedError) Error() string {
+	return string(e)
+}
+
+func (e ResourceExhaustedError) Unwrap() error {
+	return ErrResourceExhausted
+}
+
+// -----------------------------------------------------------------------------
+
+// RetryAfterError tells how long to wait before trying again.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
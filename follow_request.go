@@ -0,0 +1,307 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/lib/pq"
+)
+
+// ErrFollowRequestNotFound denotes a not found follow request.
+var ErrFollowRequestNotFound = NotFoundError("follow request not found")
+
+// FollowRequests sent to the authenticated user
+// in ascending order with forward pagination.
+func (s *Service) FollowRequests(ctx context.Context, first uint64, after *string) (UserProfiles, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var afterUsername string
+	if after != nil {
+		var err error
+		afterUsername, err = decodeSimpleCursor(*after)
+		if err != nil || !ValidUsername(afterUsername) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT users.username
+		, users.avatar
+		, users.cover
+		, users.followers_count
+		, users.followees_count
+		, users.private
+		, followees.followee_id IS NOT NULL AS following
+		FROM follow_requests
+		INNER JOIN users ON follow_requests.follower_id = users.id
+		LEFT JOIN follows AS followees
+			ON followees.follower_id = @uid AND followees.followee_id = users.id
+		WHERE follow_requests.followee_id = @uid
+		{{ if .afterUsername }}AND username > @afterUsername{{ end }}
+		ORDER BY username ASC
+		LIMIT @first`, map[string]interface{}{
+		"uid":           uid,
+		"first":         first,
+		"afterUsername": afterUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build follow requests sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query select follow requests: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu UserProfiles
+	for rows.Next() {
+		var u UserProfile
+		var avatar, cover sql.NullString
+		err := rows.Scan(
+			&u.Username,
+			&avatar,
+			&cover,
+			&u.FollowersCount,
+			&u.FolloweesCount,
+			&u.Private,
+			&u.Following,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan follow request: %w", err)
+		}
+
+		u.AvatarURL = s.avatarURL(avatar)
+		u.CoverURL = s.coverURL(cover)
+		uu = append(uu, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate follow request rows: %w", err)
+	}
+
+	return uu, nil
+}
+
+// ApproveFollowRequest from the user with the given username,
+// making them a follower of the authenticated user.
+func (s *Service) ApproveFollowRequest(ctx context.Context, username string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	var followerID string
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var err error
+		followerID, err = deleteFollowRequest(ctx, tx, username, uid)
+		if err != nil {
+			return err
+		}
+
+		query := "INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		res, err := tx.ExecContext(ctx, query, followerID, uid)
+		if err != nil {
+			return fmt.Errorf("could not insert follow: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get inserted follow rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		query = "UPDATE users SET followees_count = followees_count + 1 WHERE id = $1"
+		if _, err = tx.ExecContext(ctx, query, followerID); err != nil {
+			return fmt.Errorf("could not increment followees count: %w", err)
+		}
+
+		query = "UPDATE users SET followers_count = followers_count + 1 WHERE id = $1"
+		if _, err = tx.ExecContext(ctx, query, uid); err != nil {
+			return fmt.Errorf("could not increment followers count: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	go s.notifyFollowRequest("follow_request_approved", followerID, uid)
+
+	return nil
+}
+
+// DenyFollowRequest from the user with the given username.
+func (s *Service) DenyFollowRequest(ctx context.Context, username string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	followerID, err := deleteFollowRequest(ctx, s.DB, username, uid)
+	if err != nil {
+		return err
+	}
+
+	go s.notifyFollowRequest("follow_request_denied", followerID, uid)
+
+	return nil
+}
+
+// deleteFollowRequest from the user with the given username
+// to the given followee. It returns the follower ID.
+func deleteFollowRequest(ctx context.Context, db queryRower, followerUsername, followeeID string) (string, error) {
+	var followerID string
+	query := `
+		DELETE FROM follow_requests
+		WHERE follower_id = (SELECT id FROM users WHERE username = $1)
+			AND followee_id = $2
+		RETURNING follower_id`
+	err := db.QueryRowContext(ctx, query, followerUsername, followeeID).Scan(&followerID)
+	if err == sql.ErrNoRows {
+		return "", ErrFollowRequestNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("could not sql delete follow request: %w", err)
+	}
+
+	return followerID, nil
+}
+
+// approveFollowRequests sent to the given user all at once.
+// Used when making an account public again.
+func approveFollowRequests(ctx context.Context, tx *sql.Tx, userID string) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id)
+		SELECT follower_id, followee_id FROM follow_requests WHERE followee_id = $1
+		ON CONFLICT DO NOTHING
+		RETURNING follower_id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("could not sql insert follows from follow requests: %w", err)
+	}
+
+	defer rows.Close()
+
+	var followerIDs []string
+	for rows.Next() {
+		var followerID string
+		if err := rows.Scan(&followerID); err != nil {
+			return fmt.Errorf("could not sql scan approved follower id: %w", err)
+		}
+
+		followerIDs = append(followerIDs, followerID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not iterate approved follower rows: %w", err)
+	}
+
+	if len(followerIDs) != 0 {
+		query = "UPDATE users SET followees_count = followees_count + 1 WHERE id = ANY($1)"
+		if _, err := tx.ExecContext(ctx, query, pq.Array(followerIDs)); err != nil {
+			return fmt.Errorf("could not increment approved followers followees count: %w", err)
+		}
+
+		query = "UPDATE users SET followers_count = followers_count + $1 WHERE id = $2"
+		if _, err := tx.ExecContext(ctx, query, len(followerIDs), userID); err != nil {
+			return fmt.Errorf("could not increment followers count: %w", err)
+		}
+	}
+
+	query = "DELETE FROM follow_requests WHERE followee_id = $1"
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("could not sql delete approved follow requests: %w", err)
+	}
+
+	return nil
+}
+
+// visiblePost gives a condition to use inside buildQuery,
+// that filters out posts from private users,
+// unless @uid is them or one of their followers.
+// Posts hidden by moderators are filtered out too, unless @uid is the author.
+func visiblePost() string {
+	return `(posts.hidden_at IS NULL{{ if .auth }} OR posts.user_id = @uid{{ end }})
+	AND NOT EXISTS (
+		SELECT 1 FROM users AS authors
+		WHERE authors.id = posts.user_id AND authors.private
+		{{ if .auth }}
+			AND authors.id != @uid
+			AND NOT EXISTS (
+				SELECT 1 FROM follows
+				WHERE follows.follower_id = @uid AND follows.followee_id = authors.id
+			)
+		{{ end }}
+	)`
+}
+
+// postVisible tells whether the post author is either public, the given user
+// or followed by them. An empty userID stands for an anonymous user.
+func postVisible(ctx context.Context, db queryRower, userID, authorID string) (bool, error) {
+	if userID == authorID {
+		return true, nil
+	}
+
+	var ok bool
+	query := `
+		SELECT NOT private OR EXISTS (
+			SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = users.id
+		) FROM users WHERE id = $2`
+	err := db.QueryRowContext(ctx, query, sql.NullString{String: userID, Valid: userID != ""}, authorID).Scan(&ok)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select post visibility: %w", err)
+	}
+
+	return ok, nil
+}
+
+// postVisibleByID tells whether the post with the given ID exists
+// and the given user can see it, same as visiblePost does.
+// An empty userID stands for an anonymous user.
+func postVisibleByID(ctx context.Context, db queryRower, userID, postID string) (bool, error) {
+	var authorID string
+	var hidden bool
+	query := "SELECT user_id, hidden_at IS NOT NULL FROM posts WHERE id = $1"
+	err := db.QueryRowContext(ctx, query, postID).Scan(&authorID, &hidden)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select post author: %w", err)
+	}
+
+	if hidden && authorID != userID {
+		return false, nil
+	}
+
+	return postVisible(ctx, db, userID, authorID)
+}
@@ -0,0 +1,118 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_ApproveFollowRequest(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.ApproveFollowRequest(context.Background(), "someone")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.ApproveFollowRequest(ctx, "@nope")
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("private_account", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping follow requests integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		owner := createTestUser(t)
+		follower := createTestUser(t)
+		ownerCtx := context.WithValue(ctx, KeyAuthUserID, owner.ID)
+		followerCtx := context.WithValue(ctx, KeyAuthUserID, follower.ID)
+
+		private := true
+		err := svc.UpdateUser(ownerCtx, UpdateUserParams{Private: &private})
+		testutil.WantEq(t, nil, err, "update user error")
+
+		var postID string
+		err = testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content) VALUES ($1, 'private post') RETURNING id", owner.ID).Scan(&postID)
+		testutil.WantEq(t, nil, err, "insert post error")
+
+		pp, err := svc.Posts(followerCtx, 0, nil, PostsFromUser(owner.Username))
+		testutil.WantEq(t, nil, err, "posts error")
+		testutil.WantEq(t, 0, len(pp), "posts before approval")
+
+		_, err = svc.CreateComment(followerCtx, postID, "hi")
+		testutil.WantEq(t, ErrPostNotFound, err, "comment before approval error")
+
+		_, err = svc.TogglePostReaction(followerCtx, postID, ReactionInput{Type: "emoji", Reaction: "👍"})
+		testutil.WantEq(t, ErrPostNotFound, err, "react before approval error")
+
+		_, err = svc.TogglePostSubscription(followerCtx, postID)
+		testutil.WantEq(t, ErrPostNotFound, err, "subscribe before approval error")
+
+		_, err = svc.CommentStream(followerCtx, postID)
+		testutil.WantEq(t, ErrPostNotFound, err, "comment stream before approval error")
+
+		_, err = svc.CommentStream(ctx, postID)
+		testutil.WantEq(t, ErrPostNotFound, err, "anonymous comment stream error")
+
+		streamed := Post{ID: postID, UserID: owner.ID, Content: "private post"}
+		ok, err := svc.streamablePost(ctx, follower.ID, true, streamed)
+		testutil.WantEq(t, nil, err, "streamable before approval error")
+		testutil.WantEq(t, false, ok, "streamable before approval")
+
+		ok, err = svc.streamablePost(ctx, "", false, streamed)
+		testutil.WantEq(t, nil, err, "anonymous streamable error")
+		testutil.WantEq(t, false, ok, "anonymous streamable")
+
+		out, err := svc.ToggleFollow(followerCtx, owner.Username)
+		testutil.WantEq(t, nil, err, "toggle follow error")
+		testutil.WantEq(t, false, out.Following, "following")
+		testutil.WantEq(t, true, out.FollowRequested, "follow requested")
+
+		uu, err := svc.FollowRequests(ownerCtx, 0, nil)
+		testutil.WantEq(t, nil, err, "follow requests error")
+		testutil.WantEq(t, 1, len(uu), "follow requests")
+		testutil.WantEq(t, follower.Username, uu[0].Username, "follow request username")
+
+		err = svc.ApproveFollowRequest(ownerCtx, follower.Username)
+		testutil.WantEq(t, nil, err, "approve follow request error")
+
+		err = svc.ApproveFollowRequest(ownerCtx, follower.Username)
+		testutil.WantEq(t, ErrFollowRequestNotFound, err, "approve follow request again error")
+
+		pp, err = svc.Posts(followerCtx, 0, nil, PostsFromUser(owner.Username))
+		testutil.WantEq(t, nil, err, "posts error")
+		testutil.WantEq(t, 1, len(pp), "posts after approval")
+
+		sub, err := svc.TogglePostSubscription(followerCtx, postID)
+		testutil.WantEq(t, nil, err, "subscribe after approval error")
+		testutil.WantEq(t, true, sub.Subscribed, "subscribed after approval")
+
+		ok, err = svc.streamablePost(ctx, follower.ID, true, streamed)
+		testutil.WantEq(t, nil, err, "streamable after approval error")
+		testutil.WantEq(t, true, ok, "streamable after approval")
+
+		_, err = svc.CreateMute(followerCtx, CreateMute{Type: MuteTypeWord, Value: "private"})
+		testutil.WantEq(t, nil, err, "create mute error")
+
+		ok, err = svc.streamablePost(ctx, follower.ID, true, streamed)
+		testutil.WantEq(t, nil, err, "muted streamable error")
+		testutil.WantEq(t, false, ok, "muted streamable")
+
+		u, err := svc.User(ownerCtx, owner.Username)
+		testutil.WantEq(t, nil, err, "user error")
+		testutil.WantEq(t, 1, u.FollowersCount, "followers count")
+	})
+}
+
+func TestService_DenyFollowRequest(t *testing.T) {
+	svc := &Service{}
+	err := svc.DenyFollowRequest(context.Background(), "someone")
+	testutil.WantEq(t, ErrUnauthenticated, err, "error")
+}
@@ -0,0 +1,135 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+var (
+	// ErrIdentityNotFound denotes a not found linked identity.
+	ErrIdentityNotFound = NotFoundError("identity not found")
+	// ErrIdentityTaken denotes that the provider identity
+	// is already linked to another user.
+	ErrIdentityTaken = AlreadyExistsError("identity taken")
+	// ErrProviderAlreadyLinked denotes that the user already has an identity
+	// from the same provider linked.
+	ErrProviderAlreadyLinked = AlreadyExistsError("provider already linked")
+)
+
+// UserIdentity is an account from an OAuth provider linked to a user.
+type UserIdentity struct {
+	Provider  string    `json:"provider"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Identities linked to the authenticated user.
+func (s *Service) Identities(ctx context.Context) ([]UserIdentity, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := "SELECT provider, created_at FROM user_identities WHERE user_id = $1 ORDER BY provider"
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select user identities: %w", err)
+	}
+
+	defer rows.Close()
+
+	var ii []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(&i.Provider, &i.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not sql scan user identity: %w", err)
+		}
+
+		ii = append(ii, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate user identity rows: %w", err)
+	}
+
+	return ii, nil
+}
+
+// LinkIdentity from an OAuth provider to the authenticated user,
+// so it can be used to login.
+func (s *Service) LinkIdentity(ctx context.Context, provider string, providedUser ProvidedUser) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reProviderName.MatchString(provider) {
+		return ErrInvalidProvider
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var linkedTo string
+		query := "SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2"
+		err := tx.QueryRowContext(ctx, query, provider, providedUser.ID).Scan(&linkedTo)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("could not sql query select user identity: %w", err)
+		}
+
+		if err == nil {
+			if linkedTo == uid {
+				return nil
+			}
+
+			return ErrIdentityTaken
+		}
+
+		query = "INSERT INTO user_identities (provider, subject, user_id) VALUES ($1, $2, $3)"
+		_, err = tx.ExecContext(ctx, query, provider, providedUser.ID, uid)
+		if isUniqueViolation(err) {
+			return ErrProviderAlreadyLinked
+		}
+
+		if isForeignKeyViolation(err) {
+			return ErrUserGone
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql insert user identity: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnlinkIdentity from the given provider.
+// The user can still login with a magic link to its email.
+func (s *Service) UnlinkIdentity(ctx context.Context, provider string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reProviderName.MatchString(provider) {
+		return ErrInvalidProvider
+	}
+
+	query := "DELETE FROM user_identities WHERE user_id = $1 AND provider = $2"
+	res, err := s.DB.ExecContext(ctx, query, uid, provider)
+	if err != nil {
+		return fmt.Errorf("could not sql delete user identity: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted user identity rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrIdentityNotFound
+	}
+
+	return nil
+}
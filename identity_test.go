@@ -0,0 +1,79 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_LinkIdentity(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.LinkIdentity(context.Background(), "github", ProvidedUser{ID: "1"})
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.LinkIdentity(ctx, "Nope!", ProvidedUser{ID: "1"})
+		testutil.WantEq(t, ErrInvalidProvider, err, "error")
+	})
+
+	t.Run("link_and_unlink", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping identities integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		user := createTestUser(t)
+		other := createTestUser(t)
+		userCtx := context.WithValue(ctx, KeyAuthUserID, user.ID)
+		otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+
+		subject := testutil.RandStr(t, 8)
+		err := svc.LinkIdentity(userCtx, "github", ProvidedUser{ID: subject})
+		testutil.WantEq(t, nil, err, "link error")
+
+		err = svc.LinkIdentity(userCtx, "github", ProvidedUser{ID: subject})
+		testutil.WantEq(t, nil, err, "link again error")
+
+		err = svc.LinkIdentity(otherCtx, "github", ProvidedUser{ID: subject})
+		testutil.WantEq(t, ErrIdentityTaken, err, "link taken identity error")
+
+		err = svc.LinkIdentity(userCtx, "github", ProvidedUser{ID: "other_" + subject})
+		testutil.WantEq(t, ErrProviderAlreadyLinked, err, "link provider twice error")
+
+		ii, err := svc.Identities(userCtx)
+		testutil.WantEq(t, nil, err, "identities error")
+		testutil.WantEq(t, 1, len(ii), "identities length")
+		testutil.WantEq(t, "github", ii[0].Provider, "identity provider")
+
+		err = svc.UnlinkIdentity(userCtx, "github")
+		testutil.WantEq(t, nil, err, "unlink error")
+
+		err = svc.UnlinkIdentity(userCtx, "github")
+		testutil.WantEq(t, ErrIdentityNotFound, err, "unlink again error")
+
+		err = svc.LinkIdentity(otherCtx, "github", ProvidedUser{ID: subject})
+		testutil.WantEq(t, nil, err, "link unlinked identity error")
+	})
+}
+
+func TestService_UnlinkIdentity(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.UnlinkIdentity(context.Background(), "github")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.UnlinkIdentity(ctx, "Nope!")
+		testutil.WantEq(t, ErrInvalidProvider, err, "error")
+	})
+}
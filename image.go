@@ -0,0 +1,677 @@
+package nakama
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/webp"
+
+	"github.com/nakamauwu/nakama/storage"
+)
+
+const (
+	// maxAnimationFrames is how many frames an animation can have.
+	maxAnimationFrames = 500
+	// maxAnimationPixels is how many pixels all the frames of an animation can add up to.
+	maxAnimationPixels = 64 << 20
+	// minAnimationSide is how small animations can get
+	// while downscaling them to fit within the byte limit.
+	minAnimationSide = 64
+	// animationDownscale is how much animations get downscaled
+	// each time they don't fit within the byte limit.
+	animationDownscale = 0.75
+)
+
+// ErrAnimationTooLarge denotes an animation with too many frames or pixels,
+// or one that does not fit within the byte limit even after downscaling it.
+var ErrAnimationTooLarge = InvalidArgumentError("animation too large")
+
+var (
+	// errNotAnimated denotes a GIF or WebP with a single frame.
+	errNotAnimated = errors.New("not animated")
+	// errUnsupportedImage denotes a file that could not be decoded.
+	errUnsupportedImage = errors.New("unsupported image")
+)
+
+var (
+	fourCCRIFF = [4]byte{'R', 'I', 'F', 'F'}
+	fourCCWEBP = [4]byte{'W', 'E', 'B', 'P'}
+	fourCCVP8X = [4]byte{'V', 'P', '8', 'X'}
+	fourCCVP8  = [4]byte{'V', 'P', '8', ' '}
+	fourCCVP8L = [4]byte{'V', 'P', '8', 'L'}
+	fourCCALPH = [4]byte{'A', 'L', 'P', 'H'}
+	fourCCANIM = [4]byte{'A', 'N', 'I', 'M'}
+	fourCCANMF = [4]byte{'A', 'N', 'M', 'F'}
+)
+
+const (
+	webpAnimationFlag = 1 << 1
+	webpAlphaFlag     = 1 << 4
+	// webpMetadataFlags are the XMP, EXIF and ICC profile flags.
+	webpMetadataFlags = 1<<2 | 1<<3 | 1<<5
+)
+
+// transparentPlan9 is the palette for frames that come without one.
+var transparentPlan9 = append(color.Palette{color.Transparent}, palette.Plan9[:255]...)
+
+// encodedImage is an uploaded image ready to be stored.
+type encodedImage struct {
+	ext         string
+	contentType string
+	content     []byte
+	// poster is a static PNG of the first frame.
+	// Only set for animations.
+	poster []byte
+}
+
+// supportedImageType tells whether the given content type
+// can be processed by encodeImage.
+func supportedImageType(ct string) bool {
+	switch ct {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// encodeImage decodes the given PNG, JPEG, GIF or WebP file
+// and encodes it again after applying the given transform.
+// Static images come out as PNG or JPEG.
+// Animations keep their frames: they are kept as they are (minus metadata)
+// when the transform leaves their size untouched and they fit within maxBytes.
+// Otherwise the transform is applied frame by frame and they come out as GIF,
+// downscaled further until they fit.
+// Either way, animations come along a static poster of their first frame.
+func encodeImage(b []byte, ct string, maxBytes int, transform func(image.Image) image.Image, errUnsupported error) (encodedImage, error) {
+	var out encodedImage
+	if !supportedImageType(ct) {
+		return out, errUnsupported
+	}
+
+	if ct == "image/gif" || ct == "image/webp" {
+		a, err := decodeAnimation(b, ct, transform)
+		if err == nil {
+			return a.encode(b, maxBytes)
+		}
+
+		if err == errUnsupportedImage {
+			return out, errUnsupported
+		}
+
+		if err != errNotAnimated {
+			return out, err
+		}
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(b), imaging.AutoOrientation(true))
+	if err == image.ErrFormat || (err != nil && ct != "image/png" && ct != "image/jpeg") {
+		return out, errUnsupported
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	img = transform(img)
+
+	buf := &bytes.Buffer{}
+	if ct == "image/jpeg" {
+		out.ext = ".jpg"
+		out.contentType = "image/jpeg"
+		err = jpeg.Encode(buf, img, nil)
+	} else {
+		// no encoder for WebP, and single frame GIFs do better as PNG.
+		out.ext = ".png"
+		out.contentType = "image/png"
+		err = png.Encode(buf, img)
+	}
+	if err != nil {
+		return out, fmt.Errorf("could not encode image: %w", err)
+	}
+
+	out.content = buf.Bytes()
+	return out, nil
+}
+
+// storeImage along with its poster, if any.
+func (s *Service) storeImage(ctx context.Context, bucket, name string, img encodedImage) error {
+	if img.poster != nil {
+		err := s.Store.Store(ctx, bucket, posterFileName(name), img.poster, storage.StoreWithContentType("image/png"))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.Store.Store(ctx, bucket, name, img.content, storage.StoreWithContentType(img.contentType))
+	if err != nil && img.poster != nil {
+		go s.deleteFile(bucket, name)
+	}
+
+	return err
+}
+
+// posterFileName is where the poster of the given animated file is stored.
+// Clients can use it for people that prefer reduced motion.
+func posterFileName(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + ".poster.png"
+}
+
+// withPosters adds the poster of each animated file to the given file names.
+// Only animations are stored as GIF or WebP.
+func withPosters(names ...string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		out = append(out, name)
+		if ext := path.Ext(name); ext == ".gif" || ext == ".webp" {
+			out = append(out, posterFileName(name))
+		}
+	}
+	return out
+}
+
+// animationFrame is a fully composited frame of an animation
+// with the transform already applied.
+type animationFrame struct {
+	img image.Image
+	// delay in 100ths of a second.
+	delay int
+	// palette of the original frame. Nil for WebP.
+	palette color.Palette
+}
+
+type animation struct {
+	ct            string
+	width, height int
+	loopCount     int
+	// resized tells whether the transform changed the size of the frames.
+	resized bool
+	frames  []animationFrame
+	// gif as decoded, to encode it again without decoding it twice.
+	gif *gif.GIF
+}
+
+// decodeAnimation composites every frame of the given GIF or WebP
+// applying the transform to each of them.
+// It returns errNotAnimated for single frame files.
+func decodeAnimation(b []byte, ct string, transform func(image.Image) image.Image) (animation, error) {
+	a := animation{ct: ct}
+
+	emit := func(canvas *image.NRGBA, delay int, pal color.Palette) {
+		img := transform(imaging.Clone(canvas))
+		if img.Bounds().Dx() != a.width || img.Bounds().Dy() != a.height {
+			a.resized = true
+		}
+
+		a.frames = append(a.frames, animationFrame{img: img, delay: delay, palette: pal})
+	}
+
+	var err error
+	if ct == "image/gif" {
+		err = a.decodeGIF(b, emit)
+	} else {
+		err = a.decodeWebP(b, emit)
+	}
+	return a, err
+}
+
+func checkAnimationSize(width, height, frames int) error {
+	if width <= 0 || height <= 0 || frames > maxAnimationFrames || width*height*frames > maxAnimationPixels {
+		return ErrAnimationTooLarge
+	}
+	return nil
+}
+
+// decodeGIF checks the declared size and the frame count
+// before decoding any frame, so the animation limits bound the memory used.
+func (a *animation) decodeGIF(b []byte, emit func(*image.NRGBA, int, color.Palette)) error {
+	cfg, err := gif.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return errUnsupportedImage
+	}
+
+	n, err := gifFrameCount(b)
+	if err != nil {
+		return err
+	}
+
+	if n < 2 {
+		return errNotAnimated
+	}
+
+	if err := checkAnimationSize(cfg.Width, cfg.Height, n); err != nil {
+		return err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(b))
+	if err != nil {
+		return errUnsupportedImage
+	}
+
+	if len(g.Image) < 2 {
+		return errNotAnimated
+	}
+
+	a.gif = g
+	a.width, a.height, a.loopCount = g.Config.Width, g.Config.Height, g.LoopCount
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, a.width, a.height))
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.NRGBA
+		if disposal == gif.DisposalPrevious {
+			previous = imaging.Clone(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		emit(canvas, g.Delay[i], frame.Palette)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return nil
+}
+
+// gifFrameCount walks the blocks of the given GIF without decoding them.
+// It stops counting past maxAnimationFrames.
+func gifFrameCount(b []byte) (int, error) {
+	// header and logical screen descriptor.
+	if len(b) < 13 {
+		return 0, errUnsupportedImage
+	}
+
+	i := 13
+	if flags := b[10]; flags&0x80 != 0 {
+		i += 3 << ((flags & 0x07) + 1)
+	}
+
+	skipSubBlocks := func() error {
+		for {
+			if i >= len(b) {
+				return errUnsupportedImage
+			}
+
+			size := int(b[i])
+			i += 1 + size
+			if size == 0 {
+				return nil
+			}
+		}
+	}
+
+	var n int
+	for i < len(b) && n <= maxAnimationFrames {
+		switch b[i] {
+		case 0x21: // extension: introducer, label and sub-blocks.
+			i += 2
+			if err := skipSubBlocks(); err != nil {
+				return n, err
+			}
+		case 0x2C: // image: descriptor, color table, LZW code size and sub-blocks.
+			if i+10 > len(b) {
+				return n, errUnsupportedImage
+			}
+
+			flags := b[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << ((flags & 0x07) + 1)
+			}
+			i++
+			if err := skipSubBlocks(); err != nil {
+				return n, err
+			}
+
+			n++
+		case 0x3B: // trailer.
+			return n, nil
+		default:
+			return n, errUnsupportedImage
+		}
+	}
+
+	return n, nil
+}
+
+// webpChunk is a RIFF chunk within a WebP file.
+type webpChunk struct {
+	id   [4]byte
+	data []byte
+}
+
+// readWebPChunks splits the given data into RIFF chunks.
+func readWebPChunks(b []byte) ([]webpChunk, error) {
+	var cc []webpChunk
+	for len(b) != 0 {
+		if len(b) < 8 {
+			return nil, errUnsupportedImage
+		}
+
+		var c webpChunk
+		copy(c.id[:], b[:4])
+		size := binary.LittleEndian.Uint32(b[4:8])
+		b = b[8:]
+		if uint64(size) > uint64(len(b)) {
+			return nil, errUnsupportedImage
+		}
+
+		c.data = b[:size]
+		b = b[size:]
+		// chunks are padded to an even size.
+		if size%2 == 1 && len(b) != 0 {
+			b = b[1:]
+		}
+
+		cc = append(cc, c)
+	}
+	return cc, nil
+}
+
+// writeWebP puts the given chunks together into a WebP file.
+func writeWebP(cc ...webpChunk) []byte {
+	buf := &bytes.Buffer{}
+	buf.Write(fourCCWEBP[:])
+	for _, c := range cc {
+		buf.Write(c.id[:])
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(c.data)))
+		buf.Write(c.data)
+		if len(c.data)%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	out.Write(fourCCRIFF[:])
+	_ = binary.Write(out, binary.LittleEndian, uint32(buf.Len()))
+	out.Write(buf.Bytes())
+	return out.Bytes()
+}
+
+// webpChunks of the given WebP file.
+func webpChunks(b []byte) ([]webpChunk, error) {
+	if len(b) < 12 || !bytes.Equal(b[:4], fourCCRIFF[:]) || !bytes.Equal(b[8:12], fourCCWEBP[:]) {
+		return nil, errUnsupportedImage
+	}
+
+	size := binary.LittleEndian.Uint32(b[4:8])
+	if size < 4 || uint64(size) > uint64(len(b)-8) {
+		return nil, errUnsupportedImage
+	}
+
+	return readWebPChunks(b[12 : 8+size])
+}
+
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+func (a *animation) decodeWebP(b []byte, emit func(*image.NRGBA, int, color.Palette)) error {
+	cc, err := webpChunks(b)
+	if err != nil {
+		return errUnsupportedImage
+	}
+
+	if len(cc) == 0 || cc[0].id != fourCCVP8X || len(cc[0].data) != 10 || cc[0].data[0]&webpAnimationFlag == 0 {
+		return errNotAnimated
+	}
+
+	a.width = uint24(cc[0].data[4:7]) + 1
+	a.height = uint24(cc[0].data[7:10]) + 1
+
+	var frames []webpChunk
+	for _, c := range cc {
+		switch c.id {
+		case fourCCANIM:
+			if len(c.data) != 6 {
+				return errUnsupportedImage
+			}
+
+			a.loopCount = int(binary.LittleEndian.Uint16(c.data[4:6]))
+			// GIF loops count repeats instead of plays, and -1 means play once.
+			if a.loopCount == 1 {
+				a.loopCount = -1
+			} else if a.loopCount > 1 {
+				a.loopCount--
+			}
+		case fourCCANMF:
+			frames = append(frames, c)
+		}
+	}
+
+	if len(frames) == 0 {
+		return errUnsupportedImage
+	}
+
+	if err := checkAnimationSize(a.width, a.height, len(frames)); err != nil {
+		return err
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, a.width, a.height))
+	for _, f := range frames {
+		if len(f.data) < 16 {
+			return errUnsupportedImage
+		}
+
+		x, y := uint24(f.data[0:3])*2, uint24(f.data[3:6])*2
+		w, h := uint24(f.data[6:9])+1, uint24(f.data[9:12])+1
+		duration := uint24(f.data[12:15])
+		noBlend := f.data[15]&(1<<1) != 0
+		dispose := f.data[15]&1 != 0
+
+		img, err := decodeWebPFrame(f.data[16:], w, h)
+		if err != nil {
+			return errUnsupportedImage
+		}
+
+		r := image.Rect(x, y, x+w, y+h)
+		if !r.In(canvas.Bounds()) {
+			return errUnsupportedImage
+		}
+
+		op := draw.Over
+		if noBlend {
+			op = draw.Src
+		}
+
+		draw.Draw(canvas, r, img, img.Bounds().Min, op)
+		emit(canvas, duration/10, nil)
+
+		if dispose {
+			draw.Draw(canvas, r, image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+
+	return nil
+}
+
+// decodeWebPFrame decodes the bitstream of an animation frame
+// by wrapping it as a standalone still WebP.
+func decodeWebPFrame(b []byte, w, h int) (image.Image, error) {
+	cc, err := readWebPChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var alph, bitstream *webpChunk
+	for i := range cc {
+		switch cc[i].id {
+		case fourCCALPH:
+			alph = &cc[i]
+		case fourCCVP8, fourCCVP8L:
+			bitstream = &cc[i]
+		}
+	}
+
+	if bitstream == nil {
+		return nil, errUnsupportedImage
+	}
+
+	still := []webpChunk{*bitstream}
+	if alph != nil && bitstream.id == fourCCVP8 {
+		header := make([]byte, 10)
+		header[0] = webpAlphaFlag
+		header[4], header[5], header[6] = byte(w-1), byte((w-1)>>8), byte((w-1)>>16)
+		header[7], header[8], header[9] = byte(h-1), byte((h-1)>>8), byte((h-1)>>16)
+		still = []webpChunk{{id: fourCCVP8X, data: header}, *alph, *bitstream}
+	}
+
+	return webp.Decode(bytes.NewReader(writeWebP(still...)))
+}
+
+// stripWebPMetadata leaves only the chunks needed to play the given animated WebP.
+func stripWebPMetadata(b []byte) ([]byte, error) {
+	cc, err := webpChunks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []webpChunk
+	for _, c := range cc {
+		switch c.id {
+		case fourCCVP8X:
+			header := append([]byte(nil), c.data...)
+			header[0] &^= webpMetadataFlags
+			kept = append(kept, webpChunk{id: c.id, data: header})
+		case fourCCANIM, fourCCANMF:
+			kept = append(kept, c)
+		}
+	}
+	return writeWebP(kept...), nil
+}
+
+func (a animation) encode(original []byte, maxBytes int) (encodedImage, error) {
+	var out encodedImage
+
+	poster := &bytes.Buffer{}
+	if err := png.Encode(poster, a.frames[0].img); err != nil {
+		return out, fmt.Errorf("could not encode animation poster: %w", err)
+	}
+
+	out.poster = poster.Bytes()
+
+	if !a.resized && len(original) <= maxBytes {
+		var err error
+		if a.ct == "image/gif" {
+			out.ext = ".gif"
+			out.contentType = "image/gif"
+			// encoding it again drops comments and other extensions.
+			buf := &bytes.Buffer{}
+			err = gif.EncodeAll(buf, a.gif)
+			out.content = buf.Bytes()
+		} else {
+			out.ext = ".webp"
+			out.contentType = "image/webp"
+			out.content, err = stripWebPMetadata(original)
+		}
+		if err != nil {
+			return out, fmt.Errorf("could not encode animation: %w", err)
+		}
+
+		return out, nil
+	}
+
+	out.ext = ".gif"
+	out.contentType = "image/gif"
+	frames := a.frames
+	for {
+		b, err := encodeGIF(frames, a.loopCount)
+		if err != nil {
+			return out, err
+		}
+
+		if len(b) <= maxBytes {
+			out.content = b
+			break
+		}
+
+		size := frames[0].img.Bounds().Size()
+		w, h := int(float64(size.X)*animationDownscale), int(float64(size.Y)*animationDownscale)
+		if w < minAnimationSide || h < minAnimationSide {
+			return out, ErrAnimationTooLarge
+		}
+
+		downscaled := make([]animationFrame, len(frames))
+		for i, f := range frames {
+			downscaled[i] = f
+			downscaled[i].img = imaging.Resize(f.img, w, h, imaging.CatmullRom)
+		}
+		frames = downscaled
+	}
+
+	// match the poster with what ended up stored.
+	if frames[0].img.Bounds().Size() != a.frames[0].img.Bounds().Size() {
+		poster.Reset()
+		if err := png.Encode(poster, frames[0].img); err != nil {
+			return out, fmt.Errorf("could not encode animation poster: %w", err)
+		}
+
+		out.poster = poster.Bytes()
+	}
+
+	return out, nil
+}
+
+// encodeGIF quantizes each frame to the palette of the original frame
+// or to a web palette when there is none.
+func encodeGIF(frames []animationFrame, loopCount int) ([]byte, error) {
+	g := &gif.GIF{LoopCount: loopCount}
+	for _, f := range frames {
+		pal := transparentPlan9
+		if f.palette != nil {
+			pal = withTransparent(f.palette)
+		}
+
+		bounds := f.img.Bounds()
+		paletted := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), pal)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), f.img, bounds.Min)
+
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, f.delay)
+		// frames are fully composited, so each one replaces the previous.
+		g.Disposal = append(g.Disposal, gif.DisposalBackground)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := gif.EncodeAll(buf, g); err != nil {
+		return nil, fmt.Errorf("could not encode gif: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// withTransparent makes sure the palette has a fully transparent color
+// when there is room for it.
+func withTransparent(p color.Palette) color.Palette {
+	for _, c := range p {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return p
+		}
+	}
+
+	if len(p) >= 256 {
+		return p
+	}
+
+	return append(append(color.Palette(nil), p...), color.Transparent)
+}
@@ -0,0 +1,213 @@
+package nakama
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+// testLosslessWebP is a 1x1 lossless WebP.
+const testLosslessWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func testAnimatedGIF(t *testing.T, width, height, frames int, noise bool) []byte {
+	t.Helper()
+
+	rnd := rand.New(rand.NewSource(1))
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		img := image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9)
+		for j := range img.Pix {
+			if noise {
+				img.Pix[j] = uint8(rnd.Intn(256))
+			} else {
+				img.Pix[j] = uint8(i)
+			}
+		}
+
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := gif.EncodeAll(buf, g); err != nil {
+		t.Fatalf("failed to encode gif: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func testAnimatedWebP(t *testing.T, frames int, metadata bool) []byte {
+	t.Helper()
+
+	still, err := base64.StdEncoding.DecodeString(testLosslessWebP)
+	if err != nil {
+		t.Fatalf("failed to decode webp: %v", err)
+	}
+
+	cc, err := webpChunks(still)
+	if err != nil {
+		t.Fatalf("failed to read webp chunks: %v", err)
+	}
+
+	header := make([]byte, 10)
+	header[0] = webpAnimationFlag
+	if metadata {
+		header[0] |= 1 << 3
+	}
+
+	out := []webpChunk{
+		{id: fourCCVP8X, data: header},
+		{id: fourCCANIM, data: []byte{0, 0, 0, 0, 0, 0}},
+	}
+	for i := 0; i < frames; i++ {
+		frame := make([]byte, 16)
+		frame[12] = 100 // duration in ms.
+		frame = append(frame, writeWebP(cc...)[12:]...)
+		out = append(out, webpChunk{id: fourCCANMF, data: frame})
+	}
+
+	if metadata {
+		out = append(out, webpChunk{id: [4]byte{'E', 'X', 'I', 'F'}, data: []byte("gps")})
+	}
+
+	return writeWebP(out...)
+}
+
+func Test_encodeImage(t *testing.T) {
+	avatar := func(img image.Image) image.Image {
+		return imaging.Fill(img, 400, 400, imaging.Center, imaging.CatmullRom)
+	}
+	same := func(img image.Image) image.Image {
+		return img
+	}
+
+	t.Run("animated_gif", func(t *testing.T) {
+		img, err := encodeImage(testAnimatedGIF(t, 10, 10, 3, false), "image/gif", MaxAvatarBytes, avatar, ErrUnsupportedAvatarFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".gif", img.ext, "ext")
+
+		g, err := gif.DecodeAll(bytes.NewReader(img.content))
+		testutil.WantEq(t, nil, err, "gif decode error")
+		testutil.WantEq(t, 3, len(g.Image), "frames")
+		testutil.WantEq(t, image.Rect(0, 0, 400, 400), g.Image[0].Bounds(), "bounds")
+
+		poster, err := png.Decode(bytes.NewReader(img.poster))
+		testutil.WantEq(t, nil, err, "poster decode error")
+		testutil.WantEq(t, image.Rect(0, 0, 400, 400), poster.Bounds(), "poster bounds")
+	})
+
+	t.Run("animated_gif_within_limits", func(t *testing.T) {
+		img, err := encodeImage(testAnimatedGIF(t, 10, 10, 2, false), "image/gif", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".gif", img.ext, "ext")
+		testutil.WantEq(t, true, img.poster != nil, "poster")
+	})
+
+	t.Run("animated_gif_downscaled", func(t *testing.T) {
+		const maxBytes = 64 << 10
+		img, err := encodeImage(testAnimatedGIF(t, 256, 256, 4, true), "image/gif", maxBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, true, len(img.content) <= maxBytes, "within byte limit")
+
+		g, err := gif.DecodeAll(bytes.NewReader(img.content))
+		testutil.WantEq(t, nil, err, "gif decode error")
+		testutil.WantEq(t, 4, len(g.Image), "frames")
+		testutil.WantEq(t, true, g.Image[0].Bounds().Dx() < 256, "downscaled")
+
+		poster, err := png.Decode(bytes.NewReader(img.poster))
+		testutil.WantEq(t, nil, err, "poster decode error")
+		testutil.WantEq(t, g.Image[0].Bounds(), poster.Bounds(), "poster bounds")
+	})
+
+	t.Run("animation_too_large", func(t *testing.T) {
+		_, err := encodeImage(testAnimatedGIF(t, 256, 256, 4, true), "image/gif", 1<<10, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, ErrAnimationTooLarge, err, "error")
+	})
+
+	t.Run("animation_oversized_canvas", func(t *testing.T) {
+		b := testAnimatedGIF(t, 1, 1, 2, false)
+		// declare a 65535x65535 logical screen while keeping the frames 1x1.
+		b[6], b[7], b[8], b[9] = 0xFF, 0xFF, 0xFF, 0xFF
+		_, err := encodeImage(b, "image/gif", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, ErrAnimationTooLarge, err, "error")
+	})
+
+	t.Run("animation_too_many_frames", func(t *testing.T) {
+		_, err := encodeImage(testAnimatedGIF(t, 1, 1, maxAnimationFrames+1, false), "image/gif", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, ErrAnimationTooLarge, err, "error")
+	})
+
+	t.Run("static_gif", func(t *testing.T) {
+		img, err := encodeImage(testAnimatedGIF(t, 10, 10, 1, false), "image/gif", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".png", img.ext, "ext")
+		testutil.WantEq(t, true, img.poster == nil, "no poster")
+	})
+
+	t.Run("animated_webp_within_limits", func(t *testing.T) {
+		img, err := encodeImage(testAnimatedWebP(t, 2, true), "image/webp", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".webp", img.ext, "ext")
+		testutil.WantEq(t, true, img.poster != nil, "poster")
+
+		cc, err := webpChunks(img.content)
+		testutil.WantEq(t, nil, err, "webp chunks error")
+		testutil.WantEq(t, 4, len(cc), "chunks")
+		testutil.WantEq(t, byte(webpAnimationFlag), cc[0].data[0], "flags")
+	})
+
+	t.Run("animated_webp_resized", func(t *testing.T) {
+		img, err := encodeImage(testAnimatedWebP(t, 2, false), "image/webp", MaxAvatarBytes, avatar, ErrUnsupportedAvatarFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".gif", img.ext, "ext")
+
+		g, err := gif.DecodeAll(bytes.NewReader(img.content))
+		testutil.WantEq(t, nil, err, "gif decode error")
+		testutil.WantEq(t, 2, len(g.Image), "frames")
+		testutil.WantEq(t, 10, g.Delay[0], "delay")
+	})
+
+	t.Run("static_webp", func(t *testing.T) {
+		b, err := base64.StdEncoding.DecodeString(testLosslessWebP)
+		testutil.WantEq(t, nil, err, "base64 decode error")
+
+		img, err := encodeImage(b, "image/webp", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, ".png", img.ext, "ext")
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		_, err := encodeImage([]byte("GIF89a nope"), "image/gif", MaxMediaItemBytes, same, ErrUnsupportedMediaItemFormat)
+		testutil.WantEq(t, ErrUnsupportedMediaItemFormat, err, "error")
+	})
+}
+
+func Test_withPosters(t *testing.T) {
+	got := withPosters("a.png", "b.gif", "c.webp")
+	testutil.WantEq(t, []string{"a.png", "b.gif", "b.poster.png", "c.webp", "c.poster.png"}, got, "names")
+}
+
+func Test_withTransparent(t *testing.T) {
+	got := withTransparent(color.Palette{color.Black})
+	testutil.WantEq(t, 2, len(got), "palette length")
+}
+
+func Test_gifFrameCount(t *testing.T) {
+	n, err := gifFrameCount(testAnimatedGIF(t, 4, 4, 3, true))
+	testutil.WantEq(t, nil, err, "error")
+	testutil.WantEq(t, 3, n, "frames")
+
+	b := testAnimatedGIF(t, 4, 4, 2, true)
+	_, err = gifFrameCount(b[:len(b)-8])
+	testutil.WantEq(t, errUnsupportedImage, err, "truncated error")
+}
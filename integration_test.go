@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/ory/dockertest/v3"
+
+	"github.com/nakamauwu/nakama/testutil"
 )
 
 var testDB *sql.DB
@@ -87,3 +89,14 @@ func setupTestDB(pool *dockertest.Pool) (*sql.DB, func() error, error) {
 		return pool.Purge(resource)
 	}, nil
 }
+
+// createTestUser with a random username.
+func createTestUser(t *testing.T) User {
+	t.Helper()
+
+	u := User{Username: "test_" + testutil.RandStr(t, 8)}
+	query := "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id"
+	err := testDB.QueryRow(query, u.Username+"@example.org", u.Username).Scan(&u.ID)
+	testutil.WantEq(t, nil, err, "insert user error")
+	return u
+}
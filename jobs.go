@@ -0,0 +1,378 @@
+package nakama
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/nakamauwu/nakama/storage"
+)
+
+const (
+	// readNotificationsRetention is how long read notifications are kept around.
+	readNotificationsRetention = time.Hour * 24 * 30
+	// orphanedFilesBatch is how many orphaned files are deleted per job run.
+	orphanedFilesBatch = 100
+)
+
+var (
+	jobDur = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "background_job_duration_ms",
+	}, []string{"job"})
+	jobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "background_job_runs_total",
+	}, []string{"job", "status"})
+	jobAffected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "background_job_affected_total",
+	}, []string{"job"})
+	jobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "background_job_last_success_timestamp_seconds",
+	}, []string{"job"})
+)
+
+// execer is either a *sql.DB or a *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// backgroundJob is a maintenance task run periodically.
+// run returns how many items it took care of.
+type backgroundJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) (int64, error)
+}
+
+// RunBackgroundJobs runs periodic maintenance tasks:
+// purging expired codes and sessions, deleting orphaned files from the store,
+// pruning old read notifications, building data exports, purging accounts
+// whose deletion grace period is over, reconciling denormalized counters
+// and refreshing cached user suggestions.
+// Every replica can call it: each run first takes a lease on the job
+// so only one instance runs it per interval.
+// It blocks until the given context is canceled
+// and all running jobs have stopped.
+func (s *Service) RunBackgroundJobs(ctx context.Context) {
+	holder, err := genJobLeaseHolder()
+	if err != nil {
+		_ = s.Logger.Log("error", err)
+		return
+	}
+
+	jobs := []backgroundJob{
+		{name: "purge_expired", interval: time.Minute * 15, run: s.purgeExpired},
+		{name: "delete_orphaned_files", interval: time.Minute * 5, run: s.deleteOrphanedFiles},
+		{name: "prune_read_notifications", interval: time.Hour, run: s.pruneReadNotifications},
+		{name: "build_data_exports", interval: time.Minute, run: s.buildDataExports},
+		{name: "purge_deleted_accounts", interval: time.Hour, run: s.purgeDeletedAccounts},
+		{name: "reconcile_counters", interval: time.Hour * 6, run: s.reconcileCounters},
+		{name: "refresh_user_suggestions", interval: time.Minute * 10, run: s.refreshStaleUserSuggestions},
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runBackgroundJob(ctx, holder, job)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (s *Service) runBackgroundJob(ctx context.Context, holder string, job backgroundJob) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ok, err := s.acquireJobLease(ctx, job.name, holder, job.interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			jobRuns.WithLabelValues(job.name, "error").Inc()
+			_ = s.Logger.Log("error", err)
+			continue
+		}
+
+		if !ok {
+			// another instance is running it.
+			continue
+		}
+
+		start := time.Now()
+		n, err := job.run(ctx)
+		if err != nil && ctx.Err() != nil {
+			// stopped halfway because of shutdown.
+			return
+		}
+
+		jobDur.WithLabelValues(job.name).Observe(float64(time.Since(start)) / float64(time.Millisecond))
+		jobAffected.WithLabelValues(job.name).Add(float64(n))
+
+		if err != nil {
+			jobRuns.WithLabelValues(job.name, "error").Inc()
+			_ = s.Logger.Log("error", fmt.Errorf("could not run %s background job: %w", job.name, err))
+			continue
+		}
+
+		jobRuns.WithLabelValues(job.name, "ok").Inc()
+		jobLastSuccess.WithLabelValues(job.name).SetToCurrentTime()
+	}
+}
+
+// acquireJobLease takes the lease on the given job for the given duration.
+// It succeeds when nobody holds it, when the previous lease expired,
+// or when the holder already has it.
+func (s *Service) acquireJobLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO background_job_leases (name, holder, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE background_job_leases.expires_at <= now() OR background_job_leases.holder = excluded.holder`
+	res, err := s.DB.ExecContext(ctx, query, name, holder, time.Now().Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("could not sql upsert %s background job lease: %w", name, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not get %s background job lease rows affected: %w", name, err)
+	}
+
+	return n == 1, nil
+}
+
+func genJobLeaseHolder() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate background job lease holder: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// purgeExpired deletes verification codes, challenges, lockouts,
+// rate limit hits, sessions, OAuth codes and tokens, and mutes that can no longer be used.
+func (s *Service) purgeExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	purges := []struct {
+		what  string
+		query string
+		arg   time.Time
+	}{
+		{"email verification codes", "DELETE FROM email_verification_codes WHERE created_at < $1", now.Add(-emailVerificationCodeTTL)},
+		{"account deletion codes", "DELETE FROM account_deletion_codes WHERE created_at < $1", now.Add(-accountDeletionCodeTTL)},
+		{"login code attempts", "DELETE FROM login_code_attempts WHERE locked_until < $1", now},
+		{"rate limit hits", "DELETE FROM rate_limit_hits WHERE expires_at < $1", now},
+		{"passkey ceremonies", "DELETE FROM passkey_ceremonies WHERE created_at < $1", now.Add(-passkeyCeremonyTTL)},
+		{"second factor challenges", "DELETE FROM second_factor_challenges WHERE created_at < $1", now.Add(-secondFactorTTL)},
+		{"sessions", "DELETE FROM sessions WHERE expires_at < $1", now},
+		{"oauth authorization codes", "DELETE FROM oauth_authorization_codes WHERE expires_at < $1", now},
+		{"oauth access tokens", "DELETE FROM oauth_access_tokens WHERE expires_at < $1", now},
+		{"mutes", "DELETE FROM mutes WHERE expires_at < $1", now},
+	}
+
+	var total int64
+	for _, p := range purges {
+		res, err := s.DB.ExecContext(ctx, p.query, p.arg)
+		if err != nil {
+			return total, fmt.Errorf("could not sql delete expired %s: %w", p.what, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("could not get deleted expired %s rows affected: %w", p.what, err)
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// deleteOrphanedFiles from the store.
+// Files get queued as orphaned when deleting them right away failed,
+// or when the post they belonged to was deleted.
+// Only queued files are deleted: the store cannot be listed,
+// so files left unreferenced without being queued are not swept.
+func (s *Service) deleteOrphanedFiles(ctx context.Context) (int64, error) {
+	query := "SELECT bucket, name FROM orphaned_files ORDER BY created_at LIMIT $1"
+	rows, err := s.DB.QueryContext(ctx, query, orphanedFilesBatch)
+	if err != nil {
+		return 0, fmt.Errorf("could not sql query select orphaned files: %w", err)
+	}
+
+	defer rows.Close()
+
+	type file struct{ bucket, name string }
+	var ff []file
+	for rows.Next() {
+		var f file
+		if err := rows.Scan(&f.bucket, &f.name); err != nil {
+			return 0, fmt.Errorf("could not sql scan orphaned file: %w", err)
+		}
+
+		ff = append(ff, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("could not iterate orphaned file rows: %w", err)
+	}
+
+	var n int64
+	var errs []error
+	for _, f := range ff {
+		err := s.Store.Delete(ctx, f.bucket, f.name)
+		if err != nil && !isFileNotFound(err) {
+			errs = append(errs, fmt.Errorf("could not delete orphaned file %s/%s: %w", f.bucket, f.name, err))
+			continue
+		}
+
+		query := "DELETE FROM orphaned_files WHERE bucket = $1 AND name = $2"
+		if _, err := s.DB.ExecContext(ctx, query, f.bucket, f.name); err != nil {
+			return n, fmt.Errorf("could not sql delete orphaned file: %w", err)
+		}
+
+		n++
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// pruneReadNotifications deletes notifications that were read
+// and that have not been updated in a while.
+func (s *Service) pruneReadNotifications(ctx context.Context) (int64, error) {
+	query := "DELETE FROM notifications WHERE read_at IS NOT NULL AND issued_at < $1"
+	res, err := s.DB.ExecContext(ctx, query, time.Now().Add(-readNotificationsRetention))
+	if err != nil {
+		return 0, fmt.Errorf("could not sql delete read notifications: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("could not get deleted read notifications rows affected: %w", err)
+	}
+
+	return n, nil
+}
+
+// reconcileCounters fixes denormalized counters
+// that drifted away from the rows they count.
+func (s *Service) reconcileCounters(ctx context.Context) (int64, error) {
+	reconciles := []struct {
+		what  string
+		query string
+	}{
+		{"users followers count", `
+			UPDATE users SET followers_count = c.count
+			FROM (
+				SELECT users.id, count(follows.follower_id) AS count FROM users
+				LEFT JOIN follows ON follows.followee_id = users.id
+				GROUP BY users.id
+			) AS c
+			WHERE users.id = c.id AND users.followers_count != c.count`},
+		{"users followees count", `
+			UPDATE users SET followees_count = c.count
+			FROM (
+				SELECT users.id, count(follows.followee_id) AS count FROM users
+				LEFT JOIN follows ON follows.follower_id = users.id
+				GROUP BY users.id
+			) AS c
+			WHERE users.id = c.id AND users.followees_count != c.count`},
+		{"posts comments count", `
+			UPDATE posts SET comments_count = c.count
+			FROM (
+				SELECT posts.id, count(comments.id) AS count FROM posts
+				LEFT JOIN comments ON comments.post_id = posts.id
+				GROUP BY posts.id
+			) AS c
+			WHERE posts.id = c.id AND posts.comments_count != c.count`},
+		{"user lists members count", `
+			UPDATE user_lists SET members_count = c.count
+			FROM (
+				SELECT user_lists.id, count(user_list_members.user_id) AS count FROM user_lists
+				LEFT JOIN user_list_members ON user_list_members.list_id = user_lists.id
+				GROUP BY user_lists.id
+			) AS c
+			WHERE user_lists.id = c.id AND user_lists.members_count != c.count`},
+	}
+
+	var total int64
+	for _, r := range reconciles {
+		res, err := s.DB.ExecContext(ctx, r.query)
+		if err != nil {
+			return total, fmt.Errorf("could not sql reconcile %s: %w", r.what, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("could not get reconciled %s rows affected: %w", r.what, err)
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// deleteFile from the store in the background, along with its poster if animated.
+// If that fails, the file gets queued for the orphaned files job to retry.
+func (s *Service) deleteFile(bucket, name string) {
+	ctx := context.Background()
+	for _, name := range withPosters(name) {
+		err := s.Store.Delete(ctx, bucket, name)
+		if err == nil || isFileNotFound(err) {
+			continue
+		}
+
+		_ = s.Logger.Log("error", fmt.Errorf("could not delete %s/%s file: %w", bucket, name, err))
+
+		if err := s.orphanFiles(ctx, s.DB, bucket, name); err != nil {
+			_ = s.Logger.Log("error", err)
+		}
+	}
+}
+
+// orphanFiles queues the given files for deletion by the orphaned files job.
+// Posters of animated files are queued too.
+func (s *Service) orphanFiles(ctx context.Context, db execer, bucket string, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	names = withPosters(names...)
+
+	query := `
+		INSERT INTO orphaned_files (bucket, name)
+		SELECT $1, unnest($2::VARCHAR[])
+		ON CONFLICT (bucket, name) DO NOTHING`
+	if _, err := db.ExecContext(ctx, query, bucket, pq.Array(names)); err != nil {
+		return fmt.Errorf("could not sql insert orphaned files: %w", err)
+	}
+
+	return nil
+}
+
+// isFileNotFound tells whether the store failed because the file
+// was already gone, so there is nothing left to delete.
+func isFileNotFound(err error) bool {
+	return errors.Is(err, storage.ErrNotFound) || errors.Is(err, fs.ErrNotExist)
+}
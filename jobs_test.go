@@ -0,0 +1,101 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/lib/pq"
+
+	fsstorage "github.com/nakamauwu/nakama/storage/fs"
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_deleteOrphanedFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping orphaned files integration test in short mode")
+	}
+
+	ctx := context.Background()
+	store := &fsstorage.Store{Root: t.TempDir()}
+	svc := &Service{
+		Logger: log.NewNopLogger(),
+		DB:     testDB,
+		Store:  store,
+	}
+
+	stored := testutil.RandStr(t, 10) + ".png"
+	err := store.Store(ctx, MediaBucket, stored, []byte("nakama"))
+	testutil.WantEq(t, nil, err, "store error")
+
+	// already gone files are taken as deleted too.
+	gone := testutil.RandStr(t, 10) + ".png"
+
+	err = svc.orphanFiles(ctx, testDB, MediaBucket, stored, gone)
+	testutil.WantEq(t, nil, err, "orphan files error")
+
+	n, err := svc.deleteOrphanedFiles(ctx)
+	testutil.WantEq(t, nil, err, "delete orphaned files error")
+	testutil.WantEq(t, int64(2), n, "deleted orphaned files")
+
+	_, err = store.Open(ctx, MediaBucket, stored)
+	testutil.WantEq(t, true, err != nil, "open deleted file error")
+
+	var left int
+	err = testDB.QueryRowContext(ctx, "SELECT count(*) FROM orphaned_files WHERE name = ANY($1)", pq.Array([]string{stored, gone})).Scan(&left)
+	testutil.WantEq(t, nil, err, "count orphaned files error")
+	testutil.WantEq(t, 0, left, "orphaned files left")
+}
+
+func TestService_reconcileCounters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping counters integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	username := "reconcile_" + testutil.RandStr(t, 8)
+	var uid string
+	row := testDB.QueryRowContext(ctx, "INSERT INTO users (email, username, followers_count) VALUES ($1, $2, 3) RETURNING id", username+"@example.org", username)
+	err := row.Scan(&uid)
+	testutil.WantEq(t, nil, err, "insert user error")
+
+	_, err = svc.reconcileCounters(ctx)
+	testutil.WantEq(t, nil, err, "reconcile counters error")
+
+	var followersCount int
+	err = testDB.QueryRowContext(ctx, "SELECT followers_count FROM users WHERE id = $1", uid).Scan(&followersCount)
+	testutil.WantEq(t, nil, err, "select followers count error")
+	testutil.WantEq(t, 0, followersCount, "followers count")
+}
+
+func TestService_acquireJobLease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping background job lease integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+	name := "test_" + testutil.RandStr(t, 8)
+
+	ok, err := svc.acquireJobLease(ctx, name, "a", time.Hour)
+	testutil.WantEq(t, nil, err, "acquire error")
+	testutil.WantEq(t, true, ok, "acquired")
+
+	ok, err = svc.acquireJobLease(ctx, name, "b", time.Hour)
+	testutil.WantEq(t, nil, err, "acquire held error")
+	testutil.WantEq(t, false, ok, "acquired held")
+
+	ok, err = svc.acquireJobLease(ctx, name, "a", time.Hour)
+	testutil.WantEq(t, nil, err, "renew error")
+	testutil.WantEq(t, true, ok, "renewed")
+
+	_, err = testDB.ExecContext(ctx, "UPDATE background_job_leases SET expires_at = now() - INTERVAL '1 second' WHERE name = $1", name)
+	testutil.WantEq(t, nil, err, "expire lease error")
+
+	ok, err = svc.acquireJobLease(ctx, name, "b", time.Hour)
+	testutil.WantEq(t, nil, err, "acquire expired error")
+	testutil.WantEq(t, true, ok, "acquired expired")
+}
@@ -0,0 +1,171 @@
+package nakama
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+const (
+	// maxLoginCodeAttempts is how many wrong login codes can be typed
+	// for the same email before it gets locked out.
+	maxLoginCodeAttempts = 5
+	loginCodeLockout     = time.Minute * 15
+)
+
+var reLoginCode = regexp.MustCompile(`^\d{6}$`)
+
+var (
+	// ErrInvalidLoginCode denotes a login code that is not 6 digits,
+	// or that does not match any of the ones sent to the email.
+	ErrInvalidLoginCode = InvalidArgumentError("invalid login code")
+	// ErrLoginCodeLocked denotes that too many wrong login codes
+	// were typed for the email. Codes sent so far stop working,
+	// and a new one can be requested after the lockout.
+	ErrLoginCodeLocked = PermissionDeniedError("login code locked")
+)
+
+// VerifyLoginCode is like VerifyMagicLink, but takes the 6-digit code
+// sent along with the magic link, so it can be typed
+// on a device other than the one the email was opened on.
+// Wrong codes are counted per email and lock it out after a few attempts.
+func (s *Service) VerifyLoginCode(ctx context.Context, email, code string, username *string) (AuthOutput, error) {
+	var auth AuthOutput
+
+	email = strings.TrimSpace(email)
+	email = strings.ToLower(email)
+	if !reEmail.MatchString(email) {
+		return auth, ErrInvalidEmail
+	}
+
+	code = strings.TrimSpace(code)
+	if !reLoginCode.MatchString(code) {
+		return auth, ErrInvalidLoginCode
+	}
+
+	if username != nil && !ValidUsername(*username) {
+		return auth, ErrInvalidUsername
+	}
+
+	// wrong attempts must be recorded, so the transaction
+	// is committed and the error returned afterwards.
+	var attemptErr error
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		attemptErr = nil
+
+		var lockedUntil sql.NullTime
+		query := "SELECT locked_until FROM login_code_attempts WHERE email = $1 FOR UPDATE"
+		err := tx.QueryRowContext(ctx, query, email).Scan(&lockedUntil)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("could not sql query select login code attempts: %w", err)
+		}
+
+		if lockedUntil.Valid && lockedUntil.Time.After(time.Now()) {
+			attemptErr = ErrLoginCodeLocked
+			return nil
+		}
+
+		var verificationCode string
+		var userID sql.NullString
+		var createdAt time.Time
+		query = `
+			SELECT code, user_id, created_at FROM email_verification_codes
+			WHERE email = $1 AND login_code = $2
+			ORDER BY created_at DESC
+			LIMIT 1`
+		err = tx.QueryRowContext(ctx, query, email, code).Scan(&verificationCode, &userID, &createdAt)
+		if err == sql.ErrNoRows || (err == nil && isVerificationCodeExpired(createdAt)) {
+			locked, err := failLoginCodeAttempt(ctx, tx, email)
+			if err != nil {
+				return err
+			}
+
+			attemptErr = ErrInvalidLoginCode
+			if locked {
+				attemptErr = ErrLoginCodeLocked
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select verification code by login code: %w", err)
+		}
+
+		auth.User, err = s.verifiedEmailUser(ctx, tx, email, userID, username)
+		if err != nil {
+			return err
+		}
+
+		query = "DELETE FROM email_verification_codes WHERE email = $1 AND code = $2"
+		if _, err := tx.ExecContext(ctx, query, email, verificationCode); err != nil {
+			return fmt.Errorf("could not sql delete verification code: %w", err)
+		}
+
+		query = "DELETE FROM login_code_attempts WHERE email = $1"
+		if _, err := tx.ExecContext(ctx, query, email); err != nil {
+			return fmt.Errorf("could not sql delete login code attempts: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return auth, err
+	}
+
+	if attemptErr != nil {
+		return auth, attemptErr
+	}
+
+	return s.login(ctx, auth.User)
+}
+
+// failLoginCodeAttempt records a wrong login code for the given email.
+// Once the max attempts are reached, the email gets locked out
+// and the login codes sent to it so far are invalidated.
+func failLoginCodeAttempt(ctx context.Context, tx *sql.Tx, email string) (bool, error) {
+	var attempts int
+	query := `
+		INSERT INTO login_code_attempts (email, failed_attempts) VALUES ($1, 1)
+		ON CONFLICT (email) DO UPDATE SET
+			failed_attempts = login_code_attempts.failed_attempts + 1
+			, locked_until = NULL
+		RETURNING failed_attempts`
+	err := tx.QueryRowContext(ctx, query, email).Scan(&attempts)
+	if err != nil {
+		return false, fmt.Errorf("could not sql upsert login code attempts: %w", err)
+	}
+
+	if attempts < maxLoginCodeAttempts {
+		return false, nil
+	}
+
+	query = "UPDATE login_code_attempts SET failed_attempts = 0, locked_until = $1 WHERE email = $2"
+	if _, err := tx.ExecContext(ctx, query, time.Now().Add(loginCodeLockout), email); err != nil {
+		return false, fmt.Errorf("could not sql update login code lockout: %w", err)
+	}
+
+	query = "UPDATE email_verification_codes SET login_code = NULL WHERE email = $1"
+	if _, err := tx.ExecContext(ctx, query, email); err != nil {
+		return false, fmt.Errorf("could not sql invalidate login codes: %w", err)
+	}
+
+	return true, nil
+}
+
+// genLoginCode generates a random 6-digit code.
+func genLoginCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("could not generate login code: %w", err)
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
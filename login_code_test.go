@@ -0,0 +1,39 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func Test_genLoginCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := genLoginCode()
+		testutil.WantEq(t, nil, err, "error")
+		testutil.WantEq(t, true, reLoginCode.MatchString(code), "6 digits")
+	}
+}
+
+func TestService_VerifyLoginCode(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{}
+
+	t.Run("invalid_email", func(t *testing.T) {
+		_, err := svc.VerifyLoginCode(ctx, "nope", "123456", nil)
+		testutil.WantEq(t, ErrInvalidEmail, err, "error")
+	})
+
+	t.Run("invalid_code", func(t *testing.T) {
+		for _, code := range []string{"", "12345", "1234567", "12345a", "00000000-0000-0000-0000-000000000000"} {
+			_, err := svc.VerifyLoginCode(ctx, "shinji@example.org", code, nil)
+			testutil.WantEq(t, ErrInvalidLoginCode, err, "error")
+		}
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		username := "-nope"
+		_, err := svc.VerifyLoginCode(ctx, "shinji@example.org", "123456", &username)
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+}
@@ -0,0 +1,347 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/lib/pq"
+)
+
+// Mute types.
+const (
+	MuteTypeUser = "user"
+	MuteTypeWord = "word"
+	MuteTypeTag  = "tag"
+)
+
+const mutedWordMaxLength = 100
+
+// muteWordSeparators matches what is not part of a word.
+// Muted words and content are split by it so words only match whole,
+// like "art" not matching "party".
+// It is an RE2 regexp since CockroachDB uses those.
+const muteWordSeparators = `[^\p{L}\p{N}_]+`
+
+var (
+	reMutedTag       = regexp.MustCompile(`^(?:\p{L}|\p{N}|_)+$`)
+	reMutedWordChars = regexp.MustCompile(`\p{L}|\p{N}|_`)
+)
+
+var (
+	// ErrInvalidMuteID denotes an invalid mute ID; that is not uuid.
+	ErrInvalidMuteID = InvalidArgumentError("invalid mute ID")
+	// ErrInvalidMuteType denotes a mute type other than user, word or tag.
+	ErrInvalidMuteType = InvalidArgumentError("invalid mute type")
+	// ErrInvalidMutedWord denotes an empty muted word or phrase,
+	// or one that exceeds the max allowed characters (100).
+	ErrInvalidMutedWord = InvalidArgumentError("invalid muted word")
+	// ErrInvalidMutedTag denotes a muted hashtag with characters
+	// other than letters, numbers or underscores.
+	ErrInvalidMutedTag = InvalidArgumentError("invalid muted tag")
+	// ErrForbiddenMute denotes a forbidden mute. Like muting yourself.
+	ErrForbiddenMute = PermissionDeniedError("forbidden mute")
+	// ErrAlreadyMuted denotes that the user, word or tag is already muted.
+	ErrAlreadyMuted = AlreadyExistsError("already muted")
+	// ErrMuteNotFound denotes a not found mute.
+	ErrMuteNotFound = NotFoundError("mute not found")
+)
+
+// Mute hides posts from a user, or containing a word or hashtag,
+// from the timeline and posts of the user that created it.
+// Notifications matching it are not sent either.
+// Value is the muted username, word or hashtag depending on the type.
+type Mute struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type CreateMute struct {
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// UpdateMute changes when a mute expires.
+// A nil ExpiresAt means it never does.
+type UpdateMute struct {
+	ID        string     `json:"-"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreateMute for the authenticated user.
+// Words and hashtags are matched case insensitive.
+// Words and phrases only match whole words of the content.
+func (s *Service) CreateMute(ctx context.Context, in CreateMute) (Mute, error) {
+	var out Mute
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	in.Value = strings.TrimSpace(in.Value)
+	switch in.Type {
+	case MuteTypeUser:
+		if !ValidUsername(in.Value) {
+			return out, ErrInvalidUsername
+		}
+	case MuteTypeWord:
+		in.Value = strings.ToLower(smartTrim(in.Value))
+		if !reMutedWordChars.MatchString(in.Value) || utf8.RuneCountInString(in.Value) > mutedWordMaxLength {
+			return out, ErrInvalidMutedWord
+		}
+	case MuteTypeTag:
+		in.Value = strings.ToLower(strings.TrimPrefix(in.Value, "#"))
+		if !reMutedTag.MatchString(in.Value) {
+			return out, ErrInvalidMutedTag
+		}
+	default:
+		return out, ErrInvalidMuteType
+	}
+
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return out, ErrInvalidExpiration
+	}
+
+	var mutedUserID, value sql.NullString
+	if in.Type == MuteTypeUser {
+		query := "SELECT id FROM users WHERE username = $1"
+		err := s.DB.QueryRowContext(ctx, query, in.Value).Scan(&mutedUserID)
+		if err == sql.ErrNoRows {
+			return out, ErrUserNotFound
+		}
+
+		if err != nil {
+			return out, fmt.Errorf("could not sql query select muted user id: %w", err)
+		}
+
+		if mutedUserID.String == uid {
+			return out, ErrForbiddenMute
+		}
+	} else {
+		value = sql.NullString{String: in.Value, Valid: true}
+	}
+
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		// An expired mute for the same target would still hold the unique index
+		// until the expired mutes job gets to it.
+		query := `
+			DELETE FROM mutes
+			WHERE user_id = $1
+				AND expires_at <= now()
+				AND (muted_user_id = $2 OR (type = $3 AND value = $4))`
+		_, err := tx.ExecContext(ctx, query, uid, mutedUserID, in.Type, value)
+		if err != nil {
+			return fmt.Errorf("could not sql delete expired mute: %w", err)
+		}
+
+		query = `
+			INSERT INTO mutes (user_id, type, muted_user_id, value, expires_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at`
+		row := tx.QueryRowContext(ctx, query, uid, in.Type, mutedUserID, value, in.ExpiresAt)
+		err = row.Scan(&out.ID, &out.CreatedAt)
+		if isUniqueViolation(err) {
+			return ErrAlreadyMuted
+		}
+
+		if isForeignKeyViolation(err) {
+			return ErrUserGone
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql insert mute: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	out.Type = in.Type
+	out.Value = in.Value
+	out.ExpiresAt = in.ExpiresAt
+
+	return out, nil
+}
+
+// Mutes from the authenticated user that did not expire yet.
+// Newest first.
+func (s *Service) Mutes(ctx context.Context) ([]Mute, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT mutes.id, mutes.type, COALESCE(users.username, mutes.value), mutes.expires_at, mutes.created_at
+		FROM mutes
+		LEFT JOIN users ON mutes.muted_user_id = users.id
+		WHERE mutes.user_id = $1
+			AND (mutes.expires_at IS NULL OR mutes.expires_at > now())
+		ORDER BY mutes.created_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select mutes: %w", err)
+	}
+
+	defer rows.Close()
+
+	var mm []Mute
+	for rows.Next() {
+		var m Mute
+		if err := rows.Scan(&m.ID, &m.Type, &m.Value, &m.ExpiresAt, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not sql scan mute: %w", err)
+		}
+
+		mm = append(mm, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate mute rows: %w", err)
+	}
+
+	return mm, nil
+}
+
+// UpdateMute from the authenticated user.
+func (s *Service) UpdateMute(ctx context.Context, in UpdateMute) (Mute, error) {
+	var out Mute
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(in.ID) {
+		return out, ErrInvalidMuteID
+	}
+
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return out, ErrInvalidExpiration
+	}
+
+	query := `
+		WITH updated_mute AS (
+			UPDATE mutes SET expires_at = $1
+			WHERE id = $2 AND user_id = $3
+			RETURNING id, type, muted_user_id, value, expires_at, created_at
+		)
+		SELECT updated_mute.id
+		, updated_mute.type
+		, COALESCE(users.username, updated_mute.value)
+		, updated_mute.expires_at
+		, updated_mute.created_at
+		FROM updated_mute
+		LEFT JOIN users ON updated_mute.muted_user_id = users.id`
+	row := s.DB.QueryRowContext(ctx, query, in.ExpiresAt, in.ID, uid)
+	err := row.Scan(&out.ID, &out.Type, &out.Value, &out.ExpiresAt, &out.CreatedAt)
+	if err == sql.ErrNoRows {
+		return out, ErrMuteNotFound
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql update mute: %w", err)
+	}
+
+	return out, nil
+}
+
+// DeleteMute from the authenticated user.
+func (s *Service) DeleteMute(ctx context.Context, muteID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(muteID) {
+		return ErrInvalidMuteID
+	}
+
+	query := "DELETE FROM mutes WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, muteID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete mute: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted mute rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrMuteNotFound
+	}
+
+	return nil
+}
+
+// muted tells whether the given user muted either the actor,
+// a word contained in the content, or one of the tags.
+func (s *Service) muted(ctx context.Context, userID, actorID, content string, tags []string) (bool, error) {
+	var ok bool
+	query := "SELECT " + muteMatch("$1", "$2", "$3", "$4")
+	err := s.DB.QueryRowContext(ctx, query, userID, actorID, content, pq.Array(lowerTags(tags))).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select mute existence: %w", err)
+	}
+
+	return ok, nil
+}
+
+// mutedPost tells whether the given post is hidden to the user by any of its mutes.
+// Users own posts never are.
+func (s *Service) mutedPost(ctx context.Context, userID string, p Post) (bool, error) {
+	if p.UserID == userID {
+		return false, nil
+	}
+
+	return s.muted(ctx, userID, p.UserID, p.Content, collectTags(p.Content))
+}
+
+// notMutedPost gives a condition to use inside buildQuery,
+// that filters out posts hidden to @uid by any of its mutes.
+func notMutedPost() string {
+	return `(posts.user_id = @uid OR NOT ` + muteMatch("@uid", "posts.user_id", "posts.content", `ARRAY(
+		SELECT lower(post_tags.tag) FROM post_tags
+		WHERE post_tags.post_id = posts.id AND post_tags.comment_id IS NULL
+	)`) + `)`
+}
+
+// muteMatch gives an SQL condition that is true when the given user
+// has a mute matching either the actor, a word or phrase of the content,
+// or one of the lowercased tags.
+// Both the content and the muted word get their separators collapsed
+// into single spaces and padded with one, so they match on word boundaries.
+func muteMatch(userID, actorID, content, tags string) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM mutes
+		WHERE mutes.user_id = %[1]s
+			AND (mutes.expires_at IS NULL OR mutes.expires_at > now())
+			AND (
+				mutes.muted_user_id = %[2]s
+				OR (mutes.type = 'word' AND strpos(
+					' ' || regexp_replace(lower(%[3]s), '%[5]s', ' ', 'g') || ' ',
+					' ' || trim(regexp_replace(mutes.value, '%[5]s', ' ', 'g')) || ' '
+				) > 0)
+				OR (mutes.type = 'tag' AND mutes.value = ANY(%[4]s))
+			)
+	)`, userID, actorID, content, tags, muteWordSeparators)
+}
+
+// lowerTags to match them against muted tags.
+func lowerTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		out = append(out, strings.ToLower(tag))
+	}
+	return out
+}
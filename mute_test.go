@@ -0,0 +1,135 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_CreateMute(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := svc.CreateMute(context.Background(), CreateMute{Type: MuteTypeWord, Value: "spoilers"})
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	past := time.Now().Add(-time.Minute)
+	ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+	tt := []struct {
+		name string
+		in   CreateMute
+		want error
+	}{
+		{
+			name: "invalid_type",
+			in:   CreateMute{Type: "emoji", Value: "nope"},
+			want: ErrInvalidMuteType,
+		},
+		{
+			name: "invalid_username",
+			in:   CreateMute{Type: MuteTypeUser, Value: "@nope"},
+			want: ErrInvalidUsername,
+		},
+		{
+			name: "empty_word",
+			in:   CreateMute{Type: MuteTypeWord, Value: "  "},
+			want: ErrInvalidMutedWord,
+		},
+		{
+			name: "punctuation_word",
+			in:   CreateMute{Type: MuteTypeWord, Value: "!!!"},
+			want: ErrInvalidMutedWord,
+		},
+		{
+			name: "invalid_tag",
+			in:   CreateMute{Type: MuteTypeTag, Value: "#not a tag"},
+			want: ErrInvalidMutedTag,
+		},
+		{
+			name: "expired",
+			in:   CreateMute{Type: MuteTypeWord, Value: "spoilers", ExpiresAt: &past},
+			want: ErrInvalidExpiration,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateMute(ctx, tc.in)
+			testutil.WantEq(t, tc.want, err, "error")
+		})
+	}
+}
+
+func TestService_muted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping mutes integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	user := createTestUser(t)
+	author := createTestUser(t)
+	userCtx := context.WithValue(ctx, KeyAuthUserID, user.ID)
+
+	_, err := svc.CreateMute(userCtx, CreateMute{Type: MuteTypeWord, Value: "Spoilers"})
+	testutil.WantEq(t, nil, err, "create muted word error")
+
+	_, err = svc.CreateMute(userCtx, CreateMute{Type: MuteTypeWord, Value: "final season"})
+	testutil.WantEq(t, nil, err, "create muted phrase error")
+
+	_, err = svc.CreateMute(userCtx, CreateMute{Type: MuteTypeTag, Value: "#Anime"})
+	testutil.WantEq(t, nil, err, "create muted tag error")
+
+	_, err = svc.CreateMute(userCtx, CreateMute{Type: MuteTypeWord, Value: "spoilers"})
+	testutil.WantEq(t, ErrAlreadyMuted, err, "create duplicated mute error")
+
+	tt := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "word", content: "no SPOILERS please", want: true},
+		{name: "word_punctuation", content: "spoilers!", want: true},
+		{name: "word_inside_another", content: "antispoilers", want: false},
+		{name: "phrase", content: "the Final,  Season is out", want: true},
+		{name: "phrase_split", content: "final exams this season", want: false},
+		{name: "tag", content: "watching #anime", want: true},
+		{name: "none", content: "hello there", want: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := svc.mutedPost(ctx, user.ID, Post{UserID: author.ID, Content: tc.content})
+			testutil.WantEq(t, nil, err, "muted post error")
+			testutil.WantEq(t, tc.want, got, "muted")
+		})
+	}
+
+	mute, err := svc.CreateMute(userCtx, CreateMute{Type: MuteTypeUser, Value: author.Username})
+	testutil.WantEq(t, nil, err, "create muted user error")
+
+	got, err := svc.muted(ctx, user.ID, author.ID, "", nil)
+	testutil.WantEq(t, nil, err, "muted user error")
+	testutil.WantEq(t, true, got, "muted user")
+
+	_, err = testDB.ExecContext(ctx, "UPDATE mutes SET expires_at = now() - INTERVAL '1 second' WHERE id = $1", mute.ID)
+	testutil.WantEq(t, nil, err, "expire mute error")
+
+	mm, err := svc.Mutes(userCtx)
+	testutil.WantEq(t, nil, err, "mutes error")
+	for _, m := range mm {
+		testutil.WantEq(t, false, m.ID == mute.ID, "expired mute listed")
+	}
+
+	mute, err = svc.CreateMute(userCtx, CreateMute{Type: MuteTypeUser, Value: author.Username})
+	testutil.WantEq(t, nil, err, "mute user again after expiration error")
+
+	err = svc.DeleteMute(userCtx, mute.ID)
+	testutil.WantEq(t, nil, err, "delete mute error")
+
+	got, err = svc.muted(ctx, user.ID, author.ID, "", nil)
+	testutil.WantEq(t, nil, err, "unmuted user error")
+	testutil.WantEq(t, false, got, "unmuted user")
+}
@@ -0,0 +1,142 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// mutualFollowersPreviewSize is how many mutual followers come along a user profile.
+const mutualFollowersPreviewSize = 3
+
+// MutualFollowers of the user with the given username; that is
+// followers of them that the authenticated user follows.
+// In ascending order with forward pagination.
+// It goes through the followees of the authenticated user
+// so it does not depend on how many followers the user has.
+func (s *Service) MutualFollowers(ctx context.Context, username string, first uint64, after *string) (UserProfiles, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	var afterUsername string
+	if after != nil {
+		var err error
+		afterUsername, err = decodeSimpleCursor(*after)
+		if err != nil || !ValidUsername(afterUsername) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT users.username
+		, users.avatar
+		, users.cover
+		, users.followers_count
+		, users.followees_count
+		, users.private
+		, followees.followee_id IS NOT NULL AS followeed
+		FROM follows AS viewer_followees
+		INNER JOIN follows AS mutuals
+			ON mutuals.follower_id = viewer_followees.followee_id
+				AND mutuals.followee_id = (SELECT id FROM users WHERE username = @username)
+		INNER JOIN users ON viewer_followees.followee_id = users.id
+		LEFT JOIN follows AS followees
+			ON followees.follower_id = users.id AND followees.followee_id = @uid
+		WHERE viewer_followees.follower_id = @uid
+		{{ if .afterUsername }}AND users.username > @afterUsername{{ end }}
+		ORDER BY users.username ASC
+		LIMIT @first`, map[string]interface{}{
+		"uid":           uid,
+		"username":      username,
+		"first":         first,
+		"afterUsername": afterUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build mutual followers sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query select mutual followers: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu UserProfiles
+	for rows.Next() {
+		var u UserProfile
+		var avatar, cover sql.NullString
+		err := rows.Scan(
+			&u.Username,
+			&avatar,
+			&cover,
+			&u.FollowersCount,
+			&u.FolloweesCount,
+			&u.Private,
+			&u.Followeed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan mutual follower: %w", err)
+		}
+
+		// the authenticated user follows all of them.
+		u.Following = true
+		u.AvatarURL = s.avatarURL(avatar)
+		u.CoverURL = s.coverURL(cover)
+		uu = append(uu, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate mutual follower rows: %w", err)
+	}
+
+	return uu, nil
+}
+
+// mutualFollowersSummary gives how many followees of the given viewer
+// follow the given user, along with the first few of them.
+func (s *Service) mutualFollowersSummary(ctx context.Context, viewerID, userID string) (int, []User, error) {
+	query := `
+		SELECT users.username, users.avatar, count(*) OVER ()
+		FROM follows AS viewer_followees
+		INNER JOIN follows AS mutuals
+			ON mutuals.follower_id = viewer_followees.followee_id AND mutuals.followee_id = $2
+		INNER JOIN users ON viewer_followees.followee_id = users.id
+		WHERE viewer_followees.follower_id = $1
+		ORDER BY users.username ASC
+		LIMIT $3`
+	rows, err := s.DB.QueryContext(ctx, query, viewerID, userID, mutualFollowersPreviewSize)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not sql query select mutual followers summary: %w", err)
+	}
+
+	defer rows.Close()
+
+	var count int
+	uu := []User{}
+	for rows.Next() {
+		var u User
+		var avatar sql.NullString
+		if err := rows.Scan(&u.Username, &avatar, &count); err != nil {
+			return 0, nil, fmt.Errorf("could not sql scan mutual follower: %w", err)
+		}
+
+		u.AvatarURL = s.avatarURL(avatar)
+		uu = append(uu, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("could not iterate mutual followers summary rows: %w", err)
+	}
+
+	return count, uu, nil
+}
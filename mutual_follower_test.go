@@ -0,0 +1,59 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_MutualFollowers(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := svc.MutualFollowers(context.Background(), "someone", 0, nil)
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		_, err := svc.MutualFollowers(ctx, "@nope", 0, nil)
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		_, err := svc.MutualFollowers(ctx, "someone", 0, ptrString("nope"))
+		testutil.WantEq(t, ErrInvalidCursor, err, "error")
+	})
+
+	t.Run("followed_by_followees", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping mutual followers integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		viewer := createTestUser(t)
+		mutual := createTestUser(t)
+		stranger := createTestUser(t)
+		profile := createTestUser(t)
+
+		_, err := testDB.ExecContext(ctx, `
+			INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2), ($2, $4), ($3, $4)`,
+			viewer.ID, mutual.ID, stranger.ID, profile.ID)
+		testutil.WantEq(t, nil, err, "insert follows error")
+
+		viewerCtx := context.WithValue(ctx, KeyAuthUserID, viewer.ID)
+		uu, err := svc.MutualFollowers(viewerCtx, profile.Username, 0, nil)
+		testutil.WantEq(t, nil, err, "mutual followers error")
+		testutil.WantEq(t, 1, len(uu), "mutual followers length")
+		testutil.WantEq(t, mutual.Username, uu[0].Username, "mutual follower username")
+
+		u, err := svc.User(viewerCtx, profile.Username)
+		testutil.WantEq(t, nil, err, "user error")
+		testutil.WantEq(t, 1, u.MutualFollowersCount, "mutual followers count")
+		testutil.WantEq(t, 1, len(u.MutualFollowers), "mutual followers preview length")
+	})
+}
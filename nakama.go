@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/go-kit/log"
+	"github.com/go-webauthn/webauthn/webauthn"
 
 	"github.com/nakamauwu/nakama/mailing"
 	"github.com/nakamauwu/nakama/pubsub"
@@ -38,4 +39,14 @@ type Service struct {
 
 	magicLinkTmplOncer sync.Once
 	magicLinkTmpl      *template.Template
+
+	accountDeletionTmplOncer sync.Once
+	accountDeletionTmpl      *template.Template
+
+	dataExportTmplOncer sync.Once
+	dataExportTmpl      *template.Template
+
+	webAuthnOncer sync.Once
+	webAuthnRP    *webauthn.WebAuthn
+	webAuthnErr   error
 }
@@ -17,10 +17,13 @@ import (
 var ErrInvalidNotificationID = InvalidArgumentError("invalid notification ID")
 
 // Notification model.
+// Actors are hydrated from ActorIDs, newest first,
+// so they stay up to date after a username change.
 type Notification struct {
 	ID       string    `json:"id"`
 	UserID   string    `json:"-"`
-	Actors   []string  `json:"actors"`
+	ActorIDs []string  `json:"-"`
+	Actors   []User    `json:"actors"`
 	Type     string    `json:"type"`
 	PostID   *string   `json:"postID,omitempty"`
 	Read     bool      `json:"read"`
@@ -59,7 +62,7 @@ func (s *Service) Notifications(ctx context.Context, last uint64, before *string
 	last = normalizePageSize(last)
 	query, args, err := buildQuery(`
 		SELECT id
-		, actors
+		, actor_ids
 		, type
 		, post_id
 		, read_at
@@ -95,7 +98,7 @@ func (s *Service) Notifications(ctx context.Context, last uint64, before *string
 	for rows.Next() {
 		var n Notification
 		var readAt *time.Time
-		if err = rows.Scan(&n.ID, pq.Array(&n.Actors), &n.Type, &n.PostID, &readAt, &n.IssuedAt); err != nil {
+		if err = rows.Scan(&n.ID, pq.Array(&n.ActorIDs), &n.Type, &n.PostID, &readAt, &n.IssuedAt); err != nil {
 			return nil, fmt.Errorf("could not scan notification: %w", err)
 		}
 
@@ -107,6 +110,10 @@ func (s *Service) Notifications(ctx context.Context, last uint64, before *string
 		return nil, fmt.Errorf("could not iterate over notification rows: %w", err)
 	}
 
+	if err := s.hydrateNotificationActors(ctx, nn); err != nil {
+		return nil, err
+	}
+
 	return nn, nil
 }
 
@@ -202,24 +209,27 @@ func (s *Service) MarkNotificationsAsRead(ctx context.Context) error {
 
 func (s *Service) notifyFollow(followerID, followeeID string) {
 	ctx := context.Background()
+	isMuted, err := s.muted(ctx, followeeID, followerID, "", nil)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not check follow notification mutes: %w", err))
+		return
+	}
+
+	if isMuted {
+		return
+	}
+
 	var n Notification
 	var notified bool
 
-	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
-		var actor string
-		query := "SELECT username FROM users WHERE id = $1"
-		err := tx.QueryRowContext(ctx, query, followerID).Scan(&actor)
-		if err != nil {
-			return fmt.Errorf("could not query select follow notification actor: %w", err)
-		}
-
-		query = `SELECT EXISTS (
+	err = crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		query := `SELECT EXISTS (
 			SELECT 1 FROM notifications
 			WHERE user_id = $1
-				AND $2:::VARCHAR = ANY(actors)
+				AND $2:::UUID = ANY(actor_ids)
 				AND type = 'follow'
 		)`
-		err = tx.QueryRowContext(ctx, query, followeeID, actor).Scan(&notified)
+		err := tx.QueryRowContext(ctx, query, followeeID, followerID).Scan(&notified)
 		if err != nil {
 			return fmt.Errorf("could not query select follow notification existence: %w", err)
 		}
@@ -236,26 +246,26 @@ func (s *Service) notifyFollow(followerID, followeeID string) {
 		}
 
 		if err == sql.ErrNoRows {
-			actors := []string{actor}
+			actorIDs := []string{followerID}
 			query = `
-				INSERT INTO notifications (user_id, actors, type) VALUES ($1, $2, 'follow')
+				INSERT INTO notifications (user_id, actor_ids, type) VALUES ($1, $2, 'follow')
 				RETURNING id, issued_at`
-			row := tx.QueryRowContext(ctx, query, followeeID, pq.Array(actors))
+			row := tx.QueryRowContext(ctx, query, followeeID, pq.Array(actorIDs))
 			err = row.Scan(&n.ID, &n.IssuedAt)
 			if err != nil {
 				return fmt.Errorf("could not insert follow notification: %w", err)
 			}
 
-			n.Actors = actors
+			n.ActorIDs = actorIDs
 		} else {
 			query = `
 				UPDATE notifications SET
-					actors = array_prepend($1, notifications.actors),
+					actor_ids = array_prepend($1:::UUID, notifications.actor_ids),
 					issued_at = now()
 				WHERE id = $2
-				RETURNING actors, issued_at`
-			row := tx.QueryRowContext(ctx, query, actor, nid)
-			err = row.Scan(pq.Array(&n.Actors), &n.IssuedAt)
+				RETURNING actor_ids, issued_at`
+			row := tx.QueryRowContext(ctx, query, followerID, nid)
+			err = row.Scan(pq.Array(&n.ActorIDs), &n.IssuedAt)
 			if err != nil {
 				return fmt.Errorf("could not update follow notification: %w", err)
 			}
@@ -278,21 +288,65 @@ func (s *Service) notifyFollow(followerID, followeeID string) {
 	}
 }
 
+// notifyFollowRequest notifies the given user that the actor either sent them
+// a follow request (follow_request), or approved (follow_request_approved)
+// or denied (follow_request_denied) theirs.
+func (s *Service) notifyFollowRequest(typ, userID, actorID string) {
+	ctx := context.Background()
+	isMuted, err := s.muted(ctx, userID, actorID, "", nil)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not check %s notification mutes: %w", typ, err))
+		return
+	}
+
+	if isMuted {
+		return
+	}
+
+	n := Notification{
+		UserID: userID,
+		Type:   typ,
+	}
+	query := `
+		INSERT INTO notifications (user_id, actor_ids, type)
+		SELECT $1, ARRAY[id], $2 FROM users WHERE id = $3
+		RETURNING id, actor_ids, issued_at`
+	row := s.DB.QueryRowContext(ctx, query, userID, typ, actorID)
+	err = row.Scan(&n.ID, pq.Array(&n.ActorIDs), &n.IssuedAt)
+	if err == sql.ErrNoRows {
+		return
+	}
+
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not insert %s notification: %w", typ, err))
+		return
+	}
+
+	go s.broadcastNotification(n)
+}
+
 func (s *Service) notifyComment(c Comment) {
-	actor := c.User.Username
 	rows, err := s.DB.Query(`
-		INSERT INTO notifications (user_id, actors, type, post_id, read_at)
+		INSERT INTO notifications (user_id, actor_ids, type, post_id, read_at)
 		SELECT user_id, $1, 'comment', $2, '0001-01-01 00:00:00' FROM post_subscriptions
 		WHERE post_subscriptions.user_id != $3
 			AND post_subscriptions.post_id = $2
+			AND NOT `+muteMatch("post_subscriptions.user_id", "$3", "$5", "$6")+`
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks
+				WHERE (blocks.blocker_id = $3 AND blocks.blocked_id = post_subscriptions.user_id)
+					OR (blocks.blocker_id = post_subscriptions.user_id AND blocks.blocked_id = $3)
+			)
 		ON CONFLICT (user_id, type, post_id, read_at) DO UPDATE SET
-			actors = array_prepend($4, array_remove(notifications.actors, $4)),
+			actor_ids = array_prepend($4:::UUID, array_remove(notifications.actor_ids, $4:::UUID)),
 			issued_at = now()
-		RETURNING id, user_id, actors, issued_at`,
-		pq.Array([]string{actor}),
+		RETURNING id, user_id, actor_ids, issued_at`,
+		pq.Array([]string{c.UserID}),
 		c.PostID,
 		c.UserID,
-		actor,
+		c.UserID,
+		c.Content,
+		pq.Array(lowerTags(collectTags(c.Content))),
 	)
 	if err != nil {
 		_ = s.Logger.Log("error", fmt.Errorf("could not insert comment notifications: %w", err))
@@ -303,7 +357,7 @@ func (s *Service) notifyComment(c Comment) {
 
 	for rows.Next() {
 		var n Notification
-		if err = rows.Scan(&n.ID, &n.UserID, pq.Array(&n.Actors), &n.IssuedAt); err != nil {
+		if err = rows.Scan(&n.ID, &n.UserID, pq.Array(&n.ActorIDs), &n.IssuedAt); err != nil {
 			_ = s.Logger.Log("error", fmt.Errorf("could not scan comment notification: %w", err))
 			return
 		}
@@ -326,17 +380,25 @@ func (s *Service) notifyPostMention(p Post) {
 		return
 	}
 
-	actors := []string{p.User.Username}
+	actorIDs := []string{p.UserID}
 	rows, err := s.DB.Query(`
-		INSERT INTO notifications (user_id, actors, type, post_id)
+		INSERT INTO notifications (user_id, actor_ids, type, post_id)
 		SELECT users.id, $1, 'post_mention', $2 FROM users
 		WHERE users.id != $3
 			AND username = ANY($4)
+			AND NOT `+muteMatch("users.id", "$3", "$5", "$6")+`
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks
+				WHERE (blocks.blocker_id = $3 AND blocks.blocked_id = users.id)
+					OR (blocks.blocker_id = users.id AND blocks.blocked_id = $3)
+			)
 		RETURNING id, user_id, issued_at`,
-		pq.Array(actors),
+		pq.Array(actorIDs),
 		p.ID,
 		p.UserID,
 		pq.Array(mentions),
+		p.Content,
+		pq.Array(lowerTags(collectTags(p.Content))),
 	)
 	if err != nil {
 		_ = s.Logger.Log("error", fmt.Errorf("could not insert post mention notifications: %w", err))
@@ -352,7 +414,7 @@ func (s *Service) notifyPostMention(p Post) {
 			return
 		}
 
-		n.Actors = actors
+		n.ActorIDs = actorIDs
 		n.Type = "post_mention"
 		n.PostID = &p.ID
 
@@ -371,21 +433,28 @@ func (s *Service) notifyCommentMention(c Comment) {
 		return
 	}
 
-	actor := c.User.Username
 	rows, err := s.DB.Query(`
-		INSERT INTO notifications (user_id, actors, type, post_id, read_at)
+		INSERT INTO notifications (user_id, actor_ids, type, post_id, read_at)
 		SELECT users.id, $1, 'comment_mention', $2, '0001-01-01 00:00:00' FROM users
 		WHERE users.id != $3
 			AND username = ANY($4)
+			AND NOT `+muteMatch("users.id", "$3", "$6", "$7")+`
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks
+				WHERE (blocks.blocker_id = $3 AND blocks.blocked_id = users.id)
+					OR (blocks.blocker_id = users.id AND blocks.blocked_id = $3)
+			)
 		ON CONFLICT (user_id, type, post_id, read_at) DO UPDATE SET
-			actors = array_prepend($5, array_remove(notifications.actors, $5)),
+			actor_ids = array_prepend($5:::UUID, array_remove(notifications.actor_ids, $5:::UUID)),
 			issued_at = now()
-		RETURNING id, user_id, actors, issued_at`,
-		pq.Array([]string{actor}),
+		RETURNING id, user_id, actor_ids, issued_at`,
+		pq.Array([]string{c.UserID}),
 		c.PostID,
 		c.UserID,
 		pq.Array(mentions),
-		actor,
+		c.UserID,
+		c.Content,
+		pq.Array(lowerTags(collectTags(c.Content))),
 	)
 	if err != nil {
 		_ = s.Logger.Log("error", fmt.Errorf("could not insert comment mention notifications: %w", err))
@@ -396,7 +465,7 @@ func (s *Service) notifyCommentMention(c Comment) {
 
 	for rows.Next() {
 		var n Notification
-		if err = rows.Scan(&n.ID, &n.UserID, pq.Array(&n.Actors), &n.IssuedAt); err != nil {
+		if err = rows.Scan(&n.ID, &n.UserID, pq.Array(&n.ActorIDs), &n.IssuedAt); err != nil {
 			_ = s.Logger.Log("error", fmt.Errorf("could not scan comment mention notification: %w", err))
 			return
 		}
@@ -413,7 +482,94 @@ func (s *Service) notifyCommentMention(c Comment) {
 	}
 }
 
+// notifyReport to moderators and admins, other than the reporter,
+// so they know there is a new report awaiting in the queue.
+func (s *Service) notifyReport(reporterID string, postID *string) {
+	actorIDs := []string{reporterID}
+	rows, err := s.DB.Query(`
+		INSERT INTO notifications (user_id, actor_ids, type, post_id)
+		SELECT users.id, $1, 'report', $2 FROM users
+		WHERE users.role != 'user' AND users.id != $3
+		RETURNING id, user_id, issued_at`,
+		pq.Array(actorIDs),
+		postID,
+		reporterID,
+	)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not insert report notifications: %w", err))
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var n Notification
+		if err = rows.Scan(&n.ID, &n.UserID, &n.IssuedAt); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not scan report notification: %w", err))
+			return
+		}
+
+		n.ActorIDs = actorIDs
+		n.Type = "report"
+		n.PostID = postID
+
+		go s.broadcastNotification(n)
+	}
+
+	if err = rows.Err(); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not iterate report notification rows: %w", err))
+		return
+	}
+}
+
+// notifyReportResolved to everyone that sent the report.
+// The moderator that resolved it is left anonymous.
+func (s *Service) notifyReportResolved(reporterIDs []string) {
+	if len(reporterIDs) == 0 {
+		return
+	}
+
+	rows, err := s.DB.Query(`
+		INSERT INTO notifications (user_id, type)
+		SELECT users.id, 'report_resolved' FROM users
+		WHERE users.id = ANY($1)
+		RETURNING id, user_id, issued_at`,
+		pq.Array(reporterIDs),
+	)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not insert report resolved notifications: %w", err))
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var n Notification
+		if err = rows.Scan(&n.ID, &n.UserID, &n.IssuedAt); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not scan report resolved notification: %w", err))
+			return
+		}
+
+		n.Type = "report_resolved"
+
+		go s.broadcastNotification(n)
+	}
+
+	if err = rows.Err(); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not iterate report resolved notification rows: %w", err))
+		return
+	}
+}
+
 func (s *Service) broadcastNotification(n Notification) {
+	nn := []Notification{n}
+	if err := s.hydrateNotificationActors(context.Background(), nn); err != nil {
+		_ = s.Logger.Log("error", err)
+		return
+	}
+
+	n = nn[0]
+
 	var b bytes.Buffer
 	err := gob.NewEncoder(&b).Encode(n)
 	if err != nil {
@@ -430,4 +586,52 @@ func (s *Service) broadcastNotification(n Notification) {
 	go s.sendWebPushNotifications(n)
 }
 
+// hydrateNotificationActors sets the actors of the given notifications
+// from their actor IDs in a single query.
+// Actors that no longer exist are left out.
+func (s *Service) hydrateNotificationActors(ctx context.Context, nn []Notification) error {
+	var actorIDs []string
+	for _, n := range nn {
+		actorIDs = append(actorIDs, n.ActorIDs...)
+	}
+
+	actors := map[string]User{}
+	if len(actorIDs) != 0 {
+		query := "SELECT id, username, avatar FROM users WHERE id = ANY($1)"
+		rows, err := s.DB.QueryContext(ctx, query, pq.Array(actorIDs))
+		if err != nil {
+			return fmt.Errorf("could not sql query select notification actors: %w", err)
+		}
+
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			var u User
+			var avatar sql.NullString
+			if err := rows.Scan(&id, &u.Username, &avatar); err != nil {
+				return fmt.Errorf("could not sql scan notification actor: %w", err)
+			}
+
+			u.AvatarURL = s.avatarURL(avatar)
+			actors[id] = u
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not iterate notification actor rows: %w", err)
+		}
+	}
+
+	for i, n := range nn {
+		nn[i].Actors = make([]User, 0, len(n.ActorIDs))
+		for _, id := range n.ActorIDs {
+			if u, ok := actors[id]; ok {
+				nn[i].Actors = append(nn[i].Actors, u)
+			}
+		}
+	}
+
+	return nil
+}
+
 func notificationTopic(userID string) string { return "notification_" + userID }
@@ -0,0 +1,38 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_hydrateNotificationActors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping notification actors integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB, Logger: log.NewNopLogger()}
+
+	renamed := createTestUser(t)
+	other := createTestUser(t)
+
+	newUsername := "test_" + testutil.RandStr(t, 8)
+	_, err := testDB.ExecContext(ctx, "UPDATE users SET username = $1 WHERE id = $2", newUsername, renamed.ID)
+	testutil.WantEq(t, nil, err, "rename error")
+
+	nn := []Notification{
+		{ActorIDs: []string{renamed.ID, "00000000-0000-0000-0000-000000000000", other.ID}},
+		{},
+	}
+	err = svc.hydrateNotificationActors(ctx, nn)
+	testutil.WantEq(t, nil, err, "hydrate error")
+
+	testutil.WantEq(t, 2, len(nn[0].Actors), "actors length")
+	testutil.WantEq(t, newUsername, nn[0].Actors[0].Username, "renamed actor username")
+	testutil.WantEq(t, other.Username, nn[0].Actors[1].Username, "other actor username")
+	testutil.WantEq(t, 0, len(nn[1].Actors), "empty actors length")
+}
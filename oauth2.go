@@ -4,64 +4,87 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 )
 
+var reProviderName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+var (
+	// ErrInvalidProvider denotes an invalid OAuth provider name.
+	ErrInvalidProvider = InvalidArgumentError("invalid provider")
+	// ErrEmailNotVerified denotes that the OAuth provider
+	// did not verify the user email.
+	ErrEmailNotVerified = PermissionDeniedError("email not verified")
+)
+
 type ProvidedUser struct {
-	ID       string
-	Email    string
-	Username *string
+	ID            string
+	Email         string
+	EmailVerified bool
+	Username      *string
 }
 
-func (svc *Service) LoginFromProvider(ctx context.Context, name string, providedUser ProvidedUser) (User, error) {
+// LoginFromProvider logins or creates the user given by an OAuth provider
+// and issues a new auth token for it.
+// A user with the same email gets the identity linked automatically,
+// unless it already has a different one from the same provider.
+// Replacing it requires an explicit LinkIdentity from the logged in user.
+func (svc *Service) LoginFromProvider(ctx context.Context, name string, providedUser ProvidedUser) (AuthOutput, error) {
+	var out AuthOutput
 	var u User
 
+	if !reProviderName.MatchString(name) {
+		return out, ErrInvalidProvider
+	}
+
 	providedUser.Email = strings.ToLower(providedUser.Email)
 	if !reEmail.MatchString(providedUser.Email) {
-		return u, ErrInvalidEmail
+		return out, ErrInvalidEmail
+	}
+
+	if !providedUser.EmailVerified {
+		return out, ErrEmailNotVerified
 	}
 
 	if providedUser.Username != nil && !ValidUsername(*providedUser.Username) {
-		return u, ErrInvalidUsername
+		return out, ErrInvalidUsername
 	}
 
 	err := crdb.ExecuteTx(ctx, svc.DB, nil, func(tx *sql.Tx) error {
-		var existsWithProviderID bool
-
-		query := fmt.Sprintf(`
-			SELECT EXISTS (
-				SELECT 1 FROM users WHERE %s_provider_id = $1
-			)
-		`, name)
-		row := tx.QueryRowContext(ctx, query, providedUser.ID)
-		err := row.Scan(&existsWithProviderID)
-		if err != nil {
-			return fmt.Errorf("could not sql query user existence with provider id: %w", err)
+		query := "SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2"
+		row := tx.QueryRowContext(ctx, query, name, providedUser.ID)
+		err := row.Scan(&u.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("could not sql query select user identity: %w", err)
 		}
 
-		if !existsWithProviderID {
-			var existsWithEmail bool
-
-			query := `
-				SELECT EXISTS (
-					SELECT 1 FROM users WHERE email = $1
-				)
-			`
+		if err == sql.ErrNoRows {
+			query := "SELECT id FROM users WHERE email = $1"
 			row := tx.QueryRowContext(ctx, query, providedUser.Email)
-			err := row.Scan(&existsWithEmail)
-			if err != nil {
-				return fmt.Errorf("could not sql query user existence with provider email: %w", err)
+			err := row.Scan(&u.ID)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("could not sql query select user by provider email: %w", err)
 			}
 
-			if !existsWithEmail {
+			if err == sql.ErrNoRows {
 				if providedUser.Username == nil {
 					return ErrUserNotFound
 				}
 
-				query = fmt.Sprintf(`INSERT INTO users (email, username, %s_provider_id) VALUES ($1, $2, $3) RETURNING id`, name)
-				row = tx.QueryRowContext(ctx, query, providedUser.Email, *providedUser.Username, providedUser.ID)
+				reserved, err := usernameReserved(ctx, tx, *providedUser.Username, "")
+				if err != nil {
+					return err
+				}
+
+				if reserved {
+					return ErrUsernameTaken
+				}
+
+				query := "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id"
+				row := tx.QueryRowContext(ctx, query, providedUser.Email, *providedUser.Username)
 				err = row.Scan(&u.ID)
 				if isUniqueViolation(err) && strings.Contains(err.Error(), "username") {
 					return ErrUsernameTaken
@@ -70,30 +93,40 @@ func (svc *Service) LoginFromProvider(ctx context.Context, name string, provided
 				if err != nil {
 					return fmt.Errorf("could not sql insert provided user: %w", err)
 				}
+			}
 
-				u.Username = *providedUser.Username
-				return nil
+			query = "INSERT INTO user_identities (provider, subject, user_id) VALUES ($1, $2, $3)"
+			_, err = tx.ExecContext(ctx, query, name, providedUser.ID, u.ID)
+			if isUniqueViolation(err) {
+				return ErrProviderAlreadyLinked
 			}
 
-			query = fmt.Sprintf(`UPDATE users SET %s_provider_id = $1 WHERE email = $2`, name)
-			_, err = tx.ExecContext(ctx, query, providedUser.ID, providedUser.Email)
 			if err != nil {
-				return fmt.Errorf("could not sql update user with provider id: %w", err)
+				return fmt.Errorf("could not sql insert user identity: %w", err)
 			}
 		}
 
 		var avatar sql.NullString
-		query = fmt.Sprintf(`SELECT id, username, avatar FROM users WHERE %s_provider_id = $1`, name)
-		row = tx.QueryRowContext(ctx, query, providedUser.ID)
-		err = row.Scan(&u.ID, &u.Username, &avatar)
+		query = "SELECT username, avatar FROM users WHERE id = $1"
+		row = tx.QueryRowContext(ctx, query, u.ID)
+		err = row.Scan(&u.Username, &avatar)
 		if err != nil {
-			return fmt.Errorf("could not sql query user by provider id: %w", err)
+			return fmt.Errorf("could not sql query user by provider identity: %w", err)
 		}
 
 		u.AvatarURL = svc.avatarURL(avatar)
 
 		return nil
 	})
+	if err != nil {
+		return out, err
+	}
+
+	return svc.login(ctx, u)
+}
 
-	return u, err
+// ValidProviderName tells whether the name can be used for an OAuth provider.
+// It ends up in URL paths like /api/{name}_auth.
+func ValidProviderName(s string) bool {
+	return reProviderName.MatchString(s)
 }
@@ -0,0 +1,71 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_LoginFromProvider(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		_, err := svc.LoginFromProvider(context.Background(), "Nope!", ProvidedUser{})
+		testutil.WantEq(t, ErrInvalidProvider, err, "error")
+	})
+
+	t.Run("invalid_email", func(t *testing.T) {
+		_, err := svc.LoginFromProvider(context.Background(), "github", ProvidedUser{ID: "1", Email: "nope", EmailVerified: true})
+		testutil.WantEq(t, ErrInvalidEmail, err, "error")
+	})
+
+	t.Run("email_not_verified", func(t *testing.T) {
+		_, err := svc.LoginFromProvider(context.Background(), "github", ProvidedUser{ID: "1", Email: "user@example.org"})
+		testutil.WantEq(t, ErrEmailNotVerified, err, "error")
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		username := "@nope"
+		_, err := svc.LoginFromProvider(context.Background(), "github", ProvidedUser{ID: "1", Email: "user@example.org", EmailVerified: true, Username: &username})
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("login", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping provider login integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB, TokenKey: "supersecretkeyyoushouldnotcommit"}
+
+		subject := testutil.RandStr(t, 8)
+		email := "provided_" + subject + "@example.org"
+		_, err := svc.LoginFromProvider(ctx, "github", ProvidedUser{ID: subject, Email: email, EmailVerified: true})
+		testutil.WantEq(t, ErrUserNotFound, err, "unknown user without username error")
+
+		username := "provided_" + subject
+		out, err := svc.LoginFromProvider(ctx, "github", ProvidedUser{ID: subject, Email: email, EmailVerified: true, Username: &username})
+		testutil.WantEq(t, nil, err, "create user error")
+		testutil.WantEq(t, username, out.User.Username, "created username")
+		testutil.WantEq(t, true, out.Token != "", "token")
+
+		again, err := svc.LoginFromProvider(ctx, "github", ProvidedUser{ID: subject, Email: "changed_" + email, EmailVerified: true})
+		testutil.WantEq(t, nil, err, "login by subject error")
+		testutil.WantEq(t, out.User.ID, again.User.ID, "user ID by subject")
+
+		existing := createTestUser(t)
+		existingEmail := existing.Username + "@example.org"
+		linked, err := svc.LoginFromProvider(ctx, "github", ProvidedUser{ID: "a_" + subject, Email: existingEmail, EmailVerified: true})
+		testutil.WantEq(t, nil, err, "link by email error")
+		testutil.WantEq(t, existing.ID, linked.User.ID, "user ID linked by email")
+
+		_, err = svc.LoginFromProvider(ctx, "github", ProvidedUser{ID: "b_" + subject, Email: existingEmail, EmailVerified: true})
+		testutil.WantEq(t, ErrProviderAlreadyLinked, err, "other subject with same email error")
+
+		var linkedSubject string
+		err = testDB.QueryRowContext(ctx, "SELECT subject FROM user_identities WHERE user_id = $1 AND provider = 'github'", existing.ID).Scan(&linkedSubject)
+		testutil.WantEq(t, nil, err, "select linked subject error")
+		testutil.WantEq(t, "a_"+subject, linkedSubject, "linked subject untouched")
+	})
+}
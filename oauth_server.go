@@ -0,0 +1,734 @@
+package nakama
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/lib/pq"
+)
+
+const (
+	oauthAccessTokenPrefix = "nkoat_"
+	oauthAccessTokenTTL    = time.Hour * 24 * 30
+	oauthCodeTTL           = time.Minute * 10
+	oauthSecretLen         = 32
+	maxOAuthRedirectURIs   = 10
+)
+
+var (
+	reCodeChallenge = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+	reCodeVerifier  = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+)
+
+var (
+	// ErrInvalidOAuthAppID denotes an invalid OAuth app ID; that is not uuid.
+	// The app ID is the client ID.
+	ErrInvalidOAuthAppID = InvalidArgumentError("invalid OAuth app ID")
+	// ErrInvalidOAuthAppName denotes an invalid OAuth app name.
+	ErrInvalidOAuthAppName = InvalidArgumentError("invalid OAuth app name")
+	// ErrOAuthAppNotFound denotes a not found OAuth app.
+	ErrOAuthAppNotFound = NotFoundError("OAuth app not found")
+	// ErrRedirectURIMismatch denotes a redirect URI
+	// not registered by the OAuth app.
+	ErrRedirectURIMismatch = InvalidArgumentError("redirect URI mismatch")
+	// ErrUnsupportedResponseType denotes an authorization request
+	// with a response type other than "code".
+	ErrUnsupportedResponseType = InvalidArgumentError("unsupported response type")
+	// ErrInvalidCodeChallenge denotes a missing or invalid PKCE code challenge.
+	// Only the S256 method is supported.
+	ErrInvalidCodeChallenge = InvalidArgumentError("invalid code challenge")
+	// ErrInvalidAuthorizationCode denotes an unknown, expired or already used
+	// authorization code, or one that does not match the code verifier.
+	ErrInvalidAuthorizationCode = InvalidArgumentError("invalid authorization code")
+	// ErrInvalidClientSecret denotes a client secret that does not belong to the OAuth app.
+	ErrInvalidClientSecret = UnauthenticatedError("invalid client secret")
+)
+
+// OAuthApp is a third-party app registered by a user
+// that can act on behalf of other users with their consent.
+// ID is the OAuth client ID.
+type OAuthApp struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirectURIs"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type CreateOAuthApp struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirectURIs"`
+	Scopes       []string `json:"scopes"`
+}
+
+// CreatedOAuthApp response.
+// ClientSecret is only given back once, at creation time.
+// Apps that cannot keep it secret can rely on PKCE alone.
+type CreatedOAuthApp struct {
+	OAuthApp
+	ClientSecret string `json:"clientSecret"`
+}
+
+// OAuthAuthorizationRequest as sent by the app to the authorization endpoint.
+// Scope is space separated, defaults to all the scopes of the app.
+type OAuthAuthorizationRequest struct {
+	ResponseType        string `json:"responseType"`
+	ClientID            string `json:"clientID"`
+	RedirectURI         string `json:"redirectURI"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"codeChallenge"`
+	CodeChallengeMethod string `json:"codeChallengeMethod"`
+}
+
+// OAuthAuthorization is a validated authorization request
+// to show to the user for consent.
+type OAuthAuthorization struct {
+	AppID         string   `json:"appID"`
+	AppName       string   `json:"appName"`
+	OwnerUsername string   `json:"ownerUsername"`
+	Scopes        []string `json:"scopes"`
+	RedirectURI   *url.URL `json:"-"`
+	// redirectURIGiven tells whether the request included the redirect URI,
+	// as then the token request must include it too.
+	redirectURIGiven bool
+}
+
+type ExchangeOAuthCode struct {
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+}
+
+// OAuthToken response from the token endpoint.
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// OAuthTokenIntrospection response.
+// Only Active is set for inactive tokens.
+type OAuthTokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+// AuthorizedOAuthApp is an app the authenticated user granted access to.
+type AuthorizedOAuthApp struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	AuthorizedAt time.Time `json:"authorizedAt"`
+}
+
+// CreateOAuthApp registers a new OAuth app owned by the authenticated user.
+func (s *Service) CreateOAuthApp(ctx context.Context, in CreateOAuthApp) (CreatedOAuthApp, error) {
+	var out CreatedOAuthApp
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > 64 {
+		return out, ErrInvalidOAuthAppName
+	}
+
+	if len(in.RedirectURIs) == 0 || len(in.RedirectURIs) > maxOAuthRedirectURIs {
+		return out, ErrInvalidRedirectURI
+	}
+
+	for _, uri := range in.RedirectURIs {
+		if !validOAuthRedirectURI(uri) {
+			return out, ErrInvalidRedirectURI
+		}
+	}
+
+	if len(in.Scopes) == 0 {
+		return out, ErrInvalidScope
+	}
+
+	for _, scope := range in.Scopes {
+		if !ValidScope(scope) {
+			return out, ErrInvalidScope
+		}
+	}
+
+	in.Scopes = slices.Clone(in.Scopes)
+	slices.Sort(in.Scopes)
+	in.Scopes = slices.Compact(in.Scopes)
+
+	secret, secretHash, err := genOAuthSecret()
+	if err != nil {
+		return out, err
+	}
+
+	query := `
+		INSERT INTO oauth_apps (user_id, name, redirect_uris, scopes, client_secret_hash)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	row := s.DB.QueryRowContext(ctx, query, uid, in.Name, pq.Array(in.RedirectURIs), pq.Array(in.Scopes), secretHash)
+	err = row.Scan(&out.ID, &out.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return out, ErrUserGone
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql insert oauth app: %w", err)
+	}
+
+	out.Name = in.Name
+	out.RedirectURIs = in.RedirectURIs
+	out.Scopes = in.Scopes
+	out.ClientSecret = secret
+
+	return out, nil
+}
+
+// OAuthApps owned by the authenticated user.
+// Newest first.
+func (s *Service) OAuthApps(ctx context.Context) ([]OAuthApp, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT id, name, redirect_uris, scopes, created_at
+		FROM oauth_apps
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select oauth apps: %w", err)
+	}
+
+	defer rows.Close()
+
+	var aa []OAuthApp
+	for rows.Next() {
+		var a OAuthApp
+		err := rows.Scan(&a.ID, &a.Name, pq.Array(&a.RedirectURIs), pq.Array(&a.Scopes), &a.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan oauth app: %w", err)
+		}
+
+		aa = append(aa, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate oauth app rows: %w", err)
+	}
+
+	return aa, nil
+}
+
+// DeleteOAuthApp owned by the authenticated user.
+// Every token issued to the app stops working right away.
+func (s *Service) DeleteOAuthApp(ctx context.Context, appID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(appID) {
+		return ErrInvalidOAuthAppID
+	}
+
+	query := "DELETE FROM oauth_apps WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, appID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete oauth app: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted oauth app rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrOAuthAppNotFound
+	}
+
+	return nil
+}
+
+// OAuthAuthorization validates an authorization request
+// so it can be shown to the user for consent.
+// Errors other than ErrInvalidOAuthAppID, ErrOAuthAppNotFound,
+// ErrInvalidRedirectURI and ErrRedirectURIMismatch
+// can be reported back to the app using the returned redirect URI.
+func (s *Service) OAuthAuthorization(ctx context.Context, in OAuthAuthorizationRequest) (OAuthAuthorization, error) {
+	var out OAuthAuthorization
+
+	if !reUUID.MatchString(in.ClientID) {
+		return out, ErrInvalidOAuthAppID
+	}
+
+	var redirectURIs, appScopes []string
+	query := `
+		SELECT oauth_apps.name, oauth_apps.redirect_uris, oauth_apps.scopes, users.username
+		FROM oauth_apps
+		INNER JOIN users ON oauth_apps.user_id = users.id
+		WHERE oauth_apps.id = $1`
+	row := s.DB.QueryRowContext(ctx, query, in.ClientID)
+	err := row.Scan(&out.AppName, pq.Array(&redirectURIs), pq.Array(&appScopes), &out.OwnerUsername)
+	if err == sql.ErrNoRows {
+		return out, ErrOAuthAppNotFound
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql query select oauth app: %w", err)
+	}
+
+	out.AppID = in.ClientID
+	out.redirectURIGiven = in.RedirectURI != ""
+
+	if in.RedirectURI == "" && len(redirectURIs) == 1 {
+		in.RedirectURI = redirectURIs[0]
+	}
+
+	if !slices.Contains(redirectURIs, in.RedirectURI) {
+		return out, ErrRedirectURIMismatch
+	}
+
+	out.RedirectURI, err = url.Parse(in.RedirectURI)
+	if err != nil {
+		return out, ErrInvalidRedirectURI
+	}
+
+	if in.ResponseType != "code" {
+		return out, ErrUnsupportedResponseType
+	}
+
+	if in.CodeChallengeMethod != "S256" || !reCodeChallenge.MatchString(in.CodeChallenge) {
+		return out, ErrInvalidCodeChallenge
+	}
+
+	out.Scopes = strings.Fields(in.Scope)
+	if len(out.Scopes) == 0 {
+		out.Scopes = appScopes
+	}
+
+	for _, scope := range out.Scopes {
+		if !slices.Contains(appScopes, scope) {
+			return out, ErrInvalidScope
+		}
+	}
+
+	slices.Sort(out.Scopes)
+	out.Scopes = slices.Compact(out.Scopes)
+
+	return out, nil
+}
+
+// AuthorizeOAuthApp grants the app access to the authenticated user
+// with the requested scopes.
+// It returns the URI to redirect the user back to the app,
+// with the authorization code and state in the query.
+func (s *Service) AuthorizeOAuthApp(ctx context.Context, in OAuthAuthorizationRequest) (*url.URL, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	authz, err := s.OAuthAuthorization(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	code, codeHash, err := genOAuthSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO oauth_authorization_codes (
+			code_hash
+			, app_id
+			, user_id
+			, redirect_uri
+			, redirect_uri_given
+			, scopes
+			, code_challenge
+			, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = s.DB.ExecContext(ctx, query,
+		codeHash,
+		authz.AppID,
+		uid,
+		authz.RedirectURI.String(),
+		authz.redirectURIGiven,
+		pq.Array(authz.Scopes),
+		in.CodeChallenge,
+		time.Now().Add(oauthCodeTTL),
+	)
+	if isForeignKeyViolation(err) {
+		return nil, ErrUserGone
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not sql insert oauth authorization code: %w", err)
+	}
+
+	redirectURI := *authz.RedirectURI
+	q := redirectURI.Query()
+	q.Set("code", code)
+	if in.State != "" {
+		q.Set("state", in.State)
+	}
+	redirectURI.RawQuery = q.Encode()
+
+	return &redirectURI, nil
+}
+
+// ExchangeOAuthCode for an access token.
+// The code verifier must match the code challenge from the authorization request.
+// So must the redirect URI, when the authorization request included it.
+// The client secret is optional for apps that cannot keep it secret,
+// but it gets checked when given.
+func (s *Service) ExchangeOAuthCode(ctx context.Context, in ExchangeOAuthCode) (OAuthToken, error) {
+	var out OAuthToken
+
+	if !reUUID.MatchString(in.ClientID) {
+		return out, ErrInvalidOAuthAppID
+	}
+
+	codeHash, ok := hashOAuthSecret(in.Code)
+	if !ok || !reCodeVerifier.MatchString(in.CodeVerifier) {
+		return out, ErrInvalidAuthorizationCode
+	}
+
+	var token string
+	var scopes []string
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var secretHash []byte
+		query := "SELECT client_secret_hash FROM oauth_apps WHERE id = $1"
+		row := tx.QueryRowContext(ctx, query, in.ClientID)
+		err := row.Scan(&secretHash)
+		if err == sql.ErrNoRows {
+			return ErrOAuthAppNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select oauth app client secret: %w", err)
+		}
+
+		if in.ClientSecret != "" {
+			hash, ok := hashOAuthSecret(in.ClientSecret)
+			if !ok || subtle.ConstantTimeCompare(hash, secretHash) != 1 {
+				return ErrInvalidClientSecret
+			}
+		}
+
+		var userID, redirectURI, codeChallenge string
+		var redirectURIGiven bool
+		var expiresAt time.Time
+		query = `
+			DELETE FROM oauth_authorization_codes
+			WHERE code_hash = $1 AND app_id = $2
+			RETURNING user_id, redirect_uri, redirect_uri_given, scopes, code_challenge, expires_at`
+		row = tx.QueryRowContext(ctx, query, codeHash, in.ClientID)
+		err = row.Scan(&userID, &redirectURI, &redirectURIGiven, pq.Array(&scopes), &codeChallenge, &expiresAt)
+		if err == sql.ErrNoRows {
+			return ErrInvalidAuthorizationCode
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql delete oauth authorization code: %w", err)
+		}
+
+		if !expiresAt.After(time.Now()) {
+			return ErrInvalidAuthorizationCode
+		}
+
+		// the redirect URI must be the same as in the authorization request,
+		// and it is required if it was given there.
+		if (redirectURIGiven || in.RedirectURI != "") && in.RedirectURI != redirectURI {
+			return ErrRedirectURIMismatch
+		}
+
+		challenge := sha256.Sum256([]byte(in.CodeVerifier))
+		if base64.RawURLEncoding.EncodeToString(challenge[:]) != codeChallenge {
+			return ErrInvalidAuthorizationCode
+		}
+
+		var tokenHash []byte
+		token, tokenHash, err = genOAuthSecret()
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO oauth_access_tokens (token_hash, app_id, user_id, scopes, expires_at)
+			VALUES ($1, $2, $3, $4, $5)`
+		_, err = tx.ExecContext(ctx, query, tokenHash, in.ClientID, userID, pq.Array(scopes), time.Now().Add(oauthAccessTokenTTL))
+		if err != nil {
+			return fmt.Errorf("could not sql insert oauth access token: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	out.AccessToken = oauthAccessTokenPrefix + token
+	out.TokenType = "Bearer"
+	out.ExpiresIn = int64(oauthAccessTokenTTL.Seconds())
+	out.Scope = strings.Join(scopes, " ")
+
+	return out, nil
+}
+
+// IntrospectOAuthToken tells whether the given access token is active.
+// Apps must authenticate with their client secret
+// and can only introspect the tokens issued to them.
+func (s *Service) IntrospectOAuthToken(ctx context.Context, clientID, clientSecret, token string) (OAuthTokenIntrospection, error) {
+	var out OAuthTokenIntrospection
+
+	if !reUUID.MatchString(clientID) {
+		return out, ErrInvalidOAuthAppID
+	}
+
+	secretHash, ok := hashOAuthSecret(clientSecret)
+	if !ok {
+		return out, ErrInvalidClientSecret
+	}
+
+	var storedSecretHash []byte
+	query := "SELECT client_secret_hash FROM oauth_apps WHERE id = $1"
+	row := s.DB.QueryRowContext(ctx, query, clientID)
+	err := row.Scan(&storedSecretHash)
+	if err == sql.ErrNoRows {
+		return out, ErrOAuthAppNotFound
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql query select oauth app client secret: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare(secretHash, storedSecretHash) != 1 {
+		return out, ErrInvalidClientSecret
+	}
+
+	if !isOAuthAccessToken(token) {
+		return out, nil
+	}
+
+	tokenHash, ok := hashOAuthSecret(strings.TrimPrefix(token, oauthAccessTokenPrefix))
+	if !ok {
+		return out, nil
+	}
+
+	var scopes []string
+	var expiresAt, createdAt time.Time
+	query = `
+		SELECT users.id, users.username, oauth_access_tokens.scopes, oauth_access_tokens.expires_at, oauth_access_tokens.created_at
+		FROM oauth_access_tokens
+		INNER JOIN users ON oauth_access_tokens.user_id = users.id
+		WHERE oauth_access_tokens.token_hash = $1 AND oauth_access_tokens.app_id = $2`
+	row = s.DB.QueryRowContext(ctx, query, tokenHash, clientID)
+	err = row.Scan(&out.Subject, &out.Username, pq.Array(&scopes), &expiresAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return out, nil
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql query select oauth access token: %w", err)
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return OAuthTokenIntrospection{}, nil
+	}
+
+	out.Active = true
+	out.Scope = strings.Join(scopes, " ")
+	out.ClientID = clientID
+	out.TokenType = "Bearer"
+	out.ExpiresAt = expiresAt.Unix()
+	out.IssuedAt = createdAt.Unix()
+
+	return out, nil
+}
+
+// AuthorizedOAuthApps the authenticated user granted access to,
+// and that still hold an active access token.
+// Most recently authorized first.
+func (s *Service) AuthorizedOAuthApps(ctx context.Context) ([]AuthorizedOAuthApp, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT oauth_apps.id, oauth_apps.name, max(oauth_access_tokens.created_at) AS authorized_at
+		FROM oauth_access_tokens
+		INNER JOIN oauth_apps ON oauth_access_tokens.app_id = oauth_apps.id
+		WHERE oauth_access_tokens.user_id = $1 AND oauth_access_tokens.expires_at > now()
+		GROUP BY oauth_apps.id, oauth_apps.name
+		ORDER BY authorized_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select authorized oauth apps: %w", err)
+	}
+
+	defer rows.Close()
+
+	var aa []AuthorizedOAuthApp
+	for rows.Next() {
+		var a AuthorizedOAuthApp
+		if err := rows.Scan(&a.ID, &a.Name, &a.AuthorizedAt); err != nil {
+			return nil, fmt.Errorf("could not sql scan authorized oauth app: %w", err)
+		}
+
+		aa = append(aa, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate authorized oauth app rows: %w", err)
+	}
+
+	return aa, nil
+}
+
+// RevokeOAuthApp access to the authenticated user.
+// Every token issued to the app for the user stops working right away.
+func (s *Service) RevokeOAuthApp(ctx context.Context, appID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(appID) {
+		return ErrInvalidOAuthAppID
+	}
+
+	query := "DELETE FROM oauth_access_tokens WHERE app_id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, appID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete oauth access tokens: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted oauth access tokens rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrOAuthAppNotFound
+	}
+
+	return nil
+}
+
+// isOAuthAccessToken tells apart access tokens issued to OAuth apps
+// from session access tokens.
+func isOAuthAccessToken(token string) bool {
+	return strings.HasPrefix(token, oauthAccessTokenPrefix)
+}
+
+// oauthAccessTokenAuth checks the given access token issued to an OAuth app.
+func (s *Service) oauthAccessTokenAuth(ctx context.Context, token string) (Auth, error) {
+	var auth Auth
+	hash, ok := hashOAuthSecret(strings.TrimPrefix(token, oauthAccessTokenPrefix))
+	if !ok {
+		return auth, ErrInvalidToken
+	}
+
+	var expiresAt time.Time
+	var suspended bool
+	query := `
+		SELECT oauth_access_tokens.user_id, oauth_access_tokens.scopes, oauth_access_tokens.expires_at, ` + userSuspended + `
+		FROM oauth_access_tokens
+		INNER JOIN users ON oauth_access_tokens.user_id = users.id
+		WHERE oauth_access_tokens.token_hash = $1`
+	row := s.DB.QueryRowContext(ctx, query, hash)
+	err := row.Scan(&auth.UserID, pq.Array(&auth.Scopes), &expiresAt, &suspended)
+	if err == sql.ErrNoRows {
+		return auth, ErrInvalidToken
+	}
+
+	if err != nil {
+		return auth, fmt.Errorf("could not sql query select oauth access token: %w", err)
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return auth, ErrExpiredToken
+	}
+
+	if suspended {
+		return auth, ErrUserSuspended
+	}
+
+	if auth.Scopes == nil {
+		auth.Scopes = []string{}
+	}
+
+	return auth, nil
+}
+
+// validOAuthRedirectURI accepts absolute URIs without fragment.
+// Plain http is only allowed for loopback redirects of native apps,
+// which can also use private-use schemes like "com.example.app:/callback".
+func validOAuthRedirectURI(rawurl string) bool {
+	uri, err := url.Parse(rawurl)
+	if err != nil || !uri.IsAbs() || uri.Fragment != "" {
+		return false
+	}
+
+	switch uri.Scheme {
+	case "https":
+		return uri.Host != ""
+	case "http":
+		host := uri.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+
+	// private-use schemes are reverse domain names.
+	return strings.Contains(uri.Scheme, ".")
+}
+
+// genOAuthSecret generates a random secret used for client secrets,
+// authorization codes and access tokens.
+// Only its hash is stored.
+func genOAuthSecret() (string, []byte, error) {
+	b := make([]byte, oauthSecretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("could not generate oauth secret: %w", err)
+	}
+
+	hash := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(b), hash[:], nil
+}
+
+func hashOAuthSecret(secret string) ([]byte, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(secret)
+	if err != nil || len(b) != oauthSecretLen {
+		return nil, false
+	}
+
+	hash := sha256.Sum256(b)
+	return hash[:], true
+}
@@ -0,0 +1,96 @@
+package nakama
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func Test_validOAuthRedirectURI(t *testing.T) {
+	tt := []struct {
+		name string
+		uri  string
+		want bool
+	}{
+		{name: "https", uri: "https://example.org/callback", want: true},
+		{name: "http_loopback", uri: "http://127.0.0.1:8080/callback", want: true},
+		{name: "http_localhost", uri: "http://localhost/callback", want: true},
+		{name: "http_remote", uri: "http://example.org/callback", want: false},
+		{name: "private_use_scheme", uri: "org.example.app:/callback", want: true},
+		{name: "javascript", uri: "javascript:alert(1)", want: false},
+		{name: "relative", uri: "/callback", want: false},
+		{name: "fragment", uri: "https://example.org/callback#nope", want: false},
+		{name: "https_without_host", uri: "https:/callback", want: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testutil.WantEq(t, tc.want, validOAuthRedirectURI(tc.uri), "valid")
+		})
+	}
+}
+
+func TestService_ExchangeOAuthCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping oauth integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	owner := createTestUser(t)
+	user := createTestUser(t)
+	userCtx := context.WithValue(ctx, KeyAuthUserID, user.ID)
+
+	const redirectURI = "https://example.org/callback"
+	app, err := svc.CreateOAuthApp(context.WithValue(ctx, KeyAuthUserID, owner.ID), CreateOAuthApp{
+		Name:         "Bot",
+		RedirectURIs: []string{redirectURI},
+		Scopes:       []string{ScopePostsWrite},
+	})
+	testutil.WantEq(t, nil, err, "create oauth app error")
+
+	verifier := strings.Repeat("v", 43)
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authorize := func(redirectURI string) string {
+		t.Helper()
+
+		uri, err := svc.AuthorizeOAuthApp(userCtx, OAuthAuthorizationRequest{
+			ResponseType:        "code",
+			ClientID:            app.ID,
+			RedirectURI:         redirectURI,
+			CodeChallenge:       base64.RawURLEncoding.EncodeToString(challenge[:]),
+			CodeChallengeMethod: "S256",
+		})
+		testutil.WantEq(t, nil, err, "authorize oauth app error")
+		return uri.Query().Get("code")
+	}
+
+	exchange := func(code, redirectURI string) error {
+		t.Helper()
+
+		_, err := svc.ExchangeOAuthCode(ctx, ExchangeOAuthCode{
+			ClientID:     app.ID,
+			Code:         code,
+			RedirectURI:  redirectURI,
+			CodeVerifier: verifier,
+		})
+		return err
+	}
+
+	t.Run("redirect_uri_given", func(t *testing.T) {
+		code := authorize(redirectURI)
+		testutil.WantEq(t, ErrRedirectURIMismatch, exchange(code, ""), "missing redirect uri error")
+		testutil.WantEq(t, ErrRedirectURIMismatch, exchange(code, "https://example.org/other"), "other redirect uri error")
+		testutil.WantEq(t, nil, exchange(code, redirectURI), "exchange error")
+	})
+
+	t.Run("redirect_uri_omitted", func(t *testing.T) {
+		code := authorize("")
+		testutil.WantEq(t, nil, exchange(code, ""), "exchange error")
+	})
+}
@@ -0,0 +1,510 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	passkeyCeremonyTTL   = time.Minute * 5
+	passkeyNameMaxLength = 64
+)
+
+var (
+	// ErrInvalidPasskey denotes a passkey credential that could not be verified.
+	ErrInvalidPasskey = InvalidArgumentError("invalid passkey")
+	// ErrInvalidPasskeyID denotes an invalid passkey ID; that is not uuid.
+	ErrInvalidPasskeyID = InvalidArgumentError("invalid passkey ID")
+	// ErrInvalidPasskeyName denotes an invalid passkey name.
+	ErrInvalidPasskeyName = InvalidArgumentError("invalid passkey name")
+	// ErrInvalidPasskeyCeremonyID denotes an invalid passkey ceremony ID; that is not uuid.
+	ErrInvalidPasskeyCeremonyID = InvalidArgumentError("invalid passkey ceremony ID")
+	// ErrPasskeyCeremonyNotFound denotes a not found passkey ceremony.
+	// Either it never existed, it was already finished or it expired.
+	ErrPasskeyCeremonyNotFound = NotFoundError("passkey ceremony not found")
+	// ErrPasskeyNotFound denotes a not found passkey.
+	ErrPasskeyNotFound = NotFoundError("passkey not found")
+	// ErrPasskeyTaken denotes a passkey already registered.
+	ErrPasskeyTaken = AlreadyExistsError("passkey taken")
+)
+
+// Passkey model.
+type Passkey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// PasskeyCeremony is the first step of a passkey registration or login.
+// Options must be passed to the browser WebAuthn API,
+// and ID sent back along with the resulting credential.
+type PasskeyCeremony struct {
+	ID      string          `json:"id"`
+	Options json.RawMessage `json:"options"`
+}
+
+// FinishPasskeyRegistration input.
+type FinishPasskeyRegistration struct {
+	CeremonyID string          `json:"ceremonyID"`
+	Name       *string         `json:"name"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// FinishPasskeyLogin input.
+type FinishPasskeyLogin struct {
+	CeremonyID string          `json:"ceremonyID"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// passkeyUser implements webauthn.User.
+// The user handle is the user ID so it can be used to find the user
+// back on discoverable logins.
+type passkeyUser struct {
+	id          string
+	username    string
+	credentials []webauthn.Credential
+}
+
+func (u passkeyUser) WebAuthnID() []byte                         { return []byte(u.id) }
+func (u passkeyUser) WebAuthnName() string                       { return u.username }
+func (u passkeyUser) WebAuthnDisplayName() string                { return u.username }
+func (u passkeyUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+// BeginPasskeyRegistration starts the registration of a new passkey
+// for the authenticated user.
+func (s *Service) BeginPasskeyRegistration(ctx context.Context) (PasskeyCeremony, error) {
+	var out PasskeyCeremony
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		return out, err
+	}
+
+	u, err := s.passkeyUser(ctx, uid)
+	if err != nil {
+		return out, err
+	}
+
+	creation, session, err := wa.BeginRegistration(u,
+		webauthn.WithExclusions(webauthn.Credentials(u.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		return out, fmt.Errorf("could not begin passkey registration: %w", err)
+	}
+
+	return s.createPasskeyCeremony(ctx, &uid, creation, session)
+}
+
+// FinishPasskeyRegistration verifies the credential created by the authenticator
+// and stores it as a new passkey for the authenticated user.
+func (s *Service) FinishPasskeyRegistration(ctx context.Context, in FinishPasskeyRegistration) (Passkey, error) {
+	var out Passkey
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(in.CeremonyID) {
+		return out, ErrInvalidPasskeyCeremonyID
+	}
+
+	if in.Name != nil {
+		*in.Name = strings.TrimSpace(*in.Name)
+		if *in.Name == "" || utf8.RuneCountInString(*in.Name) > passkeyNameMaxLength {
+			return out, ErrInvalidPasskeyName
+		}
+
+		out.Name = *in.Name
+	} else if ua, ok := ctx.Value(KeyUserAgent).(string); ok && deviceFromUserAgent(ua) != nil {
+		out.Name = *deviceFromUserAgent(ua)
+	} else {
+		out.Name = "Passkey"
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(in.Credential)
+	if err != nil {
+		return out, ErrInvalidPasskey
+	}
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		return out, err
+	}
+
+	session, err := s.takePasskeyCeremony(ctx, in.CeremonyID, &uid)
+	if err != nil {
+		return out, err
+	}
+
+	u, err := s.passkeyUser(ctx, uid)
+	if err != nil {
+		return out, err
+	}
+
+	cred, err := wa.CreateCredential(u, session, parsed)
+	if err != nil {
+		return out, ErrInvalidPasskey
+	}
+
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return out, fmt.Errorf("could not json marshal passkey credential: %w", err)
+	}
+
+	query := `
+		INSERT INTO passkeys (user_id, credential_id, name, credential) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	row := s.DB.QueryRowContext(ctx, query, uid, cred.ID, out.Name, data)
+	err = row.Scan(&out.ID, &out.CreatedAt)
+	if isUniqueViolation(err) {
+		return out, ErrPasskeyTaken
+	}
+
+	if isForeignKeyViolation(err) {
+		return out, ErrUserGone
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql insert passkey: %w", err)
+	}
+
+	return out, nil
+}
+
+// BeginPasskeyLogin starts a login with any passkey
+// the authenticator has for this site.
+func (s *Service) BeginPasskeyLogin(ctx context.Context) (PasskeyCeremony, error) {
+	var out PasskeyCeremony
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		return out, err
+	}
+
+	assertion, session, err := wa.BeginDiscoverableLogin()
+	if err != nil {
+		return out, fmt.Errorf("could not begin passkey login: %w", err)
+	}
+
+	return s.createPasskeyCeremony(ctx, nil, assertion, session)
+}
+
+// FinishPasskeyLogin verifies the assertion signed by the authenticator
+// and logs in the user the passkey belongs to.
+// Passkeys don't count as a second factor, since user verification
+// is only preferred; so users with two-factor authentication enabled
+// still get a second factor challenge.
+func (s *Service) FinishPasskeyLogin(ctx context.Context, in FinishPasskeyLogin) (AuthOutput, error) {
+	var out AuthOutput
+
+	if !reUUID.MatchString(in.CeremonyID) {
+		return out, ErrInvalidPasskeyCeremonyID
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(in.Credential)
+	if err != nil {
+		return out, ErrInvalidPasskey
+	}
+
+	wa, err := s.webAuthn()
+	if err != nil {
+		return out, err
+	}
+
+	session, err := s.takePasskeyCeremony(ctx, in.CeremonyID, nil)
+	if err != nil {
+		return out, err
+	}
+
+	var uid string
+	_, cred, err := wa.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		uid = string(userHandle)
+		if !reUUID.MatchString(uid) {
+			return nil, ErrPasskeyNotFound
+		}
+
+		return s.passkeyUser(ctx, uid)
+	}, session, parsed)
+	if err != nil {
+		return out, ErrInvalidPasskey
+	}
+
+	// the sign counter going backwards means the authenticator could have been cloned.
+	if cred.Authenticator.CloneWarning {
+		return out, ErrInvalidPasskey
+	}
+
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return out, fmt.Errorf("could not json marshal passkey credential: %w", err)
+	}
+
+	query := `
+		UPDATE passkeys SET credential = $1, last_used_at = now()
+		WHERE user_id = $2 AND credential_id = $3`
+	_, err = s.DB.ExecContext(ctx, query, data, uid, cred.ID)
+	if err != nil {
+		return out, fmt.Errorf("could not sql update passkey usage: %w", err)
+	}
+
+	u, err := s.userByID(ctx, uid)
+	if err != nil {
+		return out, err
+	}
+
+	return s.login(ctx, u)
+}
+
+// Passkeys registered by the authenticated user.
+// Most recently created first.
+func (s *Service) Passkeys(ctx context.Context) ([]Passkey, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT id, name, created_at, last_used_at
+		FROM passkeys
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select passkeys: %w", err)
+	}
+
+	defer rows.Close()
+
+	var pp []Passkey
+	for rows.Next() {
+		var p Passkey
+		err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt, &p.LastUsedAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan passkey: %w", err)
+		}
+
+		pp = append(pp, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate passkey rows: %w", err)
+	}
+
+	return pp, nil
+}
+
+// RenamePasskey from the authenticated user.
+func (s *Service) RenamePasskey(ctx context.Context, passkeyID, name string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(passkeyID) {
+		return ErrInvalidPasskeyID
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > passkeyNameMaxLength {
+		return ErrInvalidPasskeyName
+	}
+
+	query := "UPDATE passkeys SET name = $1 WHERE id = $2 AND user_id = $3"
+	res, err := s.DB.ExecContext(ctx, query, name, passkeyID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql update passkey name: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get updated passkey rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+// DeletePasskey from the authenticated user.
+// It cannot be used to login anymore.
+func (s *Service) DeletePasskey(ctx context.Context, passkeyID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(passkeyID) {
+		return ErrInvalidPasskeyID
+	}
+
+	query := "DELETE FROM passkeys WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, passkeyID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete passkey: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted passkey rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+// passkeyUser loads the given user along with all its passkey credentials.
+func (s *Service) passkeyUser(ctx context.Context, userID string) (passkeyUser, error) {
+	u := passkeyUser{id: userID}
+	err := crdb.ExecuteTx(ctx, s.DB, &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		query := "SELECT username FROM users WHERE id = $1"
+		err := tx.QueryRowContext(ctx, query, userID).Scan(&u.username)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select passkey user: %w", err)
+		}
+
+		query = "SELECT credential FROM passkeys WHERE user_id = $1"
+		rows, err := tx.QueryContext(ctx, query, userID)
+		if err != nil {
+			return fmt.Errorf("could not sql query select passkey credentials: %w", err)
+		}
+
+		defer rows.Close()
+
+		u.credentials = nil
+		for rows.Next() {
+			var data []byte
+			if err := rows.Scan(&data); err != nil {
+				return fmt.Errorf("could not sql scan passkey credential: %w", err)
+			}
+
+			var cred webauthn.Credential
+			if err := json.Unmarshal(data, &cred); err != nil {
+				return fmt.Errorf("could not json unmarshal passkey credential: %w", err)
+			}
+
+			u.credentials = append(u.credentials, cred)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not iterate passkey credential rows: %w", err)
+		}
+
+		return nil
+	})
+	return u, err
+}
+
+// createPasskeyCeremony stores the webauthn session data
+// until the ceremony gets finished.
+func (s *Service) createPasskeyCeremony(ctx context.Context, userID *string, options interface{}, session *webauthn.SessionData) (PasskeyCeremony, error) {
+	var out PasskeyCeremony
+
+	var err error
+	out.Options, err = json.Marshal(options)
+	if err != nil {
+		return out, fmt.Errorf("could not json marshal passkey ceremony options: %w", err)
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return out, fmt.Errorf("could not json marshal passkey ceremony session: %w", err)
+	}
+
+	query := "INSERT INTO passkey_ceremonies (user_id, session) VALUES ($1, $2) RETURNING id"
+	err = s.DB.QueryRowContext(ctx, query, userID, data).Scan(&out.ID)
+	if isForeignKeyViolation(err) {
+		return out, ErrUserGone
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql insert passkey ceremony: %w", err)
+	}
+
+	return out, nil
+}
+
+// takePasskeyCeremony deletes the given ceremony so it cannot be used twice
+// and returns its webauthn session data.
+// Registration ceremonies are only available to the user that started them.
+func (s *Service) takePasskeyCeremony(ctx context.Context, ceremonyID string, userID *string) (webauthn.SessionData, error) {
+	var session webauthn.SessionData
+	var data []byte
+	var createdAt time.Time
+	query := `
+		DELETE FROM passkey_ceremonies
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2
+		RETURNING session, created_at`
+	err := s.DB.QueryRowContext(ctx, query, ceremonyID, userID).Scan(&data, &createdAt)
+	if err == sql.ErrNoRows {
+		return session, ErrPasskeyCeremonyNotFound
+	}
+
+	if err != nil {
+		return session, fmt.Errorf("could not sql delete passkey ceremony: %w", err)
+	}
+
+	if time.Since(createdAt) >= passkeyCeremonyTTL {
+		return session, ErrPasskeyCeremonyNotFound
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("could not json unmarshal passkey ceremony session: %w", err)
+	}
+
+	return session, nil
+}
+
+// webAuthn relying party configured out of the service origin.
+func (s *Service) webAuthn() (*webauthn.WebAuthn, error) {
+	s.webAuthnOncer.Do(func() {
+		origins := []string{s.Origin.Scheme + "://" + s.Origin.Host}
+		for _, origin := range s.AllowedOrigins {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				origins = append(origins, origin)
+			}
+		}
+
+		timeout := webauthn.TimeoutConfig{
+			Enforce:    true,
+			Timeout:    passkeyCeremonyTTL,
+			TimeoutUVD: passkeyCeremonyTTL,
+		}
+		s.webAuthnRP, s.webAuthnErr = webauthn.New(&webauthn.Config{
+			RPID:          s.Origin.Hostname(),
+			RPDisplayName: "Nakama",
+			RPOrigins:     origins,
+			Timeouts: webauthn.TimeoutsConfig{
+				Login:        timeout,
+				Registration: timeout,
+			},
+		})
+		if s.webAuthnErr != nil {
+			s.webAuthnErr = fmt.Errorf("could not create webauthn relying party: %w", s.webAuthnErr)
+		}
+	})
+	return s.webAuthnRP, s.webAuthnErr
+}
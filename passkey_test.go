@@ -0,0 +1,318 @@
+package nakama
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_webAuthn(t *testing.T) {
+	svc := &Service{Origin: &url.URL{Scheme: "http", Host: "localhost:3000"}}
+	wa, err := svc.webAuthn()
+	testutil.WantEq(t, nil, err, "error")
+
+	user := passkeyUser{id: "00000000-0000-0000-0000-000000000001", username: "shinji"}
+	auth := newSoftAuthenticator(t, "http://localhost:3000")
+
+	creation, session, err := wa.BeginRegistration(user)
+	testutil.WantEq(t, nil, err, "begin registration error")
+
+	parsedCreation, err := protocol.ParseCredentialCreationResponseBytes(auth.create(t, creation))
+	testutil.WantEq(t, nil, err, "parse creation error")
+
+	cred, err := wa.CreateCredential(user, *session, parsedCreation)
+	testutil.WantEq(t, nil, err, "create credential error")
+
+	user.credentials = append(user.credentials, *cred)
+
+	assertion, session, err := wa.BeginDiscoverableLogin()
+	testutil.WantEq(t, nil, err, "begin login error")
+
+	parsedAssertion, err := protocol.ParseCredentialRequestResponseBytes(auth.get(t, assertion))
+	testutil.WantEq(t, nil, err, "parse assertion error")
+
+	_, _, err = wa.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		testutil.WantEq(t, user.id, string(userHandle), "user handle")
+		return user, nil
+	}, *session, parsedAssertion)
+	testutil.WantEq(t, nil, err, "validate login error")
+
+	t.Run("other_origin", func(t *testing.T) {
+		auth := newSoftAuthenticator(t, "https://example.org")
+		creation, session, err := wa.BeginRegistration(user)
+		testutil.WantEq(t, nil, err, "begin registration error")
+
+		parsed, err := protocol.ParseCredentialCreationResponseBytes(auth.create(t, creation))
+		testutil.WantEq(t, nil, err, "parse creation error")
+
+		_, err = wa.CreateCredential(user, *session, parsed)
+		if err == nil {
+			t.Fatal("expected credential from another origin to be rejected")
+		}
+	})
+}
+
+func TestService_Passkeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping passkeys integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{
+		DB:     testDB,
+		Origin: &url.URL{Scheme: "http", Host: "localhost:3000"},
+	}
+
+	var uid string
+	username := "passkey_" + testutil.RandStr(t, 8)
+	row := testDB.QueryRowContext(ctx, "INSERT INTO users (email, username) VALUES ($1, $2) RETURNING id", username+"@example.org", username)
+	if err := row.Scan(&uid); err != nil {
+		t.Fatalf("could not insert user: %v", err)
+	}
+
+	auth := newSoftAuthenticator(t, "http://localhost:3000")
+	authCtx := context.WithValue(ctx, KeyAuthUserID, uid)
+
+	registration, err := svc.BeginPasskeyRegistration(authCtx)
+	testutil.WantEq(t, nil, err, "begin registration error")
+
+	var creation protocol.CredentialCreation
+	if err := json.Unmarshal(registration.Options, &creation); err != nil {
+		t.Fatalf("could not json unmarshal creation options: %v", err)
+	}
+
+	name := "Software authenticator"
+	passkey, err := svc.FinishPasskeyRegistration(authCtx, FinishPasskeyRegistration{
+		CeremonyID: registration.ID,
+		Name:       &name,
+		Credential: auth.create(t, &creation),
+	})
+	testutil.WantEq(t, nil, err, "finish registration error")
+	testutil.WantEq(t, name, passkey.Name, "passkey name")
+
+	t.Run("ceremony_reuse", func(t *testing.T) {
+		_, err := svc.FinishPasskeyRegistration(authCtx, FinishPasskeyRegistration{
+			CeremonyID: registration.ID,
+			Credential: auth.create(t, &creation),
+		})
+		testutil.WantEq(t, ErrPasskeyCeremonyNotFound, err, "error")
+	})
+
+	login, err := svc.BeginPasskeyLogin(ctx)
+	testutil.WantEq(t, nil, err, "begin login error")
+
+	var assertion protocol.CredentialAssertion
+	if err := json.Unmarshal(login.Options, &assertion); err != nil {
+		t.Fatalf("could not json unmarshal assertion options: %v", err)
+	}
+
+	out, err := svc.FinishPasskeyLogin(ctx, FinishPasskeyLogin{
+		CeremonyID: login.ID,
+		Credential: auth.get(t, &assertion),
+	})
+	testutil.WantEq(t, nil, err, "finish login error")
+	testutil.WantEq(t, uid, out.User.ID, "user ID")
+	testutil.WantEq(t, username, out.User.Username, "username")
+
+	t.Run("second_factor", func(t *testing.T) {
+		_, err := testDB.ExecContext(ctx, "INSERT INTO totp_secrets (user_id, secret, enabled_at) VALUES ($1, 'secret', now())", uid)
+		testutil.WantEq(t, nil, err, "insert totp secret error")
+
+		defer func() {
+			_, err := testDB.ExecContext(ctx, "DELETE FROM totp_secrets WHERE user_id = $1", uid)
+			testutil.WantEq(t, nil, err, "delete totp secret error")
+		}()
+
+		login, err := svc.BeginPasskeyLogin(ctx)
+		testutil.WantEq(t, nil, err, "begin login error")
+
+		var assertion protocol.CredentialAssertion
+		if err := json.Unmarshal(login.Options, &assertion); err != nil {
+			t.Fatalf("could not json unmarshal assertion options: %v", err)
+		}
+
+		out, err := svc.FinishPasskeyLogin(ctx, FinishPasskeyLogin{
+			CeremonyID: login.ID,
+			Credential: auth.get(t, &assertion),
+		})
+		testutil.WantEq(t, nil, err, "finish login error")
+		testutil.WantEq(t, true, out.SecondFactorRequired, "second factor required")
+		testutil.WantEq(t, true, out.SecondFactorChallengeID != "", "second factor challenge ID")
+		testutil.WantEq(t, "", out.Token, "token")
+	})
+
+	pp, err := svc.Passkeys(authCtx)
+	testutil.WantEq(t, nil, err, "passkeys error")
+	testutil.WantEq(t, 1, len(pp), "passkeys length")
+	testutil.WantEq(t, true, pp[0].LastUsedAt != nil, "passkey used")
+
+	err = svc.DeletePasskey(authCtx, passkey.ID)
+	testutil.WantEq(t, nil, err, "delete passkey error")
+
+	login, err = svc.BeginPasskeyLogin(ctx)
+	testutil.WantEq(t, nil, err, "begin login error")
+
+	if err := json.Unmarshal(login.Options, &assertion); err != nil {
+		t.Fatalf("could not json unmarshal assertion options: %v", err)
+	}
+
+	_, err = svc.FinishPasskeyLogin(ctx, FinishPasskeyLogin{
+		CeremonyID: login.ID,
+		Credential: auth.get(t, &assertion),
+	})
+	testutil.WantEq(t, ErrInvalidPasskey, err, "login with deleted passkey error")
+}
+
+// softAuthenticator is a software authenticator
+// that creates a single ES256 passkey with "none" attestation.
+type softAuthenticator struct {
+	origin     string
+	key        *ecdsa.PrivateKey
+	credID     []byte
+	userHandle []byte
+	signCount  uint32
+}
+
+func newSoftAuthenticator(t *testing.T, origin string) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate authenticator key: %v", err)
+	}
+
+	credID := make([]byte, 16)
+	if _, err := rand.Read(credID); err != nil {
+		t.Fatalf("could not generate authenticator credential ID: %v", err)
+	}
+
+	return &softAuthenticator{origin: origin, key: key, credID: credID}
+}
+
+func (a *softAuthenticator) create(t *testing.T, creation *protocol.CredentialCreation) []byte {
+	t.Helper()
+
+	opts := creation.Response
+	switch id := opts.User.ID.(type) {
+	case protocol.URLEncodedBase64:
+		a.userHandle = id
+	case string:
+		// options that went through JSON.
+		var err error
+		a.userHandle, err = base64.RawURLEncoding.DecodeString(id)
+		if err != nil {
+			t.Fatalf("could not decode user handle: %v", err)
+		}
+	}
+
+	clientData := a.clientData(t, protocol.CreateCeremony, opts.Challenge)
+
+	pub, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("could not cbor marshal authenticator public key: %v", err)
+	}
+
+	authData := a.authData(opts.RelyingParty.ID, protocol.FlagUserPresent|protocol.FlagUserVerified|protocol.FlagAttestedCredentialData)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credID)))
+	authData = append(authData, a.credID...)
+	authData = append(authData, pub...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("could not cbor marshal attestation object: %v", err)
+	}
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+func (a *softAuthenticator) get(t *testing.T, assertion *protocol.CredentialAssertion) []byte {
+	t.Helper()
+
+	opts := assertion.Response
+	clientData := a.clientData(t, protocol.AssertCeremony, opts.Challenge)
+	authData := a.authData(opts.RelyingPartyID, protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("could not sign assertion: %v", err)
+	}
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(sig),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, typ protocol.CeremonyType, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.origin,
+	})
+	if err != nil {
+		t.Fatalf("could not json marshal client data: %v", err)
+	}
+
+	return b
+}
+
+func (a *softAuthenticator) authData(rpID string, flags protocol.AuthenticatorFlags) []byte {
+	a.signCount++
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	b := append(rpIDHash[:], byte(flags))
+	return binary.BigEndian.AppendUint32(b, a.signCount)
+}
+
+func (a *softAuthenticator) credential(t *testing.T, response map[string]any) []byte {
+	t.Helper()
+
+	id := base64.RawURLEncoding.EncodeToString(a.credID)
+	b, err := json.Marshal(map[string]any{
+		"id":       id,
+		"rawId":    id,
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("could not json marshal credential: %v", err)
+	}
+
+	return b
+}
@@ -0,0 +1,306 @@
+package nakama
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// KeyAuthScopes to use in context.
+// Only set when the request was authenticated with a personal access token,
+// and holds the scopes granted to that token.
+const KeyAuthScopes = ctxkey("auth_scopes")
+
+// Scopes that can be granted to personal access tokens.
+const (
+	ScopePostsWrite         = "posts:write"
+	ScopeCommentsWrite      = "comments:write"
+	ScopeReactionsWrite     = "reactions:write"
+	ScopeTimelineRead       = "timeline:read"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	ScopeFollowsWrite       = "follows:write"
+)
+
+const (
+	personalAccessTokenPrefix = "nkpat_"
+	personalAccessTokenLen    = 32
+	// personalAccessTokenLastUsedResolution is how often the last used time
+	// of a personal access token gets written down.
+	personalAccessTokenLastUsedResolution = time.Minute
+)
+
+var scopes = []string{
+	ScopePostsWrite,
+	ScopeCommentsWrite,
+	ScopeReactionsWrite,
+	ScopeTimelineRead,
+	ScopeNotificationsRead,
+	ScopeNotificationsWrite,
+	ScopeFollowsWrite,
+}
+
+var (
+	// ErrInvalidPersonalAccessTokenID denotes an invalid personal access token ID; that is not uuid.
+	ErrInvalidPersonalAccessTokenID = InvalidArgumentError("invalid personal access token ID")
+	// ErrInvalidPersonalAccessTokenName denotes an invalid personal access token name.
+	ErrInvalidPersonalAccessTokenName = InvalidArgumentError("invalid personal access token name")
+	// ErrInvalidScope denotes an unknown scope or an empty list of them.
+	ErrInvalidScope = InvalidArgumentError("invalid scope")
+	// ErrInvalidExpiration denotes an expiration time that is not in the future.
+	ErrInvalidExpiration = InvalidArgumentError("invalid expiration")
+	// ErrPersonalAccessTokenNotFound denotes a not found personal access token.
+	ErrPersonalAccessTokenNotFound = NotFoundError("personal access token not found")
+	// ErrInsufficientScope denotes that the personal access token used
+	// was not granted the scope required by the action.
+	ErrInsufficientScope = PermissionDeniedError("insufficient scope")
+)
+
+// PersonalAccessToken is a long-lived token with a limited set of scopes
+// that users can give to bots and scripts.
+type PersonalAccessToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreatePersonalAccessToken struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreatedPersonalAccessToken response.
+// Token is only given back once, at creation time.
+type CreatedPersonalAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// ValidScope tells whether the given scope is a known one.
+func ValidScope(scope string) bool {
+	return slices.Contains(scopes, scope)
+}
+
+// HasScope tells whether the current request is allowed to act with the given scope.
+// Requests not authenticated with a personal access token are allowed everything.
+func HasScope(ctx context.Context, scope string) bool {
+	granted, ok := ctx.Value(KeyAuthScopes).([]string)
+	if !ok {
+		return true
+	}
+
+	return scope != "" && slices.Contains(granted, scope)
+}
+
+// CreatePersonalAccessToken for the authenticated user.
+func (s *Service) CreatePersonalAccessToken(ctx context.Context, in CreatePersonalAccessToken) (CreatedPersonalAccessToken, error) {
+	var out CreatedPersonalAccessToken
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > 64 {
+		return out, ErrInvalidPersonalAccessTokenName
+	}
+
+	if len(in.Scopes) == 0 {
+		return out, ErrInvalidScope
+	}
+
+	for _, scope := range in.Scopes {
+		if !ValidScope(scope) {
+			return out, ErrInvalidScope
+		}
+	}
+
+	in.Scopes = slices.Clone(in.Scopes)
+	slices.Sort(in.Scopes)
+	in.Scopes = slices.Compact(in.Scopes)
+
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return out, ErrInvalidExpiration
+	}
+
+	b := make([]byte, personalAccessTokenLen)
+	if _, err := rand.Read(b); err != nil {
+		return out, fmt.Errorf("could not generate personal access token: %w", err)
+	}
+
+	hash := sha256.Sum256(b)
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	row := s.DB.QueryRowContext(ctx, query, uid, in.Name, hash[:], pq.Array(in.Scopes), in.ExpiresAt)
+	err := row.Scan(&out.ID, &out.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return out, ErrUserGone
+	}
+
+	if err != nil {
+		return out, fmt.Errorf("could not sql insert personal access token: %w", err)
+	}
+
+	out.Name = in.Name
+	out.Scopes = in.Scopes
+	out.ExpiresAt = in.ExpiresAt
+	out.Token = personalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return out, nil
+}
+
+// PersonalAccessTokens from the authenticated user.
+// Newest first.
+func (s *Service) PersonalAccessTokens(ctx context.Context) ([]PersonalAccessToken, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT id, name, scopes, expires_at, last_used_at, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select personal access tokens: %w", err)
+	}
+
+	defer rows.Close()
+
+	var tt []PersonalAccessToken
+	for rows.Next() {
+		var t PersonalAccessToken
+		err := rows.Scan(
+			&t.ID,
+			&t.Name,
+			pq.Array(&t.Scopes),
+			&t.ExpiresAt,
+			&t.LastUsedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan personal access token: %w", err)
+		}
+
+		tt = append(tt, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate personal access token rows: %w", err)
+	}
+
+	return tt, nil
+}
+
+// RevokePersonalAccessToken from the authenticated user.
+// It stops working right away.
+func (s *Service) RevokePersonalAccessToken(ctx context.Context, tokenID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(tokenID) {
+		return ErrInvalidPersonalAccessTokenID
+	}
+
+	query := "DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, tokenID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete personal access token: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted personal access token rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// isPersonalAccessToken tells apart personal access tokens from session access tokens.
+func isPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix)
+}
+
+// personalAccessTokenAuth checks the given personal access token
+// and takes care of updating its last used time.
+func (s *Service) personalAccessTokenAuth(ctx context.Context, token string) (Auth, error) {
+	var auth Auth
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, personalAccessTokenPrefix))
+	if err != nil || len(b) != personalAccessTokenLen {
+		return auth, ErrInvalidToken
+	}
+
+	hash := sha256.Sum256(b)
+
+	var tokenID string
+	var expiresAt, lastUsedAt *time.Time
+	var suspended bool
+	query := `
+		SELECT personal_access_tokens.id
+		, personal_access_tokens.user_id
+		, personal_access_tokens.scopes
+		, personal_access_tokens.expires_at
+		, personal_access_tokens.last_used_at
+		, ` + userSuspended + `
+		FROM personal_access_tokens
+		INNER JOIN users ON personal_access_tokens.user_id = users.id
+		WHERE personal_access_tokens.token_hash = $1`
+	row := s.DB.QueryRowContext(ctx, query, hash[:])
+	err = row.Scan(&tokenID, &auth.UserID, pq.Array(&auth.Scopes), &expiresAt, &lastUsedAt, &suspended)
+	if err == sql.ErrNoRows {
+		return auth, ErrInvalidToken
+	}
+
+	if err != nil {
+		return auth, fmt.Errorf("could not sql query select personal access token: %w", err)
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return auth, ErrExpiredToken
+	}
+
+	if suspended {
+		return auth, ErrUserSuspended
+	}
+
+	if auth.Scopes == nil {
+		auth.Scopes = []string{}
+	}
+
+	if lastUsedAt == nil || time.Since(*lastUsedAt) >= personalAccessTokenLastUsedResolution {
+		go s.touchPersonalAccessToken(tokenID)
+	}
+
+	return auth, nil
+}
+
+func (s *Service) touchPersonalAccessToken(tokenID string) {
+	query := "UPDATE personal_access_tokens SET last_used_at = now() WHERE id = $1"
+	_, err := s.DB.Exec(query, tokenID)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not update personal access token last used: %w", err))
+	}
+}
@@ -0,0 +1,110 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+// maxPinnedPosts is how many posts a user can pin to their profile.
+const maxPinnedPosts = 3
+
+var (
+	// ErrPinPostDenied denotes an attempt to pin a post from someone else.
+	ErrPinPostDenied = PermissionDeniedError("pin post denied")
+	// ErrTooManyPinnedPosts denotes that the user reached the max allowed pinned posts (3).
+	ErrTooManyPinnedPosts = ResourceExhaustedError("too many pinned posts")
+	// ErrPostNotPinned denotes an attempt to unpin a post that is not pinned.
+	ErrPostNotPinned = NotFoundError("post not pinned")
+)
+
+// PinPost to the top of the authenticated user profile.
+// Only own posts can be pinned. Pinning an already pinned post does nothing.
+// Deleting a post unpins it.
+func (s *Service) PinPost(ctx context.Context, postID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(postID) {
+		return ErrInvalidPostID
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var authorID string
+		var pinned bool
+		query := "SELECT user_id, pinned_at IS NOT NULL FROM posts WHERE id = $1"
+		err := tx.QueryRowContext(ctx, query, postID).Scan(&authorID, &pinned)
+		if err == sql.ErrNoRows {
+			return ErrPostNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select post to pin: %w", err)
+		}
+
+		if authorID != uid {
+			return ErrPinPostDenied
+		}
+
+		if pinned {
+			return nil
+		}
+
+		var count int
+		query = "SELECT count(*) FROM posts WHERE user_id = $1 AND pinned_at IS NOT NULL"
+		if err := tx.QueryRowContext(ctx, query, uid).Scan(&count); err != nil {
+			return fmt.Errorf("could not sql count pinned posts: %w", err)
+		}
+
+		if count >= maxPinnedPosts {
+			return ErrTooManyPinnedPosts
+		}
+
+		query = "UPDATE posts SET pinned_at = now() WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+			return fmt.Errorf("could not sql update post pinned at: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnpinPost from the authenticated user profile.
+func (s *Service) UnpinPost(ctx context.Context, postID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(postID) {
+		return ErrInvalidPostID
+	}
+
+	query := "UPDATE posts SET pinned_at = NULL WHERE id = $1 AND user_id = $2 AND pinned_at IS NOT NULL"
+	res, err := s.DB.ExecContext(ctx, query, postID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql update post pinned at: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get unpinned post rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrPostNotPinned
+	}
+
+	return nil
+}
+
+// PinnedPosts from the user with the given username.
+// Latest pinned first.
+// They also show up in between the rest of their posts.
+func (s *Service) PinnedPosts(ctx context.Context, username string) (Posts, error) {
+	return s.Posts(ctx, maxPinnedPosts, nil, PostsFromUser(username), pinnedPostsOnly())
+}
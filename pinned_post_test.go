@@ -0,0 +1,79 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_PinPost(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.PinPost(context.Background(), "00000000-0000-0000-0000-000000000001")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_post_id", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.PinPost(ctx, "nope")
+		testutil.WantEq(t, ErrInvalidPostID, err, "error")
+	})
+
+	t.Run("pinned_posts", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping pinned posts integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		author := createTestUser(t)
+		other := createTestUser(t)
+
+		var postIDs []string
+		for i := 0; i < maxPinnedPosts+1; i++ {
+			var postID string
+			err := testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content) VALUES ($1, 'test') RETURNING id", author.ID).Scan(&postID)
+			testutil.WantEq(t, nil, err, "insert post error")
+			postIDs = append(postIDs, postID)
+		}
+
+		otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+		err := svc.PinPost(otherCtx, postIDs[0])
+		testutil.WantEq(t, ErrPinPostDenied, err, "pin someone else post error")
+
+		authorCtx := context.WithValue(ctx, KeyAuthUserID, author.ID)
+		for _, postID := range postIDs[:maxPinnedPosts] {
+			err := svc.PinPost(authorCtx, postID)
+			testutil.WantEq(t, nil, err, "pin post error")
+		}
+
+		// pinning again does nothing.
+		err = svc.PinPost(authorCtx, postIDs[0])
+		testutil.WantEq(t, nil, err, "pin post again error")
+
+		err = svc.PinPost(authorCtx, postIDs[maxPinnedPosts])
+		testutil.WantEq(t, ErrTooManyPinnedPosts, err, "pin too many posts error")
+
+		pp, err := svc.PinnedPosts(ctx, author.Username)
+		testutil.WantEq(t, nil, err, "pinned posts error")
+		testutil.WantEq(t, maxPinnedPosts, len(pp), "pinned posts length")
+		testutil.WantEq(t, postIDs[maxPinnedPosts-1], pp[0].ID, "latest pinned first")
+		testutil.WantEq(t, true, pp[0].Pinned, "pinned")
+
+		err = svc.DeletePost(authorCtx, postIDs[0])
+		testutil.WantEq(t, nil, err, "delete post error")
+
+		err = svc.UnpinPost(authorCtx, postIDs[1])
+		testutil.WantEq(t, nil, err, "unpin post error")
+
+		err = svc.UnpinPost(authorCtx, postIDs[1])
+		testutil.WantEq(t, ErrPostNotPinned, err, "unpin post again error")
+
+		pp, err = svc.PinnedPosts(ctx, author.Username)
+		testutil.WantEq(t, nil, err, "pinned posts after unpin error")
+		testutil.WantEq(t, 1, len(pp), "pinned posts after unpin length")
+	})
+}
@@ -38,6 +38,8 @@ var (
 	// not a valid emoji, or invalid reaction image URL.
 	ErrInvalidReaction  = InvalidArgumentError("invalid reaction")
 	ErrUpdatePostDenied = PermissionDeniedError("update post denied")
+	// ErrDeletePostDenied denotes that the user is not allowed to delete someone else's post.
+	ErrDeletePostDenied = PermissionDeniedError("delete post denied")
 )
 
 // Post model.
@@ -52,6 +54,7 @@ type Post struct {
 	MediaURLs     []string   `json:"mediaURLs"`
 	CreatedAt     time.Time  `json:"createdAt"`
 	UpdatedAt     time.Time  `json:"updatedAt"`
+	Pinned        bool       `json:"pinned"`
 	User          *User      `json:"user,omitempty"`
 	Mine          bool       `json:"mine"`
 	Subscribed    bool       `json:"subscribed"`
@@ -88,6 +91,9 @@ func (pp Posts) EndCursor() *string {
 type PostsOpts struct {
 	Username *string
 	Tag      *string
+
+	listID *string
+	pinned bool
 }
 
 type PostsOpt func(*PostsOpts)
@@ -104,6 +110,22 @@ func PostsTagged(tag string) PostsOpt {
 	}
 }
 
+// postsFromList filters posts from members of the given user list.
+// It is unexported since access to the list must be checked first.
+func postsFromList(listID string) PostsOpt {
+	return func(opts *PostsOpts) {
+		opts.listID = &listID
+	}
+}
+
+// pinnedPostsOnly filters posts pinned by their author, in pin order.
+// It is unexported since pinned posts are not paginated; see PinnedPosts.
+func pinnedPostsOnly() PostsOpt {
+	return func(opts *PostsOpts) {
+		opts.pinned = true
+	}
+}
+
 // Posts in descending order and with backward pagination.
 // They can be filtered from a specific user by using `PostsFromUser` option
 // in this late case, user field won't be populated.
@@ -144,6 +166,7 @@ func (s *Service) Posts(ctx context.Context, last uint64, before *string, opts .
 		, posts.media
 		, posts.created_at
 		, posts.updated_at
+		, posts.pinned_at IS NOT NULL AS post_pinned
 		{{ if .auth }}
 		, posts.user_id = @uid AS post_mine
 		, reactions.user_reactions
@@ -171,28 +194,39 @@ func (s *Service) Posts(ctx context.Context, last uint64, before *string, opts .
 		{{ if .tag }}
 		INNER JOIN post_tags ON post_tags.post_id = posts.id AND post_tags.tag = @tag
 		{{ end }}
-		{{ if or .username (and .beforePostID .beforeCreatedAt) }}
-		WHERE
+		WHERE `+visiblePost()+`
+		{{ if .auth }}
+			AND `+notBlocked("posts.user_id")+`
+			AND `+notMutedPost()+`
 		{{ end }}
 		{{ if .username }}
-			posts.user_id = (SELECT id FROM users WHERE username = @username)
+			AND posts.user_id = (SELECT id FROM users WHERE username = @username)
+		{{ end }}
+		{{ if .listID }}
+			AND posts.user_id IN (SELECT user_id FROM user_list_members WHERE list_id = @listID)
+		{{ end }}
+		{{ if .pinned }}
+			AND posts.pinned_at IS NOT NULL
 		{{ end }}
 		{{ if and .beforePostID .beforeCreatedAt }}
-			{{ if .username }}
-			AND
-			{{ end }}
-			posts.created_at <= @beforeCreatedAt
+			AND posts.created_at <= @beforeCreatedAt
 			AND (
 				posts.id < @beforePostID
 					OR posts.created_at < @beforeCreatedAt
 			)
 		{{ end }}
+		{{ if .pinned }}
+		ORDER BY posts.pinned_at DESC
+		{{ else }}
 		ORDER BY posts.created_at DESC, posts.id ASC
+		{{ end }}
 		LIMIT @last`, map[string]interface{}{
 		"auth":            auth,
 		"uid":             uid,
 		"username":        options.Username,
 		"tag":             options.Tag,
+		"listID":          options.listID,
+		"pinned":          options.pinned,
 		"last":            last,
 		"beforePostID":    beforePostID,
 		"beforeCreatedAt": beforeCreatedAt,
@@ -226,6 +260,7 @@ func (s *Service) Posts(ctx context.Context, last uint64, before *string, opts .
 			pq.Array(&media),
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.Pinned,
 		}
 		if auth {
 			dest = append(dest, &p.Mine, &rawUserReactions, &p.Subscribed)
@@ -282,6 +317,7 @@ func (s *Service) Posts(ctx context.Context, last uint64, before *string, opts .
 
 // PostStream to receive posts in realtime.
 func (s *Service) PostStream(ctx context.Context) (<-chan Post, error) {
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
 	pp := make(chan Post)
 	unsub, err := s.PubSub.Sub(postsTopic, func(data []byte) {
 		go func(r io.Reader) {
@@ -292,7 +328,15 @@ func (s *Service) PostStream(ctx context.Context) (<-chan Post, error) {
 				return
 			}
 
-			pp <- p
+			ok, err := s.streamablePost(ctx, uid, auth, p)
+			if err != nil {
+				_ = s.Logger.Log("error", err)
+				return
+			}
+
+			if ok {
+				pp <- p
+			}
 		}(bytes.NewReader(data))
 	})
 	if err != nil {
@@ -312,6 +356,45 @@ func (s *Service) PostStream(ctx context.Context) (<-chan Post, error) {
 	return pp, nil
 }
 
+// streamablePost tells whether a post received in realtime can be sent to the given user.
+// That is, the post is visible to them and its author is neither in a block
+// with them nor muted.
+// As it runs for every subscriber, all of that is checked with a single query.
+func (s *Service) streamablePost(ctx context.Context, uid string, auth bool, p Post) (bool, error) {
+	if auth && p.UserID == uid {
+		return true, nil
+	}
+
+	var ok bool
+	query := `
+		SELECT (NOT users.private OR EXISTS (
+			SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = users.id
+		))
+		AND NOT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = users.id)
+				OR (blocker_id = users.id AND blocked_id = $1)
+		)
+		AND NOT ` + muteMatch("$1", "users.id", "$3", "$4") + `
+		FROM users WHERE users.id = $2`
+	row := s.DB.QueryRowContext(ctx, query,
+		sql.NullString{String: uid, Valid: auth},
+		p.UserID,
+		p.Content,
+		pq.Array(lowerTags(collectTags(p.Content))),
+	)
+	err := row.Scan(&ok)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select post streamability: %w", err)
+	}
+
+	return ok, nil
+}
+
 // Post with the given ID.
 func (s *Service) Post(ctx context.Context, postID string) (Post, error) {
 	var p Post
@@ -330,6 +413,7 @@ func (s *Service) Post(ctx context.Context, postID string) (Post, error) {
 			, posts.media
 			, posts.created_at
 			, posts.updated_at
+			, posts.pinned_at IS NOT NULL AS pinned
 			, users.username
 			, users.avatar
 			{{if .auth}}
@@ -350,7 +434,9 @@ func (s *Service) Post(ctx context.Context, postID string) (Post, error) {
 		LEFT JOIN post_subscriptions AS subscriptions
 			ON subscriptions.user_id = @uid AND subscriptions.post_id = posts.id
 		{{end}}
-		WHERE posts.id = @post_id`, map[string]interface{}{
+		WHERE posts.id = @post_id
+		AND `+visiblePost()+`
+		{{if .auth}}AND `+notBlocked("posts.user_id")+`{{end}}`, map[string]interface{}{
 		"auth":    auth,
 		"uid":     uid,
 		"post_id": postID,
@@ -374,6 +460,7 @@ func (s *Service) Post(ctx context.Context, postID string) (Post, error) {
 		pq.Array(&media),
 		&p.CreatedAt,
 		&p.UpdatedAt,
+		&p.Pinned,
 		&u.Username,
 		&avatar,
 	}
@@ -542,6 +629,8 @@ func (s *Service) updatePost(ctx context.Context, postID string, params UpdatePo
 	return updated, nil
 }
 
+// DeletePost from the authenticated user.
+// Moderators and admins can delete the posts of users with a lower role.
 func (s *Service) DeletePost(ctx context.Context, postID string) error {
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
 	if !ok {
@@ -552,13 +641,38 @@ func (s *Service) DeletePost(ctx context.Context, postID string) error {
 		return ErrInvalidPostID
 	}
 
-	query := "DELETE FROM posts WHERE id = $1 AND user_id = $2"
-	_, err := s.DB.ExecContext(ctx, query, postID, uid)
-	if err != nil {
-		return fmt.Errorf("could not sql delete post: %w", err)
-	}
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var authorID string
+		query := "SELECT user_id FROM posts WHERE id = $1"
+		err := tx.QueryRowContext(ctx, query, postID).Scan(&authorID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select post to delete user id: %w", err)
+		}
+
+		if authorID != uid {
+			if err := authorizeOver(ctx, tx, uid, authorID, permDeleteAnyPost, ErrDeletePostDenied); err != nil {
+				return err
+			}
+		}
+
+		var media []string
+		query = "DELETE FROM posts WHERE id = $1 RETURNING media"
+		err = tx.QueryRowContext(ctx, query, postID).Scan(pq.Array(&media))
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql delete post: %w", err)
+		}
 
-	return nil
+		// media files get deleted by the orphaned files background job.
+		return s.orphanFiles(ctx, tx, MediaBucket, media...)
+	})
 }
 
 type ReactionInput struct {
@@ -590,8 +704,9 @@ func (s *Service) TogglePostReaction(ctx context.Context, postID string, in Reac
 
 		var rawReactions []byte
 		var rawUserReactions []byte
+		var postUserID string
 		query := `
-			SELECT posts.reactions, reactions.user_reactions
+			SELECT posts.user_id, posts.reactions, reactions.user_reactions
 			FROM posts
 			LEFT JOIN (
 				SELECT user_id
@@ -602,7 +717,7 @@ func (s *Service) TogglePostReaction(ctx context.Context, postID string, in Reac
 			) AS reactions ON reactions.user_id = $1 AND reactions.post_id = posts.id
 			WHERE posts.id = $2`
 		row := tx.QueryRowContext(ctx, query, uid, postID)
-		err := row.Scan(&rawReactions, &rawUserReactions)
+		err := row.Scan(&postUserID, &rawReactions, &rawUserReactions)
 		if err == sql.ErrNoRows {
 			return ErrPostNotFound
 		}
@@ -611,6 +726,26 @@ func (s *Service) TogglePostReaction(ctx context.Context, postID string, in Reac
 			return fmt.Errorf("could not sql scan post and user reactions: %w", err)
 		}
 
+		visible, err := postVisibleByID(ctx, tx, uid, postID)
+		if err != nil {
+			return err
+		}
+
+		if !visible {
+			return ErrPostNotFound
+		}
+
+		if postUserID != uid {
+			isBlocked, err := blocked(ctx, tx, uid, postUserID)
+			if err != nil {
+				return err
+			}
+
+			if isBlocked {
+				return ErrBlocked
+			}
+		}
+
 		var reactions []Reaction
 		if rawReactions != nil {
 			err = json.Unmarshal(rawReactions, &reactions)
@@ -752,6 +887,17 @@ func (s *Service) TogglePostSubscription(ctx context.Context, postID string) (To
 			return fmt.Errorf("could not query select post subscription existence: %w", err)
 		}
 
+		if !out.Subscribed {
+			visible, err := postVisibleByID(ctx, tx, uid, postID)
+			if err != nil {
+				return err
+			}
+
+			if !visible {
+				return ErrPostNotFound
+			}
+		}
+
 		if out.Subscribed {
 			query = "DELETE FROM post_subscriptions WHERE user_id = $1 AND post_id = $2"
 			if _, err = tx.ExecContext(ctx, query, uid, postID); err != nil {
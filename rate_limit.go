@@ -0,0 +1,78 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+// rateLimit is a sliding window limit of hits for a key.
+// With a cooldown, consecutive hits must be at least that far apart.
+type rateLimit struct {
+	key      string
+	limit    int
+	window   time.Duration
+	cooldown time.Duration
+}
+
+// takeRateLimits records a hit for each of the given limits
+// if none of them is exceeded.
+// Otherwise it returns a RetryAfterError wrapping errLimited.
+// Hits are stored in the database so the limits hold across replicas.
+func (s *Service) takeRateLimits(ctx context.Context, errLimited error, limits ...rateLimit) error {
+	var retryAfter time.Duration
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		retryAfter = 0
+		now := time.Now()
+
+		for _, l := range limits {
+			query := "DELETE FROM rate_limit_hits WHERE key = $1 AND created_at <= $2"
+			_, err := tx.ExecContext(ctx, query, l.key, now.Add(-l.window))
+			if err != nil {
+				return fmt.Errorf("could not sql delete old rate limit hits: %w", err)
+			}
+
+			var count int
+			var first, last sql.NullTime
+			query = "SELECT count(*), min(created_at), max(created_at) FROM rate_limit_hits WHERE key = $1"
+			err = tx.QueryRowContext(ctx, query, l.key).Scan(&count, &first, &last)
+			if err != nil {
+				return fmt.Errorf("could not sql query select rate limit hits: %w", err)
+			}
+
+			if count >= l.limit && first.Valid {
+				retryAfter = max(retryAfter, first.Time.Add(l.window).Sub(now))
+			}
+
+			if l.cooldown > 0 && last.Valid && now.Sub(last.Time) < l.cooldown {
+				retryAfter = max(retryAfter, last.Time.Add(l.cooldown).Sub(now))
+			}
+		}
+
+		if retryAfter > 0 {
+			return nil
+		}
+
+		for _, l := range limits {
+			query := "INSERT INTO rate_limit_hits (key, created_at, expires_at) VALUES ($1, $2, $3)"
+			_, err := tx.ExecContext(ctx, query, l.key, now, now.Add(l.window))
+			if err != nil {
+				return fmt.Errorf("could not sql insert rate limit hit: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return &RetryAfterError{Err: errLimited, RetryAfter: retryAfter}
+	}
+
+	return nil
+}
@@ -0,0 +1,529 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/lib/pq"
+)
+
+// Report target types.
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetUser    = "user"
+)
+
+// Report reasons.
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonHateSpeech    = "hate_speech"
+	ReportReasonViolence      = "violence"
+	ReportReasonNSFW          = "nsfw"
+	ReportReasonSpoiler       = "spoiler"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonOther         = "other"
+)
+
+// Report resolve actions.
+const (
+	// ReportActionDismiss closes the report without taking any action.
+	ReportActionDismiss = "dismiss"
+	// ReportActionHide hides the reported post or comment from everyone but its author.
+	ReportActionHide = "hide"
+	// ReportActionSuspend suspends the author of the reported content, or the reported user.
+	ReportActionSuspend = "suspend"
+)
+
+const (
+	reportDetailsMaxLength = 480
+	// reportsPerHour a single user can send.
+	reportsPerHour = 20
+)
+
+var (
+	// ErrInvalidReportID denotes an invalid report ID; that is not uuid.
+	ErrInvalidReportID = InvalidArgumentError("invalid report ID")
+	// ErrInvalidReportReason denotes a report reason other than the known ones.
+	ErrInvalidReportReason = InvalidArgumentError("invalid report reason")
+	// ErrInvalidReportDetails denotes report details that exceed the max allowed characters (480).
+	ErrInvalidReportDetails = InvalidArgumentError("invalid report details")
+	// ErrInvalidReportAction denotes a report resolve action other than dismiss, hide or suspend.
+	// Or hiding a reported user, which only makes sense for posts and comments.
+	ErrInvalidReportAction = InvalidArgumentError("invalid report action")
+	// ErrForbiddenReport denotes a forbidden report. Like reporting yourself.
+	ErrForbiddenReport = PermissionDeniedError("forbidden report")
+	// ErrModerateReportsDenied denotes that the user is not allowed
+	// to see nor resolve reports.
+	ErrModerateReportsDenied = PermissionDeniedError("moderate reports denied")
+	// ErrReportNotFound denotes a not found report, or one already resolved.
+	ErrReportNotFound = NotFoundError("report not found")
+	// ErrTooManyReports denotes that the user sent too many reports recently.
+	ErrTooManyReports = ResourceExhaustedError("too many reports")
+)
+
+// ReportInput is why a user reports a post, comment or profile.
+// Details are optional.
+type ReportInput struct {
+	Reason  string  `json:"reason"`
+	Details *string `json:"details"`
+}
+
+// Report groups all the reports that users sent on the same target
+// while it awaits for a moderator to resolve it.
+// Content is that of the reported post or comment,
+// nil for users, or if it has already been deleted.
+type Report struct {
+	ID             string    `json:"id"`
+	TargetType     string    `json:"targetType"`
+	TargetID       string    `json:"targetID"`
+	PostID         *string   `json:"postID"`
+	Content        *string   `json:"content"`
+	Author         User      `json:"author"`
+	Reasons        []string  `json:"reasons"`
+	Details        []string  `json:"details"`
+	ReportersCount int       `json:"reportersCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastReportedAt time.Time `json:"lastReportedAt"`
+}
+
+type Reports []Report
+
+func (rr Reports) EndCursor() *string {
+	if len(rr) == 0 {
+		return nil
+	}
+
+	last := rr[len(rr)-1]
+	return ptrString(encodeCursor(last.ID, last.CreatedAt))
+}
+
+// reportTarget is what a report is about.
+// Comments and posts carry their post ID so the report
+// goes away along with the post.
+type reportTarget struct {
+	typ      string
+	id       string
+	authorID string
+	postID   *string
+}
+
+// ReportPost from someone else.
+// Posts already hidden by a moderator cannot be reported.
+func (s *Service) ReportPost(ctx context.Context, postID string, in ReportInput) error {
+	if !reUUID.MatchString(postID) {
+		return ErrInvalidPostID
+	}
+
+	return s.report(ctx, in, func(tx *sql.Tx) (reportTarget, error) {
+		t := reportTarget{typ: ReportTargetPost, id: postID, postID: &postID}
+		query := "SELECT user_id FROM posts WHERE id = $1 AND hidden_at IS NULL"
+		err := tx.QueryRowContext(ctx, query, postID).Scan(&t.authorID)
+		if err == sql.ErrNoRows {
+			return t, ErrPostNotFound
+		}
+
+		if err != nil {
+			return t, fmt.Errorf("could not sql query select reported post: %w", err)
+		}
+
+		return t, nil
+	})
+}
+
+// ReportComment from someone else.
+// Comments already hidden by a moderator cannot be reported.
+func (s *Service) ReportComment(ctx context.Context, commentID string, in ReportInput) error {
+	if !reUUID.MatchString(commentID) {
+		return ErrInvalidCommentID
+	}
+
+	return s.report(ctx, in, func(tx *sql.Tx) (reportTarget, error) {
+		t := reportTarget{typ: ReportTargetComment, id: commentID}
+		query := "SELECT user_id, post_id FROM comments WHERE id = $1 AND hidden_at IS NULL"
+		err := tx.QueryRowContext(ctx, query, commentID).Scan(&t.authorID, &t.postID)
+		if err == sql.ErrNoRows {
+			return t, ErrCommentNotFound
+		}
+
+		if err != nil {
+			return t, fmt.Errorf("could not sql query select reported comment: %w", err)
+		}
+
+		return t, nil
+	})
+}
+
+// ReportUser profile.
+func (s *Service) ReportUser(ctx context.Context, username string, in ReportInput) error {
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	return s.report(ctx, in, func(tx *sql.Tx) (reportTarget, error) {
+		t := reportTarget{typ: ReportTargetUser}
+		query := "SELECT id FROM users WHERE username = $1"
+		err := tx.QueryRowContext(ctx, query, username).Scan(&t.id)
+		if err == sql.ErrNoRows {
+			return t, ErrUserNotFound
+		}
+
+		if err != nil {
+			return t, fmt.Errorf("could not sql query select reported user: %w", err)
+		}
+
+		t.authorID = t.id
+		return t, nil
+	})
+}
+
+// report the target found by the given function on behalf of the authenticated user.
+// Reports on a target that already awaits moderation are added to it,
+// and reporting the same target twice does nothing.
+// Moderators get notified once per target.
+func (s *Service) report(ctx context.Context, in ReportInput, target func(tx *sql.Tx) (reportTarget, error)) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !validReportReason(in.Reason) {
+		return ErrInvalidReportReason
+	}
+
+	if in.Details != nil {
+		*in.Details = strings.TrimSpace(*in.Details)
+		if *in.Details == "" {
+			in.Details = nil
+		} else if utf8.RuneCountInString(*in.Details) > reportDetailsMaxLength {
+			return ErrInvalidReportDetails
+		}
+	}
+
+	err := s.takeRateLimits(ctx, ErrTooManyReports, rateLimit{
+		key:    "report:user:" + uid,
+		limit:  reportsPerHour,
+		window: time.Hour,
+	})
+	if err != nil {
+		return err
+	}
+
+	var t reportTarget
+	var reportID string
+	var opened bool
+	err = crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		opened = false
+
+		var err error
+		t, err = target(tx)
+		if err != nil {
+			return err
+		}
+
+		if t.authorID == uid {
+			return ErrForbiddenReport
+		}
+
+		query := `
+			SELECT id FROM reports
+			WHERE target_type = $1 AND target_id = $2 AND resolved_at IS NULL`
+		err = tx.QueryRowContext(ctx, query, t.typ, t.id).Scan(&reportID)
+		if err == sql.ErrNoRows {
+			query = `
+				INSERT INTO reports (target_type, target_id, author_id, post_id)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id`
+			err = tx.QueryRowContext(ctx, query, t.typ, t.id, t.authorID, t.postID).Scan(&reportID)
+			if err != nil {
+				return fmt.Errorf("could not sql insert report: %w", err)
+			}
+
+			opened = true
+		} else if err != nil {
+			return fmt.Errorf("could not sql query select open report: %w", err)
+		}
+
+		query = `
+			INSERT INTO report_reporters (report_id, reporter_id, reason, details)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (report_id, reporter_id) DO NOTHING`
+		res, err := tx.ExecContext(ctx, query, reportID, uid, in.Reason, in.Details)
+		if err != nil {
+			return fmt.Errorf("could not sql insert report reporter: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get inserted report reporter rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		query = `
+			UPDATE reports SET
+				reporters_count = reporters_count + 1
+				, last_reported_at = now()
+			WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, reportID); err != nil {
+			return fmt.Errorf("could not sql update report reporters count: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opened {
+		go s.notifyReport(uid, t.postID)
+	}
+
+	return nil
+}
+
+// Reports awaiting moderation, oldest first with forward pagination.
+// Only moderators and admins can see them.
+func (s *Service) Reports(ctx context.Context, first uint64, after *string) (Reports, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var afterReportID string
+	var afterCreatedAt time.Time
+	if after != nil {
+		var err error
+		afterReportID, afterCreatedAt, err = decodeCursor(*after)
+		if err != nil || !reUUID.MatchString(afterReportID) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	if err := authorize(ctx, s.DB, uid, permModerateReports, ErrModerateReportsDenied); err != nil {
+		return nil, err
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT reports.id
+		, reports.target_type
+		, reports.target_id
+		, reports.post_id
+		, CASE reports.target_type
+			WHEN 'post' THEN (SELECT content FROM posts WHERE posts.id = reports.target_id)
+			WHEN 'comment' THEN (SELECT content FROM comments WHERE comments.id = reports.target_id)
+		END AS content
+		, users.id
+		, users.username
+		, users.avatar
+		, ARRAY(
+			SELECT DISTINCT reason FROM report_reporters
+			WHERE report_reporters.report_id = reports.id
+		) AS reasons
+		, ARRAY(
+			SELECT details FROM report_reporters
+			WHERE report_reporters.report_id = reports.id AND details IS NOT NULL
+			ORDER BY created_at
+		) AS details
+		, reports.reporters_count
+		, reports.created_at
+		, reports.last_reported_at
+		FROM reports
+		INNER JOIN users ON reports.author_id = users.id
+		WHERE reports.resolved_at IS NULL
+		{{ if and .afterReportID .afterCreatedAt }}
+			AND reports.created_at >= @afterCreatedAt
+			AND (
+				reports.id > @afterReportID
+					OR reports.created_at > @afterCreatedAt
+			)
+		{{ end }}
+		ORDER BY reports.created_at ASC, reports.id ASC
+		LIMIT @first`, map[string]interface{}{
+		"first":          first,
+		"afterReportID":  afterReportID,
+		"afterCreatedAt": afterCreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build reports sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select reports: %w", err)
+	}
+
+	defer rows.Close()
+
+	var rr Reports
+	for rows.Next() {
+		var r Report
+		var avatar sql.NullString
+		err := rows.Scan(
+			&r.ID,
+			&r.TargetType,
+			&r.TargetID,
+			&r.PostID,
+			&r.Content,
+			&r.Author.ID,
+			&r.Author.Username,
+			&avatar,
+			pq.Array(&r.Reasons),
+			pq.Array(&r.Details),
+			&r.ReportersCount,
+			&r.CreatedAt,
+			&r.LastReportedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan report: %w", err)
+		}
+
+		r.Author.AvatarURL = s.avatarURL(avatar)
+		if r.Details == nil {
+			r.Details = []string{}
+		}
+		rr = append(rr, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate report rows: %w", err)
+	}
+
+	return rr, nil
+}
+
+// ResolveReport with the given action, closing it.
+// Only moderators and admins can resolve reports,
+// and only suspend users of a lower role.
+// Everyone that sent the report gets notified.
+func (s *Service) ResolveReport(ctx context.Context, reportID, action string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(reportID) {
+		return ErrInvalidReportID
+	}
+
+	switch action {
+	case ReportActionDismiss, ReportActionHide, ReportActionSuspend:
+	default:
+		return ErrInvalidReportAction
+	}
+
+	var reporterIDs []string
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		role, err := userRole(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+
+		if !role.can(permModerateReports) {
+			return ErrModerateReportsDenied
+		}
+
+		var t reportTarget
+		var authorRole Role
+		query := `
+			SELECT reports.target_type, reports.target_id, reports.author_id, reports.post_id, users.role
+			FROM reports
+			INNER JOIN users ON reports.author_id = users.id
+			WHERE reports.id = $1 AND reports.resolved_at IS NULL
+			FOR UPDATE`
+		row := tx.QueryRowContext(ctx, query, reportID)
+		err = row.Scan(&t.typ, &t.id, &t.authorID, &t.postID, &authorRole)
+		if err == sql.ErrNoRows {
+			return ErrReportNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select report to resolve: %w", err)
+		}
+
+		switch action {
+		case ReportActionHide:
+			if err := hideReportTarget(ctx, tx, t); err != nil {
+				return err
+			}
+		case ReportActionSuspend:
+			if !role.can(permSuspendUsers) || !role.outranks(authorRole) {
+				return ErrSuspendUserDenied
+			}
+
+			query := "UPDATE users SET suspended_at = COALESCE(suspended_at, now()), suspended_until = NULL WHERE id = $1"
+			if _, err := tx.ExecContext(ctx, query, t.authorID); err != nil {
+				return fmt.Errorf("could not sql update reported user suspension: %w", err)
+			}
+		}
+
+		query = `
+			UPDATE reports SET
+				resolution = $1
+				, resolved_by = $2
+				, resolved_at = now()
+			WHERE id = $3`
+		if _, err := tx.ExecContext(ctx, query, action, uid, reportID); err != nil {
+			return fmt.Errorf("could not sql update report resolution: %w", err)
+		}
+
+		query = "SELECT array_agg(reporter_id) FROM report_reporters WHERE report_id = $1"
+		if err := tx.QueryRowContext(ctx, query, reportID).Scan(pq.Array(&reporterIDs)); err != nil {
+			return fmt.Errorf("could not sql query select report reporters: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	go s.notifyReportResolved(reporterIDs)
+
+	return nil
+}
+
+// hideReportTarget hides the reported post or comment.
+// Content that was already deleted is left alone.
+func hideReportTarget(ctx context.Context, tx *sql.Tx, t reportTarget) error {
+	var query string
+	switch t.typ {
+	case ReportTargetPost:
+		query = "UPDATE posts SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1"
+	case ReportTargetComment:
+		query = "UPDATE comments SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1"
+	default:
+		return ErrInvalidReportAction
+	}
+
+	if _, err := tx.ExecContext(ctx, query, t.id); err != nil {
+		return fmt.Errorf("could not sql update reported %s to hide it: %w", t.typ, err)
+	}
+
+	return nil
+}
+
+func validReportReason(reason string) bool {
+	switch reason {
+	case ReportReasonSpam,
+		ReportReasonHarassment,
+		ReportReasonHateSpeech,
+		ReportReasonViolence,
+		ReportReasonNSFW,
+		ReportReasonSpoiler,
+		ReportReasonImpersonation,
+		ReportReasonOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package nakama
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_ReportPost(t *testing.T) {
+	svc := &Service{}
+	postID := "00000000-0000-0000-0000-000000000002"
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.ReportPost(context.Background(), postID, ReportInput{Reason: ReportReasonSpam})
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+
+	t.Run("invalid_post_id", func(t *testing.T) {
+		err := svc.ReportPost(ctx, "nope", ReportInput{Reason: ReportReasonSpam})
+		testutil.WantEq(t, ErrInvalidPostID, err, "error")
+	})
+
+	t.Run("invalid_reason", func(t *testing.T) {
+		err := svc.ReportPost(ctx, postID, ReportInput{Reason: "boring"})
+		testutil.WantEq(t, ErrInvalidReportReason, err, "error")
+	})
+
+	t.Run("invalid_details", func(t *testing.T) {
+		details := strings.Repeat("x", reportDetailsMaxLength+1)
+		err := svc.ReportPost(ctx, postID, ReportInput{Reason: ReportReasonOther, Details: &details})
+		testutil.WantEq(t, ErrInvalidReportDetails, err, "error")
+	})
+}
+
+func TestService_ResolveReport(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.ResolveReport(context.Background(), "00000000-0000-0000-0000-000000000002", ReportActionDismiss)
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+
+	t.Run("invalid_report_id", func(t *testing.T) {
+		err := svc.ResolveReport(ctx, "nope", ReportActionDismiss)
+		testutil.WantEq(t, ErrInvalidReportID, err, "error")
+	})
+
+	t.Run("invalid_action", func(t *testing.T) {
+		err := svc.ResolveReport(ctx, "00000000-0000-0000-0000-000000000002", "ban")
+		testutil.WantEq(t, ErrInvalidReportAction, err, "error")
+	})
+
+	t.Run("moderation_queue", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping moderation queue integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB, Logger: log.NewNopLogger()}
+
+		author := createTestUser(t)
+		reporter := createTestUser(t)
+		other := createTestUser(t)
+		mod := createTestUser(t)
+
+		err := svc.SetUserRole(ctx, mod.Username, RoleModerator)
+		testutil.WantEq(t, nil, err, "set moderator role error")
+
+		var postID, commentID string
+		err = testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content, comments_count) VALUES ($1, 'test', 1) RETURNING id", author.ID).Scan(&postID)
+		testutil.WantEq(t, nil, err, "insert post error")
+
+		err = testDB.QueryRowContext(ctx, "INSERT INTO comments (user_id, post_id, content) VALUES ($1, $2, 'test') RETURNING id", author.ID, postID).Scan(&commentID)
+		testutil.WantEq(t, nil, err, "insert comment error")
+
+		authorCtx := context.WithValue(ctx, KeyAuthUserID, author.ID)
+		reporterCtx := context.WithValue(ctx, KeyAuthUserID, reporter.ID)
+		otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+		modCtx := context.WithValue(ctx, KeyAuthUserID, mod.ID)
+
+		err = svc.ReportPost(authorCtx, postID, ReportInput{Reason: ReportReasonSpam})
+		testutil.WantEq(t, ErrForbiddenReport, err, "self report error")
+
+		details := "  buy followers  "
+		err = svc.ReportPost(reporterCtx, postID, ReportInput{Reason: ReportReasonSpam, Details: &details})
+		testutil.WantEq(t, nil, err, "report post error")
+
+		err = svc.ReportPost(reporterCtx, postID, ReportInput{Reason: ReportReasonSpam})
+		testutil.WantEq(t, nil, err, "report post again error")
+
+		err = svc.ReportPost(otherCtx, postID, ReportInput{Reason: ReportReasonHarassment})
+		testutil.WantEq(t, nil, err, "other report post error")
+
+		err = svc.ReportComment(reporterCtx, commentID, ReportInput{Reason: ReportReasonSpoiler})
+		testutil.WantEq(t, nil, err, "report comment error")
+
+		err = svc.ReportUser(reporterCtx, author.Username, ReportInput{Reason: ReportReasonImpersonation})
+		testutil.WantEq(t, nil, err, "report user error")
+
+		_, err = svc.Reports(reporterCtx, 0, nil)
+		testutil.WantEq(t, ErrModerateReportsDenied, err, "user reports error")
+
+		err = svc.ResolveReport(reporterCtx, "00000000-0000-0000-0000-000000000002", ReportActionDismiss)
+		testutil.WantEq(t, ErrModerateReportsDenied, err, "user resolve report error")
+
+		rr, err := svc.Reports(modCtx, 0, nil)
+		testutil.WantEq(t, nil, err, "reports error")
+
+		byTarget := map[string]Report{}
+		for _, r := range rr {
+			byTarget[r.TargetID] = r
+		}
+
+		postReport := byTarget[postID]
+		testutil.WantEq(t, ReportTargetPost, postReport.TargetType, "post report target type")
+		testutil.WantEq(t, 2, postReport.ReportersCount, "post report reporters count")
+		testutil.WantEq(t, 2, len(postReport.Reasons), "post report reasons")
+		testutil.WantEq(t, []string{"buy followers"}, postReport.Details, "post report details")
+		testutil.WantEq(t, author.ID, postReport.Author.ID, "post report author")
+
+		commentReport := byTarget[commentID]
+		testutil.WantEq(t, ReportTargetComment, commentReport.TargetType, "comment report target type")
+		testutil.WantEq(t, []string{}, commentReport.Details, "comment report details")
+
+		userReport := byTarget[author.ID]
+		testutil.WantEq(t, ReportTargetUser, userReport.TargetType, "user report target type")
+
+		err = svc.ResolveReport(modCtx, userReport.ID, ReportActionHide)
+		testutil.WantEq(t, ErrInvalidReportAction, err, "hide user error")
+
+		err = svc.ResolveReport(modCtx, postReport.ID, ReportActionHide)
+		testutil.WantEq(t, nil, err, "hide post error")
+
+		err = svc.ResolveReport(modCtx, postReport.ID, ReportActionDismiss)
+		testutil.WantEq(t, ErrReportNotFound, err, "resolve report again error")
+
+		_, err = svc.Post(otherCtx, postID)
+		testutil.WantEq(t, ErrPostNotFound, err, "hidden post error")
+
+		p, err := svc.Post(authorCtx, postID)
+		testutil.WantEq(t, nil, err, "hidden post for author error")
+		testutil.WantEq(t, postID, p.ID, "hidden post for author ID")
+
+		err = svc.ReportPost(reporterCtx, postID, ReportInput{Reason: ReportReasonSpam})
+		testutil.WantEq(t, ErrPostNotFound, err, "report hidden post error")
+
+		err = svc.ResolveReport(modCtx, commentReport.ID, ReportActionDismiss)
+		testutil.WantEq(t, nil, err, "dismiss comment report error")
+
+		err = svc.ResolveReport(modCtx, userReport.ID, ReportActionSuspend)
+		testutil.WantEq(t, nil, err, "suspend user error")
+
+		err = checkNotSuspended(ctx, testDB, author.ID)
+		testutil.WantEq(t, ErrUserSuspended, err, "suspended error")
+	})
+}
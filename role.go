@@ -0,0 +1,297 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+// Role of a user.
+// Other than regular users, roles grant permissions
+// over content and accounts that are not their own.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// permission over someone else's content or account.
+type permission string
+
+const (
+	permDeleteAnyPost    permission = "delete_any_post"
+	permDeleteAnyComment permission = "delete_any_comment"
+	permSuspendUsers     permission = "suspend_users"
+	permModerateReports  permission = "moderate_reports"
+)
+
+// rolePermissions maps each role to the permissions it grants.
+// Regular users don't get any, they can only manage their own stuff.
+var rolePermissions = map[Role][]permission{
+	RoleModerator: {permDeleteAnyPost, permDeleteAnyComment, permSuspendUsers, permModerateReports},
+	RoleAdmin:     {permDeleteAnyPost, permDeleteAnyComment, permSuspendUsers, permModerateReports},
+}
+
+// roleRanks are used so a role cannot act upon an equal or higher one.
+// Like a moderator suspending an admin.
+var roleRanks = map[Role]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// userSuspended SQL condition tells whether the user from the users table
+// is currently suspended.
+const userSuspended = "(users.suspended_at IS NOT NULL AND (users.suspended_until IS NULL OR users.suspended_until > now()))"
+
+var (
+	// ErrInvalidRole denotes an invalid role, that is not user, moderator nor admin.
+	ErrInvalidRole = InvalidArgumentError("invalid role")
+	// ErrInvalidSuspendedUntil denotes an invalid suspension end, that is in the past.
+	ErrInvalidSuspendedUntil = InvalidArgumentError("invalid suspended until")
+	// ErrSuspendUserDenied denotes that the user is not allowed
+	// to suspend or unsuspend the given account.
+	ErrSuspendUserDenied = PermissionDeniedError("suspend user denied")
+	// ErrUserSuspended denotes that the user account is suspended
+	// and cannot log in nor use the API.
+	ErrUserSuspended = PermissionDeniedError("user suspended")
+	// ErrUserNotSuspended denotes that the user account is not suspended.
+	ErrUserNotSuspended = NotFoundError("user not suspended")
+)
+
+// ValidRole tells whether the given role is one of the known roles.
+func ValidRole(r Role) bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+func (r Role) can(perm permission) bool {
+	return slices.Contains(rolePermissions[r], perm)
+}
+
+func (r Role) outranks(other Role) bool {
+	return roleRanks[r] > roleRanks[other]
+}
+
+// authorize checks the given user role grants them the permission,
+// returning the given denied error otherwise.
+// It is meant for actions over someone else's content or account,
+// ownership checks are still up to each caller.
+func authorize(ctx context.Context, db queryRower, userID string, perm permission, denied error) error {
+	role, err := userRole(ctx, db, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.can(perm) {
+		return denied
+	}
+
+	return nil
+}
+
+// authorizeOver checks like authorize, and also that the user role
+// outranks the role of the given owner of the content or account.
+// So a moderator cannot delete an admin's post, for example.
+func authorizeOver(ctx context.Context, db queryRower, userID, ownerID string, perm permission, denied error) error {
+	role, err := userRole(ctx, db, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.can(perm) {
+		return denied
+	}
+
+	ownerRole, err := userRole(ctx, db, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if !role.outranks(ownerRole) {
+		return denied
+	}
+
+	return nil
+}
+
+func userRole(ctx context.Context, db queryRower, userID string) (Role, error) {
+	var role Role
+	query := "SELECT role FROM users WHERE id = $1"
+	err := db.QueryRowContext(ctx, query, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return role, ErrUserGone
+	}
+
+	if err != nil {
+		return role, fmt.Errorf("could not sql query select user role: %w", err)
+	}
+
+	return role, nil
+}
+
+// SetUserRole of the user with the given username.
+// It does no authorization, so it is only meant for trusted callers,
+// like the command line, and not to be exposed through the API.
+func (s *Service) SetUserRole(ctx context.Context, username string, role Role) error {
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	query := "UPDATE users SET role = $1 WHERE username = $2"
+	res, err := s.DB.ExecContext(ctx, query, role, username)
+	if err != nil {
+		return fmt.Errorf("could not sql update user role: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get updated user role rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SuspendUser with the given username until the given time,
+// or indefinitely if nil.
+// Only moderators and admins can suspend accounts,
+// and only those of a lower role.
+// Suspended users cannot log in and their tokens stop working right away.
+// Suspending an already suspended account updates its suspension end.
+func (s *Service) SuspendUser(ctx context.Context, username string, until *time.Time) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	if until != nil && !until.After(time.Now()) {
+		return ErrInvalidSuspendedUntil
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		suspendedID, err := authorizeSuspension(ctx, tx, uid, username)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE users SET
+				suspended_at = COALESCE(suspended_at, now())
+				, suspended_until = $1
+			WHERE id = $2`
+		if _, err := tx.ExecContext(ctx, query, until, suspendedID); err != nil {
+			return fmt.Errorf("could not sql update user suspension: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnsuspendUser with the given username, lifting its suspension early.
+func (s *Service) UnsuspendUser(ctx context.Context, username string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		suspendedID, err := authorizeSuspension(ctx, tx, uid, username)
+		if err != nil {
+			return err
+		}
+
+		query := "UPDATE users SET suspended_at = NULL, suspended_until = NULL WHERE id = $1 AND " + userSuspended
+		res, err := tx.ExecContext(ctx, query, suspendedID)
+		if err != nil {
+			return fmt.Errorf("could not sql update user to unsuspend: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get unsuspended user rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return ErrUserNotSuspended
+		}
+
+		return nil
+	})
+}
+
+// authorizeSuspension checks the given user can suspend the account
+// with the given username and returns its ID.
+func authorizeSuspension(ctx context.Context, tx *sql.Tx, uid, username string) (string, error) {
+	role, err := userRole(ctx, tx, uid)
+	if err != nil {
+		return "", err
+	}
+
+	if !role.can(permSuspendUsers) {
+		return "", ErrSuspendUserDenied
+	}
+
+	var suspendedID string
+	var suspendedRole Role
+	query := "SELECT id, role FROM users WHERE username = $1"
+	err = tx.QueryRowContext(ctx, query, username).Scan(&suspendedID, &suspendedRole)
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("could not sql query select user to suspend: %w", err)
+	}
+
+	if !role.outranks(suspendedRole) {
+		return "", ErrSuspendUserDenied
+	}
+
+	return suspendedID, nil
+}
+
+// checkNotSuspended returns ErrUserSuspended if the given user is suspended.
+func checkNotSuspended(ctx context.Context, db queryRower, userID string) error {
+	var suspended bool
+	query := "SELECT " + userSuspended + " FROM users WHERE id = $1"
+	err := db.QueryRowContext(ctx, query, userID).Scan(&suspended)
+	if err == sql.ErrNoRows {
+		return ErrUserGone
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not sql query select user suspension: %w", err)
+	}
+
+	if suspended {
+		return ErrUserSuspended
+	}
+
+	return nil
+}
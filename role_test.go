@@ -0,0 +1,157 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestRole(t *testing.T) {
+	testutil.WantEq(t, true, ValidRole(RoleModerator), "valid role")
+	testutil.WantEq(t, false, ValidRole(Role("root")), "invalid role")
+
+	testutil.WantEq(t, false, RoleUser.can(permDeleteAnyPost), "user can delete any post")
+	testutil.WantEq(t, true, RoleModerator.can(permDeleteAnyComment), "moderator can delete any comment")
+	testutil.WantEq(t, true, RoleAdmin.can(permSuspendUsers), "admin can suspend users")
+
+	testutil.WantEq(t, true, RoleModerator.outranks(RoleUser), "moderator outranks user")
+	testutil.WantEq(t, false, RoleModerator.outranks(RoleModerator), "moderator outranks moderator")
+	testutil.WantEq(t, false, RoleModerator.outranks(RoleAdmin), "moderator outranks admin")
+}
+
+func TestService_SetUserRole(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("invalid_username", func(t *testing.T) {
+		err := svc.SetUserRole(context.Background(), "@nope", RoleAdmin)
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("invalid_role", func(t *testing.T) {
+		err := svc.SetUserRole(context.Background(), "john", Role("root"))
+		testutil.WantEq(t, ErrInvalidRole, err, "error")
+	})
+}
+
+func TestService_SuspendUser(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.SuspendUser(context.Background(), "john", nil)
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_suspended_until", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		past := time.Now().Add(-time.Hour)
+		err := svc.SuspendUser(ctx, "john", &past)
+		testutil.WantEq(t, ErrInvalidSuspendedUntil, err, "error")
+	})
+
+	t.Run("moderation", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping moderation integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB, TokenKey: "supersecretkeyyoushouldnotcommit"}
+
+		author := createTestUser(t)
+		other := createTestUser(t)
+		mod := createTestUser(t)
+		admin := createTestUser(t)
+
+		err := svc.SetUserRole(ctx, mod.Username, RoleModerator)
+		testutil.WantEq(t, nil, err, "set moderator role error")
+
+		err = svc.SetUserRole(ctx, admin.Username, RoleAdmin)
+		testutil.WantEq(t, nil, err, "set admin role error")
+
+		var postID, commentID string
+		err = testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content, comments_count) VALUES ($1, 'test', 1) RETURNING id", author.ID).Scan(&postID)
+		testutil.WantEq(t, nil, err, "insert post error")
+
+		err = testDB.QueryRowContext(ctx, "INSERT INTO comments (user_id, post_id, content) VALUES ($1, $2, 'test') RETURNING id", author.ID, postID).Scan(&commentID)
+		testutil.WantEq(t, nil, err, "insert comment error")
+
+		otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+		modCtx := context.WithValue(ctx, KeyAuthUserID, mod.ID)
+		adminCtx := context.WithValue(ctx, KeyAuthUserID, admin.ID)
+
+		err = svc.DeleteComment(otherCtx, commentID)
+		testutil.WantEq(t, ErrDeleteCommentDenied, err, "delete someone else comment error")
+
+		err = svc.DeletePost(otherCtx, postID)
+		testutil.WantEq(t, ErrDeletePostDenied, err, "delete someone else post error")
+
+		err = svc.DeleteComment(modCtx, commentID)
+		testutil.WantEq(t, nil, err, "moderator delete comment error")
+
+		err = svc.DeletePost(modCtx, postID)
+		testutil.WantEq(t, nil, err, "moderator delete post error")
+
+		_, err = svc.Post(ctx, postID)
+		testutil.WantEq(t, ErrPostNotFound, err, "deleted post error")
+
+		var adminPostID, adminCommentID string
+		err = testDB.QueryRowContext(ctx, "INSERT INTO posts (user_id, content, comments_count) VALUES ($1, 'test', 1) RETURNING id", admin.ID).Scan(&adminPostID)
+		testutil.WantEq(t, nil, err, "insert admin post error")
+
+		err = testDB.QueryRowContext(ctx, "INSERT INTO comments (user_id, post_id, content) VALUES ($1, $2, 'test') RETURNING id", admin.ID, adminPostID).Scan(&adminCommentID)
+		testutil.WantEq(t, nil, err, "insert admin comment error")
+
+		err = svc.DeleteComment(modCtx, adminCommentID)
+		testutil.WantEq(t, ErrDeleteCommentDenied, err, "moderator delete admin comment error")
+
+		err = svc.DeletePost(modCtx, adminPostID)
+		testutil.WantEq(t, ErrDeletePostDenied, err, "moderator delete admin post error")
+
+		err = svc.DeletePost(adminCtx, adminPostID)
+		testutil.WantEq(t, nil, err, "admin delete own post error")
+
+		err = svc.SuspendUser(otherCtx, author.Username, nil)
+		testutil.WantEq(t, ErrSuspendUserDenied, err, "user suspend error")
+
+		err = svc.SuspendUser(modCtx, admin.Username, nil)
+		testutil.WantEq(t, ErrSuspendUserDenied, err, "moderator suspend admin error")
+
+		err = svc.SuspendUser(adminCtx, mod.Username, nil)
+		testutil.WantEq(t, nil, err, "admin suspend moderator error")
+
+		err = svc.UnsuspendUser(adminCtx, mod.Username)
+		testutil.WantEq(t, nil, err, "admin unsuspend moderator error")
+
+		out, err := svc.issueToken(ctx, author.ID)
+		testutil.WantEq(t, nil, err, "issue token error")
+
+		until := time.Now().Add(time.Hour)
+		err = svc.SuspendUser(modCtx, author.Username, &until)
+		testutil.WantEq(t, nil, err, "moderator suspend user error")
+
+		_, err = svc.AuthFromToken(ctx, out.Token)
+		testutil.WantEq(t, ErrUserSuspended, err, "suspended auth error")
+
+		_, err = svc.issueToken(ctx, author.ID)
+		testutil.WantEq(t, ErrUserSuspended, err, "suspended login error")
+
+		uu, err := svc.Users(ctx, author.Username, 10, nil)
+		testutil.WantEq(t, nil, err, "search suspended user error")
+		testutil.WantEq(t, 0, len(uu), "searched suspended users")
+
+		err = svc.UnsuspendUser(modCtx, author.Username)
+		testutil.WantEq(t, nil, err, "unsuspend user error")
+
+		err = svc.UnsuspendUser(modCtx, author.Username)
+		testutil.WantEq(t, ErrUserNotSuspended, err, "unsuspend user again error")
+
+		auth, err := svc.AuthFromToken(ctx, out.Token)
+		testutil.WantEq(t, nil, err, "unsuspended auth error")
+		testutil.WantEq(t, author.ID, auth.UserID, "auth user id")
+
+		u, err := svc.AuthUser(modCtx)
+		testutil.WantEq(t, nil, err, "auth user error")
+		testutil.WantEq(t, RoleModerator, u.Role, "auth user role")
+	})
+}
@@ -9,6 +9,7 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR NOT NULL UNIQUE,
     username VARCHAR NOT NULL UNIQUE,
     avatar VARCHAR,
+    -- deprecated: kept only to migrate them into user_identities.
     google_provider_id VARCHAR UNIQUE,
     github_provider_id VARCHAR UNIQUE,
     cover VARCHAR,
@@ -20,6 +21,35 @@ CREATE TABLE IF NOT EXISTS users (
     created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS scheduled_user_deletions ON users (deletion_scheduled_at);
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS search_vector TSVECTOR AS (
+    setweight(to_tsvector('simple', username), 'A')
+    || setweight(to_tsvector('simple', COALESCE(waifu, '') || ' ' || COALESCE(husbando, '')), 'B')
+    || setweight(to_tsvector('simple', COALESCE(bio, '')), 'C')
+) STORED;
+CREATE INVERTED INDEX IF NOT EXISTS users_search ON users (search_vector);
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS suggestions_refreshed_at TIMESTAMPTZ;
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS role VARCHAR NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'moderator', 'admin'));
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS staff_users ON users (role) WHERE role != 'user';
+
+CREATE TABLE IF NOT EXISTS username_history (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    username VARCHAR NOT NULL,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    released_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_username_history (username, released_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS account_deletion_codes (
+    code UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE TABLE IF NOT EXISTS email_verification_codes (
     email VARCHAR NOT NULL,
     code UUID NOT NULL DEFAULT gen_random_uuid(),
@@ -28,6 +58,145 @@ CREATE TABLE IF NOT EXISTS email_verification_codes (
 );
 
 ALTER TABLE IF EXISTS email_verification_codes ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users ON DELETE CASCADE;
+ALTER TABLE IF EXISTS email_verification_codes ADD COLUMN IF NOT EXISTS login_code VARCHAR;
+
+CREATE TABLE IF NOT EXISTS login_code_attempts (
+    email VARCHAR NOT NULL PRIMARY KEY,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS rate_limit_hits (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    INDEX sorted_key_rate_limit_hits (key, created_at),
+    INDEX expired_rate_limit_hits (expires_at)
+);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider VARCHAR NOT NULL,
+    subject VARCHAR NOT NULL,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, subject),
+    UNIQUE INDEX user_provider (user_id, provider)
+);
+
+INSERT INTO user_identities (provider, subject, user_id)
+    SELECT 'google', google_provider_id, id FROM users WHERE google_provider_id IS NOT NULL
+    ON CONFLICT DO NOTHING;
+INSERT INTO user_identities (provider, subject, user_id)
+    SELECT 'github', github_provider_id, id FROM users WHERE github_provider_id IS NOT NULL
+    ON CONFLICT DO NOTHING;
+UPDATE users SET google_provider_id = NULL, github_provider_id = NULL
+    WHERE google_provider_id IS NOT NULL OR github_provider_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    user_agent VARCHAR,
+    ip VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    INDEX sorted_user_sessions (user_id, last_seen_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_hash BYTES NOT NULL PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES sessions ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    used_at TIMESTAMPTZ,
+    INDEX session_refresh_tokens (session_id)
+);
+
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    token_hash BYTES NOT NULL UNIQUE,
+    scopes VARCHAR[] NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_user_personal_access_tokens (user_id, created_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_apps (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    redirect_uris VARCHAR[] NOT NULL,
+    scopes VARCHAR[] NOT NULL,
+    client_secret_hash BYTES NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_user_oauth_apps (user_id, created_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    code_hash BYTES NOT NULL PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES oauth_apps ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    redirect_uri VARCHAR NOT NULL,
+    scopes VARCHAR[] NOT NULL,
+    code_challenge VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+ALTER TABLE IF EXISTS oauth_authorization_codes ADD COLUMN IF NOT EXISTS redirect_uri_given BOOL NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS oauth_access_tokens (
+    token_hash BYTES NOT NULL PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES oauth_apps ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    scopes VARCHAR[] NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX user_oauth_access_tokens (user_id, app_id)
+);
+
+CREATE TABLE IF NOT EXISTS passkeys (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    credential_id BYTES NOT NULL UNIQUE,
+    name VARCHAR NOT NULL,
+    credential JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    INDEX sorted_user_passkeys (user_id, created_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS passkey_ceremonies (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users ON DELETE CASCADE,
+    session JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS totp_secrets (
+    user_id UUID NOT NULL PRIMARY KEY REFERENCES users ON DELETE CASCADE,
+    secret VARCHAR NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_used_step INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+ALTER TABLE IF EXISTS totp_secrets ADD COLUMN IF NOT EXISTS failed_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE IF EXISTS totp_secrets ADD COLUMN IF NOT EXISTS last_failed_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    code_hash BYTES NOT NULL,
+    used_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS second_factor_challenges (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
 
 CREATE TABLE IF NOT EXISTS follows (
     follower_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
@@ -35,6 +204,61 @@ CREATE TABLE IF NOT EXISTS follows (
     PRIMARY KEY (follower_id, followee_id)
 );
 
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX blocked_users (blocked_id)
+);
+
+CREATE TABLE IF NOT EXISTS follow_requests (
+    follower_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id),
+    INDEX followee_follow_requests (followee_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_suggestions (
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    suggested_user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    score FLOAT NOT NULL,
+    PRIMARY KEY (user_id, suggested_user_id),
+    INDEX ranked_user_suggestions (user_id, score DESC)
+);
+
+CREATE TABLE IF NOT EXISTS user_lists (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    private BOOLEAN NOT NULL DEFAULT false,
+    members_count INT NOT NULL DEFAULT 0 CHECK (members_count >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX sorted_user_lists (user_id, created_at DESC)
+);
+
+CREATE TABLE IF NOT EXISTS user_list_members (
+    list_id UUID NOT NULL REFERENCES user_lists ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (list_id, user_id),
+    INDEX user_list_memberships (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS mutes (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    type VARCHAR NOT NULL,
+    muted_user_id UUID REFERENCES users ON DELETE CASCADE,
+    value VARCHAR,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE INDEX unique_user_muted_users (user_id, muted_user_id),
+    UNIQUE INDEX unique_user_muted_values (user_id, type, value),
+    INDEX sorted_user_mutes (user_id, created_at DESC)
+);
+
 CREATE TABLE IF NOT EXISTS posts (
     id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
@@ -49,6 +273,10 @@ CREATE TABLE IF NOT EXISTS posts (
     INDEX sorted_posts (created_at DESC, id)
 );
 
+ALTER TABLE IF EXISTS posts ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS pinned_posts ON posts (user_id, pinned_at DESC) WHERE pinned_at IS NOT NULL;
+ALTER TABLE IF EXISTS posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
 CREATE TABLE IF NOT EXISTS post_reactions (
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
     post_id UUID NOT NULL REFERENCES posts ON DELETE CASCADE,
@@ -80,6 +308,8 @@ CREATE TABLE IF NOT EXISTS comments (
     INDEX sorted_comments (created_at DESC, id)
 );
 
+ALTER TABLE IF EXISTS comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
 CREATE TABLE IF NOT EXISTS comment_reactions (
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
     comment_id UUID NOT NULL REFERENCES comments ON DELETE CASCADE,
@@ -98,7 +328,9 @@ CREATE TABLE IF NOT EXISTS post_tags (
 CREATE TABLE IF NOT EXISTS notifications (
     id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
-    actors VARCHAR[] NOT NULL,
+    -- deprecated: kept only to migrate them into actor_ids.
+    actors VARCHAR[],
+    actor_ids UUID[] NOT NULL DEFAULT ARRAY[]:::UUID[],
     type VARCHAR NOT NULL,
     post_id UUID REFERENCES posts ON DELETE CASCADE,
     read_at TIMESTAMPTZ,
@@ -107,6 +339,15 @@ CREATE TABLE IF NOT EXISTS notifications (
     UNIQUE INDEX unique_notifications (user_id, type, post_id, read_at)
 );
 
+ALTER TABLE IF EXISTS notifications ADD COLUMN IF NOT EXISTS actor_ids UUID[] NOT NULL DEFAULT ARRAY[]:::UUID[];
+ALTER TABLE IF EXISTS notifications ALTER COLUMN actors DROP NOT NULL;
+UPDATE notifications SET actor_ids = ARRAY(
+        SELECT users.id FROM unnest(notifications.actors) WITH ORDINALITY AS actor (username, i)
+        INNER JOIN users ON users.username = actor.username
+        ORDER BY actor.i
+    ), actors = NULL
+    WHERE actors IS NOT NULL;
+
 CREATE TABLE IF NOT EXISTS user_web_push_subscriptions (
     id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
@@ -115,6 +356,62 @@ CREATE TABLE IF NOT EXISTS user_web_push_subscriptions (
     UNIQUE INDEX unique_user_web_push_subscriptions (user_id, (sub->>'endpoint'::TEXT))
 );
 
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    status VARCHAR NOT NULL,
+    file_name VARCHAR,
+    token_hash BYTES UNIQUE,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE INDEX unique_pending_user_data_exports (user_id) WHERE status = 'pending',
+    INDEX sorted_pending_data_exports (status, created_at)
+);
+
+-- a background job lease lets a single instance run the job
+-- until expires_at.
+CREATE TABLE IF NOT EXISTS background_job_leases (
+    name VARCHAR NOT NULL PRIMARY KEY,
+    holder VARCHAR NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS orphaned_files (
+    bucket VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (bucket, name)
+);
+
+-- a report groups every user report on the same target
+-- until a moderator resolves it.
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    target_type VARCHAR NOT NULL CHECK (target_type IN ('post', 'comment', 'user')),
+    target_id UUID NOT NULL,
+    author_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    post_id UUID REFERENCES posts ON DELETE CASCADE,
+    reporters_count INT NOT NULL DEFAULT 0 CHECK (reporters_count >= 0),
+    resolution VARCHAR CHECK (resolution IN ('dismiss', 'hide', 'suspend')),
+    resolved_by UUID REFERENCES users ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_reported_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE INDEX unique_open_reports (target_type, target_id) WHERE resolved_at IS NULL,
+    INDEX sorted_open_reports (created_at, id) WHERE resolved_at IS NULL
+);
+
+CREATE TABLE IF NOT EXISTS report_reporters (
+    report_id UUID NOT NULL REFERENCES reports ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+    reason VARCHAR NOT NULL,
+    details VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (report_id, reporter_id)
+);
+
 -- INSERT INTO users (id, email, username) VALUES
 --     ('24ca6ce6-b3e9-4276-a99a-45c77115cc9f', 'shinji@example.org', 'shinji'),
 --     ('93dfcef9-0b45-46ae-933c-ea52fbf80edb', 'rei@example.org', 'rei');
@@ -0,0 +1,264 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sessionLastSeenResolution is how often the last seen time of a session
+// gets written down. So we don't write on every single request.
+const sessionLastSeenResolution = time.Minute * 5
+
+var (
+	// ErrInvalidSessionID denotes an invalid session ID; that is not uuid.
+	ErrInvalidSessionID = InvalidArgumentError("invalid session ID")
+	// ErrSessionNotFound denotes a not found session.
+	ErrSessionNotFound = NotFoundError("session not found")
+	// ErrSessionRevoked denotes that the session the token belongs to
+	// has been revoked, either by logging out or by the user from another device.
+	ErrSessionRevoked = UnauthenticatedError("session revoked")
+)
+
+// Session model.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"userAgent"`
+	Device     *string   `json:"device"`
+	IP         *string   `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// Sessions of the authenticated user that are still active.
+// Most recently seen first.
+func (s *Service) Sessions(ctx context.Context) ([]Session, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	sid, _ := ctx.Value(KeyAuthSessionID).(string)
+
+	query := `
+		SELECT id, user_agent, ip, created_at, last_seen_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > now()
+		ORDER BY last_seen_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, uid)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select sessions: %w", err)
+	}
+
+	defer rows.Close()
+
+	var ss []Session
+	for rows.Next() {
+		var sess Session
+		err := rows.Scan(
+			&sess.ID,
+			&sess.UserAgent,
+			&sess.IP,
+			&sess.CreatedAt,
+			&sess.LastSeenAt,
+			&sess.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan session: %w", err)
+		}
+
+		if sess.UserAgent != nil {
+			sess.Device = deviceFromUserAgent(*sess.UserAgent)
+		}
+		sess.Current = sess.ID == sid
+		ss = append(ss, sess)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate session rows: %w", err)
+	}
+
+	return ss, nil
+}
+
+// RevokeSession from the authenticated user.
+// Tokens issued for that session will stop working right away.
+func (s *Service) RevokeSession(ctx context.Context, sessionID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(sessionID) {
+		return ErrInvalidSessionID
+	}
+
+	query := "DELETE FROM sessions WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, sessionID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete session: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted session rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// Logout revokes the current session.
+func (s *Service) Logout(ctx context.Context) error {
+	sid, ok := ctx.Value(KeyAuthSessionID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	return s.RevokeSession(ctx, sid)
+}
+
+// LogoutEverywhere revokes all the sessions from the authenticated user,
+// the current one included.
+func (s *Service) LogoutEverywhere(ctx context.Context) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	query := "DELETE FROM sessions WHERE user_id = $1"
+	_, err := s.DB.ExecContext(ctx, query, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// createSession for the given user, recording the client info found in context.
+// The session expiration is that of its refresh tokens.
+func createSession(ctx context.Context, tx *sql.Tx, userID string) (string, time.Time, error) {
+	userAgent, _ := ctx.Value(KeyUserAgent).(string)
+	ip, _ := ctx.Value(KeyClientIP).(string)
+
+	var sid string
+	expiresAt := time.Now().Add(refreshTokenTTL)
+	query := `
+		INSERT INTO sessions (user_id, user_agent, ip, expires_at) VALUES ($1, $2, $3, $4)
+		RETURNING id`
+	row := tx.QueryRowContext(ctx, query, userID, emptyStrPtr(userAgent), emptyStrPtr(ip), expiresAt)
+	err := row.Scan(&sid)
+	if isForeignKeyViolation(err) {
+		return sid, expiresAt, ErrUserGone
+	}
+
+	if err != nil {
+		return sid, expiresAt, fmt.Errorf("could not sql insert session: %w", err)
+	}
+
+	return sid, expiresAt, nil
+}
+
+// sessionUserID checks the given session is still active
+// and its user not suspended, and returns its user ID.
+// It also takes care of updating the session last seen time.
+func (s *Service) sessionUserID(ctx context.Context, sessionID string) (string, error) {
+	var uid string
+	var lastSeenAt, expiresAt time.Time
+	var suspended bool
+	query := `
+		SELECT sessions.user_id, sessions.last_seen_at, sessions.expires_at, ` + userSuspended + `
+		FROM sessions
+		INNER JOIN users ON sessions.user_id = users.id
+		WHERE sessions.id = $1`
+	row := s.DB.QueryRowContext(ctx, query, sessionID)
+	err := row.Scan(&uid, &lastSeenAt, &expiresAt, &suspended)
+	if err == sql.ErrNoRows {
+		return "", ErrSessionRevoked
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("could not sql query select session: %w", err)
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return "", ErrExpiredToken
+	}
+
+	if suspended {
+		return "", ErrUserSuspended
+	}
+
+	if time.Since(lastSeenAt) >= sessionLastSeenResolution {
+		userAgent, _ := ctx.Value(KeyUserAgent).(string)
+		ip, _ := ctx.Value(KeyClientIP).(string)
+		go s.touchSession(sessionID, userAgent, ip)
+	}
+
+	return uid, nil
+}
+
+func (s *Service) touchSession(sessionID, userAgent, ip string) {
+	query := `
+		UPDATE sessions SET
+			last_seen_at = now()
+			, user_agent = COALESCE($1, user_agent)
+			, ip = COALESCE($2, ip)
+		WHERE id = $3`
+	_, err := s.DB.Exec(query, emptyStrPtr(userAgent), emptyStrPtr(ip), sessionID)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not update session last seen: %w", err))
+	}
+}
+
+// deviceFromUserAgent makes a best effort to give a human readable
+// description like "Firefox on Linux" out of a user agent string.
+func deviceFromUserAgent(ua string) *string {
+	var browser, os string
+
+	switch {
+	case strings.Contains(ua, "Edg/") || strings.Contains(ua, "Edge/"):
+		browser = "Edge"
+	case strings.Contains(ua, "OPR/") || strings.Contains(ua, "Opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "Firefox/") || strings.Contains(ua, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "Chrome/") || strings.Contains(ua, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPad"):
+		os = "iOS"
+	case strings.Contains(ua, "Android"):
+		os = "Android"
+	case strings.Contains(ua, "Windows"):
+		os = "Windows"
+	case strings.Contains(ua, "Mac OS X") || strings.Contains(ua, "Macintosh"):
+		os = "macOS"
+	case strings.Contains(ua, "CrOS"):
+		os = "ChromeOS"
+	case strings.Contains(ua, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return ptrString(browser + " on " + os)
+	case browser != "":
+		return &browser
+	case os != "":
+		return &os
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package nakama
+
+import "testing"
+
+func Test_deviceFromUserAgent(t *testing.T) {
+	tt := []struct {
+		name string
+		ua   string
+		want *string
+	}{
+		{
+			name: "firefox_linux",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0",
+			want: ptrString("Firefox on Linux"),
+		},
+		{
+			name: "chrome_windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			want: ptrString("Chrome on Windows"),
+		},
+		{
+			name: "edge_windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			want: ptrString("Edge on Windows"),
+		},
+		{
+			name: "safari_ios",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			want: ptrString("Safari on iOS"),
+		},
+		{
+			name: "chrome_android",
+			ua:   "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			want: ptrString("Chrome on Android"),
+		},
+		{
+			name: "unknown",
+			ua:   "curl/8.4.0",
+			want: nil,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := deviceFromUserAgent(tc.ua)
+			if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+				t.Errorf("deviceFromUserAgent(%q) = %v, want %v", tc.ua, got, tc.want)
+			}
+		})
+	}
+}
@@ -26,7 +26,11 @@ func (s *Store) init() {
 	}
 }
 
-func (s *Store) Store(_ context.Context, bucket, name string, data []byte, opts ...func(*storage.StoreOpts)) error {
+func (s *Store) Store(ctx context.Context, bucket, name string, data []byte, opts ...func(*storage.StoreOpts)) error {
+	return s.StoreFrom(ctx, bucket, name, bytes.NewReader(data), int64(len(data)), opts...)
+}
+
+func (s *Store) StoreFrom(_ context.Context, bucket, name string, r io.Reader, size int64, opts ...func(*storage.StoreOpts)) error {
 	s.once.Do(s.init)
 
 	f, err := os.Create(filepath.Join(s.Root, bucket, name))
@@ -44,7 +48,7 @@ func (s *Store) Store(_ context.Context, bucket, name string, data []byte, opts
 
 	defer f.Close()
 
-	_, err = io.Copy(f, bytes.NewReader(data))
+	_, err = io.CopyN(f, r, size)
 	if err != nil {
 		return fmt.Errorf("could not copy data to file: %w", err)
 	}
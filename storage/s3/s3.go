@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -59,13 +60,17 @@ func (s *Store) Setup(ctx context.Context) error {
 
 // Store a file.
 func (s *Store) Store(ctx context.Context, bucket, name string, data []byte, opts ...func(*storage.StoreOpts)) error {
+	return s.StoreFrom(ctx, bucket, name, bytes.NewReader(data), int64(len(data)), opts...)
+}
+
+// StoreFrom stores a file read from r.
+// Big files get uploaded in parts.
+func (s *Store) StoreFrom(ctx context.Context, bucket, name string, r io.Reader, size int64, opts ...func(*storage.StoreOpts)) error {
 	var options storage.StoreOpts
 	for _, o := range opts {
 		o(&options)
 	}
 
-	r := bytes.NewReader(data)
-	size := int64(len(data))
 	_, err := s.client.PutObject(ctx, bucket, name, r, size, minio.PutObjectOptions{
 		ContentType:     options.ContentType,
 		ContentEncoding: options.ContentEncoding,
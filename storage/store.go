@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"io"
 )
 
 // ErrNotFound denotes that the object does not exists.
@@ -11,6 +12,9 @@ var ErrNotFound = errors.New("not found")
 // Store interface.
 type Store interface {
 	Store(ctx context.Context, bucket, name string, data []byte, opts ...func(*StoreOpts)) (err error)
+	// StoreFrom stores size bytes read from r,
+	// without holding the whole file in memory.
+	StoreFrom(ctx context.Context, bucket, name string, r io.Reader, size int64, opts ...func(*StoreOpts)) (err error)
 	Open(ctx context.Context, bucket, name string) (f *File, err error)
 	Delete(ctx context.Context, bucket, name string) (err error)
 }
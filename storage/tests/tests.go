@@ -45,4 +45,22 @@ func RunStoreTests(t *testing.T, store storage.Store, bucket string) {
 
 	err = store.Delete(ctx, bucket, logoName)
 	testutil.WantEq(t, nil, err, "error")
+
+	_, err = logoFile.Seek(0, io.SeekStart)
+	testutil.WantEq(t, nil, err, "seek")
+
+	err = store.StoreFrom(ctx, bucket, logoName, logoFile, int64(len(logoBytes)), storage.StoreWithContentType(logoContentType))
+	testutil.WantEq(t, nil, err, "store from error")
+
+	f, err = store.Open(ctx, bucket, logoName)
+	testutil.WantEq(t, nil, err, "open stored from error")
+
+	t.Cleanup(func() { f.Close() })
+
+	gotBytes, err = io.ReadAll(f)
+	testutil.WantEq(t, nil, err, "read stored from error")
+	testutil.WantEq(t, logoBytes, gotBytes, "stored from bytes")
+
+	err = store.Delete(ctx, bucket, logoName)
+	testutil.WantEq(t, nil, err, "error")
 }
@@ -0,0 +1,238 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+const (
+	// userSuggestionsTTL is how long cached user suggestions are served
+	// before being refreshed.
+	userSuggestionsTTL = time.Hour * 24
+	// userSuggestionsLimit is how many suggestions are cached per user.
+	userSuggestionsLimit = 50
+	// userSuggestionsBatch is how many users get their suggestions refreshed per job run.
+	userSuggestionsBatch = 100
+	// userSuggestionsActivityWindow is how far back posts count as recent activity,
+	// and how recently users must have been seen to get their suggestions refreshed.
+	userSuggestionsActivityWindow = time.Hour * 24 * 7
+)
+
+// SuggestedUsers for the authenticated user to follow.
+// Ranked by how many of their followees follow them,
+// hashtags they share and how active they have been lately.
+// Suggestions are cached and refreshed periodically in the background.
+func (s *Service) SuggestedUsers(ctx context.Context, first uint64) (UserProfiles, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var refreshed bool
+	query := "SELECT suggestions_refreshed_at IS NOT NULL FROM users WHERE id = $1"
+	err := s.DB.QueryRowContext(ctx, query, uid).Scan(&refreshed)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserGone
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select user suggestions refreshed: %w", err)
+	}
+
+	// first time: compute them right away instead of waiting for the job.
+	if !refreshed {
+		if err := s.refreshUserSuggestions(ctx, uid); err != nil {
+			return nil, err
+		}
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT users.username
+		, users.avatar
+		, users.cover
+		, users.bio
+		, users.waifu
+		, users.husbando
+		, users.followers_count
+		, users.followees_count
+		, users.private
+		, follow_requests.follower_id IS NOT NULL AS follow_requested
+		FROM user_suggestions
+		INNER JOIN users ON user_suggestions.suggested_user_id = users.id
+		LEFT JOIN follow_requests
+			ON follow_requests.follower_id = @uid AND follow_requests.followee_id = users.id
+		WHERE user_suggestions.user_id = @uid
+			AND NOT EXISTS (
+				SELECT 1 FROM follows
+				WHERE follows.follower_id = @uid AND follows.followee_id = users.id
+			)
+			AND `+notBlocked("users.id")+`
+			AND NOT `+userSuspended+`
+		ORDER BY user_suggestions.score DESC, users.username ASC
+		LIMIT @first`, map[string]interface{}{
+		"uid":   uid,
+		"first": first,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build suggested users sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select suggested users: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu UserProfiles
+	for rows.Next() {
+		var u UserProfile
+		var avatar, cover sql.NullString
+		err := rows.Scan(
+			&u.Username,
+			&avatar,
+			&cover,
+			&u.Bio,
+			&u.Waifu,
+			&u.Husbando,
+			&u.FollowersCount,
+			&u.FolloweesCount,
+			&u.Private,
+			&u.FollowRequested,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan suggested user: %w", err)
+		}
+
+		u.AvatarURL = s.avatarURL(avatar)
+		u.CoverURL = s.coverURL(cover)
+		uu = append(uu, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate suggested user rows: %w", err)
+	}
+
+	return uu, nil
+}
+
+// refreshStaleUserSuggestions of recently seen users
+// whose suggestions are older than userSuggestionsTTL.
+func (s *Service) refreshStaleUserSuggestions(ctx context.Context) (int64, error) {
+	now := time.Now()
+	query := `
+		SELECT id FROM users
+		WHERE (suggestions_refreshed_at IS NULL OR suggestions_refreshed_at < $1)
+			AND deletion_scheduled_at IS NULL
+			AND EXISTS (
+				SELECT 1 FROM sessions
+				WHERE sessions.user_id = users.id AND sessions.last_seen_at > $2
+			)
+		ORDER BY suggestions_refreshed_at ASC NULLS FIRST
+		LIMIT $3`
+	rows, err := s.DB.QueryContext(ctx, query, now.Add(-userSuggestionsTTL), now.Add(-userSuggestionsActivityWindow), userSuggestionsBatch)
+	if err != nil {
+		return 0, fmt.Errorf("could not sql query select users with stale suggestions: %w", err)
+	}
+
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return 0, fmt.Errorf("could not sql scan user with stale suggestions: %w", err)
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("could not iterate users with stale suggestions: %w", err)
+	}
+
+	var n int64
+	for _, userID := range userIDs {
+		if err := s.refreshUserSuggestions(ctx, userID); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// refreshUserSuggestions recomputes and caches the suggestions for the given user.
+// Candidates come from three sources that add up to their score:
+// followees of their followees, users that share hashtags with them,
+// and users that posted recently.
+// Followees, blocks either way, suspended accounts
+// and accounts scheduled for deletion are left out.
+func (s *Service) refreshUserSuggestions(ctx context.Context, userID string) error {
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		query := "DELETE FROM user_suggestions WHERE user_id = $1"
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("could not sql delete user suggestions: %w", err)
+		}
+
+		query = `
+			INSERT INTO user_suggestions (user_id, suggested_user_id, score)
+			SELECT $1, candidates.user_id, sum(candidates.score) AS score
+			FROM (
+				SELECT followees_follows.followee_id AS user_id, count(*)::FLOAT AS score
+				FROM follows AS followees
+				INNER JOIN follows AS followees_follows
+					ON followees_follows.follower_id = followees.followee_id
+				WHERE followees.follower_id = $1
+				GROUP BY followees_follows.followee_id
+				UNION ALL
+				SELECT posts.user_id, count(DISTINCT lower(post_tags.tag))::FLOAT / 2
+				FROM post_tags
+				INNER JOIN posts ON post_tags.post_id = posts.id
+				WHERE lower(post_tags.tag) IN (
+					SELECT lower(own_tags.tag) FROM post_tags AS own_tags
+					INNER JOIN posts AS own_posts ON own_tags.post_id = own_posts.id
+					WHERE own_posts.user_id = $1
+				)
+				GROUP BY posts.user_id
+				UNION ALL
+				SELECT posts.user_id, ln(1 + count(*)::FLOAT) / 4
+				FROM posts
+				WHERE posts.created_at > $2
+				GROUP BY posts.user_id
+			) AS candidates
+			INNER JOIN users ON candidates.user_id = users.id
+			WHERE candidates.user_id != $1
+				AND users.deletion_scheduled_at IS NULL
+				AND NOT ` + userSuspended + `
+				AND NOT EXISTS (
+					SELECT 1 FROM follows
+					WHERE follows.follower_id = $1 AND follows.followee_id = candidates.user_id
+				)
+				AND NOT EXISTS (
+					SELECT 1 FROM blocks
+					WHERE (blocks.blocker_id = $1 AND blocks.blocked_id = candidates.user_id)
+						OR (blocks.blocker_id = candidates.user_id AND blocks.blocked_id = $1)
+				)
+			GROUP BY candidates.user_id
+			ORDER BY score DESC
+			LIMIT $3`
+		_, err := tx.ExecContext(ctx, query, userID, time.Now().Add(-userSuggestionsActivityWindow), userSuggestionsLimit)
+		if err != nil {
+			return fmt.Errorf("could not sql insert user suggestions: %w", err)
+		}
+
+		query = "UPDATE users SET suggestions_refreshed_at = now() WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("could not sql update user suggestions refreshed at: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_SuggestedUsers(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := svc.SuggestedUsers(context.Background(), 0)
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("friends_of_friends", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping user suggestions integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		user := createTestUser(t)
+		followee := createTestUser(t)
+		suggested := createTestUser(t)
+		blockedUser := createTestUser(t)
+
+		_, err := testDB.ExecContext(ctx, `
+			INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2), ($2, $3), ($2, $4)`,
+			user.ID, followee.ID, suggested.ID, blockedUser.ID)
+		testutil.WantEq(t, nil, err, "insert follows error")
+
+		_, err = testDB.ExecContext(ctx, "INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1, $2)", blockedUser.ID, user.ID)
+		testutil.WantEq(t, nil, err, "insert block error")
+
+		userCtx := context.WithValue(ctx, KeyAuthUserID, user.ID)
+		uu, err := svc.SuggestedUsers(userCtx, 0)
+		testutil.WantEq(t, nil, err, "suggested users error")
+
+		var found bool
+		for _, u := range uu {
+			testutil.WantEq(t, true, u.Username != user.Username, "self suggested")
+			testutil.WantEq(t, true, u.Username != followee.Username, "followee suggested")
+			testutil.WantEq(t, true, u.Username != blockedUser.Username, "blocked user suggested")
+			if u.Username == suggested.Username {
+				found = true
+			}
+		}
+		testutil.WantEq(t, true, found, "friend of friend suggested")
+	})
+}
@@ -8,8 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
 	"io"
 	"strings"
 	"sync"
@@ -17,13 +15,9 @@ import (
 	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
-	"github.com/disintegration/imaging"
-	"github.com/go-kit/log/level"
 	"github.com/lib/pq"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"golang.org/x/sync/errgroup"
-
-	"github.com/nakamauwu/nakama/storage"
 )
 
 const MediaBucket = "media"
@@ -51,6 +45,8 @@ type TimelineItem struct {
 }
 
 // CreateTimelineItem publishes a post to the user timeline and fan-outs it to his followers.
+// Animated GIF and WebP media keep their frames, and a static poster
+// of the first frame is stored next to them; see posterFileName.
 func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (TimelineItem, error) {
 	var ti TimelineItem
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
@@ -73,9 +69,8 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 	tags := collectTags(content)
 
 	type File struct {
-		Name        string
-		ContentType string
-		Content     []byte
+		Name  string
+		Image encodedImage
 	}
 
 	var files []File
@@ -96,27 +91,20 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 					return fmt.Errorf("create timeline item: detect media content type: %w", err)
 				}
 
-				if ct != "image/png" && ct != "image/jpeg" {
-					return ErrUnsupportedAvatarFormat
-				}
-
-				img, err := imaging.Decode(io.LimitReader(mediaItem, MaxMediaItemBytes), imaging.AutoOrientation(true))
-				if err == image.ErrFormat {
+				if !supportedImageType(ct) {
 					return ErrUnsupportedMediaItemFormat
 				}
 
+				b, err := io.ReadAll(io.LimitReader(mediaItem, MaxMediaItemBytes))
 				if err != nil {
-					return fmt.Errorf("could not image decode post media item: %w", err)
+					return fmt.Errorf("could not read post media item: %w", err)
 				}
 
-				buf := &bytes.Buffer{}
-				if ct == "image/png" {
-					err = png.Encode(buf, img)
-				} else {
-					err = jpeg.Encode(buf, img, nil)
-				}
+				img, err := encodeImage(b, ct, MaxMediaItemBytes, func(img image.Image) image.Image {
+					return img
+				}, ErrUnsupportedMediaItemFormat)
 				if err != nil {
-					return fmt.Errorf("could not encode post media item: %w", err)
+					return err
 				}
 
 				fileName, err := gonanoid.New()
@@ -124,18 +112,13 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 					return fmt.Errorf("could not generate media item filename: %w", err)
 				}
 
-				if ct == "image/png" {
-					fileName += ".png"
-				} else {
-					fileName += ".jpg"
-				}
+				fileName += img.ext
 
 				mu.Lock()
 
 				files[i] = File{
-					Name:        fileName,
-					ContentType: ct,
-					Content:     buf.Bytes(),
+					Name:  fileName,
+					Image: img,
 				}
 
 				mu.Unlock()
@@ -151,7 +134,7 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 	var mediaItemsBytes int64
 	var fileNames []string
 	for _, file := range files {
-		mediaItemsBytes += int64(len(file.Content))
+		mediaItemsBytes += int64(len(file.Image.content))
 		fileNames = append(fileNames, file.Name)
 	}
 
@@ -164,7 +147,7 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 		for _, file := range files {
 			file := file
 			g.Go(func() error {
-				err := s.Store.Store(gctx, MediaBucket, file.Name, file.Content, storage.StoreWithContentType(file.ContentType))
+				err := s.storeImage(gctx, MediaBucket, file.Name, file.Image)
 				if err != nil {
 					return fmt.Errorf("could not store post media item: %w", err)
 				}
@@ -234,23 +217,8 @@ func (s *Service) CreateTimelineItem(ctx context.Context, content string, spoile
 		return nil
 	})
 	if err != nil {
-		if len(files) != 0 {
-			go func() {
-				g, gctx := errgroup.WithContext(ctx)
-				for _, fileName := range fileNames {
-					fileName := fileName
-					g.Go(func() error {
-						err := s.Store.Delete(gctx, MediaBucket, fileName)
-						if err != nil {
-							return fmt.Errorf("could not delete post media item: %w", err)
-						}
-						return nil
-					})
-				}
-				if err := g.Wait(); err != nil {
-					_ = level.Error(s.Logger).Log("msg", "could not delete post media items", "err", err)
-				}
-			}()
+		for _, fileName := range fileNames {
+			go s.deleteFile(MediaBucket, fileName)
 		}
 
 		return ti, err
@@ -274,6 +242,7 @@ func (s *Service) postCreated(p Post) {
 
 	go s.broadcastPost(p)
 	go s.fanoutPost(p)
+	go s.fanoutListPost(p)
 	go s.notifyPostMention(p)
 }
 
@@ -323,6 +292,7 @@ func (s *Service) Timeline(ctx context.Context, last uint64, before *string) (Ti
 		, posts.media
 		, posts.created_at
 		, posts.updated_at
+		, posts.pinned_at IS NOT NULL AS post_pinned
 		, posts.user_id = @uid AS post_mine
 		, subscriptions.user_id IS NOT NULL AS post_subscribed
 		, users.username
@@ -340,6 +310,9 @@ func (s *Service) Timeline(ctx context.Context, last uint64, before *string) (Ti
 		LEFT JOIN post_subscriptions AS subscriptions
 			ON subscriptions.user_id = @uid AND subscriptions.post_id = posts.id
 		WHERE timeline.user_id = @uid
+		AND `+visiblePost()+`
+		AND `+notBlocked("posts.user_id")+`
+		AND `+notMutedPost()+`
 		{{ if and .beforePostID .beforeCreatedAt }}
 			AND posts.created_at <= @beforeCreatedAt
 			AND (
@@ -349,6 +322,7 @@ func (s *Service) Timeline(ctx context.Context, last uint64, before *string) (Ti
 		{{ end }}
 		ORDER BY posts.created_at DESC, posts.id ASC
 		LIMIT @last`, map[string]interface{}{
+		"auth":            true,
 		"uid":             uid,
 		"last":            last,
 		"beforePostID":    beforePostID,
@@ -386,6 +360,7 @@ func (s *Service) Timeline(ctx context.Context, last uint64, before *string) (Ti
 			pq.Array(&media),
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.Pinned,
 			&p.Mine,
 			&p.Subscribed,
 			&u.Username,
@@ -451,6 +426,18 @@ func (s *Service) TimelineItemStream(ctx context.Context) (<-chan TimelineItem,
 				return
 			}
 
+			if ti.Post != nil {
+				isMuted, err := s.mutedPost(ctx, uid, *ti.Post)
+				if err != nil {
+					_ = s.Logger.Log("error", err)
+					return
+				}
+
+				if isMuted {
+					return
+				}
+			}
+
 			tt <- ti
 		}(bytes.NewReader(data))
 	})
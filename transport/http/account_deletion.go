@@ -0,0 +1,65 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	webtemplate "github.com/nakamauwu/nakama/web"
+)
+
+var accountDeletionTmpl = template.Must(template.ParseFS(webtemplate.TemplateFiles, "template/account-deletion.html.tmpl"))
+
+type accountDeletionData struct {
+	Error       string
+	Code        string
+	ScheduledAt *time.Time
+}
+
+func (h *handler) requestAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.RequestAccountDeletion(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// accountDeletionPage is where the link sent by email lands.
+// It asks once more before confirming, so that just opening the link
+// (like some email clients do to preview it) does not delete anything.
+func (h *handler) accountDeletionPage(w http.ResponseWriter, r *http.Request) {
+	h.renderAccountDeletion(w, accountDeletionData{Code: r.URL.Query().Get("code")}, http.StatusOK)
+}
+
+func (h *handler) confirmAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderAccountDeletion(w, accountDeletionData{Error: errBadRequest.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	scheduledAt, err := h.svc.ConfirmAccountDeletion(r.Context(), r.PostForm.Get("code"))
+	if err != nil {
+		statusCode := err2code(err)
+		if statusCode == http.StatusInternalServerError {
+			h.respondErr(w, err)
+			return
+		}
+
+		h.renderAccountDeletion(w, accountDeletionData{Error: err.Error()}, statusCode)
+		return
+	}
+
+	h.renderAccountDeletion(w, accountDeletionData{ScheduledAt: &scheduledAt}, http.StatusOK)
+}
+
+func (h *handler) renderAccountDeletion(w http.ResponseWriter, data accountDeletionData, statusCode int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.WriteHeader(statusCode)
+	if err := accountDeletionTmpl.Execute(w, data); err != nil {
+		_ = h.logger.Log("err", err)
+	}
+}
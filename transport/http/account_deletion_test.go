@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_confirmAccountDeletion(t *testing.T) {
+	const code = "00000000-0000-0000-0000-000000000000"
+	scheduledAt := time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)
+	svc := &transport.ServiceMock{
+		ConfirmAccountDeletionFunc: func(_ context.Context, got string) (time.Time, error) {
+			if got != code {
+				return time.Time{}, nakama.ErrAccountDeletionCodeNotFound
+			}
+
+			return scheduledAt, nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		code       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "ok",
+			code:       code,
+			wantStatus: http.StatusOK,
+			wantBody:   "January 2, 2026",
+		},
+		{
+			name:       "not_found",
+			code:       "11111111-1111-1111-1111-111111111111",
+			wantStatus: http.StatusNotFound,
+			wantBody:   nakama.ErrAccountDeletionCodeNotFound.Error(),
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.PostForm(srv.URL+"/account_deletion", url.Values{"code": {tc.code}})
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read response body: %v", err)
+			}
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+			testutil.WantEq(t, true, strings.Contains(string(b), tc.wantBody), "body contains")
+		})
+	}
+}
+
+func Test_handler_accountDeletionPage(t *testing.T) {
+	svc := &transport.ServiceMock{
+		ConfirmAccountDeletionFunc: func(context.Context, string) (time.Time, error) {
+			t.Fatal("account deletion should not be confirmed just by opening the link")
+			return time.Time{}, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/account_deletion?code=some_code")
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+	testutil.WantEq(t, true, strings.Contains(string(b), `value="some_code"`), "form has code")
+}
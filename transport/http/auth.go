@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -16,6 +17,16 @@ type loginInput struct {
 	Email string
 }
 
+type refreshTokenInput struct {
+	RefreshToken string
+}
+
+type verifyLoginCodeInput struct {
+	Email    string
+	Code     string
+	Username *string
+}
+
 func (h *handler) sendMagicLink(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -34,6 +45,24 @@ func (h *handler) sendMagicLink(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *handler) verifyLoginCode(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in verifyLoginCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.VerifyLoginCode(r.Context(), in.Email, in.Code, in.Username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
 func (h *handler) verifyMagicLink(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	redirectURI, err := h.svc.ParseRedirectURI(q.Get("redirect_uri"))
@@ -73,16 +102,29 @@ func (h *handler) verifyMagicLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redirectWithHashFragment(w, r, redirectURI, authValues(auth), http.StatusFound)
+}
+
+// authValues to pass an auth output into a redirect URI.
+// A pending second factor challenge is passed instead of the tokens.
+func authValues(auth nakama.AuthOutput) url.Values {
 	values := url.Values{
-		"token":         []string{auth.Token},
-		"expires_at":    []string{auth.ExpiresAt.Format(time.RFC3339Nano)},
 		"user.id":       []string{auth.User.ID},
 		"user.username": []string{auth.User.Username},
 	}
 	if auth.User.AvatarURL != nil {
 		values.Set("user.avatar_url", *auth.User.AvatarURL)
 	}
-	redirectWithHashFragment(w, r, redirectURI, values, http.StatusFound)
+	if auth.SecondFactorRequired {
+		values.Set("second_factor_challenge_id", auth.SecondFactorChallengeID)
+		return values
+	}
+
+	values.Set("token", auth.Token)
+	values.Set("expires_at", auth.ExpiresAt.Format(time.RFC3339Nano))
+	values.Set("refresh_token", auth.RefreshToken)
+	values.Set("refresh_token_expires_at", auth.RefreshTokenExpiresAt.Format(time.RFC3339Nano))
+	return values
 }
 
 func (h *handler) devLogin(w http.ResponseWriter, r *http.Request) {
@@ -113,8 +155,16 @@ func (h *handler) authUser(w http.ResponseWriter, r *http.Request) {
 	h.respond(w, u, http.StatusOK)
 }
 
-func (h *handler) token(w http.ResponseWriter, r *http.Request) {
-	out, err := h.svc.Token(r.Context())
+func (h *handler) refreshToken(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in refreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.RefreshToken(r.Context(), in.RefreshToken)
 	if err != nil {
 		h.respondErr(w, err)
 		return
@@ -126,6 +176,7 @@ func (h *handler) token(w http.ResponseWriter, r *http.Request) {
 func (h *handler) withAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := strings.TrimSpace(r.URL.Query().Get("auth_token"))
+		fromQuery := token != ""
 
 		if token == "" {
 			if a := r.Header.Get("Authorization"); strings.HasPrefix(a, "Bearer ") {
@@ -138,14 +189,67 @@ func (h *handler) withAuth(next http.Handler) http.Handler {
 			return
 		}
 
-		uid, err := h.svc.AuthUserIDFromToken(token)
+		ctx := r.Context()
+		auth, err := h.svc.AuthFromToken(ctx, token)
 		if err != nil {
 			h.respondErr(w, err)
 			return
 		}
 
+		// scoped tokens are long-lived,
+		// so they are not allowed in URLs where they could end up logged.
+		if auth.Scopes != nil && fromQuery {
+			h.respondErr(w, errScopedTokenInQuery)
+			return
+		}
+
+		ctx = context.WithValue(ctx, nakama.KeyAuthUserID, auth.UserID)
+		if auth.Scopes != nil {
+			ctx = context.WithValue(ctx, nakama.KeyAuthScopes, auth.Scopes)
+		} else {
+			ctx = context.WithValue(ctx, nakama.KeyAuthSessionID, auth.SessionID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withClientInfo sets the client IP and user agent into the request context.
+func (h *handler) withClientInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		ctx = context.WithValue(ctx, nakama.KeyAuthUserID, uid)
+		if ua := r.UserAgent(); ua != "" {
+			ctx = context.WithValue(ctx, nakama.KeyUserAgent, ua)
+		}
+		if ip := clientIP(r, h.trustedProxies); ip != "" {
+			ctx = context.WithValue(ctx, nakama.KeyClientIP, ip)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// clientIP from the request.
+// X-Forwarded-For is only honored when running behind the given number
+// of trusted reverse proxies. Each of them appends the address it got
+// the request from, so the client IP is that many entries from the right.
+// Entries further left can be set by the client at will, so those are ignored.
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var parts []string
+		for _, xff := range r.Header.Values("X-Forwarded-For") {
+			parts = append(parts, strings.Split(xff, ",")...)
+		}
+		if len(parts) >= trustedProxies {
+			ip := strings.TrimSpace(parts[len(parts)-trustedProxies])
+			if net.ParseIP(ip) != nil {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
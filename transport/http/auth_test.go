@@ -3,11 +3,13 @@ package http
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 
@@ -83,6 +85,20 @@ func Test_handler_sendMagicLink(t *testing.T) {
 				testutil.WantEq(t, "untrusted redirect URI", string(readAllAndTrim(t, resp.Body)), "body")
 			},
 		},
+		{
+			name: "too_many_magic_links",
+			body: []byte(`{}`),
+			svc: &transport.ServiceMock{
+				SendMagicLinkFunc: func(context.Context, nakama.SendMagicLink) error {
+					return &nakama.RetryAfterError{Err: nakama.ErrTooManyMagicLinks, RetryAfter: time.Millisecond * 1500}
+				},
+			},
+			testResp: func(t *testing.T, resp *http.Response) {
+				testutil.WantEq(t, http.StatusTooManyRequests, resp.StatusCode, "status code")
+				testutil.WantEq(t, "2", resp.Header.Get("Retry-After"), "retry after")
+				testutil.WantEq(t, "too many magic links", string(readAllAndTrim(t, resp.Body)), "body")
+			},
+		},
 		{
 			name: "internal_error",
 			body: []byte(`{}`),
@@ -116,7 +132,7 @@ func Test_handler_sendMagicLink(t *testing.T) {
 	for _, tc := range tt {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			h := New(tc.svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true)
+			h := New(tc.svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
 			srv := httptest.NewServer(h)
 			defer srv.Close()
 
@@ -140,6 +156,170 @@ func Test_handler_sendMagicLink(t *testing.T) {
 	}
 }
 
+func Test_handler_sendMagicLink_spoofedXFF(t *testing.T) {
+	svc := &transport.ServiceMock{
+		SendMagicLinkFunc: func(context.Context, nakama.SendMagicLink) error {
+			return nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, xff := range []string{"203.0.113.1", "203.0.113.2", "198.51.100.3, 203.0.113.4"} {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/send_magic_link", bytes.NewReader([]byte(`{}`)))
+		if err != nil {
+			t.Fatalf("failed to create request to send magic link: %v", err)
+		}
+
+		req.Header.Set("X-Forwarded-For", xff)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to do request to send magic link: %v", err)
+		}
+
+		resp.Body.Close()
+	}
+
+	// The per IP rate limit bucket is keyed by the client IP,
+	// so every request must end up with the same one.
+	calls := svc.SendMagicLinkCalls()
+	testutil.WantEq(t, 3, len(calls), "calls")
+	for _, call := range calls {
+		ip, _ := call.Ctx.Value(nakama.KeyClientIP).(string)
+		testutil.WantEq(t, "127.0.0.1", ip, "client IP")
+	}
+}
+
+func Test_handler_refreshToken(t *testing.T) {
+	tt := []struct {
+		name     string
+		body     []byte
+		svc      *transport.ServiceMock
+		testResp func(*testing.T, *http.Response)
+	}{
+		{
+			name: "malformed_request_body",
+			body: []byte(`nope`),
+			testResp: func(t *testing.T, resp *http.Response) {
+				testutil.WantEq(t, http.StatusBadRequest, resp.StatusCode, "status code")
+				testutil.WantEq(t, "bad request", string(readAllAndTrim(t, resp.Body)), "body")
+			},
+		},
+		{
+			name: "reused_refresh_token",
+			body: []byte(`{"refreshToken":"used"}`),
+			svc: &transport.ServiceMock{
+				RefreshTokenFunc: func(context.Context, string) (nakama.TokenOutput, error) {
+					return nakama.TokenOutput{}, nakama.ErrRefreshTokenReused
+				},
+			},
+			testResp: func(t *testing.T, resp *http.Response) {
+				testutil.WantEq(t, http.StatusUnauthorized, resp.StatusCode, "status code")
+				testutil.WantEq(t, "refresh token reused", string(readAllAndTrim(t, resp.Body)), "body")
+			},
+		},
+		{
+			name: "ok",
+			body: []byte(`{"refreshToken":"fresh"}`),
+			svc: &transport.ServiceMock{
+				RefreshTokenFunc: func(_ context.Context, refreshToken string) (nakama.TokenOutput, error) {
+					testutil.WantEq(t, "fresh", refreshToken, "refresh token")
+					return nakama.TokenOutput{Token: "access", RefreshToken: "rotated"}, nil
+				},
+			},
+			testResp: func(t *testing.T, resp *http.Response) {
+				testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+
+				var out nakama.TokenOutput
+				if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+					t.Fatalf("failed to decode token output: %v", err)
+				}
+
+				testutil.WantEq(t, "access", out.Token, "token")
+				testutil.WantEq(t, "rotated", out.RefreshToken, "refresh token")
+			},
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(tc.svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/token", bytes.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to create request to refresh token: %v", err)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to do request to refresh token: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			tc.testResp(t, resp)
+		})
+	}
+}
+
+func Test_clientIP(t *testing.T) {
+	tt := []struct {
+		name           string
+		xff            []string
+		trustedProxies int
+		want           string
+	}{
+		{
+			name: "remote_addr",
+			want: "192.0.2.1",
+		},
+		{
+			name: "untrusted_xff",
+			xff:  []string{"203.0.113.7"},
+			want: "192.0.2.1",
+		},
+		{
+			name:           "one_proxy",
+			xff:            []string{"203.0.113.7, 198.51.100.2"},
+			trustedProxies: 1,
+			want:           "198.51.100.2",
+		},
+		{
+			name:           "two_proxies",
+			xff:            []string{"203.0.113.7, 198.51.100.2", "10.0.0.1"},
+			trustedProxies: 2,
+			want:           "198.51.100.2",
+		},
+		{
+			name:           "fewer_entries_than_proxies",
+			xff:            []string{"203.0.113.7"},
+			trustedProxies: 2,
+			want:           "192.0.2.1",
+		},
+		{
+			name:           "malformed",
+			xff:            []string{"nope"},
+			trustedProxies: 1,
+			want:           "192.0.2.1",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			for _, xff := range tc.xff {
+				r.Header.Add("X-Forwarded-For", xff)
+			}
+
+			testutil.WantEq(t, tc.want, clientIP(r, tc.trustedProxies), "client IP")
+		})
+	}
+}
+
 func readAllAndTrim(t *testing.T, r io.Reader) []byte {
 	t.Helper()
 
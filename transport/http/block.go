@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) toggleBlock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+
+	out, err := h.svc.ToggleBlock(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) blocks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	first, _ := strconv.ParseUint(q.Get("first"), 10, 64)
+	after := emptyStrPtr(q.Get("after"))
+	uu, err := h.svc.Blocks(ctx, first, after)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if uu == nil {
+		uu = []nakama.UserProfile{} // non null array
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     uu,
+		EndCursor: uu.EndCursor(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_toggleBlock(t *testing.T) {
+	svc := &transport.ServiceMock{
+		ToggleBlockFunc: func(_ context.Context, username string) (nakama.ToggleBlockOutput, error) {
+			if username == "me" {
+				return nakama.ToggleBlockOutput{}, nakama.ErrForbiddenBlock
+			}
+
+			return nakama.ToggleBlockOutput{Blocking: true}, nil
+		},
+	}
+
+	tt := []struct {
+		name         string
+		username     string
+		wantStatus   int
+		wantBlocking bool
+	}{
+		{
+			name:         "ok",
+			username:     "someone",
+			wantStatus:   http.StatusOK,
+			wantBlocking: true,
+		},
+		{
+			name:       "forbidden",
+			username:   "me",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/api/users/"+tc.username+"/toggle_block", "", nil)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var out nakama.ToggleBlockOutput
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("failed to json decode response body: %v", err)
+			}
+
+			testutil.WantEq(t, tc.wantBlocking, out.Blocking, "blocking")
+		})
+	}
+}
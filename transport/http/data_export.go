@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"syscall"
+)
+
+func (h *handler) requestDataExport(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.RequestDataExport(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+// dataExport downloads the archive using the token from the link sent by email.
+func (h *handler) dataExport(w http.ResponseWriter, r *http.Request) {
+	f, err := h.svc.DataExportFile(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	defer f.Close()
+
+	w.Header().Set("Content-Type", f.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(f.Size, 10))
+	w.Header().Set("Content-Disposition", `attachment; filename="nakama-data-export.zip"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, f)
+	if err != nil && !errors.Is(err, syscall.EPIPE) && !errors.Is(err, context.Canceled) {
+		_ = h.logger.Log("err", fmt.Errorf("could not write down data export: %w", err))
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/storage"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func Test_handler_dataExport(t *testing.T) {
+	archive := []byte("PK archive")
+	svc := &transport.ServiceMock{
+		DataExportFileFunc: func(_ context.Context, token string) (*storage.File, error) {
+			if token != "valid" {
+				return nil, nakama.ErrDataExportNotFound
+			}
+
+			return &storage.File{
+				ReadSeekCloser: nopReadSeekCloser{bytes.NewReader(archive)},
+				Size:           int64(len(archive)),
+				ContentType:    "application/zip",
+			}, nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "ok",
+			token:      "valid",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not_found",
+			token:      "expired",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/api/data_export?token=" + tc.token)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read response body: %v", err)
+			}
+
+			testutil.WantEq(t, archive, b, "body")
+			testutil.WantEq(t, "application/zip", resp.Header.Get("Content-Type"), "content type")
+			testutil.WantEq(t, `attachment; filename="nakama-data-export.zip"`, resp.Header.Get("Content-Disposition"), "content disposition")
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) followRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	first, _ := strconv.ParseUint(q.Get("first"), 10, 64)
+	after := emptyStrPtr(q.Get("after"))
+	uu, err := h.svc.FollowRequests(ctx, first, after)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if uu == nil {
+		uu = []nakama.UserProfile{} // non null array
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     uu,
+		EndCursor: uu.EndCursor(),
+	}, http.StatusOK)
+}
+
+func (h *handler) approveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	err := h.svc.ApproveFollowRequest(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) denyFollowRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	err := h.svc.DenyFollowRequest(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_approveFollowRequest(t *testing.T) {
+	svc := &transport.ServiceMock{
+		ApproveFollowRequestFunc: func(_ context.Context, username string) error {
+			if username != "requester" {
+				return nakama.ErrFollowRequestNotFound
+			}
+
+			return nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		username   string
+		wantStatus int
+	}{
+		{
+			name:       "ok",
+			username:   "requester",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "not_found",
+			username:   "someone",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/api/auth_user/follow_requests/"+tc.username+"/approval", "", nil)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+		})
+	}
+}
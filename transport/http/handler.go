@@ -19,10 +19,11 @@ type handler struct {
 	store            storage.Store
 	cookieCodec      *securecookie.SecureCookie
 	embedStaticFiles bool
+	trustedProxies   int
 }
 
 // New makes use of the service to provide an http.Handler with predefined routing.
-func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL, logger log.Logger, store storage.Store, cdc *securecookie.SecureCookie, promHandler http.Handler, embedStaticFiles bool) http.Handler {
+func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL, logger log.Logger, store storage.Store, cdc *securecookie.SecureCookie, promHandler http.Handler, embedStaticFiles bool, trustedProxies int) http.Handler {
 	h := &handler{
 		svc:              svc,
 		origin:           origin,
@@ -30,11 +31,16 @@ func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL,
 		store:            store,
 		cookieCodec:      cdc,
 		embedStaticFiles: embedStaticFiles,
+		trustedProxies:   trustedProxies,
 	}
 
 	api := way.NewRouter()
 	api.HandleFunc("POST", "/api/send_magic_link", h.sendMagicLink)
 	api.HandleFunc("GET", "/api/verify_magic_link", h.verifyMagicLink)
+	api.HandleFunc("POST", "/api/verify_login_code", h.verifyLoginCode)
+	api.HandleFunc("POST", "/api/verify_second_factor", h.verifySecondFactor)
+	api.HandleFunc("POST", "/api/passkey_login", h.beginPasskeyLogin)
+	api.HandleFunc("POST", "/api/verify_passkey_login", h.finishPasskeyLogin)
 
 	for _, provider := range oauthProviders {
 		api.HandleFunc("GET", "/api/"+provider.Name+"_auth", h.oauth2Handler(provider))
@@ -43,23 +49,80 @@ func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL,
 
 	api.HandleFunc("POST", "/api/dev_login", h.devLogin)
 	api.HandleFunc("GET", "/api/auth_user", h.authUser)
-	api.HandleFunc("GET", "/api/token", h.token)
+	api.HandleFunc("POST", "/api/token", h.refreshToken)
+	api.HandleFunc("POST", "/api/logout", h.logout)
+	api.HandleFunc("GET", "/api/auth_user/sessions", h.sessions)
+	api.HandleFunc("DELETE", "/api/auth_user/sessions", h.logoutEverywhere)
+	api.HandleFunc("DELETE", "/api/auth_user/sessions/:session_id", h.revokeSession)
+	api.HandleFunc("GET", "/api/auth_user/identities", h.identities)
+	api.HandleFunc("DELETE", "/api/auth_user/identities/:provider", h.unlinkIdentity)
+	api.HandleFunc("GET", "/api/auth_user/two_factor", h.twoFactorStatus)
+	api.HandleFunc("POST", "/api/auth_user/totp_enrollment", h.beginTOTPEnrollment)
+	api.HandleFunc("POST", "/api/auth_user/enable_totp", h.enableTOTP)
+	api.HandleFunc("POST", "/api/auth_user/disable_totp", h.disableTOTP)
+	api.HandleFunc("POST", "/api/auth_user/recovery_codes", h.regenerateRecoveryCodes)
+	api.HandleFunc("GET", "/api/auth_user/passkeys", h.passkeys)
+	api.HandleFunc("POST", "/api/auth_user/passkeys", h.finishPasskeyRegistration)
+	api.HandleFunc("POST", "/api/auth_user/passkey_registration", h.beginPasskeyRegistration)
+	api.HandleFunc("PATCH", "/api/auth_user/passkeys/:passkey_id", h.renamePasskey)
+	api.HandleFunc("DELETE", "/api/auth_user/passkeys/:passkey_id", h.deletePasskey)
+	api.HandleFunc("GET", "/api/auth_user/access_tokens", h.personalAccessTokens)
+	api.HandleFunc("POST", "/api/auth_user/access_tokens", h.createPersonalAccessToken)
+	api.HandleFunc("DELETE", "/api/auth_user/access_tokens/:token_id", h.revokePersonalAccessToken)
+	api.HandleFunc("GET", "/api/auth_user/oauth_apps", h.oauthApps)
+	api.HandleFunc("POST", "/api/auth_user/oauth_apps", h.createOAuthApp)
+	api.HandleFunc("DELETE", "/api/auth_user/oauth_apps/:app_id", h.deleteOAuthApp)
+	api.HandleFunc("GET", "/api/auth_user/authorized_apps", h.authorizedOAuthApps)
+	api.HandleFunc("DELETE", "/api/auth_user/authorized_apps/:app_id", h.revokeOAuthApp)
+	api.HandleFunc("POST", "/api/oauth/authorize", h.authorizeOAuthApp)
+	api.HandleFunc("POST", "/api/oauth/token", h.oauthToken)
+	api.HandleFunc("POST", "/api/oauth/introspect", h.introspectOAuthToken)
 	api.HandleFunc("GET", "/api/users", h.users)
 	api.HandleFunc("GET", "/api/usernames", h.usernames)
 	api.HandleFunc("GET", "/api/users/:username", h.user)
+	api.HandleFunc("GET", "/api/suggested_users", h.suggestedUsers)
 	api.HandleFunc("PATCH", "/api/auth_user", h.updateUser)
 	api.HandleFunc("PUT", "/api/auth_user/avatar", h.updateAvatar)
 	api.HandleFunc("PUT", "/api/auth_user/cover", h.updateCover)
+	api.HandleFunc("POST", "/api/auth_user/deletion_request", h.requestAccountDeletion)
+	api.HandleFunc("POST", "/api/auth_user/data_exports", h.requestDataExport)
+	api.HandleFunc("GET", "/api/auth_user/blocks", h.blocks)
+	api.HandleFunc("GET", "/api/auth_user/follow_requests", h.followRequests)
+	api.HandleFunc("POST", "/api/auth_user/follow_requests/:username/approval", h.approveFollowRequest)
+	api.HandleFunc("DELETE", "/api/auth_user/follow_requests/:username", h.denyFollowRequest)
+	api.HandleFunc("GET", "/api/auth_user/mutes", h.mutes)
+	api.HandleFunc("POST", "/api/auth_user/mutes", h.createMute)
+	api.HandleFunc("PATCH", "/api/auth_user/mutes/:mute_id", h.updateMute)
+	api.HandleFunc("DELETE", "/api/auth_user/mutes/:mute_id", h.deleteMute)
+	api.HandleFunc("GET", "/api/data_export", h.dataExport)
 	api.HandleFunc("POST", "/api/users/:username/toggle_follow", h.toggleFollow)
 	api.HandleFunc("GET", "/api/users/:username/followers", h.followers)
+	api.HandleFunc("GET", "/api/users/:username/mutual_followers", h.mutualFollowers)
 	api.HandleFunc("GET", "/api/users/:username/followees", h.followees)
+	api.HandleFunc("POST", "/api/users/:username/toggle_block", h.toggleBlock)
+	api.HandleFunc("PUT", "/api/users/:username/suspension", h.suspendUser)
+	api.HandleFunc("DELETE", "/api/users/:username/suspension", h.unsuspendUser)
+	api.HandleFunc("POST", "/api/users/:username/reports", h.reportUser)
 	api.HandleFunc("GET", "/api/users/:username/posts", h.userPosts)
+	api.HandleFunc("GET", "/api/users/:username/pinned_posts", h.pinnedPosts)
+	api.HandleFunc("GET", "/api/users/:username/lists", h.userLists)
+	api.HandleFunc("POST", "/api/lists", h.createUserList)
+	api.HandleFunc("GET", "/api/lists/:list_id", h.userList)
+	api.HandleFunc("PATCH", "/api/lists/:list_id", h.updateUserList)
+	api.HandleFunc("DELETE", "/api/lists/:list_id", h.deleteUserList)
+	api.HandleFunc("GET", "/api/lists/:list_id/members", h.userListMembers)
+	api.HandleFunc("PUT", "/api/lists/:list_id/members/:username", h.addUserListMember)
+	api.HandleFunc("DELETE", "/api/lists/:list_id/members/:username", h.removeUserListMember)
+	api.HandleFunc("GET", "/api/lists/:list_id/timeline", h.listTimeline)
 	api.HandleFunc("GET", "/api/posts", h.posts)
 	api.HandleFunc("GET", "/api/posts/:post_id", h.post)
 	api.HandleFunc("PATCH", "/api/posts/:post_id", h.updatePost)
 	api.HandleFunc("DELETE", "/api/posts/:post_id", h.deletePost)
 	api.HandleFunc("POST", "/api/posts/:post_id/toggle_reaction", h.togglePostReaction)
 	api.HandleFunc("POST", "/api/posts/:post_id/toggle_subscription", h.togglePostSubscription)
+	api.HandleFunc("PUT", "/api/posts/:post_id/pin", h.pinPost)
+	api.HandleFunc("DELETE", "/api/posts/:post_id/pin", h.unpinPost)
+	api.HandleFunc("POST", "/api/posts/:post_id/reports", h.reportPost)
 	api.HandleFunc("POST", "/api/timeline", h.createTimelineItem)
 	api.HandleFunc("GET", "/api/timeline", h.timeline)
 	api.HandleFunc("DELETE", "/api/timeline/:timeline_item_id", h.deleteTimelineItem)
@@ -68,6 +131,9 @@ func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL,
 	api.HandleFunc("PATCH", "/api/comments/:comment_id", h.updateComment)
 	api.HandleFunc("DELETE", "/api/comments/:comment_id", h.deleteComment)
 	api.HandleFunc("POST", "/api/comments/:comment_id/toggle_reaction", h.toggleCommentReaction)
+	api.HandleFunc("POST", "/api/comments/:comment_id/reports", h.reportComment)
+	api.HandleFunc("GET", "/api/reports", h.reports)
+	api.HandleFunc("POST", "/api/reports/:report_id/resolution", h.resolveReport)
 	api.HandleFunc("GET", "/api/notifications", h.notifications)
 	api.HandleFunc("GET", "/api/has_unread_notifications", h.hasUnreadNotifications)
 	api.HandleFunc("POST", "/api/notifications/:notification_id/mark_as_read", h.markNotificationAsRead)
@@ -82,7 +148,10 @@ func New(svc transport.Service, oauthProviders []OauthProvider, origin *url.URL,
 	api.Handle("GET", "/api/prom", promHandler)
 
 	r := way.NewRouter()
-	r.Handle("*", "/api/...", h.withAuth(api))
+	r.Handle("*", "/api/...", h.withClientInfo(h.withAuth(api)))
+	r.HandleFunc("GET", "/oauth/authorize", h.oauthAuthorizePage)
+	r.HandleFunc("GET", "/account_deletion", h.accountDeletionPage)
+	r.HandleFunc("POST", "/account_deletion", h.confirmAccountDeletion)
 	r.HandleFunc("GET", "/img/avatars/:name", h.avatar)
 	r.HandleFunc("GET", "/img/covers/:name", h.cover)
 	r.HandleFunc("GET", "/img/media/:name", h.media)
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) identities(w http.ResponseWriter, r *http.Request) {
+	ii, err := h.svc.Identities(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if ii == nil {
+		ii = []nakama.UserIdentity{} // non null array
+	}
+
+	h.respond(w, ii, http.StatusOK)
+}
+
+func (h *handler) unlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	provider := way.Param(ctx, "provider")
+	err := h.svc.UnlinkIdentity(ctx, provider)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
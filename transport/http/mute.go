@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) createMute(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.CreateMute
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.CreateMute(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) mutes(w http.ResponseWriter, r *http.Request) {
+	mm, err := h.svc.Mutes(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if mm == nil {
+		mm = []nakama.Mute{} // non null array
+	}
+
+	h.respond(w, mm, http.StatusOK)
+}
+
+func (h *handler) updateMute(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.UpdateMute
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	in.ID = way.Param(ctx, "mute_id")
+	out, err := h.svc.UpdateMute(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) deleteMute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	muteID := way.Param(ctx, "mute_id")
+	err := h.svc.DeleteMute(ctx, muteID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_createMute(t *testing.T) {
+	svc := &transport.ServiceMock{
+		CreateMuteFunc: func(_ context.Context, in nakama.CreateMute) (nakama.Mute, error) {
+			if in.Type != nakama.MuteTypeWord {
+				return nakama.Mute{}, nakama.ErrInvalidMuteType
+			}
+
+			return nakama.Mute{ID: "mute_id", Type: in.Type, Value: in.Value}, nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "ok",
+			body:       `{"type":"word","value":"spoilers"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid_type",
+			body:       `{"type":"emoji","value":"spoilers"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "bad_request",
+			body:       `nope`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/api/auth_user/mutes", "application/json", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+		})
+	}
+}
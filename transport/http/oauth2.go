@@ -130,6 +130,7 @@ var GithubUserFetcher = func(ctx context.Context, config *oauth2.Config, token *
 		return out, errEmailNotProvided
 	}
 
+	out.EmailVerified = true // only verified emails are picked.
 	out.ID = fmt.Sprintf("%d", user.ID)
 	out.Username = &user.Login
 
@@ -157,6 +158,27 @@ func (h *handler) oauth2Handler(provider OauthProvider) http.HandlerFunc {
 			return
 		}
 
+		// Linking the identity to the authenticated user instead of login.
+		// Since this is a browser navigation, the token comes in the query string.
+		var linkValue string
+		if q.Get("link") == "true" {
+			if _, ok := r.Context().Value(nakama.KeyAuthUserID).(string); !ok {
+				redirectWithHashFragment(w, r, redirectURI, url.Values{
+					"error": []string{nakama.ErrUnauthenticated.Error()},
+				}, http.StatusSeeOther)
+				return
+			}
+
+			linkValue, err = h.cookieCodec.Encode("oauth2_link", q.Get("auth_token"))
+			if err != nil {
+				_ = h.logger.Log("err", fmt.Errorf("could not cookie encode oauth2 link: %w", err))
+				redirectWithHashFragment(w, r, redirectURI, url.Values{
+					"error": []string{"internal server error"},
+				}, http.StatusSeeOther)
+				return
+			}
+		}
+
 		b := make([]byte, 32)
 		if _, err := rand.Read(b); err != nil {
 			_ = h.logger.Log("err", fmt.Errorf("could not generate oauth2 state: %w", err))
@@ -219,6 +241,20 @@ func (h *handler) oauth2Handler(provider OauthProvider) http.HandlerFunc {
 			http.SetCookie(w, cookie)
 		}
 
+		{
+			cookie := &http.Cookie{
+				Name:     "oauth2_link",
+				Value:    linkValue,
+				Expires:  time.Now().Add(oauth2Timeout),
+				Secure:   h.origin.Scheme == "https",
+				HttpOnly: true,
+			}
+			if samesite.IsSameSiteCookieSupported(r.UserAgent()) {
+				cookie.SameSite = http.SameSiteLaxMode
+			}
+			http.SetCookie(w, cookie)
+		}
+
 		u := provider.Config.AuthCodeURL(state)
 		http.Redirect(w, r, u, http.StatusTemporaryRedirect)
 	}
@@ -357,13 +393,6 @@ func (h *handler) oauth2CallbackHandler(provider OauthProvider) http.HandlerFunc
 				return
 			}
 
-			if !claims.Verified {
-				redirectWithHashFragment(w, r, redirectURI, url.Values{
-					"error": []string{errEmailNotVerified.Error()},
-				}, http.StatusSeeOther)
-				return
-			}
-
 			if claims.Email == "" {
 				redirectWithHashFragment(w, r, redirectURI, url.Values{
 					"error": []string{errEmailNotProvided.Error()},
@@ -373,6 +402,7 @@ func (h *handler) oauth2CallbackHandler(provider OauthProvider) http.HandlerFunc
 
 			providedUser.ID = claims.Sub
 			providedUser.Email = claims.Email
+			providedUser.EmailVerified = claims.Verified
 		} else {
 			var err error
 			providedUser, err = provider.FetchUser(ctx, provider.Config, token)
@@ -395,12 +425,17 @@ func (h *handler) oauth2CallbackHandler(provider OauthProvider) http.HandlerFunc
 			}
 		}
 
+		if linkCookie, err := r.Cookie("oauth2_link"); err == nil && linkCookie.Value != "" {
+			h.linkIdentity(w, r, redirectURI, provider, linkCookie.Value, providedUser)
+			return
+		}
+
 		if usernameCookie.Value != "" {
 			s := usernameCookie.Value
 			providedUser.Username = &s
 		}
 
-		user, err := h.svc.LoginFromProvider(ctx, provider.Name, providedUser)
+		auth, err := h.svc.LoginFromProvider(ctx, provider.Name, providedUser)
 		if err == nakama.ErrUserNotFound || err == nakama.ErrInvalidUsername || err == nakama.ErrUsernameTaken {
 			redirectWithHashFragment(w, r, redirectURI, url.Values{
 				"error":          []string{err.Error()},
@@ -427,35 +462,47 @@ func (h *handler) oauth2CallbackHandler(provider OauthProvider) http.HandlerFunc
 			return
 		}
 
-		ctx = context.WithValue(ctx, nakama.KeyAuthUserID, user.ID)
-		auth, err := h.svc.Token(ctx)
-		if err != nil {
-			statusCode := err2code(err)
-			if statusCode != http.StatusInternalServerError {
-				redirectWithHashFragment(w, r, redirectURI, url.Values{
-					"error": []string{err.Error()},
-				}, http.StatusSeeOther)
-				return
-			}
+		redirectWithHashFragment(w, r, redirectURI, authValues(auth), http.StatusSeeOther)
+	}
+}
 
-			if !errors.Is(err, context.Canceled) {
-				_ = h.logger.Log("err", err)
-			}
+// linkIdentity finishes an OAuth flow started with link=true
+// by linking the provided user to the user that started it.
+func (h *handler) linkIdentity(w http.ResponseWriter, r *http.Request, redirectURI *url.URL, provider OauthProvider, linkValue string, providedUser nakama.ProvidedUser) {
+	var token string
+	if err := h.cookieCodec.Decode("oauth2_link", linkValue, &token); err != nil {
+		redirectWithHashFragment(w, r, redirectURI, url.Values{
+			"error": []string{errTeaPot.Error()},
+		}, http.StatusSeeOther)
+		return
+	}
+
+	ctx := r.Context()
+	auth, err := h.svc.AuthFromToken(ctx, token)
+	if err == nil {
+		ctx = context.WithValue(ctx, nakama.KeyAuthUserID, auth.UserID)
+		ctx = context.WithValue(ctx, nakama.KeyAuthSessionID, auth.SessionID)
+		err = h.svc.LinkIdentity(ctx, provider.Name, providedUser)
+	}
+	if err != nil {
+		statusCode := err2code(err)
+		if statusCode != http.StatusInternalServerError {
 			redirectWithHashFragment(w, r, redirectURI, url.Values{
-				"error": []string{"internal server error"},
+				"error": []string{err.Error()},
 			}, http.StatusSeeOther)
 			return
 		}
 
-		values := url.Values{
-			"token":         []string{auth.Token},
-			"expires_at":    []string{auth.ExpiresAt.Format(time.RFC3339Nano)},
-			"user.id":       []string{user.ID},
-			"user.username": []string{user.Username},
-		}
-		if user.AvatarURL != nil {
-			values.Set("user.avatar_url", *user.AvatarURL)
+		if !errors.Is(err, context.Canceled) {
+			_ = h.logger.Log("err", err)
 		}
-		redirectWithHashFragment(w, r, redirectURI, values, http.StatusSeeOther)
+		redirectWithHashFragment(w, r, redirectURI, url.Values{
+			"error": []string{"internal server error"},
+		}, http.StatusSeeOther)
+		return
 	}
+
+	redirectWithHashFragment(w, r, redirectURI, url.Values{
+		"linked_identity": []string{provider.Name},
+	}, http.StatusSeeOther)
 }
@@ -0,0 +1,309 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+	webtemplate "github.com/nakamauwu/nakama/web"
+)
+
+var oauthConsentTmpl = template.Must(template.ParseFS(webtemplate.TemplateFiles, "template/oauth-consent.html.tmpl"))
+
+// scopeDescriptions to show on the consent page.
+var scopeDescriptions = map[string]string{
+	nakama.ScopePostsWrite:         "Publish, update and delete posts",
+	nakama.ScopeCommentsWrite:      "Publish, update and delete comments",
+	nakama.ScopeReactionsWrite:     "React to posts and comments",
+	nakama.ScopeTimelineRead:       "Read your timeline and private lists",
+	nakama.ScopeNotificationsRead:  "Read your notifications",
+	nakama.ScopeNotificationsWrite: "Mark notifications as read and subscribe to posts",
+	nakama.ScopeFollowsWrite:       "Follow and unfollow users",
+}
+
+type oauthConsentData struct {
+	Error         string
+	AppName       string
+	OwnerUsername string
+	Scopes        []scopeDescription
+	Request       nakama.OAuthAuthorizationRequest
+	DenyURI       string
+}
+
+type scopeDescription struct {
+	Name        string
+	Description string
+}
+
+type oauthErrorBody struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type authorizeOAuthAppOutput struct {
+	RedirectURI string `json:"redirectURI"`
+}
+
+// oauthAuthorizePage renders the consent page for an authorization request.
+// The approval itself is done from the page with the access token
+// the web app keeps in local storage.
+func (h *handler) oauthAuthorizePage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	in := nakama.OAuthAuthorizationRequest{
+		ResponseType:        q.Get("response_type"),
+		ClientID:            q.Get("client_id"),
+		RedirectURI:         q.Get("redirect_uri"),
+		Scope:               q.Get("scope"),
+		State:               q.Get("state"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+	}
+
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	authz, err := h.svc.OAuthAuthorization(r.Context(), in)
+	if err != nil {
+		statusCode := err2code(err)
+		if statusCode == http.StatusInternalServerError {
+			h.respondErr(w, err)
+			return
+		}
+
+		// the app cannot be trusted with the error
+		// when the redirect URI is not one of its own.
+		if authz.RedirectURI == nil {
+			h.renderOAuthConsent(w, oauthConsentData{Error: err.Error()}, statusCode)
+			return
+		}
+
+		http.Redirect(w, r, oauthErrorRedirectURI(authz.RedirectURI, oauthErrorCode(err), in.State), http.StatusFound)
+		return
+	}
+
+	// the request is posted back as sent, so whether it included the redirect URI
+	// is kept for the token request.
+	data := oauthConsentData{
+		AppName:       authz.AppName,
+		OwnerUsername: authz.OwnerUsername,
+		Request:       in,
+		DenyURI:       oauthErrorRedirectURI(authz.RedirectURI, "access_denied", in.State),
+	}
+	for _, scope := range authz.Scopes {
+		data.Scopes = append(data.Scopes, scopeDescription{
+			Name:        scope,
+			Description: scopeDescriptions[scope],
+		})
+	}
+
+	h.renderOAuthConsent(w, data, http.StatusOK)
+}
+
+func (h *handler) renderOAuthConsent(w http.ResponseWriter, data oauthConsentData, statusCode int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := oauthConsentTmpl.Execute(w, data); err != nil {
+		_ = h.logger.Log("err", err)
+	}
+}
+
+func (h *handler) authorizeOAuthApp(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.OAuthAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	redirectURI, err := h.svc.AuthorizeOAuthApp(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, authorizeOAuthAppOutput{RedirectURI: redirectURI.String()}, http.StatusOK)
+}
+
+// oauthToken is the token endpoint.
+// Only the authorization code grant is supported.
+func (h *handler) oauthToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.respondOAuthErr(w, errBadRequest)
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		h.respond(w, oauthErrorBody{Error: "unsupported_grant_type"}, http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret := oauthClientCredentials(r)
+	out, err := h.svc.ExchangeOAuthCode(r.Context(), nakama.ExchangeOAuthCode{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+	})
+	if err != nil {
+		h.respondOAuthErr(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respond(w, out, http.StatusOK)
+}
+
+// introspectOAuthToken requires the app to authenticate
+// with its client secret.
+func (h *handler) introspectOAuthToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.respondOAuthErr(w, errBadRequest)
+		return
+	}
+
+	clientID, clientSecret := oauthClientCredentials(r)
+	out, err := h.svc.IntrospectOAuthToken(r.Context(), clientID, clientSecret, r.PostForm.Get("token"))
+	if err != nil {
+		h.respondOAuthErr(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) createOAuthApp(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.CreateOAuthApp
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.CreateOAuthApp(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) oauthApps(w http.ResponseWriter, r *http.Request) {
+	aa, err := h.svc.OAuthApps(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if aa == nil {
+		aa = []nakama.OAuthApp{} // non null array
+	}
+
+	h.respond(w, aa, http.StatusOK)
+}
+
+func (h *handler) deleteOAuthApp(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := way.Param(ctx, "app_id")
+	err := h.svc.DeleteOAuthApp(ctx, appID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) authorizedOAuthApps(w http.ResponseWriter, r *http.Request) {
+	aa, err := h.svc.AuthorizedOAuthApps(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if aa == nil {
+		aa = []nakama.AuthorizedOAuthApp{} // non null array
+	}
+
+	h.respond(w, aa, http.StatusOK)
+}
+
+func (h *handler) revokeOAuthApp(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := way.Param(ctx, "app_id")
+	err := h.svc.RevokeOAuthApp(ctx, appID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondOAuthErr with the error format from RFC 6749.
+func (h *handler) respondOAuthErr(w http.ResponseWriter, err error) {
+	statusCode := err2code(err)
+	if statusCode == http.StatusInternalServerError {
+		h.respondErr(w, err)
+		return
+	}
+
+	code := oauthErrorCode(err)
+	statusCode = http.StatusBadRequest
+	if code == "invalid_client" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="nakama"`)
+		statusCode = http.StatusUnauthorized
+	}
+
+	h.respond(w, oauthErrorBody{Error: code, ErrorDescription: err.Error()}, statusCode)
+}
+
+func oauthErrorCode(err error) string {
+	switch {
+	case errors.Is(err, nakama.ErrInvalidOAuthAppID) ||
+		errors.Is(err, nakama.ErrOAuthAppNotFound) ||
+		errors.Is(err, nakama.ErrInvalidClientSecret):
+		return "invalid_client"
+	case errors.Is(err, nakama.ErrInvalidAuthorizationCode) ||
+		errors.Is(err, nakama.ErrRedirectURIMismatch):
+		return "invalid_grant"
+	case errors.Is(err, nakama.ErrInvalidScope):
+		return "invalid_scope"
+	case errors.Is(err, nakama.ErrUnsupportedResponseType):
+		return "unsupported_response_type"
+	}
+
+	return "invalid_request"
+}
+
+func oauthErrorRedirectURI(redirectURI *url.URL, code, state string) string {
+	uri := *redirectURI
+	q := uri.Query()
+	q.Set("error", code)
+	if state != "" {
+		q.Set("state", state)
+	}
+	uri.RawQuery = q.Encode()
+	return uri.String()
+}
+
+// oauthClientCredentials from basic auth,
+// or from the request body.
+func oauthClientCredentials(r *http.Request) (string, string) {
+	if clientID, clientSecret, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+		return clientID, clientSecret
+	}
+
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
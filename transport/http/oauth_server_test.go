@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_oauthToken(t *testing.T) {
+	tt := []struct {
+		name       string
+		form       url.Values
+		basicAuth  bool
+		svc        *transport.ServiceMock
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "unsupported_grant_type",
+			form:       url.Values{"grant_type": {"password"}},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unsupported_grant_type",
+		},
+		{
+			name:      "invalid_client",
+			form:      url.Values{"grant_type": {"authorization_code"}},
+			basicAuth: true,
+			svc: &transport.ServiceMock{
+				ExchangeOAuthCodeFunc: func(_ context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+					testutil.WantEq(t, "client_id", in.ClientID, "client ID")
+					testutil.WantEq(t, "client_secret", in.ClientSecret, "client secret")
+					return nakama.OAuthToken{}, nakama.ErrInvalidClientSecret
+				},
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid_client",
+		},
+		{
+			name: "invalid_grant",
+			form: url.Values{"grant_type": {"authorization_code"}, "client_id": {"client_id"}},
+			svc: &transport.ServiceMock{
+				ExchangeOAuthCodeFunc: func(_ context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+					testutil.WantEq(t, "client_id", in.ClientID, "client ID")
+					return nakama.OAuthToken{}, nakama.ErrInvalidAuthorizationCode
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_grant",
+		},
+		{
+			name: "redirect_uri_mismatch",
+			form: url.Values{"grant_type": {"authorization_code"}, "client_id": {"client_id"}},
+			svc: &transport.ServiceMock{
+				ExchangeOAuthCodeFunc: func(_ context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+					return nakama.OAuthToken{}, nakama.ErrRedirectURIMismatch
+				},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_grant",
+		},
+		{
+			name: "ok",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"client_id":     {"client_id"},
+				"code":          {"code"},
+				"code_verifier": {"verifier"},
+			},
+			svc: &transport.ServiceMock{
+				ExchangeOAuthCodeFunc: func(_ context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+					testutil.WantEq(t, "code", in.Code, "code")
+					testutil.WantEq(t, "verifier", in.CodeVerifier, "code verifier")
+					return nakama.OAuthToken{AccessToken: "token", TokenType: "Bearer"}, nil
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(tc.svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/oauth/token", strings.NewReader(tc.form.Encode()))
+			if err != nil {
+				t.Fatalf("failed to create request to oauth token: %v", err)
+			}
+
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.basicAuth {
+				req.SetBasicAuth("client_id", "client_secret")
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to do request to oauth token: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+
+			var body struct {
+				Error       string `json:"error"`
+				AccessToken string `json:"access_token"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response body: %v", err)
+			}
+
+			testutil.WantEq(t, tc.wantError, body.Error, "error")
+			if tc.wantError == "" {
+				testutil.WantEq(t, "token", body.AccessToken, "access token")
+			}
+		})
+	}
+}
+
+func Test_handler_oauthAuthorizePage(t *testing.T) {
+	redirectURI, _ := url.Parse("https://example.org/callback")
+	svc := &transport.ServiceMock{
+		OAuthAuthorizationFunc: func(_ context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error) {
+			switch in.ClientID {
+			case "unknown":
+				return nakama.OAuthAuthorization{}, nakama.ErrOAuthAppNotFound
+			case "bad_scope":
+				return nakama.OAuthAuthorization{RedirectURI: redirectURI}, nakama.ErrInvalidScope
+			}
+			return nakama.OAuthAuthorization{
+				AppName:       "Bot",
+				OwnerUsername: "shinji",
+				Scopes:        []string{nakama.ScopePostsWrite},
+				RedirectURI:   redirectURI,
+			}, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	t.Run("unknown_app", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/oauth/authorize?client_id=unknown")
+		if err != nil {
+			t.Fatalf("failed to do request: %v", err)
+		}
+
+		defer resp.Body.Close()
+
+		testutil.WantEq(t, http.StatusNotFound, resp.StatusCode, "status code")
+	})
+
+	t.Run("bad_scope", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/oauth/authorize?client_id=bad_scope&state=xyz")
+		if err != nil {
+			t.Fatalf("failed to do request: %v", err)
+		}
+
+		defer resp.Body.Close()
+
+		testutil.WantEq(t, http.StatusFound, resp.StatusCode, "status code")
+		testutil.WantEq(t, "https://example.org/callback?error=invalid_scope&state=xyz", resp.Header.Get("Location"), "location")
+	})
+
+	t.Run("consent", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/oauth/authorize?client_id=app&state=xyz")
+		if err != nil {
+			t.Fatalf("failed to do request: %v", err)
+		}
+
+		defer resp.Body.Close()
+
+		testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+		body := string(readAllAndTrim(t, resp.Body))
+		testutil.WantEq(t, true, strings.Contains(body, "<strong>Bot</strong> by @shinji"), "app name in body")
+		testutil.WantEq(t, true, strings.Contains(body, "posts:write"), "scope in body")
+	})
+}
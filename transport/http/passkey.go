@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+type renamePasskeyReqBody struct {
+	Name string `json:"name"`
+}
+
+func (h *handler) beginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) finishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.FinishPasskeyLogin
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.FinishPasskeyLogin(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) beginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.BeginPasskeyRegistration(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) finishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.FinishPasskeyRegistration
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.FinishPasskeyRegistration(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) passkeys(w http.ResponseWriter, r *http.Request) {
+	pp, err := h.svc.Passkeys(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if pp == nil {
+		pp = []nakama.Passkey{} // non null array
+	}
+
+	h.respond(w, pp, http.StatusOK)
+}
+
+func (h *handler) renamePasskey(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqBody renamePasskeyReqBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	passkeyID := way.Param(ctx, "passkey_id")
+	err := h.svc.RenamePasskey(ctx, passkeyID, reqBody.Name)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) deletePasskey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	passkeyID := way.Param(ctx, "passkey_id")
+	err := h.svc.DeletePasskey(ctx, passkeyID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
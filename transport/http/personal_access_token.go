@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) createPersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.CreatePersonalAccessToken
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.CreatePersonalAccessToken(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusCreated)
+}
+
+func (h *handler) personalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	tt, err := h.svc.PersonalAccessTokens(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if tt == nil {
+		tt = []nakama.PersonalAccessToken{} // non null array
+	}
+
+	h.respond(w, tt, http.StatusOK)
+}
+
+func (h *handler) revokePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tokenID := way.Param(ctx, "token_id")
+	err := h.svc.RevokePersonalAccessToken(ctx, tokenID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_withAuth_personalAccessToken(t *testing.T) {
+	svc := &transport.ServiceMock{
+		AuthFromTokenFunc: func(_ context.Context, token string) (nakama.Auth, error) {
+			return nakama.Auth{UserID: "user_id", Scopes: []string{nakama.ScopeNotificationsRead}}, nil
+		},
+		HasUnreadNotificationsFunc: func(ctx context.Context) (bool, error) {
+			testutil.WantEq(t, "user_id", ctx.Value(nakama.KeyAuthUserID), "auth user ID")
+			return true, nil
+		},
+		SessionsFunc: func(context.Context) ([]nakama.Session, error) {
+			t.Fatal("sessions should not be reached with a personal access token")
+			return nil, nil
+		},
+		TimelineFunc: func(context.Context, uint64, *string) (nakama.Timeline, error) {
+			t.Fatal("timeline should not be reached without timeline:read scope")
+			return nil, nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		target     string
+		header     bool
+		wantStatus int
+	}{
+		{
+			name:       "granted_scope",
+			target:     "/api/has_unread_notifications",
+			header:     true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing_scope",
+			target:     "/api/timeline",
+			header:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "account_management",
+			target:     "/api/auth_user/sessions",
+			header:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "in_query",
+			target:     "/api/has_unread_notifications?auth_token=nkpat_token",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(&transport.ServiceWithScopes{Next: svc}, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+tc.target, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			if tc.header {
+				req.Header.Set("Authorization", "Bearer nkpat_token")
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+		})
+	}
+}
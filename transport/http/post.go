@@ -203,3 +203,52 @@ func (h *handler) togglePostSubscription(w http.ResponseWriter, r *http.Request)
 
 	h.respond(w, out, http.StatusOK)
 }
+
+func (h *handler) pinPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	postID := way.Param(ctx, "post_id")
+	err := h.svc.PinPost(ctx, postID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unpinPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	postID := way.Param(ctx, "post_id")
+	err := h.svc.UnpinPost(ctx, postID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) pinnedPosts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	pp, err := h.svc.PinnedPosts(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if pp == nil {
+		pp = []nakama.Post{} // non null array
+	}
+
+	for i := range pp {
+		if pp[i].Reactions == nil {
+			pp[i].Reactions = []nakama.Reaction{} // non null array
+		}
+		if pp[i].MediaURLs == nil {
+			pp[i].MediaURLs = []string{} // non null array
+		}
+	}
+
+	h.respond(w, pp, http.StatusOK)
+}
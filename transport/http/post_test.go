@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_pinPost(t *testing.T) {
+	var gotPostID string
+	svc := &transport.ServiceMock{
+		PinPostFunc: func(_ context.Context, postID string) error {
+			gotPostID = postID
+			return nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/api/posts/post_id/pin", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusNoContent, resp.StatusCode, "status code")
+	testutil.WantEq(t, "post_id", gotPostID, "post ID")
+}
+
+func Test_handler_pinnedPosts(t *testing.T) {
+	svc := &transport.ServiceMock{
+		PinnedPostsFunc: func(_ context.Context, username string) (nakama.Posts, error) {
+			return nil, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/users/someone/pinned_posts")
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+
+	var out []nakama.Post
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to json decode response body: %v", err)
+	}
+
+	testutil.WantEq(t, true, out != nil, "non null array")
+}
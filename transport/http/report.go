@@ -0,0 +1,116 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+type resolveReportReqBody struct {
+	Action string `json:"action"`
+}
+
+func (h *handler) reportPost(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	postID := way.Param(ctx, "post_id")
+	err := h.svc.ReportPost(ctx, postID, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) reportComment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	commentID := way.Param(ctx, "comment_id")
+	err := h.svc.ReportComment(ctx, commentID, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) reportUser(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	err := h.svc.ReportUser(ctx, username, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) reports(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	first, _ := strconv.ParseUint(q.Get("first"), 10, 64)
+	after := emptyStrPtr(q.Get("after"))
+	rr, err := h.svc.Reports(ctx, first, after)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if rr == nil {
+		rr = []nakama.Report{} // non null array
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     rr,
+		EndCursor: rr.EndCursor(),
+	}, http.StatusOK)
+}
+
+func (h *handler) resolveReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in resolveReportReqBody
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	reportID := way.Param(ctx, "report_id")
+	err := h.svc.ResolveReport(ctx, reportID, in.Action)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_reportPost(t *testing.T) {
+	var got nakama.ReportInput
+	svc := &transport.ServiceMock{
+		ReportPostFunc: func(_ context.Context, postID string, in nakama.ReportInput) error {
+			if postID == "own_post_id" {
+				return nakama.ErrForbiddenReport
+			}
+
+			got = in
+			return nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		postID     string
+		body       string
+		wantStatus int
+		wantReason string
+	}{
+		{
+			name:       "ok",
+			postID:     "post_id",
+			body:       `{"reason":"spam"}`,
+			wantStatus: http.StatusNoContent,
+			wantReason: "spam",
+		},
+		{
+			name:       "forbidden",
+			postID:     "own_post_id",
+			body:       `{"reason":"spam"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "bad_request",
+			postID:     "post_id",
+			body:       `nope`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got = nakama.ReportInput{}
+
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/api/posts/"+tc.postID+"/reports", "application/json", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+			testutil.WantEq(t, tc.wantReason, got.Reason, "reason")
+		})
+	}
+}
+
+func Test_handler_reports(t *testing.T) {
+	svc := &transport.ServiceMock{
+		ReportsFunc: func(_ context.Context, first uint64, after *string) (nakama.Reports, error) {
+			return nil, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/reports")
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+
+	var out struct {
+		Items []nakama.Report `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to json decode response body: %v", err)
+	}
+
+	testutil.WantEq(t, true, out.Items != nil, "non null items")
+}
+
+func Test_handler_resolveReport(t *testing.T) {
+	var gotReportID, gotAction string
+	svc := &transport.ServiceMock{
+		ResolveReportFunc: func(_ context.Context, reportID, action string) error {
+			gotReportID, gotAction = reportID, action
+			return nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/api/reports/report_id/resolution", "application/json", strings.NewReader(`{"action":"hide"}`))
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusNoContent, resp.StatusCode, "status code")
+	testutil.WantEq(t, "report_id", gotReportID, "report ID")
+	testutil.WantEq(t, "hide", gotAction, "action")
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) sessions(w http.ResponseWriter, r *http.Request) {
+	ss, err := h.svc.Sessions(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if ss == nil {
+		ss = []nakama.Session{} // non null array
+	}
+
+	h.respond(w, ss, http.StatusOK)
+}
+
+func (h *handler) revokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := way.Param(ctx, "session_id")
+	err := h.svc.RevokeSession(ctx, sessionID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) logout(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.Logout(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) logoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.LogoutEverywhere(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
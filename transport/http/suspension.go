@@ -0,0 +1,45 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/matryer/way"
+)
+
+type suspendUserReqBody struct {
+	Until *time.Time `json:"until"`
+}
+
+func (h *handler) suspendUser(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in suspendUserReqBody
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	err := h.svc.SuspendUser(ctx, username, in.Until)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) unsuspendUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	err := h.svc.UnsuspendUser(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
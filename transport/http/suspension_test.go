@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_suspendUser(t *testing.T) {
+	var gotUntil *time.Time
+	svc := &transport.ServiceMock{
+		SuspendUserFunc: func(_ context.Context, username string, until *time.Time) error {
+			if username == "admin" {
+				return nakama.ErrSuspendUserDenied
+			}
+
+			gotUntil = until
+			return nil
+		},
+	}
+
+	tt := []struct {
+		name       string
+		username   string
+		body       string
+		wantStatus int
+		wantUntil  bool
+	}{
+		{
+			name:       "indefinitely",
+			username:   "john",
+			body:       `{}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "until",
+			username:   "john",
+			body:       `{"until":"2030-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusNoContent,
+			wantUntil:  true,
+		},
+		{
+			name:       "denied",
+			username:   "admin",
+			body:       `{}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "bad_request",
+			username:   "john",
+			body:       `nope`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gotUntil = nil
+
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodPut, srv.URL+"/api/users/"+tc.username+"/suspension", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+			testutil.WantEq(t, tc.wantUntil, gotUntil != nil, "until")
+		})
+	}
+}
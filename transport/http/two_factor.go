@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type verifySecondFactorReqBody struct {
+	ChallengeID string `json:"challengeID"`
+	Code        string `json:"code"`
+}
+
+type secondFactorCodeReqBody struct {
+	Code string `json:"code"`
+}
+
+type recoveryCodesRespBody struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+func (h *handler) verifySecondFactor(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqBody verifySecondFactorReqBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	out, err := h.svc.VerifySecondFactor(r.Context(), reqBody.ChallengeID, reqBody.Code)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) twoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.TwoFactorStatus(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) beginTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	out, err := h.svc.BeginTOTPEnrollment(r.Context())
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, out, http.StatusOK)
+}
+
+func (h *handler) enableTOTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqBody secondFactorCodeReqBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	codes, err := h.svc.EnableTOTP(r.Context(), reqBody.Code)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, recoveryCodesRespBody{RecoveryCodes: codes}, http.StatusOK)
+}
+
+func (h *handler) disableTOTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqBody secondFactorCodeReqBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	err := h.svc.DisableTOTP(r.Context(), reqBody.Code)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var reqBody secondFactorCodeReqBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	codes, err := h.svc.RegenerateRecoveryCodes(r.Context(), reqBody.Code)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, recoveryCodesRespBody{RecoveryCodes: codes}, http.StatusOK)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"syscall"
 
@@ -66,6 +67,10 @@ func (h *handler) user(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if u.RenamedFrom != "" {
+		w.Header().Set("Content-Location", "/api/users/"+url.PathEscape(u.Username))
+	}
+
 	h.respond(w, u, http.StatusOK)
 }
 
@@ -170,6 +175,28 @@ func (h *handler) followers(w http.ResponseWriter, r *http.Request) {
 	}, http.StatusOK)
 }
 
+func (h *handler) mutualFollowers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	username := way.Param(ctx, "username")
+	first, _ := strconv.ParseUint(q.Get("first"), 10, 64)
+	after := emptyStrPtr(q.Get("after"))
+	uu, err := h.svc.MutualFollowers(ctx, username, first, after)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if uu == nil {
+		uu = []nakama.UserProfile{} // non null array
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     uu,
+		EndCursor: uu.EndCursor(),
+	}, http.StatusOK)
+}
+
 func (h *handler) followees(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	q := r.URL.Query()
@@ -191,3 +218,19 @@ func (h *handler) followees(w http.ResponseWriter, r *http.Request) {
 		EndCursor: uu.EndCursor(),
 	}, http.StatusOK)
 }
+
+func (h *handler) suggestedUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	first, _ := strconv.ParseUint(r.URL.Query().Get("first"), 10, 64)
+	uu, err := h.svc.SuggestedUsers(ctx, first)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if uu == nil {
+		uu = []nakama.UserProfile{} // non null array
+	}
+
+	h.respond(w, uu, http.StatusOK)
+}
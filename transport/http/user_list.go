@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/matryer/way"
+
+	"github.com/nakamauwu/nakama"
+)
+
+func (h *handler) createUserList(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.CreateUserList
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	l, err := h.svc.CreateUserList(r.Context(), in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, l, http.StatusCreated)
+}
+
+func (h *handler) userLists(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	username := way.Param(ctx, "username")
+	ll, err := h.svc.UserLists(ctx, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if ll == nil {
+		ll = []nakama.UserList{} // non null array
+	}
+
+	h.respond(w, ll, http.StatusOK)
+}
+
+func (h *handler) userList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listID := way.Param(ctx, "list_id")
+	l, err := h.svc.UserList(ctx, listID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, l, http.StatusOK)
+}
+
+func (h *handler) updateUserList(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var in nakama.UpdateUserList
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		h.respondErr(w, errBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	in.ID = way.Param(ctx, "list_id")
+	l, err := h.svc.UpdateUserList(ctx, in)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	h.respond(w, l, http.StatusOK)
+}
+
+func (h *handler) deleteUserList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listID := way.Param(ctx, "list_id")
+	err := h.svc.DeleteUserList(ctx, listID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) addUserListMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listID := way.Param(ctx, "list_id")
+	username := way.Param(ctx, "username")
+	err := h.svc.AddUserListMember(ctx, listID, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) removeUserListMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	listID := way.Param(ctx, "list_id")
+	username := way.Param(ctx, "username")
+	err := h.svc.RemoveUserListMember(ctx, listID, username)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) userListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+	listID := way.Param(ctx, "list_id")
+	first, _ := strconv.ParseUint(q.Get("first"), 10, 64)
+	after := emptyStrPtr(q.Get("after"))
+	uu, err := h.svc.UserListMembers(ctx, listID, first, after)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if uu == nil {
+		uu = []nakama.UserProfile{} // non null array
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     uu,
+		EndCursor: uu.EndCursor(),
+	}, http.StatusOK)
+}
+
+func (h *handler) listTimeline(w http.ResponseWriter, r *http.Request) {
+	if a, _, err := mime.ParseMediaType(r.Header.Get("Accept")); err == nil && a == "text/event-stream" {
+		h.listTimelineStream(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	q := r.URL.Query()
+	listID := way.Param(ctx, "list_id")
+	last, _ := strconv.ParseUint(q.Get("last"), 10, 64)
+	before := emptyStrPtr(q.Get("before"))
+	pp, err := h.svc.ListTimeline(ctx, listID, last, before)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	if pp == nil {
+		pp = []nakama.Post{} // non null array
+	}
+
+	for i := range pp {
+		if pp[i].Reactions == nil {
+			pp[i].Reactions = []nakama.Reaction{} // non null array
+		}
+		if pp[i].MediaURLs == nil {
+			pp[i].MediaURLs = []string{} // non null array
+		}
+	}
+
+	h.respond(w, paginatedRespBody{
+		Items:     pp,
+		EndCursor: pp.EndCursor(),
+	}, http.StatusOK)
+}
+
+func (h *handler) listTimelineStream(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		h.respondErr(w, errStreamingUnsupported)
+		return
+	}
+
+	ctx := r.Context()
+	listID := way.Param(ctx, "list_id")
+	pp, err := h.svc.ListTimelineStream(ctx, listID)
+	if err != nil {
+		h.respondErr(w, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("Content-Type", "text/event-stream; charset=utf-8")
+
+	select {
+	case p := <-pp:
+		if p.Reactions == nil {
+			p.Reactions = []nakama.Reaction{} // non null array
+		}
+		if p.MediaURLs == nil {
+			p.MediaURLs = []string{} // non null array
+		}
+
+		h.writeSSE(w, p)
+		f.Flush()
+	case <-ctx.Done():
+		return
+	}
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_updateUserList(t *testing.T) {
+	var got nakama.UpdateUserList
+	svc := &transport.ServiceMock{
+		UpdateUserListFunc: func(_ context.Context, in nakama.UpdateUserList) (nakama.UserList, error) {
+			got = in
+			return nakama.UserList{ID: in.ID, Name: *in.Name}, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPatch, srv.URL+"/api/lists/list_id", strings.NewReader(`{"name":"friends"}`))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+	testutil.WantEq(t, "list_id", got.ID, "list ID")
+	testutil.WantEq(t, true, got.Private == nil, "private untouched")
+}
+
+func Test_handler_listTimeline(t *testing.T) {
+	var gotListID string
+	svc := &transport.ServiceMock{
+		ListTimelineFunc: func(_ context.Context, listID string, last uint64, before *string) (nakama.Posts, error) {
+			gotListID = listID
+			return nakama.Posts{{ID: "post_id"}}, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/lists/list_id/timeline")
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+	testutil.WantEq(t, "list_id", gotListID, "list ID")
+
+	var out struct {
+		Items []struct {
+			Reactions []nakama.Reaction `json:"reactions"`
+			MediaURLs []string          `json:"mediaURLs"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to json decode response body: %v", err)
+	}
+
+	testutil.WantEq(t, 1, len(out.Items), "items length")
+	testutil.WantEq(t, true, out.Items[0].Reactions != nil, "non null reactions")
+	testutil.WantEq(t, true, out.Items[0].MediaURLs != nil, "non null media URLs")
+}
+
+func Test_handler_userList_personalAccessToken(t *testing.T) {
+	newService := func(scopes ...string) *transport.ServiceMock {
+		return &transport.ServiceMock{
+			AuthFromTokenFunc: func(_ context.Context, token string) (nakama.Auth, error) {
+				return nakama.Auth{UserID: "user_id", Scopes: scopes}, nil
+			},
+			UserListsFunc: func(context.Context, string) ([]nakama.UserList, error) {
+				return []nakama.UserList{{ID: "public_list"}, {ID: "private_list", Private: true}}, nil
+			},
+			UserListFunc: func(_ context.Context, listID string) (nakama.UserList, error) {
+				return nakama.UserList{ID: listID, Private: listID == "private_list"}, nil
+			},
+			UserListMembersFunc: func(context.Context, string, uint64, *string) (nakama.UserProfiles, error) {
+				return nakama.UserProfiles{}, nil
+			},
+			ListTimelineFunc: func(context.Context, string, uint64, *string) (nakama.Posts, error) {
+				return nakama.Posts{}, nil
+			},
+		}
+	}
+
+	tt := []struct {
+		name       string
+		scopes     []string
+		target     string
+		wantStatus int
+	}{
+		{
+			name:       "private_list",
+			scopes:     []string{nakama.ScopePostsWrite},
+			target:     "/api/lists/private_list",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "private_list_members",
+			scopes:     []string{nakama.ScopePostsWrite},
+			target:     "/api/lists/private_list/members",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "private_list_timeline",
+			scopes:     []string{nakama.ScopePostsWrite},
+			target:     "/api/lists/private_list/timeline",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "public_list_timeline",
+			scopes:     []string{nakama.ScopePostsWrite},
+			target:     "/api/lists/public_list/timeline",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "private_list_granted_scope",
+			scopes:     []string{nakama.ScopeTimelineRead},
+			target:     "/api/lists/private_list/timeline",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(&transport.ServiceWithScopes{Next: newService(tc.scopes...)}, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+tc.target, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			req.Header.Set("Authorization", "Bearer nkpat_token")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, tc.wantStatus, resp.StatusCode, "status code")
+		})
+	}
+
+	t.Run("user_lists", func(t *testing.T) {
+		h := New(&transport.ServiceWithScopes{Next: newService(nakama.ScopePostsWrite)}, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+		srv := httptest.NewServer(h)
+		defer srv.Close()
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/users/john/lists", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer nkpat_token")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to do request: %v", err)
+		}
+
+		defer resp.Body.Close()
+
+		var got []nakama.UserList
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+		testutil.WantEq(t, 1, len(got), "lists")
+		testutil.WantEq(t, "public_list", got[0].ID, "list ID")
+	})
+}
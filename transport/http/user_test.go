@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/testutil"
+	"github.com/nakamauwu/nakama/transport"
+)
+
+func Test_handler_user(t *testing.T) {
+	svc := &transport.ServiceMock{
+		UserFunc: func(_ context.Context, username string) (nakama.UserProfile, error) {
+			if username == "old_name" {
+				return nakama.UserProfile{User: nakama.User{Username: "new_name"}, RenamedFrom: username}, nil
+			}
+
+			return nakama.UserProfile{User: nakama.User{Username: username}}, nil
+		},
+	}
+
+	tt := []struct {
+		name                string
+		username            string
+		wantContentLocation string
+	}{
+		{
+			name:     "current",
+			username: "new_name",
+		},
+		{
+			name:                "renamed",
+			username:            "old_name",
+			wantContentLocation: "/api/users/new_name",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + "/api/users/" + tc.username)
+			if err != nil {
+				t.Fatalf("failed to do request: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+			testutil.WantEq(t, tc.wantContentLocation, resp.Header.Get("Content-Location"), "content location")
+		})
+	}
+}
+
+func Test_handler_suggestedUsers(t *testing.T) {
+	var gotFirst uint64
+	svc := &transport.ServiceMock{
+		SuggestedUsersFunc: func(_ context.Context, first uint64) (nakama.UserProfiles, error) {
+			gotFirst = first
+			return nil, nil
+		},
+	}
+
+	h := New(svc, nil, nil, log.NewNopLogger(), nil, nil, nil, true, 0)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/suggested_users?first=5")
+	if err != nil {
+		t.Fatalf("failed to do request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	testutil.WantEq(t, http.StatusOK, resp.StatusCode, "status code")
+	testutil.WantEq(t, uint64(5), gotFirst, "first")
+
+	var out []nakama.UserProfile
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to json decode response body: %v", err)
+	}
+
+	testutil.WantEq(t, true, out != nil, "non null array")
+}
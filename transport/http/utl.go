@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -25,9 +27,9 @@ var (
 	errTeaPot               = errors.New("i am a teapot")
 	errInvalidTargetURL     = nakama.InvalidArgumentError("invalid target URL")
 	errOauthTimeout         = errors.New("oauth timeout")
-	errEmailNotVerified     = errors.New("email not verified")
 	errEmailNotProvided     = errors.New("email not provided")
 	errServiceUnavailable   = errors.New("service unavailable")
+	errScopedTokenInQuery   = errors.New("scoped token not allowed in query")
 )
 
 type paginatedRespBody struct {
@@ -52,6 +54,11 @@ func (h *handler) respond(w http.ResponseWriter, v interface{}, statusCode int)
 }
 
 func (h *handler) respondErr(w http.ResponseWriter, err error) {
+	var retryErr *nakama.RetryAfterError
+	if errors.As(err, &retryErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+
 	statusCode := err2code(err)
 	if statusCode == http.StatusInternalServerError {
 		if !errors.Is(err, context.Canceled) {
@@ -72,8 +79,8 @@ func err2code(err error) int {
 	switch {
 	case err == errBadRequest ||
 		err == errOauthTimeout ||
-		err == errEmailNotVerified ||
-		err == errEmailNotProvided:
+		err == errEmailNotProvided ||
+		err == errScopedTokenInQuery:
 		return http.StatusBadRequest
 	case err == errStreamingUnsupported:
 		return http.StatusExpectationFailed
@@ -94,6 +101,8 @@ func err2code(err error) int {
 		return http.StatusNotImplemented
 	case errors.Is(err, nakama.ErrGone):
 		return http.StatusGone
+	case errors.Is(err, nakama.ErrResourceExhausted):
+		return http.StatusTooManyRequests
 	case err == errServiceUnavailable:
 		return http.StatusServiceUnavailable
 	}
@@ -11,49 +11,118 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/storage"
 )
 
 var (
-	reqDur_SendMagicLink           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "send_magic_link_request_duration_ms"})
-	reqDur_ParseRedirectURI        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "parse_redirect_uri_request_duration_ms"})
-	reqDur_VerifyMagicLink         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "verify_magic_link_request_duration_ms"})
-	reqDur_LoginFromProvider       = promauto.NewHistogram(prometheus.HistogramOpts{Name: "login_from_provider_request_duration_ms"})
-	reqDur_DevLogin                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "dev_login_request_duration_ms"})
-	reqDur_AuthUserIDFromToken     = promauto.NewHistogram(prometheus.HistogramOpts{Name: "auth_user_id_from_token_request_duration_ms"})
-	reqDur_AuthUser                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "auth_user_request_duration_ms"})
-	reqDur_Token                   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "token_request_duration_ms"})
-	reqDur_CreateComment           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_comment_request_duration_ms"})
-	reqDur_Comments                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "comments_request_duration_ms"})
-	reqDur_CommentStream           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "comment_stream_request_duration_ms"})
-	reqDur_UpdateComment           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_comment_request_duration_ms"})
-	reqDur_DeleteComment           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_comment_request_duration_ms"})
-	reqDur_ToggleCommentReaction   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_comment_reaction_request_duration_ms"})
-	reqDur_Notifications           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "notifications_request_duration_ms"})
-	reqDur_NotificationStream      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "notification_stream_request_duration_ms"})
-	reqDur_HasUnreadNotifications  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "has_unread_notifications_request_duration_ms"})
-	reqDur_MarkNotificationAsRead  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mark_notification_as_read_request_duration_ms"})
-	reqDur_MarkNotificationsAsRead = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mark_notifications_as_read_request_duration_ms"})
-	reqDur_Posts                   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "posts_request_duration_ms"})
-	reqDur_PostStream              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "post_stream_request_duration_ms"})
-	reqDur_Post                    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "post_request_duration_ms"})
-	reqDur_UpdatePost              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_post_request_duration_ms"})
-	reqDur_DeletePost              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_post_request_duration_ms"})
-	reqDur_TogglePostReaction      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_post_reaction_request_duration_ms"})
-	reqDur_TogglePostSubscription  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_post_subscription_request_duration_ms"})
-	reqDur_CreateTimelineItem      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_timeline_item_request_duration_ms"})
-	reqDur_Timeline                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "timeline_request_duration_ms"})
-	reqDur_TimelineItemStream      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "timeline_item_stream_request_duration_ms"})
-	reqDur_DeleteTimelineItem      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_timeline_item_request_duration_ms"})
-	reqDur_Users                   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "users_request_duration_ms"})
-	reqDur_Usernames               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "usernames_request_duration_ms"})
-	reqDur_User                    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "user_request_duration_ms"})
-	reqDur_UpdateUser              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_user_request_duration_ms"})
-	reqDur_UpdateAvatar            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_avatar_request_duration_ms"})
-	reqDur_UpdateCover             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_cover_request_duration_ms"})
-	reqDur_ToggleFollow            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_follow_request_duration_ms"})
-	reqDur_Followers               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "followers_request_duration_ms"})
-	reqDur_Followees               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "followees_request_duration_ms"})
-	reqDur_AddWebPushSubscription  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "add_web_push_subscription_request_duration_ms"})
+	reqDur_SendMagicLink             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "send_magic_link_request_duration_ms"})
+	reqDur_ParseRedirectURI          = promauto.NewHistogram(prometheus.HistogramOpts{Name: "parse_redirect_uri_request_duration_ms"})
+	reqDur_VerifyMagicLink           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "verify_magic_link_request_duration_ms"})
+	reqDur_LoginFromProvider         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "login_from_provider_request_duration_ms"})
+	reqDur_DevLogin                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "dev_login_request_duration_ms"})
+	reqDur_AuthFromToken             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "auth_from_token_request_duration_ms"})
+	reqDur_AuthUser                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "auth_user_request_duration_ms"})
+	reqDur_RefreshToken              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "refresh_token_request_duration_ms"})
+	reqDur_Sessions                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "sessions_request_duration_ms"})
+	reqDur_RevokeSession             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "revoke_session_request_duration_ms"})
+	reqDur_Logout                    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "logout_request_duration_ms"})
+	reqDur_LogoutEverywhere          = promauto.NewHistogram(prometheus.HistogramOpts{Name: "logout_everywhere_request_duration_ms"})
+	reqDur_CreateComment             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_comment_request_duration_ms"})
+	reqDur_Comments                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "comments_request_duration_ms"})
+	reqDur_CommentStream             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "comment_stream_request_duration_ms"})
+	reqDur_UpdateComment             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_comment_request_duration_ms"})
+	reqDur_DeleteComment             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_comment_request_duration_ms"})
+	reqDur_ToggleCommentReaction     = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_comment_reaction_request_duration_ms"})
+	reqDur_Notifications             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "notifications_request_duration_ms"})
+	reqDur_NotificationStream        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "notification_stream_request_duration_ms"})
+	reqDur_HasUnreadNotifications    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "has_unread_notifications_request_duration_ms"})
+	reqDur_MarkNotificationAsRead    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mark_notification_as_read_request_duration_ms"})
+	reqDur_MarkNotificationsAsRead   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mark_notifications_as_read_request_duration_ms"})
+	reqDur_Posts                     = promauto.NewHistogram(prometheus.HistogramOpts{Name: "posts_request_duration_ms"})
+	reqDur_PostStream                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "post_stream_request_duration_ms"})
+	reqDur_Post                      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "post_request_duration_ms"})
+	reqDur_UpdatePost                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_post_request_duration_ms"})
+	reqDur_DeletePost                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_post_request_duration_ms"})
+	reqDur_TogglePostReaction        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_post_reaction_request_duration_ms"})
+	reqDur_TogglePostSubscription    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_post_subscription_request_duration_ms"})
+	reqDur_CreateTimelineItem        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_timeline_item_request_duration_ms"})
+	reqDur_Timeline                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "timeline_request_duration_ms"})
+	reqDur_TimelineItemStream        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "timeline_item_stream_request_duration_ms"})
+	reqDur_DeleteTimelineItem        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_timeline_item_request_duration_ms"})
+	reqDur_Users                     = promauto.NewHistogram(prometheus.HistogramOpts{Name: "users_request_duration_ms"})
+	reqDur_Usernames                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "usernames_request_duration_ms"})
+	reqDur_User                      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "user_request_duration_ms"})
+	reqDur_UpdateUser                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_user_request_duration_ms"})
+	reqDur_UpdateAvatar              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_avatar_request_duration_ms"})
+	reqDur_UpdateCover               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_cover_request_duration_ms"})
+	reqDur_ToggleFollow              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_follow_request_duration_ms"})
+	reqDur_Followers                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "followers_request_duration_ms"})
+	reqDur_Followees                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "followees_request_duration_ms"})
+	reqDur_AddWebPushSubscription    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "add_web_push_subscription_request_duration_ms"})
+	reqDur_BeginPasskeyLogin         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "begin_passkey_login_request_duration_ms"})
+	reqDur_FinishPasskeyLogin        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "finish_passkey_login_request_duration_ms"})
+	reqDur_BeginPasskeyRegistration  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "begin_passkey_registration_request_duration_ms"})
+	reqDur_FinishPasskeyRegistration = promauto.NewHistogram(prometheus.HistogramOpts{Name: "finish_passkey_registration_request_duration_ms"})
+	reqDur_Passkeys                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "passkeys_request_duration_ms"})
+	reqDur_RenamePasskey             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "rename_passkey_request_duration_ms"})
+	reqDur_DeletePasskey             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_passkey_request_duration_ms"})
+	reqDur_VerifySecondFactor        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "verify_second_factor_request_duration_ms"})
+	reqDur_TwoFactorStatus           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "two_factor_status_request_duration_ms"})
+	reqDur_BeginTOTPEnrollment       = promauto.NewHistogram(prometheus.HistogramOpts{Name: "begin_totp_enrollment_request_duration_ms"})
+	reqDur_EnableTOTP                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "enable_totp_request_duration_ms"})
+	reqDur_DisableTOTP               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "disable_totp_request_duration_ms"})
+	reqDur_RegenerateRecoveryCodes   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "regenerate_recovery_codes_request_duration_ms"})
+	reqDur_Identities                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "identities_request_duration_ms"})
+	reqDur_LinkIdentity              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "link_identity_request_duration_ms"})
+	reqDur_UnlinkIdentity            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "unlink_identity_request_duration_ms"})
+	reqDur_CreatePersonalAccessToken = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_personal_access_token_request_duration_ms"})
+	reqDur_PersonalAccessTokens      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "personal_access_tokens_request_duration_ms"})
+	reqDur_RevokePersonalAccessToken = promauto.NewHistogram(prometheus.HistogramOpts{Name: "revoke_personal_access_token_request_duration_ms"})
+	reqDur_CreateOAuthApp            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_oauth_app_request_duration_ms"})
+	reqDur_OAuthApps                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "oauth_apps_request_duration_ms"})
+	reqDur_DeleteOAuthApp            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_oauth_app_request_duration_ms"})
+	reqDur_OAuthAuthorization        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "oauth_authorization_request_duration_ms"})
+	reqDur_AuthorizeOAuthApp         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "authorize_oauth_app_request_duration_ms"})
+	reqDur_ExchangeOAuthCode         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "exchange_oauth_code_request_duration_ms"})
+	reqDur_IntrospectOAuthToken      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "introspect_oauth_token_request_duration_ms"})
+	reqDur_AuthorizedOAuthApps       = promauto.NewHistogram(prometheus.HistogramOpts{Name: "authorized_oauth_apps_request_duration_ms"})
+	reqDur_RevokeOAuthApp            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "revoke_oauth_app_request_duration_ms"})
+	reqDur_VerifyLoginCode           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "verify_login_code_request_duration_ms"})
+	reqDur_RequestAccountDeletion    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "request_account_deletion_request_duration_ms"})
+	reqDur_ConfirmAccountDeletion    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "confirm_account_deletion_request_duration_ms"})
+	reqDur_RequestDataExport         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "request_data_export_request_duration_ms"})
+	reqDur_DataExportFile            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "data_export_file_request_duration_ms"})
+	reqDur_ToggleBlock               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "toggle_block_request_duration_ms"})
+	reqDur_Blocks                    = promauto.NewHistogram(prometheus.HistogramOpts{Name: "blocks_request_duration_ms"})
+	reqDur_CreateMute                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_mute_request_duration_ms"})
+	reqDur_Mutes                     = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mutes_request_duration_ms"})
+	reqDur_UpdateMute                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_mute_request_duration_ms"})
+	reqDur_DeleteMute                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_mute_request_duration_ms"})
+	reqDur_FollowRequests            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "follow_requests_request_duration_ms"})
+	reqDur_ApproveFollowRequest      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "approve_follow_request_request_duration_ms"})
+	reqDur_DenyFollowRequest         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "deny_follow_request_request_duration_ms"})
+	reqDur_SuggestedUsers            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "suggested_users_request_duration_ms"})
+	reqDur_MutualFollowers           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "mutual_followers_request_duration_ms"})
+	reqDur_CreateUserList            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "create_user_list_request_duration_ms"})
+	reqDur_UserLists                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "user_lists_request_duration_ms"})
+	reqDur_UserList                  = promauto.NewHistogram(prometheus.HistogramOpts{Name: "user_list_request_duration_ms"})
+	reqDur_UpdateUserList            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "update_user_list_request_duration_ms"})
+	reqDur_DeleteUserList            = promauto.NewHistogram(prometheus.HistogramOpts{Name: "delete_user_list_request_duration_ms"})
+	reqDur_AddUserListMember         = promauto.NewHistogram(prometheus.HistogramOpts{Name: "add_user_list_member_request_duration_ms"})
+	reqDur_RemoveUserListMember      = promauto.NewHistogram(prometheus.HistogramOpts{Name: "remove_user_list_member_request_duration_ms"})
+	reqDur_UserListMembers           = promauto.NewHistogram(prometheus.HistogramOpts{Name: "user_list_members_request_duration_ms"})
+	reqDur_ListTimeline              = promauto.NewHistogram(prometheus.HistogramOpts{Name: "list_timeline_request_duration_ms"})
+	reqDur_ListTimelineStream        = promauto.NewHistogram(prometheus.HistogramOpts{Name: "list_timeline_stream_request_duration_ms"})
+	reqDur_PinPost                   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "pin_post_request_duration_ms"})
+	reqDur_UnpinPost                 = promauto.NewHistogram(prometheus.HistogramOpts{Name: "unpin_post_request_duration_ms"})
+	reqDur_PinnedPosts               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "pinned_posts_request_duration_ms"})
+	reqDur_SuspendUser               = promauto.NewHistogram(prometheus.HistogramOpts{Name: "suspend_user_request_duration_ms"})
+	reqDur_UnsuspendUser             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "unsuspend_user_request_duration_ms"})
+	reqDur_ReportPost                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "report_post_request_duration_ms"})
+	reqDur_ReportComment             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "report_comment_request_duration_ms"})
+	reqDur_ReportUser                = promauto.NewHistogram(prometheus.HistogramOpts{Name: "report_user_request_duration_ms"})
+	reqDur_Reports                   = promauto.NewHistogram(prometheus.HistogramOpts{Name: "reports_request_duration_ms"})
+	reqDur_ResolveReport             = promauto.NewHistogram(prometheus.HistogramOpts{Name: "resolve_report_request_duration_ms"})
 )
 
 type ServiceWithInstrumentation struct {
@@ -81,7 +150,7 @@ func (mw *ServiceWithInstrumentation) VerifyMagicLink(ctx context.Context, email
 	return mw.Next.VerifyMagicLink(ctx, email, code, username)
 }
 
-func (mw *ServiceWithInstrumentation) LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.User, error) {
+func (mw *ServiceWithInstrumentation) LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error) {
 	defer func(begin time.Time) {
 		reqDur_LoginFromProvider.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
 	}(time.Now())
@@ -95,11 +164,11 @@ func (mw *ServiceWithInstrumentation) DevLogin(ctx context.Context, email string
 	return mw.Next.DevLogin(ctx, email)
 }
 
-func (mw *ServiceWithInstrumentation) AuthUserIDFromToken(token string) (string, error) {
+func (mw *ServiceWithInstrumentation) AuthFromToken(ctx context.Context, token string) (nakama.Auth, error) {
 	defer func(begin time.Time) {
-		reqDur_AuthUserIDFromToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+		reqDur_AuthFromToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
 	}(time.Now())
-	return mw.Next.AuthUserIDFromToken(token)
+	return mw.Next.AuthFromToken(ctx, token)
 }
 
 func (mw *ServiceWithInstrumentation) AuthUser(ctx context.Context) (nakama.User, error) {
@@ -109,11 +178,39 @@ func (mw *ServiceWithInstrumentation) AuthUser(ctx context.Context) (nakama.User
 	return mw.Next.AuthUser(ctx)
 }
 
-func (mw *ServiceWithInstrumentation) Token(ctx context.Context) (nakama.TokenOutput, error) {
+func (mw *ServiceWithInstrumentation) RefreshToken(ctx context.Context, refreshToken string) (nakama.TokenOutput, error) {
 	defer func(begin time.Time) {
-		reqDur_Token.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+		reqDur_RefreshToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
 	}(time.Now())
-	return mw.Next.Token(ctx)
+	return mw.Next.RefreshToken(ctx, refreshToken)
+}
+
+func (mw *ServiceWithInstrumentation) Sessions(ctx context.Context) ([]nakama.Session, error) {
+	defer func(begin time.Time) {
+		reqDur_Sessions.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Sessions(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) RevokeSession(ctx context.Context, sessionID string) error {
+	defer func(begin time.Time) {
+		reqDur_RevokeSession.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RevokeSession(ctx, sessionID)
+}
+
+func (mw *ServiceWithInstrumentation) Logout(ctx context.Context) error {
+	defer func(begin time.Time) {
+		reqDur_Logout.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Logout(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) LogoutEverywhere(ctx context.Context) error {
+	defer func(begin time.Time) {
+		reqDur_LogoutEverywhere.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.LogoutEverywhere(ctx)
 }
 
 func (mw *ServiceWithInstrumentation) CreateComment(ctx context.Context, postID, content string) (nakama.Comment, error) {
@@ -339,3 +436,451 @@ func (mw *ServiceWithInstrumentation) AddWebPushSubscription(ctx context.Context
 	}(time.Now())
 	return mw.Next.AddWebPushSubscription(ctx, sub)
 }
+
+func (mw *ServiceWithInstrumentation) BeginPasskeyLogin(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	defer func(begin time.Time) {
+		reqDur_BeginPasskeyLogin.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.BeginPasskeyLogin(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) FinishPasskeyLogin(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error) {
+	defer func(begin time.Time) {
+		reqDur_FinishPasskeyLogin.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.FinishPasskeyLogin(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) BeginPasskeyRegistration(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	defer func(begin time.Time) {
+		reqDur_BeginPasskeyRegistration.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.BeginPasskeyRegistration(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) FinishPasskeyRegistration(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error) {
+	defer func(begin time.Time) {
+		reqDur_FinishPasskeyRegistration.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.FinishPasskeyRegistration(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) Passkeys(ctx context.Context) ([]nakama.Passkey, error) {
+	defer func(begin time.Time) {
+		reqDur_Passkeys.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Passkeys(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) RenamePasskey(ctx context.Context, passkeyID, name string) error {
+	defer func(begin time.Time) {
+		reqDur_RenamePasskey.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RenamePasskey(ctx, passkeyID, name)
+}
+
+func (mw *ServiceWithInstrumentation) DeletePasskey(ctx context.Context, passkeyID string) error {
+	defer func(begin time.Time) {
+		reqDur_DeletePasskey.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DeletePasskey(ctx, passkeyID)
+}
+
+func (mw *ServiceWithInstrumentation) VerifySecondFactor(ctx context.Context, challengeID, code string) (nakama.AuthOutput, error) {
+	defer func(begin time.Time) {
+		reqDur_VerifySecondFactor.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.VerifySecondFactor(ctx, challengeID, code)
+}
+
+func (mw *ServiceWithInstrumentation) TwoFactorStatus(ctx context.Context) (nakama.TwoFactorStatus, error) {
+	defer func(begin time.Time) {
+		reqDur_TwoFactorStatus.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.TwoFactorStatus(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) BeginTOTPEnrollment(ctx context.Context) (nakama.TOTPEnrollment, error) {
+	defer func(begin time.Time) {
+		reqDur_BeginTOTPEnrollment.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.BeginTOTPEnrollment(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) EnableTOTP(ctx context.Context, code string) ([]string, error) {
+	defer func(begin time.Time) {
+		reqDur_EnableTOTP.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.EnableTOTP(ctx, code)
+}
+
+func (mw *ServiceWithInstrumentation) DisableTOTP(ctx context.Context, code string) error {
+	defer func(begin time.Time) {
+		reqDur_DisableTOTP.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DisableTOTP(ctx, code)
+}
+
+func (mw *ServiceWithInstrumentation) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	defer func(begin time.Time) {
+		reqDur_RegenerateRecoveryCodes.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RegenerateRecoveryCodes(ctx, code)
+}
+
+func (mw *ServiceWithInstrumentation) Identities(ctx context.Context) ([]nakama.UserIdentity, error) {
+	defer func(begin time.Time) {
+		reqDur_Identities.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Identities(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) LinkIdentity(ctx context.Context, provider string, user nakama.ProvidedUser) error {
+	defer func(begin time.Time) {
+		reqDur_LinkIdentity.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.LinkIdentity(ctx, provider, user)
+}
+
+func (mw *ServiceWithInstrumentation) UnlinkIdentity(ctx context.Context, provider string) error {
+	defer func(begin time.Time) {
+		reqDur_UnlinkIdentity.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UnlinkIdentity(ctx, provider)
+}
+
+func (mw *ServiceWithInstrumentation) CreatePersonalAccessToken(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error) {
+	defer func(begin time.Time) {
+		reqDur_CreatePersonalAccessToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.CreatePersonalAccessToken(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) PersonalAccessTokens(ctx context.Context) ([]nakama.PersonalAccessToken, error) {
+	defer func(begin time.Time) {
+		reqDur_PersonalAccessTokens.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.PersonalAccessTokens(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) RevokePersonalAccessToken(ctx context.Context, tokenID string) error {
+	defer func(begin time.Time) {
+		reqDur_RevokePersonalAccessToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RevokePersonalAccessToken(ctx, tokenID)
+}
+
+func (mw *ServiceWithInstrumentation) CreateOAuthApp(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error) {
+	defer func(begin time.Time) {
+		reqDur_CreateOAuthApp.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.CreateOAuthApp(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) OAuthApps(ctx context.Context) ([]nakama.OAuthApp, error) {
+	defer func(begin time.Time) {
+		reqDur_OAuthApps.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.OAuthApps(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) DeleteOAuthApp(ctx context.Context, appID string) error {
+	defer func(begin time.Time) {
+		reqDur_DeleteOAuthApp.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DeleteOAuthApp(ctx, appID)
+}
+
+func (mw *ServiceWithInstrumentation) OAuthAuthorization(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error) {
+	defer func(begin time.Time) {
+		reqDur_OAuthAuthorization.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.OAuthAuthorization(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) AuthorizeOAuthApp(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error) {
+	defer func(begin time.Time) {
+		reqDur_AuthorizeOAuthApp.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.AuthorizeOAuthApp(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) ExchangeOAuthCode(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+	defer func(begin time.Time) {
+		reqDur_ExchangeOAuthCode.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ExchangeOAuthCode(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) IntrospectOAuthToken(ctx context.Context, clientID, clientSecret, token string) (nakama.OAuthTokenIntrospection, error) {
+	defer func(begin time.Time) {
+		reqDur_IntrospectOAuthToken.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.IntrospectOAuthToken(ctx, clientID, clientSecret, token)
+}
+
+func (mw *ServiceWithInstrumentation) AuthorizedOAuthApps(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error) {
+	defer func(begin time.Time) {
+		reqDur_AuthorizedOAuthApps.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.AuthorizedOAuthApps(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) RevokeOAuthApp(ctx context.Context, appID string) error {
+	defer func(begin time.Time) {
+		reqDur_RevokeOAuthApp.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RevokeOAuthApp(ctx, appID)
+}
+
+func (mw *ServiceWithInstrumentation) VerifyLoginCode(ctx context.Context, email, code string, username *string) (nakama.AuthOutput, error) {
+	defer func(begin time.Time) {
+		reqDur_VerifyLoginCode.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.VerifyLoginCode(ctx, email, code, username)
+}
+
+func (mw *ServiceWithInstrumentation) RequestAccountDeletion(ctx context.Context) error {
+	defer func(begin time.Time) {
+		reqDur_RequestAccountDeletion.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RequestAccountDeletion(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) ConfirmAccountDeletion(ctx context.Context, code string) (time.Time, error) {
+	defer func(begin time.Time) {
+		reqDur_ConfirmAccountDeletion.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ConfirmAccountDeletion(ctx, code)
+}
+
+func (mw *ServiceWithInstrumentation) RequestDataExport(ctx context.Context) (nakama.DataExport, error) {
+	defer func(begin time.Time) {
+		reqDur_RequestDataExport.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RequestDataExport(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) DataExportFile(ctx context.Context, token string) (*storage.File, error) {
+	defer func(begin time.Time) {
+		reqDur_DataExportFile.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DataExportFile(ctx, token)
+}
+
+func (mw *ServiceWithInstrumentation) ToggleBlock(ctx context.Context, username string) (nakama.ToggleBlockOutput, error) {
+	defer func(begin time.Time) {
+		reqDur_ToggleBlock.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ToggleBlock(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) Blocks(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	defer func(begin time.Time) {
+		reqDur_Blocks.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Blocks(ctx, first, after)
+}
+
+func (mw *ServiceWithInstrumentation) CreateMute(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error) {
+	defer func(begin time.Time) {
+		reqDur_CreateMute.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.CreateMute(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) Mutes(ctx context.Context) ([]nakama.Mute, error) {
+	defer func(begin time.Time) {
+		reqDur_Mutes.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Mutes(ctx)
+}
+
+func (mw *ServiceWithInstrumentation) UpdateMute(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error) {
+	defer func(begin time.Time) {
+		reqDur_UpdateMute.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UpdateMute(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) DeleteMute(ctx context.Context, muteID string) error {
+	defer func(begin time.Time) {
+		reqDur_DeleteMute.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DeleteMute(ctx, muteID)
+}
+
+func (mw *ServiceWithInstrumentation) FollowRequests(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	defer func(begin time.Time) {
+		reqDur_FollowRequests.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.FollowRequests(ctx, first, after)
+}
+
+func (mw *ServiceWithInstrumentation) ApproveFollowRequest(ctx context.Context, username string) error {
+	defer func(begin time.Time) {
+		reqDur_ApproveFollowRequest.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ApproveFollowRequest(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) DenyFollowRequest(ctx context.Context, username string) error {
+	defer func(begin time.Time) {
+		reqDur_DenyFollowRequest.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DenyFollowRequest(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) SuggestedUsers(ctx context.Context, first uint64) (nakama.UserProfiles, error) {
+	defer func(begin time.Time) {
+		reqDur_SuggestedUsers.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.SuggestedUsers(ctx, first)
+}
+
+func (mw *ServiceWithInstrumentation) MutualFollowers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	defer func(begin time.Time) {
+		reqDur_MutualFollowers.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.MutualFollowers(ctx, username, first, after)
+}
+
+func (mw *ServiceWithInstrumentation) CreateUserList(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error) {
+	defer func(begin time.Time) {
+		reqDur_CreateUserList.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.CreateUserList(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) UserLists(ctx context.Context, username string) ([]nakama.UserList, error) {
+	defer func(begin time.Time) {
+		reqDur_UserLists.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UserLists(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) UserList(ctx context.Context, listID string) (nakama.UserList, error) {
+	defer func(begin time.Time) {
+		reqDur_UserList.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UserList(ctx, listID)
+}
+
+func (mw *ServiceWithInstrumentation) UpdateUserList(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error) {
+	defer func(begin time.Time) {
+		reqDur_UpdateUserList.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UpdateUserList(ctx, in)
+}
+
+func (mw *ServiceWithInstrumentation) DeleteUserList(ctx context.Context, listID string) error {
+	defer func(begin time.Time) {
+		reqDur_DeleteUserList.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.DeleteUserList(ctx, listID)
+}
+
+func (mw *ServiceWithInstrumentation) AddUserListMember(ctx context.Context, listID, username string) error {
+	defer func(begin time.Time) {
+		reqDur_AddUserListMember.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.AddUserListMember(ctx, listID, username)
+}
+
+func (mw *ServiceWithInstrumentation) RemoveUserListMember(ctx context.Context, listID, username string) error {
+	defer func(begin time.Time) {
+		reqDur_RemoveUserListMember.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.RemoveUserListMember(ctx, listID, username)
+}
+
+func (mw *ServiceWithInstrumentation) UserListMembers(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error) {
+	defer func(begin time.Time) {
+		reqDur_UserListMembers.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UserListMembers(ctx, listID, first, after)
+}
+
+func (mw *ServiceWithInstrumentation) ListTimeline(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error) {
+	defer func(begin time.Time) {
+		reqDur_ListTimeline.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ListTimeline(ctx, listID, last, before)
+}
+
+func (mw *ServiceWithInstrumentation) ListTimelineStream(ctx context.Context, listID string) (<-chan nakama.Post, error) {
+	defer func(begin time.Time) {
+		reqDur_ListTimelineStream.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ListTimelineStream(ctx, listID)
+}
+
+func (mw *ServiceWithInstrumentation) PinPost(ctx context.Context, postID string) error {
+	defer func(begin time.Time) {
+		reqDur_PinPost.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.PinPost(ctx, postID)
+}
+
+func (mw *ServiceWithInstrumentation) UnpinPost(ctx context.Context, postID string) error {
+	defer func(begin time.Time) {
+		reqDur_UnpinPost.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UnpinPost(ctx, postID)
+}
+
+func (mw *ServiceWithInstrumentation) PinnedPosts(ctx context.Context, username string) (nakama.Posts, error) {
+	defer func(begin time.Time) {
+		reqDur_PinnedPosts.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.PinnedPosts(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) SuspendUser(ctx context.Context, username string, until *time.Time) error {
+	defer func(begin time.Time) {
+		reqDur_SuspendUser.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.SuspendUser(ctx, username, until)
+}
+
+func (mw *ServiceWithInstrumentation) UnsuspendUser(ctx context.Context, username string) error {
+	defer func(begin time.Time) {
+		reqDur_UnsuspendUser.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.UnsuspendUser(ctx, username)
+}
+
+func (mw *ServiceWithInstrumentation) ReportPost(ctx context.Context, postID string, in nakama.ReportInput) error {
+	defer func(begin time.Time) {
+		reqDur_ReportPost.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ReportPost(ctx, postID, in)
+}
+
+func (mw *ServiceWithInstrumentation) ReportComment(ctx context.Context, commentID string, in nakama.ReportInput) error {
+	defer func(begin time.Time) {
+		reqDur_ReportComment.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ReportComment(ctx, commentID, in)
+}
+
+func (mw *ServiceWithInstrumentation) ReportUser(ctx context.Context, username string, in nakama.ReportInput) error {
+	defer func(begin time.Time) {
+		reqDur_ReportUser.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ReportUser(ctx, username, in)
+}
+
+func (mw *ServiceWithInstrumentation) Reports(ctx context.Context, first uint64, after *string) (nakama.Reports, error) {
+	defer func(begin time.Time) {
+		reqDur_Reports.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.Reports(ctx, first, after)
+}
+
+func (mw *ServiceWithInstrumentation) ResolveReport(ctx context.Context, reportID, action string) error {
+	defer func(begin time.Time) {
+		reqDur_ResolveReport.Observe(float64(time.Since(begin)) / float64(time.Millisecond))
+	}(time.Now())
+	return mw.Next.ResolveReport(ctx, reportID, action)
+}
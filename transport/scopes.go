@@ -0,0 +1,814 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/SherClockHolmes/webpush-go"
+
+	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/storage"
+)
+
+// scopeAccount is never granted to personal access tokens.
+// Used for account management like sessions, second factors
+// or personal access tokens themselves.
+const scopeAccount = ""
+
+// ServiceWithScopes restricts requests authenticated with a personal access token
+// to the scopes granted to that token.
+// Methods that just read public data need no scope.
+type ServiceWithScopes struct {
+	Next Service
+}
+
+func authorize(ctx context.Context, scope string) error {
+	if !nakama.HasScope(ctx, scope) {
+		return nakama.ErrInsufficientScope
+	}
+
+	return nil
+}
+
+func (mw *ServiceWithScopes) SendMagicLink(ctx context.Context, in nakama.SendMagicLink) error {
+	if in.UpdateEmail {
+		if err := authorize(ctx, scopeAccount); err != nil {
+			return err
+		}
+	}
+
+	return mw.Next.SendMagicLink(ctx, in)
+}
+
+func (mw *ServiceWithScopes) ParseRedirectURI(rawurl string) (*url.URL, error) {
+	return mw.Next.ParseRedirectURI(rawurl)
+}
+
+func (mw *ServiceWithScopes) VerifyMagicLink(ctx context.Context, email, code string, username *string) (nakama.AuthOutput, error) {
+	return mw.Next.VerifyMagicLink(ctx, email, code, username)
+}
+
+func (mw *ServiceWithScopes) VerifyLoginCode(ctx context.Context, email, code string, username *string) (nakama.AuthOutput, error) {
+	return mw.Next.VerifyLoginCode(ctx, email, code, username)
+}
+
+func (mw *ServiceWithScopes) LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error) {
+	return mw.Next.LoginFromProvider(ctx, name, user)
+}
+
+func (mw *ServiceWithScopes) Identities(ctx context.Context) ([]nakama.UserIdentity, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.Identities(ctx)
+}
+
+func (mw *ServiceWithScopes) LinkIdentity(ctx context.Context, provider string, user nakama.ProvidedUser) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.LinkIdentity(ctx, provider, user)
+}
+
+func (mw *ServiceWithScopes) UnlinkIdentity(ctx context.Context, provider string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.UnlinkIdentity(ctx, provider)
+}
+
+func (mw *ServiceWithScopes) VerifySecondFactor(ctx context.Context, challengeID, code string) (nakama.AuthOutput, error) {
+	return mw.Next.VerifySecondFactor(ctx, challengeID, code)
+}
+
+func (mw *ServiceWithScopes) TwoFactorStatus(ctx context.Context) (nakama.TwoFactorStatus, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.TwoFactorStatus{}, err
+	}
+
+	return mw.Next.TwoFactorStatus(ctx)
+}
+
+func (mw *ServiceWithScopes) BeginTOTPEnrollment(ctx context.Context) (nakama.TOTPEnrollment, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.TOTPEnrollment{}, err
+	}
+
+	return mw.Next.BeginTOTPEnrollment(ctx)
+}
+
+func (mw *ServiceWithScopes) EnableTOTP(ctx context.Context, code string) ([]string, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.EnableTOTP(ctx, code)
+}
+
+func (mw *ServiceWithScopes) DisableTOTP(ctx context.Context, code string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.DisableTOTP(ctx, code)
+}
+
+func (mw *ServiceWithScopes) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.RegenerateRecoveryCodes(ctx, code)
+}
+
+func (mw *ServiceWithScopes) BeginPasskeyLogin(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	return mw.Next.BeginPasskeyLogin(ctx)
+}
+
+func (mw *ServiceWithScopes) FinishPasskeyLogin(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error) {
+	return mw.Next.FinishPasskeyLogin(ctx, in)
+}
+
+func (mw *ServiceWithScopes) BeginPasskeyRegistration(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.PasskeyCeremony{}, err
+	}
+
+	return mw.Next.BeginPasskeyRegistration(ctx)
+}
+
+func (mw *ServiceWithScopes) FinishPasskeyRegistration(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.Passkey{}, err
+	}
+
+	return mw.Next.FinishPasskeyRegistration(ctx, in)
+}
+
+func (mw *ServiceWithScopes) Passkeys(ctx context.Context) ([]nakama.Passkey, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.Passkeys(ctx)
+}
+
+func (mw *ServiceWithScopes) RenamePasskey(ctx context.Context, passkeyID, name string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RenamePasskey(ctx, passkeyID, name)
+}
+
+func (mw *ServiceWithScopes) DeletePasskey(ctx context.Context, passkeyID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.DeletePasskey(ctx, passkeyID)
+}
+
+func (mw *ServiceWithScopes) DevLogin(ctx context.Context, email string) (nakama.AuthOutput, error) {
+	return mw.Next.DevLogin(ctx, email)
+}
+
+func (mw *ServiceWithScopes) AuthFromToken(ctx context.Context, token string) (nakama.Auth, error) {
+	return mw.Next.AuthFromToken(ctx, token)
+}
+
+func (mw *ServiceWithScopes) AuthUser(ctx context.Context) (nakama.User, error) {
+	return mw.Next.AuthUser(ctx)
+}
+
+func (mw *ServiceWithScopes) RefreshToken(ctx context.Context, refreshToken string) (nakama.TokenOutput, error) {
+	return mw.Next.RefreshToken(ctx, refreshToken)
+}
+
+func (mw *ServiceWithScopes) Sessions(ctx context.Context) ([]nakama.Session, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.Sessions(ctx)
+}
+
+func (mw *ServiceWithScopes) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RevokeSession(ctx, sessionID)
+}
+
+func (mw *ServiceWithScopes) Logout(ctx context.Context) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.Logout(ctx)
+}
+
+func (mw *ServiceWithScopes) LogoutEverywhere(ctx context.Context) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.LogoutEverywhere(ctx)
+}
+
+func (mw *ServiceWithScopes) CreatePersonalAccessToken(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.CreatedPersonalAccessToken{}, err
+	}
+
+	return mw.Next.CreatePersonalAccessToken(ctx, in)
+}
+
+func (mw *ServiceWithScopes) PersonalAccessTokens(ctx context.Context) ([]nakama.PersonalAccessToken, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.PersonalAccessTokens(ctx)
+}
+
+func (mw *ServiceWithScopes) RevokePersonalAccessToken(ctx context.Context, tokenID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RevokePersonalAccessToken(ctx, tokenID)
+}
+
+func (mw *ServiceWithScopes) CreateOAuthApp(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.CreatedOAuthApp{}, err
+	}
+
+	return mw.Next.CreateOAuthApp(ctx, in)
+}
+
+func (mw *ServiceWithScopes) OAuthApps(ctx context.Context) ([]nakama.OAuthApp, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.OAuthApps(ctx)
+}
+
+func (mw *ServiceWithScopes) DeleteOAuthApp(ctx context.Context, appID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.DeleteOAuthApp(ctx, appID)
+}
+
+func (mw *ServiceWithScopes) OAuthAuthorization(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error) {
+	return mw.Next.OAuthAuthorization(ctx, in)
+}
+
+func (mw *ServiceWithScopes) AuthorizeOAuthApp(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.AuthorizeOAuthApp(ctx, in)
+}
+
+func (mw *ServiceWithScopes) ExchangeOAuthCode(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+	return mw.Next.ExchangeOAuthCode(ctx, in)
+}
+
+func (mw *ServiceWithScopes) IntrospectOAuthToken(ctx context.Context, clientID, clientSecret, token string) (nakama.OAuthTokenIntrospection, error) {
+	return mw.Next.IntrospectOAuthToken(ctx, clientID, clientSecret, token)
+}
+
+func (mw *ServiceWithScopes) AuthorizedOAuthApps(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.AuthorizedOAuthApps(ctx)
+}
+
+func (mw *ServiceWithScopes) RevokeOAuthApp(ctx context.Context, appID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RevokeOAuthApp(ctx, appID)
+}
+
+func (mw *ServiceWithScopes) CreateComment(ctx context.Context, postID, content string) (nakama.Comment, error) {
+	if err := authorize(ctx, nakama.ScopeCommentsWrite); err != nil {
+		return nakama.Comment{}, err
+	}
+
+	return mw.Next.CreateComment(ctx, postID, content)
+}
+
+func (mw *ServiceWithScopes) Comments(ctx context.Context, postID string, last uint64, before *string) (nakama.Comments, error) {
+	return mw.Next.Comments(ctx, postID, last, before)
+}
+
+func (mw *ServiceWithScopes) CommentStream(ctx context.Context, postID string) (<-chan nakama.Comment, error) {
+	return mw.Next.CommentStream(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) UpdateComment(ctx context.Context, in nakama.UpdateComment) (nakama.UpdatedComment, error) {
+	if err := authorize(ctx, nakama.ScopeCommentsWrite); err != nil {
+		return nakama.UpdatedComment{}, err
+	}
+
+	return mw.Next.UpdateComment(ctx, in)
+}
+
+func (mw *ServiceWithScopes) DeleteComment(ctx context.Context, commentID string) error {
+	if err := authorize(ctx, nakama.ScopeCommentsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.DeleteComment(ctx, commentID)
+}
+
+func (mw *ServiceWithScopes) ToggleCommentReaction(ctx context.Context, commentID string, in nakama.ReactionInput) ([]nakama.Reaction, error) {
+	if err := authorize(ctx, nakama.ScopeReactionsWrite); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.ToggleCommentReaction(ctx, commentID, in)
+}
+
+func (mw *ServiceWithScopes) Notifications(ctx context.Context, last uint64, before *string) (nakama.Notifications, error) {
+	if err := authorize(ctx, nakama.ScopeNotificationsRead); err != nil {
+		return nakama.Notifications{}, err
+	}
+
+	return mw.Next.Notifications(ctx, last, before)
+}
+
+func (mw *ServiceWithScopes) NotificationStream(ctx context.Context) (<-chan nakama.Notification, error) {
+	if err := authorize(ctx, nakama.ScopeNotificationsRead); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.NotificationStream(ctx)
+}
+
+func (mw *ServiceWithScopes) HasUnreadNotifications(ctx context.Context) (bool, error) {
+	if err := authorize(ctx, nakama.ScopeNotificationsRead); err != nil {
+		return false, err
+	}
+
+	return mw.Next.HasUnreadNotifications(ctx)
+}
+
+func (mw *ServiceWithScopes) MarkNotificationAsRead(ctx context.Context, notificationID string) error {
+	if err := authorize(ctx, nakama.ScopeNotificationsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.MarkNotificationAsRead(ctx, notificationID)
+}
+
+func (mw *ServiceWithScopes) MarkNotificationsAsRead(ctx context.Context) error {
+	if err := authorize(ctx, nakama.ScopeNotificationsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.MarkNotificationsAsRead(ctx)
+}
+
+func (mw *ServiceWithScopes) Posts(ctx context.Context, last uint64, before *string, opts ...nakama.PostsOpt) (nakama.Posts, error) {
+	return mw.Next.Posts(ctx, last, before, opts...)
+}
+
+func (mw *ServiceWithScopes) PostStream(ctx context.Context) (<-chan nakama.Post, error) {
+	return mw.Next.PostStream(ctx)
+}
+
+func (mw *ServiceWithScopes) Post(ctx context.Context, postID string) (nakama.Post, error) {
+	return mw.Next.Post(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) UpdatePost(ctx context.Context, postID string, in nakama.UpdatePost) (nakama.UpdatedPost, error) {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return nakama.UpdatedPost{}, err
+	}
+
+	return mw.Next.UpdatePost(ctx, postID, in)
+}
+
+func (mw *ServiceWithScopes) DeletePost(ctx context.Context, postID string) error {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.DeletePost(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) TogglePostReaction(ctx context.Context, postID string, in nakama.ReactionInput) ([]nakama.Reaction, error) {
+	if err := authorize(ctx, nakama.ScopeReactionsWrite); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.TogglePostReaction(ctx, postID, in)
+}
+
+func (mw *ServiceWithScopes) TogglePostSubscription(ctx context.Context, postID string) (nakama.ToggleSubscriptionOutput, error) {
+	if err := authorize(ctx, nakama.ScopeNotificationsWrite); err != nil {
+		return nakama.ToggleSubscriptionOutput{}, err
+	}
+
+	return mw.Next.TogglePostSubscription(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) PinPost(ctx context.Context, postID string) error {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.PinPost(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) UnpinPost(ctx context.Context, postID string) error {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.UnpinPost(ctx, postID)
+}
+
+func (mw *ServiceWithScopes) PinnedPosts(ctx context.Context, username string) (nakama.Posts, error) {
+	return mw.Next.PinnedPosts(ctx, username)
+}
+
+func (mw *ServiceWithScopes) CreateTimelineItem(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error) {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return nakama.TimelineItem{}, err
+	}
+
+	return mw.Next.CreateTimelineItem(ctx, content, spoilerOf, nsfw, media)
+}
+
+func (mw *ServiceWithScopes) Timeline(ctx context.Context, last uint64, before *string) (nakama.Timeline, error) {
+	if err := authorize(ctx, nakama.ScopeTimelineRead); err != nil {
+		return nakama.Timeline{}, err
+	}
+
+	return mw.Next.Timeline(ctx, last, before)
+}
+
+func (mw *ServiceWithScopes) TimelineItemStream(ctx context.Context) (<-chan nakama.TimelineItem, error) {
+	if err := authorize(ctx, nakama.ScopeTimelineRead); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.TimelineItemStream(ctx)
+}
+
+func (mw *ServiceWithScopes) DeleteTimelineItem(ctx context.Context, timelineItemID string) error {
+	if err := authorize(ctx, nakama.ScopePostsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.DeleteTimelineItem(ctx, timelineItemID)
+}
+
+func (mw *ServiceWithScopes) Users(ctx context.Context, search string, first uint64, after *string) (nakama.UserProfiles, error) {
+	return mw.Next.Users(ctx, search, first, after)
+}
+
+func (mw *ServiceWithScopes) Usernames(ctx context.Context, startingWith string, first uint64, after *string) (nakama.Usernames, error) {
+	return mw.Next.Usernames(ctx, startingWith, first, after)
+}
+
+func (mw *ServiceWithScopes) User(ctx context.Context, username string) (nakama.UserProfile, error) {
+	return mw.Next.User(ctx, username)
+}
+
+func (mw *ServiceWithScopes) UpdateUser(ctx context.Context, params nakama.UpdateUserParams) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.UpdateUser(ctx, params)
+}
+
+func (mw *ServiceWithScopes) UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return "", err
+	}
+
+	return mw.Next.UpdateAvatar(ctx, r)
+}
+
+func (mw *ServiceWithScopes) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return "", err
+	}
+
+	return mw.Next.UpdateCover(ctx, r)
+}
+
+func (mw *ServiceWithScopes) RequestAccountDeletion(ctx context.Context) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RequestAccountDeletion(ctx)
+}
+
+func (mw *ServiceWithScopes) ConfirmAccountDeletion(ctx context.Context, code string) (time.Time, error) {
+	return mw.Next.ConfirmAccountDeletion(ctx, code)
+}
+
+func (mw *ServiceWithScopes) RequestDataExport(ctx context.Context) (nakama.DataExport, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.DataExport{}, err
+	}
+
+	return mw.Next.RequestDataExport(ctx)
+}
+
+func (mw *ServiceWithScopes) DataExportFile(ctx context.Context, token string) (*storage.File, error) {
+	return mw.Next.DataExportFile(ctx, token)
+}
+
+func (mw *ServiceWithScopes) ToggleFollow(ctx context.Context, username string) (nakama.ToggleFollowOutput, error) {
+	if err := authorize(ctx, nakama.ScopeFollowsWrite); err != nil {
+		return nakama.ToggleFollowOutput{}, err
+	}
+
+	return mw.Next.ToggleFollow(ctx, username)
+}
+
+func (mw *ServiceWithScopes) Followers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	return mw.Next.Followers(ctx, username, first, after)
+}
+
+func (mw *ServiceWithScopes) Followees(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	return mw.Next.Followees(ctx, username, first, after)
+}
+
+func (mw *ServiceWithScopes) MutualFollowers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	return mw.Next.MutualFollowers(ctx, username, first, after)
+}
+
+func (mw *ServiceWithScopes) SuggestedUsers(ctx context.Context, first uint64) (nakama.UserProfiles, error) {
+	if err := authorize(ctx, nakama.ScopeTimelineRead); err != nil {
+		return nakama.UserProfiles{}, err
+	}
+
+	return mw.Next.SuggestedUsers(ctx, first)
+}
+
+func (mw *ServiceWithScopes) ToggleBlock(ctx context.Context, username string) (nakama.ToggleBlockOutput, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.ToggleBlockOutput{}, err
+	}
+
+	return mw.Next.ToggleBlock(ctx, username)
+}
+
+func (mw *ServiceWithScopes) Blocks(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.UserProfiles{}, err
+	}
+
+	return mw.Next.Blocks(ctx, first, after)
+}
+
+func (mw *ServiceWithScopes) FollowRequests(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.UserProfiles{}, err
+	}
+
+	return mw.Next.FollowRequests(ctx, first, after)
+}
+
+func (mw *ServiceWithScopes) ApproveFollowRequest(ctx context.Context, username string) error {
+	if err := authorize(ctx, nakama.ScopeFollowsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.ApproveFollowRequest(ctx, username)
+}
+
+func (mw *ServiceWithScopes) DenyFollowRequest(ctx context.Context, username string) error {
+	if err := authorize(ctx, nakama.ScopeFollowsWrite); err != nil {
+		return err
+	}
+
+	return mw.Next.DenyFollowRequest(ctx, username)
+}
+
+func (mw *ServiceWithScopes) CreateUserList(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.UserList{}, err
+	}
+
+	return mw.Next.CreateUserList(ctx, in)
+}
+
+// UserLists leaves private lists out
+// unless the token has been granted the timeline:read scope.
+func (mw *ServiceWithScopes) UserLists(ctx context.Context, username string) ([]nakama.UserList, error) {
+	ll, err := mw.Next.UserLists(ctx, username)
+	if err != nil || nakama.HasScope(ctx, nakama.ScopeTimelineRead) {
+		return ll, err
+	}
+
+	public := make([]nakama.UserList, 0, len(ll))
+	for _, l := range ll {
+		if !l.Private {
+			public = append(public, l)
+		}
+	}
+
+	return public, nil
+}
+
+func (mw *ServiceWithScopes) UserList(ctx context.Context, listID string) (nakama.UserList, error) {
+	l, err := mw.Next.UserList(ctx, listID)
+	if err != nil {
+		return l, err
+	}
+
+	if l.Private {
+		if err := authorize(ctx, nakama.ScopeTimelineRead); err != nil {
+			return nakama.UserList{}, err
+		}
+	}
+
+	return l, nil
+}
+
+// authorizeUserList requires the timeline:read scope to read from a private list.
+func (mw *ServiceWithScopes) authorizeUserList(ctx context.Context, listID string) error {
+	if nakama.HasScope(ctx, nakama.ScopeTimelineRead) {
+		return nil
+	}
+
+	_, err := mw.UserList(ctx, listID)
+	return err
+}
+
+func (mw *ServiceWithScopes) UpdateUserList(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.UserList{}, err
+	}
+
+	return mw.Next.UpdateUserList(ctx, in)
+}
+
+func (mw *ServiceWithScopes) DeleteUserList(ctx context.Context, listID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.DeleteUserList(ctx, listID)
+}
+
+func (mw *ServiceWithScopes) AddUserListMember(ctx context.Context, listID, username string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.AddUserListMember(ctx, listID, username)
+}
+
+func (mw *ServiceWithScopes) RemoveUserListMember(ctx context.Context, listID, username string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.RemoveUserListMember(ctx, listID, username)
+}
+
+func (mw *ServiceWithScopes) UserListMembers(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error) {
+	if err := mw.authorizeUserList(ctx, listID); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.UserListMembers(ctx, listID, first, after)
+}
+
+func (mw *ServiceWithScopes) ListTimeline(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error) {
+	if err := mw.authorizeUserList(ctx, listID); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.ListTimeline(ctx, listID, last, before)
+}
+
+func (mw *ServiceWithScopes) ListTimelineStream(ctx context.Context, listID string) (<-chan nakama.Post, error) {
+	if err := mw.authorizeUserList(ctx, listID); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.ListTimelineStream(ctx, listID)
+}
+
+func (mw *ServiceWithScopes) CreateMute(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.Mute{}, err
+	}
+
+	return mw.Next.CreateMute(ctx, in)
+}
+
+func (mw *ServiceWithScopes) Mutes(ctx context.Context) ([]nakama.Mute, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nil, err
+	}
+
+	return mw.Next.Mutes(ctx)
+}
+
+func (mw *ServiceWithScopes) UpdateMute(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.Mute{}, err
+	}
+
+	return mw.Next.UpdateMute(ctx, in)
+}
+
+func (mw *ServiceWithScopes) DeleteMute(ctx context.Context, muteID string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.DeleteMute(ctx, muteID)
+}
+
+func (mw *ServiceWithScopes) SuspendUser(ctx context.Context, username string, until *time.Time) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.SuspendUser(ctx, username, until)
+}
+
+func (mw *ServiceWithScopes) UnsuspendUser(ctx context.Context, username string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.UnsuspendUser(ctx, username)
+}
+
+func (mw *ServiceWithScopes) ReportPost(ctx context.Context, postID string, in nakama.ReportInput) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.ReportPost(ctx, postID, in)
+}
+
+func (mw *ServiceWithScopes) ReportComment(ctx context.Context, commentID string, in nakama.ReportInput) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.ReportComment(ctx, commentID, in)
+}
+
+func (mw *ServiceWithScopes) ReportUser(ctx context.Context, username string, in nakama.ReportInput) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.ReportUser(ctx, username, in)
+}
+
+func (mw *ServiceWithScopes) Reports(ctx context.Context, first uint64, after *string) (nakama.Reports, error) {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return nakama.Reports{}, err
+	}
+
+	return mw.Next.Reports(ctx, first, after)
+}
+
+func (mw *ServiceWithScopes) ResolveReport(ctx context.Context, reportID, action string) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.ResolveReport(ctx, reportID, action)
+}
+
+func (mw *ServiceWithScopes) AddWebPushSubscription(ctx context.Context, sub webpush.Subscription) error {
+	if err := authorize(ctx, scopeAccount); err != nil {
+		return err
+	}
+
+	return mw.Next.AddWebPushSubscription(ctx, sub)
+}
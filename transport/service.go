@@ -6,9 +6,11 @@ import (
 	"context"
 	"io"
 	"net/url"
+	"time"
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/storage"
 )
 
 // Service interface.
@@ -16,14 +18,52 @@ type Service interface {
 	SendMagicLink(ctx context.Context, in nakama.SendMagicLink) error
 	ParseRedirectURI(rawurl string) (*url.URL, error)
 	VerifyMagicLink(ctx context.Context, email, code string, username *string) (nakama.AuthOutput, error)
+	VerifyLoginCode(ctx context.Context, email, code string, username *string) (nakama.AuthOutput, error)
 
-	LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.User, error)
+	LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error)
+	Identities(ctx context.Context) ([]nakama.UserIdentity, error)
+	LinkIdentity(ctx context.Context, provider string, user nakama.ProvidedUser) error
+	UnlinkIdentity(ctx context.Context, provider string) error
+
+	VerifySecondFactor(ctx context.Context, challengeID, code string) (nakama.AuthOutput, error)
+	TwoFactorStatus(ctx context.Context) (nakama.TwoFactorStatus, error)
+	BeginTOTPEnrollment(ctx context.Context) (nakama.TOTPEnrollment, error)
+	EnableTOTP(ctx context.Context, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error)
+
+	BeginPasskeyLogin(ctx context.Context) (nakama.PasskeyCeremony, error)
+	FinishPasskeyLogin(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error)
+	BeginPasskeyRegistration(ctx context.Context) (nakama.PasskeyCeremony, error)
+	FinishPasskeyRegistration(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error)
+	Passkeys(ctx context.Context) ([]nakama.Passkey, error)
+	RenamePasskey(ctx context.Context, passkeyID, name string) error
+	DeletePasskey(ctx context.Context, passkeyID string) error
 
 	DevLogin(ctx context.Context, email string) (nakama.AuthOutput, error)
 
-	AuthUserIDFromToken(token string) (string, error)
+	AuthFromToken(ctx context.Context, token string) (nakama.Auth, error)
 	AuthUser(ctx context.Context) (nakama.User, error)
-	Token(ctx context.Context) (nakama.TokenOutput, error)
+	RefreshToken(ctx context.Context, refreshToken string) (nakama.TokenOutput, error)
+
+	Sessions(ctx context.Context) ([]nakama.Session, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	Logout(ctx context.Context) error
+	LogoutEverywhere(ctx context.Context) error
+
+	CreatePersonalAccessToken(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error)
+	PersonalAccessTokens(ctx context.Context) ([]nakama.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, tokenID string) error
+
+	CreateOAuthApp(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error)
+	OAuthApps(ctx context.Context) ([]nakama.OAuthApp, error)
+	DeleteOAuthApp(ctx context.Context, appID string) error
+	OAuthAuthorization(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error)
+	AuthorizeOAuthApp(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error)
+	ExchangeOAuthCode(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error)
+	IntrospectOAuthToken(ctx context.Context, clientID, clientSecret, token string) (nakama.OAuthTokenIntrospection, error)
+	AuthorizedOAuthApps(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error)
+	RevokeOAuthApp(ctx context.Context, appID string) error
 
 	CreateComment(ctx context.Context, postID, content string) (nakama.Comment, error)
 	Comments(ctx context.Context, postID string, last uint64, before *string) (nakama.Comments, error)
@@ -45,6 +85,9 @@ type Service interface {
 	DeletePost(ctx context.Context, postID string) error
 	TogglePostReaction(ctx context.Context, postID string, in nakama.ReactionInput) ([]nakama.Reaction, error)
 	TogglePostSubscription(ctx context.Context, postID string) (nakama.ToggleSubscriptionOutput, error)
+	PinPost(ctx context.Context, postID string) error
+	UnpinPost(ctx context.Context, postID string) error
+	PinnedPosts(ctx context.Context, username string) (nakama.Posts, error)
 
 	CreateTimelineItem(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error)
 	Timeline(ctx context.Context, last uint64, before *string) (nakama.Timeline, error)
@@ -57,9 +100,42 @@ type Service interface {
 	UpdateUser(ctx context.Context, params nakama.UpdateUserParams) error
 	UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, error)
 	UpdateCover(ctx context.Context, r io.ReadSeeker) (string, error)
+	RequestAccountDeletion(ctx context.Context) error
+	ConfirmAccountDeletion(ctx context.Context, code string) (time.Time, error)
+	RequestDataExport(ctx context.Context) (nakama.DataExport, error)
+	DataExportFile(ctx context.Context, token string) (*storage.File, error)
 	ToggleFollow(ctx context.Context, username string) (nakama.ToggleFollowOutput, error)
 	Followers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error)
 	Followees(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error)
+	MutualFollowers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error)
+	SuggestedUsers(ctx context.Context, first uint64) (nakama.UserProfiles, error)
+	ToggleBlock(ctx context.Context, username string) (nakama.ToggleBlockOutput, error)
+	Blocks(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error)
+	FollowRequests(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error)
+	ApproveFollowRequest(ctx context.Context, username string) error
+	DenyFollowRequest(ctx context.Context, username string) error
+	CreateUserList(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error)
+	UserLists(ctx context.Context, username string) ([]nakama.UserList, error)
+	UserList(ctx context.Context, listID string) (nakama.UserList, error)
+	UpdateUserList(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error)
+	DeleteUserList(ctx context.Context, listID string) error
+	AddUserListMember(ctx context.Context, listID, username string) error
+	RemoveUserListMember(ctx context.Context, listID, username string) error
+	UserListMembers(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error)
+	ListTimeline(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error)
+	ListTimelineStream(ctx context.Context, listID string) (<-chan nakama.Post, error)
+	CreateMute(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error)
+	Mutes(ctx context.Context) ([]nakama.Mute, error)
+	UpdateMute(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error)
+	DeleteMute(ctx context.Context, muteID string) error
+	SuspendUser(ctx context.Context, username string, until *time.Time) error
+	UnsuspendUser(ctx context.Context, username string) error
+
+	ReportPost(ctx context.Context, postID string, in nakama.ReportInput) error
+	ReportComment(ctx context.Context, commentID string, in nakama.ReportInput) error
+	ReportUser(ctx context.Context, username string, in nakama.ReportInput) error
+	Reports(ctx context.Context, first uint64, after *string) (nakama.Reports, error)
+	ResolveReport(ctx context.Context, reportID, action string) error
 
 	AddWebPushSubscription(ctx context.Context, sub webpush.Subscription) error
 }
@@ -7,9 +7,11 @@ import (
 	"context"
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/nakamauwu/nakama"
+	"github.com/nakamauwu/nakama/storage"
 	"io"
 	"net/url"
 	"sync"
+	"time"
 )
 
 // Ensure, that ServiceMock does implement Service.
@@ -22,14 +24,38 @@ var _ Service = &ServiceMock{}
 //
 //		// make and configure a mocked Service
 //		mockedService := &ServiceMock{
+//			AddUserListMemberFunc: func(ctx context.Context, listID string, username string) error {
+//				panic("mock out the AddUserListMember method")
+//			},
 //			AddWebPushSubscriptionFunc: func(ctx context.Context, sub webpush.Subscription) error {
 //				panic("mock out the AddWebPushSubscription method")
 //			},
+//			ApproveFollowRequestFunc: func(ctx context.Context, username string) error {
+//				panic("mock out the ApproveFollowRequest method")
+//			},
+//			AuthFromTokenFunc: func(ctx context.Context, token string) (nakama.Auth, error) {
+//				panic("mock out the AuthFromToken method")
+//			},
 //			AuthUserFunc: func(ctx context.Context) (nakama.User, error) {
 //				panic("mock out the AuthUser method")
 //			},
-//			AuthUserIDFromTokenFunc: func(token string) (string, error) {
-//				panic("mock out the AuthUserIDFromToken method")
+//			AuthorizeOAuthAppFunc: func(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error) {
+//				panic("mock out the AuthorizeOAuthApp method")
+//			},
+//			AuthorizedOAuthAppsFunc: func(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error) {
+//				panic("mock out the AuthorizedOAuthApps method")
+//			},
+//			BeginPasskeyLoginFunc: func(ctx context.Context) (nakama.PasskeyCeremony, error) {
+//				panic("mock out the BeginPasskeyLogin method")
+//			},
+//			BeginPasskeyRegistrationFunc: func(ctx context.Context) (nakama.PasskeyCeremony, error) {
+//				panic("mock out the BeginPasskeyRegistration method")
+//			},
+//			BeginTOTPEnrollmentFunc: func(ctx context.Context) (nakama.TOTPEnrollment, error) {
+//				panic("mock out the BeginTOTPEnrollment method")
+//			},
+//			BlocksFunc: func(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+//				panic("mock out the Blocks method")
 //			},
 //			CommentStreamFunc: func(ctx context.Context, postID string) (<-chan nakama.Comment, error) {
 //				panic("mock out the CommentStream method")
@@ -37,24 +63,75 @@ var _ Service = &ServiceMock{}
 //			CommentsFunc: func(ctx context.Context, postID string, last uint64, before *string) (nakama.Comments, error) {
 //				panic("mock out the Comments method")
 //			},
+//			ConfirmAccountDeletionFunc: func(ctx context.Context, code string) (time.Time, error) {
+//				panic("mock out the ConfirmAccountDeletion method")
+//			},
 //			CreateCommentFunc: func(ctx context.Context, postID string, content string) (nakama.Comment, error) {
 //				panic("mock out the CreateComment method")
 //			},
+//			CreateMuteFunc: func(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error) {
+//				panic("mock out the CreateMute method")
+//			},
+//			CreateOAuthAppFunc: func(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error) {
+//				panic("mock out the CreateOAuthApp method")
+//			},
+//			CreatePersonalAccessTokenFunc: func(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error) {
+//				panic("mock out the CreatePersonalAccessToken method")
+//			},
 //			CreateTimelineItemFunc: func(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error) {
 //				panic("mock out the CreateTimelineItem method")
 //			},
+//			CreateUserListFunc: func(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error) {
+//				panic("mock out the CreateUserList method")
+//			},
+//			DataExportFileFunc: func(ctx context.Context, token string) (*storage.File, error) {
+//				panic("mock out the DataExportFile method")
+//			},
 //			DeleteCommentFunc: func(ctx context.Context, commentID string) error {
 //				panic("mock out the DeleteComment method")
 //			},
+//			DeleteMuteFunc: func(ctx context.Context, muteID string) error {
+//				panic("mock out the DeleteMute method")
+//			},
+//			DeleteOAuthAppFunc: func(ctx context.Context, appID string) error {
+//				panic("mock out the DeleteOAuthApp method")
+//			},
+//			DeletePasskeyFunc: func(ctx context.Context, passkeyID string) error {
+//				panic("mock out the DeletePasskey method")
+//			},
 //			DeletePostFunc: func(ctx context.Context, postID string) error {
 //				panic("mock out the DeletePost method")
 //			},
 //			DeleteTimelineItemFunc: func(ctx context.Context, timelineItemID string) error {
 //				panic("mock out the DeleteTimelineItem method")
 //			},
+//			DeleteUserListFunc: func(ctx context.Context, listID string) error {
+//				panic("mock out the DeleteUserList method")
+//			},
+//			DenyFollowRequestFunc: func(ctx context.Context, username string) error {
+//				panic("mock out the DenyFollowRequest method")
+//			},
 //			DevLoginFunc: func(ctx context.Context, email string) (nakama.AuthOutput, error) {
 //				panic("mock out the DevLogin method")
 //			},
+//			DisableTOTPFunc: func(ctx context.Context, code string) error {
+//				panic("mock out the DisableTOTP method")
+//			},
+//			EnableTOTPFunc: func(ctx context.Context, code string) ([]string, error) {
+//				panic("mock out the EnableTOTP method")
+//			},
+//			ExchangeOAuthCodeFunc: func(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+//				panic("mock out the ExchangeOAuthCode method")
+//			},
+//			FinishPasskeyLoginFunc: func(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error) {
+//				panic("mock out the FinishPasskeyLogin method")
+//			},
+//			FinishPasskeyRegistrationFunc: func(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error) {
+//				panic("mock out the FinishPasskeyRegistration method")
+//			},
+//			FollowRequestsFunc: func(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+//				panic("mock out the FollowRequests method")
+//			},
 //			FolloweesFunc: func(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
 //				panic("mock out the Followees method")
 //			},
@@ -64,24 +141,69 @@ var _ Service = &ServiceMock{}
 //			HasUnreadNotificationsFunc: func(ctx context.Context) (bool, error) {
 //				panic("mock out the HasUnreadNotifications method")
 //			},
-//			LoginFromProviderFunc: func(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.User, error) {
+//			IdentitiesFunc: func(ctx context.Context) ([]nakama.UserIdentity, error) {
+//				panic("mock out the Identities method")
+//			},
+//			IntrospectOAuthTokenFunc: func(ctx context.Context, clientID string, clientSecret string, token string) (nakama.OAuthTokenIntrospection, error) {
+//				panic("mock out the IntrospectOAuthToken method")
+//			},
+//			LinkIdentityFunc: func(ctx context.Context, provider string, user nakama.ProvidedUser) error {
+//				panic("mock out the LinkIdentity method")
+//			},
+//			ListTimelineFunc: func(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error) {
+//				panic("mock out the ListTimeline method")
+//			},
+//			ListTimelineStreamFunc: func(ctx context.Context, listID string) (<-chan nakama.Post, error) {
+//				panic("mock out the ListTimelineStream method")
+//			},
+//			LoginFromProviderFunc: func(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error) {
 //				panic("mock out the LoginFromProvider method")
 //			},
+//			LogoutFunc: func(ctx context.Context) error {
+//				panic("mock out the Logout method")
+//			},
+//			LogoutEverywhereFunc: func(ctx context.Context) error {
+//				panic("mock out the LogoutEverywhere method")
+//			},
 //			MarkNotificationAsReadFunc: func(ctx context.Context, notificationID string) error {
 //				panic("mock out the MarkNotificationAsRead method")
 //			},
 //			MarkNotificationsAsReadFunc: func(ctx context.Context) error {
 //				panic("mock out the MarkNotificationsAsRead method")
 //			},
+//			MutesFunc: func(ctx context.Context) ([]nakama.Mute, error) {
+//				panic("mock out the Mutes method")
+//			},
+//			MutualFollowersFunc: func(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+//				panic("mock out the MutualFollowers method")
+//			},
 //			NotificationStreamFunc: func(ctx context.Context) (<-chan nakama.Notification, error) {
 //				panic("mock out the NotificationStream method")
 //			},
 //			NotificationsFunc: func(ctx context.Context, last uint64, before *string) (nakama.Notifications, error) {
 //				panic("mock out the Notifications method")
 //			},
+//			OAuthAppsFunc: func(ctx context.Context) ([]nakama.OAuthApp, error) {
+//				panic("mock out the OAuthApps method")
+//			},
+//			OAuthAuthorizationFunc: func(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error) {
+//				panic("mock out the OAuthAuthorization method")
+//			},
 //			ParseRedirectURIFunc: func(rawurl string) (*url.URL, error) {
 //				panic("mock out the ParseRedirectURI method")
 //			},
+//			PasskeysFunc: func(ctx context.Context) ([]nakama.Passkey, error) {
+//				panic("mock out the Passkeys method")
+//			},
+//			PersonalAccessTokensFunc: func(ctx context.Context) ([]nakama.PersonalAccessToken, error) {
+//				panic("mock out the PersonalAccessTokens method")
+//			},
+//			PinPostFunc: func(ctx context.Context, postID string) error {
+//				panic("mock out the PinPost method")
+//			},
+//			PinnedPostsFunc: func(ctx context.Context, username string) (nakama.Posts, error) {
+//				panic("mock out the PinnedPosts method")
+//			},
 //			PostFunc: func(ctx context.Context, postID string) (nakama.Post, error) {
 //				panic("mock out the Post method")
 //			},
@@ -91,15 +213,69 @@ var _ Service = &ServiceMock{}
 //			PostsFunc: func(ctx context.Context, last uint64, before *string, opts ...nakama.PostsOpt) (nakama.Posts, error) {
 //				panic("mock out the Posts method")
 //			},
+//			RefreshTokenFunc: func(ctx context.Context, refreshToken string) (nakama.TokenOutput, error) {
+//				panic("mock out the RefreshToken method")
+//			},
+//			RegenerateRecoveryCodesFunc: func(ctx context.Context, code string) ([]string, error) {
+//				panic("mock out the RegenerateRecoveryCodes method")
+//			},
+//			RemoveUserListMemberFunc: func(ctx context.Context, listID string, username string) error {
+//				panic("mock out the RemoveUserListMember method")
+//			},
+//			RenamePasskeyFunc: func(ctx context.Context, passkeyID string, name string) error {
+//				panic("mock out the RenamePasskey method")
+//			},
+//			ReportCommentFunc: func(ctx context.Context, commentID string, in nakama.ReportInput) error {
+//				panic("mock out the ReportComment method")
+//			},
+//			ReportPostFunc: func(ctx context.Context, postID string, in nakama.ReportInput) error {
+//				panic("mock out the ReportPost method")
+//			},
+//			ReportUserFunc: func(ctx context.Context, username string, in nakama.ReportInput) error {
+//				panic("mock out the ReportUser method")
+//			},
+//			ReportsFunc: func(ctx context.Context, first uint64, after *string) (nakama.Reports, error) {
+//				panic("mock out the Reports method")
+//			},
+//			RequestAccountDeletionFunc: func(ctx context.Context) error {
+//				panic("mock out the RequestAccountDeletion method")
+//			},
+//			RequestDataExportFunc: func(ctx context.Context) (nakama.DataExport, error) {
+//				panic("mock out the RequestDataExport method")
+//			},
+//			ResolveReportFunc: func(ctx context.Context, reportID string, action string) error {
+//				panic("mock out the ResolveReport method")
+//			},
+//			RevokeOAuthAppFunc: func(ctx context.Context, appID string) error {
+//				panic("mock out the RevokeOAuthApp method")
+//			},
+//			RevokePersonalAccessTokenFunc: func(ctx context.Context, tokenID string) error {
+//				panic("mock out the RevokePersonalAccessToken method")
+//			},
+//			RevokeSessionFunc: func(ctx context.Context, sessionID string) error {
+//				panic("mock out the RevokeSession method")
+//			},
 //			SendMagicLinkFunc: func(ctx context.Context, in nakama.SendMagicLink) error {
 //				panic("mock out the SendMagicLink method")
 //			},
+//			SessionsFunc: func(ctx context.Context) ([]nakama.Session, error) {
+//				panic("mock out the Sessions method")
+//			},
+//			SuggestedUsersFunc: func(ctx context.Context, first uint64) (nakama.UserProfiles, error) {
+//				panic("mock out the SuggestedUsers method")
+//			},
+//			SuspendUserFunc: func(ctx context.Context, username string, until *time.Time) error {
+//				panic("mock out the SuspendUser method")
+//			},
 //			TimelineFunc: func(ctx context.Context, last uint64, before *string) (nakama.Timeline, error) {
 //				panic("mock out the Timeline method")
 //			},
 //			TimelineItemStreamFunc: func(ctx context.Context) (<-chan nakama.TimelineItem, error) {
 //				panic("mock out the TimelineItemStream method")
 //			},
+//			ToggleBlockFunc: func(ctx context.Context, username string) (nakama.ToggleBlockOutput, error) {
+//				panic("mock out the ToggleBlock method")
+//			},
 //			ToggleCommentReactionFunc: func(ctx context.Context, commentID string, in nakama.ReactionInput) ([]nakama.Reaction, error) {
 //				panic("mock out the ToggleCommentReaction method")
 //			},
@@ -112,8 +288,17 @@ var _ Service = &ServiceMock{}
 //			TogglePostSubscriptionFunc: func(ctx context.Context, postID string) (nakama.ToggleSubscriptionOutput, error) {
 //				panic("mock out the TogglePostSubscription method")
 //			},
-//			TokenFunc: func(ctx context.Context) (nakama.TokenOutput, error) {
-//				panic("mock out the Token method")
+//			TwoFactorStatusFunc: func(ctx context.Context) (nakama.TwoFactorStatus, error) {
+//				panic("mock out the TwoFactorStatus method")
+//			},
+//			UnlinkIdentityFunc: func(ctx context.Context, provider string) error {
+//				panic("mock out the UnlinkIdentity method")
+//			},
+//			UnpinPostFunc: func(ctx context.Context, postID string) error {
+//				panic("mock out the UnpinPost method")
+//			},
+//			UnsuspendUserFunc: func(ctx context.Context, username string) error {
+//				panic("mock out the UnsuspendUser method")
 //			},
 //			UpdateAvatarFunc: func(ctx context.Context, r io.ReadSeeker) (string, error) {
 //				panic("mock out the UpdateAvatar method")
@@ -124,24 +309,45 @@ var _ Service = &ServiceMock{}
 //			UpdateCoverFunc: func(ctx context.Context, r io.ReadSeeker) (string, error) {
 //				panic("mock out the UpdateCover method")
 //			},
+//			UpdateMuteFunc: func(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error) {
+//				panic("mock out the UpdateMute method")
+//			},
 //			UpdatePostFunc: func(ctx context.Context, postID string, in nakama.UpdatePost) (nakama.UpdatedPost, error) {
 //				panic("mock out the UpdatePost method")
 //			},
 //			UpdateUserFunc: func(ctx context.Context, params nakama.UpdateUserParams) error {
 //				panic("mock out the UpdateUser method")
 //			},
+//			UpdateUserListFunc: func(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error) {
+//				panic("mock out the UpdateUserList method")
+//			},
 //			UserFunc: func(ctx context.Context, username string) (nakama.UserProfile, error) {
 //				panic("mock out the User method")
 //			},
+//			UserListFunc: func(ctx context.Context, listID string) (nakama.UserList, error) {
+//				panic("mock out the UserList method")
+//			},
+//			UserListMembersFunc: func(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error) {
+//				panic("mock out the UserListMembers method")
+//			},
+//			UserListsFunc: func(ctx context.Context, username string) ([]nakama.UserList, error) {
+//				panic("mock out the UserLists method")
+//			},
 //			UsernamesFunc: func(ctx context.Context, startingWith string, first uint64, after *string) (nakama.Usernames, error) {
 //				panic("mock out the Usernames method")
 //			},
 //			UsersFunc: func(ctx context.Context, search string, first uint64, after *string) (nakama.UserProfiles, error) {
 //				panic("mock out the Users method")
 //			},
+//			VerifyLoginCodeFunc: func(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error) {
+//				panic("mock out the VerifyLoginCode method")
+//			},
 //			VerifyMagicLinkFunc: func(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error) {
 //				panic("mock out the VerifyMagicLink method")
 //			},
+//			VerifySecondFactorFunc: func(ctx context.Context, challengeID string, code string) (nakama.AuthOutput, error) {
+//				panic("mock out the VerifySecondFactor method")
+//			},
 //		}
 //
 //		// use mockedService in code that requires Service
@@ -149,14 +355,38 @@ var _ Service = &ServiceMock{}
 //
 //	}
 type ServiceMock struct {
+	// AddUserListMemberFunc mocks the AddUserListMember method.
+	AddUserListMemberFunc func(ctx context.Context, listID string, username string) error
+
 	// AddWebPushSubscriptionFunc mocks the AddWebPushSubscription method.
 	AddWebPushSubscriptionFunc func(ctx context.Context, sub webpush.Subscription) error
 
+	// ApproveFollowRequestFunc mocks the ApproveFollowRequest method.
+	ApproveFollowRequestFunc func(ctx context.Context, username string) error
+
+	// AuthFromTokenFunc mocks the AuthFromToken method.
+	AuthFromTokenFunc func(ctx context.Context, token string) (nakama.Auth, error)
+
 	// AuthUserFunc mocks the AuthUser method.
 	AuthUserFunc func(ctx context.Context) (nakama.User, error)
 
-	// AuthUserIDFromTokenFunc mocks the AuthUserIDFromToken method.
-	AuthUserIDFromTokenFunc func(token string) (string, error)
+	// AuthorizeOAuthAppFunc mocks the AuthorizeOAuthApp method.
+	AuthorizeOAuthAppFunc func(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error)
+
+	// AuthorizedOAuthAppsFunc mocks the AuthorizedOAuthApps method.
+	AuthorizedOAuthAppsFunc func(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error)
+
+	// BeginPasskeyLoginFunc mocks the BeginPasskeyLogin method.
+	BeginPasskeyLoginFunc func(ctx context.Context) (nakama.PasskeyCeremony, error)
+
+	// BeginPasskeyRegistrationFunc mocks the BeginPasskeyRegistration method.
+	BeginPasskeyRegistrationFunc func(ctx context.Context) (nakama.PasskeyCeremony, error)
+
+	// BeginTOTPEnrollmentFunc mocks the BeginTOTPEnrollment method.
+	BeginTOTPEnrollmentFunc func(ctx context.Context) (nakama.TOTPEnrollment, error)
+
+	// BlocksFunc mocks the Blocks method.
+	BlocksFunc func(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error)
 
 	// CommentStreamFunc mocks the CommentStream method.
 	CommentStreamFunc func(ctx context.Context, postID string) (<-chan nakama.Comment, error)
@@ -164,24 +394,75 @@ type ServiceMock struct {
 	// CommentsFunc mocks the Comments method.
 	CommentsFunc func(ctx context.Context, postID string, last uint64, before *string) (nakama.Comments, error)
 
+	// ConfirmAccountDeletionFunc mocks the ConfirmAccountDeletion method.
+	ConfirmAccountDeletionFunc func(ctx context.Context, code string) (time.Time, error)
+
 	// CreateCommentFunc mocks the CreateComment method.
 	CreateCommentFunc func(ctx context.Context, postID string, content string) (nakama.Comment, error)
 
+	// CreateMuteFunc mocks the CreateMute method.
+	CreateMuteFunc func(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error)
+
+	// CreateOAuthAppFunc mocks the CreateOAuthApp method.
+	CreateOAuthAppFunc func(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error)
+
+	// CreatePersonalAccessTokenFunc mocks the CreatePersonalAccessToken method.
+	CreatePersonalAccessTokenFunc func(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error)
+
 	// CreateTimelineItemFunc mocks the CreateTimelineItem method.
 	CreateTimelineItemFunc func(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error)
 
+	// CreateUserListFunc mocks the CreateUserList method.
+	CreateUserListFunc func(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error)
+
+	// DataExportFileFunc mocks the DataExportFile method.
+	DataExportFileFunc func(ctx context.Context, token string) (*storage.File, error)
+
 	// DeleteCommentFunc mocks the DeleteComment method.
 	DeleteCommentFunc func(ctx context.Context, commentID string) error
 
+	// DeleteMuteFunc mocks the DeleteMute method.
+	DeleteMuteFunc func(ctx context.Context, muteID string) error
+
+	// DeleteOAuthAppFunc mocks the DeleteOAuthApp method.
+	DeleteOAuthAppFunc func(ctx context.Context, appID string) error
+
+	// DeletePasskeyFunc mocks the DeletePasskey method.
+	DeletePasskeyFunc func(ctx context.Context, passkeyID string) error
+
 	// DeletePostFunc mocks the DeletePost method.
 	DeletePostFunc func(ctx context.Context, postID string) error
 
 	// DeleteTimelineItemFunc mocks the DeleteTimelineItem method.
 	DeleteTimelineItemFunc func(ctx context.Context, timelineItemID string) error
 
+	// DeleteUserListFunc mocks the DeleteUserList method.
+	DeleteUserListFunc func(ctx context.Context, listID string) error
+
+	// DenyFollowRequestFunc mocks the DenyFollowRequest method.
+	DenyFollowRequestFunc func(ctx context.Context, username string) error
+
 	// DevLoginFunc mocks the DevLogin method.
 	DevLoginFunc func(ctx context.Context, email string) (nakama.AuthOutput, error)
 
+	// DisableTOTPFunc mocks the DisableTOTP method.
+	DisableTOTPFunc func(ctx context.Context, code string) error
+
+	// EnableTOTPFunc mocks the EnableTOTP method.
+	EnableTOTPFunc func(ctx context.Context, code string) ([]string, error)
+
+	// ExchangeOAuthCodeFunc mocks the ExchangeOAuthCode method.
+	ExchangeOAuthCodeFunc func(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error)
+
+	// FinishPasskeyLoginFunc mocks the FinishPasskeyLogin method.
+	FinishPasskeyLoginFunc func(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error)
+
+	// FinishPasskeyRegistrationFunc mocks the FinishPasskeyRegistration method.
+	FinishPasskeyRegistrationFunc func(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error)
+
+	// FollowRequestsFunc mocks the FollowRequests method.
+	FollowRequestsFunc func(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error)
+
 	// FolloweesFunc mocks the Followees method.
 	FolloweesFunc func(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error)
 
@@ -191,8 +472,29 @@ type ServiceMock struct {
 	// HasUnreadNotificationsFunc mocks the HasUnreadNotifications method.
 	HasUnreadNotificationsFunc func(ctx context.Context) (bool, error)
 
+	// IdentitiesFunc mocks the Identities method.
+	IdentitiesFunc func(ctx context.Context) ([]nakama.UserIdentity, error)
+
+	// IntrospectOAuthTokenFunc mocks the IntrospectOAuthToken method.
+	IntrospectOAuthTokenFunc func(ctx context.Context, clientID string, clientSecret string, token string) (nakama.OAuthTokenIntrospection, error)
+
+	// LinkIdentityFunc mocks the LinkIdentity method.
+	LinkIdentityFunc func(ctx context.Context, provider string, user nakama.ProvidedUser) error
+
+	// ListTimelineFunc mocks the ListTimeline method.
+	ListTimelineFunc func(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error)
+
+	// ListTimelineStreamFunc mocks the ListTimelineStream method.
+	ListTimelineStreamFunc func(ctx context.Context, listID string) (<-chan nakama.Post, error)
+
 	// LoginFromProviderFunc mocks the LoginFromProvider method.
-	LoginFromProviderFunc func(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.User, error)
+	LoginFromProviderFunc func(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error)
+
+	// LogoutFunc mocks the Logout method.
+	LogoutFunc func(ctx context.Context) error
+
+	// LogoutEverywhereFunc mocks the LogoutEverywhere method.
+	LogoutEverywhereFunc func(ctx context.Context) error
 
 	// MarkNotificationAsReadFunc mocks the MarkNotificationAsRead method.
 	MarkNotificationAsReadFunc func(ctx context.Context, notificationID string) error
@@ -200,15 +502,39 @@ type ServiceMock struct {
 	// MarkNotificationsAsReadFunc mocks the MarkNotificationsAsRead method.
 	MarkNotificationsAsReadFunc func(ctx context.Context) error
 
+	// MutesFunc mocks the Mutes method.
+	MutesFunc func(ctx context.Context) ([]nakama.Mute, error)
+
+	// MutualFollowersFunc mocks the MutualFollowers method.
+	MutualFollowersFunc func(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error)
+
 	// NotificationStreamFunc mocks the NotificationStream method.
 	NotificationStreamFunc func(ctx context.Context) (<-chan nakama.Notification, error)
 
 	// NotificationsFunc mocks the Notifications method.
 	NotificationsFunc func(ctx context.Context, last uint64, before *string) (nakama.Notifications, error)
 
+	// OAuthAppsFunc mocks the OAuthApps method.
+	OAuthAppsFunc func(ctx context.Context) ([]nakama.OAuthApp, error)
+
+	// OAuthAuthorizationFunc mocks the OAuthAuthorization method.
+	OAuthAuthorizationFunc func(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error)
+
 	// ParseRedirectURIFunc mocks the ParseRedirectURI method.
 	ParseRedirectURIFunc func(rawurl string) (*url.URL, error)
 
+	// PasskeysFunc mocks the Passkeys method.
+	PasskeysFunc func(ctx context.Context) ([]nakama.Passkey, error)
+
+	// PersonalAccessTokensFunc mocks the PersonalAccessTokens method.
+	PersonalAccessTokensFunc func(ctx context.Context) ([]nakama.PersonalAccessToken, error)
+
+	// PinPostFunc mocks the PinPost method.
+	PinPostFunc func(ctx context.Context, postID string) error
+
+	// PinnedPostsFunc mocks the PinnedPosts method.
+	PinnedPostsFunc func(ctx context.Context, username string) (nakama.Posts, error)
+
 	// PostFunc mocks the Post method.
 	PostFunc func(ctx context.Context, postID string) (nakama.Post, error)
 
@@ -218,15 +544,69 @@ type ServiceMock struct {
 	// PostsFunc mocks the Posts method.
 	PostsFunc func(ctx context.Context, last uint64, before *string, opts ...nakama.PostsOpt) (nakama.Posts, error)
 
+	// RefreshTokenFunc mocks the RefreshToken method.
+	RefreshTokenFunc func(ctx context.Context, refreshToken string) (nakama.TokenOutput, error)
+
+	// RegenerateRecoveryCodesFunc mocks the RegenerateRecoveryCodes method.
+	RegenerateRecoveryCodesFunc func(ctx context.Context, code string) ([]string, error)
+
+	// RemoveUserListMemberFunc mocks the RemoveUserListMember method.
+	RemoveUserListMemberFunc func(ctx context.Context, listID string, username string) error
+
+	// RenamePasskeyFunc mocks the RenamePasskey method.
+	RenamePasskeyFunc func(ctx context.Context, passkeyID string, name string) error
+
+	// ReportCommentFunc mocks the ReportComment method.
+	ReportCommentFunc func(ctx context.Context, commentID string, in nakama.ReportInput) error
+
+	// ReportPostFunc mocks the ReportPost method.
+	ReportPostFunc func(ctx context.Context, postID string, in nakama.ReportInput) error
+
+	// ReportUserFunc mocks the ReportUser method.
+	ReportUserFunc func(ctx context.Context, username string, in nakama.ReportInput) error
+
+	// ReportsFunc mocks the Reports method.
+	ReportsFunc func(ctx context.Context, first uint64, after *string) (nakama.Reports, error)
+
+	// RequestAccountDeletionFunc mocks the RequestAccountDeletion method.
+	RequestAccountDeletionFunc func(ctx context.Context) error
+
+	// RequestDataExportFunc mocks the RequestDataExport method.
+	RequestDataExportFunc func(ctx context.Context) (nakama.DataExport, error)
+
+	// ResolveReportFunc mocks the ResolveReport method.
+	ResolveReportFunc func(ctx context.Context, reportID string, action string) error
+
+	// RevokeOAuthAppFunc mocks the RevokeOAuthApp method.
+	RevokeOAuthAppFunc func(ctx context.Context, appID string) error
+
+	// RevokePersonalAccessTokenFunc mocks the RevokePersonalAccessToken method.
+	RevokePersonalAccessTokenFunc func(ctx context.Context, tokenID string) error
+
+	// RevokeSessionFunc mocks the RevokeSession method.
+	RevokeSessionFunc func(ctx context.Context, sessionID string) error
+
 	// SendMagicLinkFunc mocks the SendMagicLink method.
 	SendMagicLinkFunc func(ctx context.Context, in nakama.SendMagicLink) error
 
+	// SessionsFunc mocks the Sessions method.
+	SessionsFunc func(ctx context.Context) ([]nakama.Session, error)
+
+	// SuggestedUsersFunc mocks the SuggestedUsers method.
+	SuggestedUsersFunc func(ctx context.Context, first uint64) (nakama.UserProfiles, error)
+
+	// SuspendUserFunc mocks the SuspendUser method.
+	SuspendUserFunc func(ctx context.Context, username string, until *time.Time) error
+
 	// TimelineFunc mocks the Timeline method.
 	TimelineFunc func(ctx context.Context, last uint64, before *string) (nakama.Timeline, error)
 
 	// TimelineItemStreamFunc mocks the TimelineItemStream method.
 	TimelineItemStreamFunc func(ctx context.Context) (<-chan nakama.TimelineItem, error)
 
+	// ToggleBlockFunc mocks the ToggleBlock method.
+	ToggleBlockFunc func(ctx context.Context, username string) (nakama.ToggleBlockOutput, error)
+
 	// ToggleCommentReactionFunc mocks the ToggleCommentReaction method.
 	ToggleCommentReactionFunc func(ctx context.Context, commentID string, in nakama.ReactionInput) ([]nakama.Reaction, error)
 
@@ -239,8 +619,17 @@ type ServiceMock struct {
 	// TogglePostSubscriptionFunc mocks the TogglePostSubscription method.
 	TogglePostSubscriptionFunc func(ctx context.Context, postID string) (nakama.ToggleSubscriptionOutput, error)
 
-	// TokenFunc mocks the Token method.
-	TokenFunc func(ctx context.Context) (nakama.TokenOutput, error)
+	// TwoFactorStatusFunc mocks the TwoFactorStatus method.
+	TwoFactorStatusFunc func(ctx context.Context) (nakama.TwoFactorStatus, error)
+
+	// UnlinkIdentityFunc mocks the UnlinkIdentity method.
+	UnlinkIdentityFunc func(ctx context.Context, provider string) error
+
+	// UnpinPostFunc mocks the UnpinPost method.
+	UnpinPostFunc func(ctx context.Context, postID string) error
+
+	// UnsuspendUserFunc mocks the UnsuspendUser method.
+	UnsuspendUserFunc func(ctx context.Context, username string) error
 
 	// UpdateAvatarFunc mocks the UpdateAvatar method.
 	UpdateAvatarFunc func(ctx context.Context, r io.ReadSeeker) (string, error)
@@ -251,26 +640,56 @@ type ServiceMock struct {
 	// UpdateCoverFunc mocks the UpdateCover method.
 	UpdateCoverFunc func(ctx context.Context, r io.ReadSeeker) (string, error)
 
+	// UpdateMuteFunc mocks the UpdateMute method.
+	UpdateMuteFunc func(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error)
+
 	// UpdatePostFunc mocks the UpdatePost method.
 	UpdatePostFunc func(ctx context.Context, postID string, in nakama.UpdatePost) (nakama.UpdatedPost, error)
 
 	// UpdateUserFunc mocks the UpdateUser method.
 	UpdateUserFunc func(ctx context.Context, params nakama.UpdateUserParams) error
 
+	// UpdateUserListFunc mocks the UpdateUserList method.
+	UpdateUserListFunc func(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error)
+
 	// UserFunc mocks the User method.
 	UserFunc func(ctx context.Context, username string) (nakama.UserProfile, error)
 
+	// UserListFunc mocks the UserList method.
+	UserListFunc func(ctx context.Context, listID string) (nakama.UserList, error)
+
+	// UserListMembersFunc mocks the UserListMembers method.
+	UserListMembersFunc func(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error)
+
+	// UserListsFunc mocks the UserLists method.
+	UserListsFunc func(ctx context.Context, username string) ([]nakama.UserList, error)
+
 	// UsernamesFunc mocks the Usernames method.
 	UsernamesFunc func(ctx context.Context, startingWith string, first uint64, after *string) (nakama.Usernames, error)
 
 	// UsersFunc mocks the Users method.
 	UsersFunc func(ctx context.Context, search string, first uint64, after *string) (nakama.UserProfiles, error)
 
+	// VerifyLoginCodeFunc mocks the VerifyLoginCode method.
+	VerifyLoginCodeFunc func(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error)
+
 	// VerifyMagicLinkFunc mocks the VerifyMagicLink method.
 	VerifyMagicLinkFunc func(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error)
 
+	// VerifySecondFactorFunc mocks the VerifySecondFactor method.
+	VerifySecondFactorFunc func(ctx context.Context, challengeID string, code string) (nakama.AuthOutput, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddUserListMember holds details about calls to the AddUserListMember method.
+		AddUserListMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+			// Username is the username argument value.
+			Username string
+		}
 		// AddWebPushSubscription holds details about calls to the AddWebPushSubscription method.
 		AddWebPushSubscription []struct {
 			// Ctx is the ctx argument value.
@@ -278,16 +697,61 @@ type ServiceMock struct {
 			// Sub is the sub argument value.
 			Sub webpush.Subscription
 		}
-		// AuthUser holds details about calls to the AuthUser method.
-		AuthUser []struct {
+		// ApproveFollowRequest holds details about calls to the ApproveFollowRequest method.
+		ApproveFollowRequest []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
+			// Username is the username argument value.
+			Username string
 		}
-		// AuthUserIDFromToken holds details about calls to the AuthUserIDFromToken method.
-		AuthUserIDFromToken []struct {
+		// AuthFromToken holds details about calls to the AuthFromToken method.
+		AuthFromToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Token is the token argument value.
 			Token string
 		}
+		// AuthUser holds details about calls to the AuthUser method.
+		AuthUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// AuthorizeOAuthApp holds details about calls to the AuthorizeOAuthApp method.
+		AuthorizeOAuthApp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.OAuthAuthorizationRequest
+		}
+		// AuthorizedOAuthApps holds details about calls to the AuthorizedOAuthApps method.
+		AuthorizedOAuthApps []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// BeginPasskeyLogin holds details about calls to the BeginPasskeyLogin method.
+		BeginPasskeyLogin []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// BeginPasskeyRegistration holds details about calls to the BeginPasskeyRegistration method.
+		BeginPasskeyRegistration []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// BeginTOTPEnrollment holds details about calls to the BeginTOTPEnrollment method.
+		BeginTOTPEnrollment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Blocks holds details about calls to the Blocks method.
+		Blocks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// First is the first argument value.
+			First uint64
+			// After is the after argument value.
+			After *string
+		}
 		// CommentStream holds details about calls to the CommentStream method.
 		CommentStream []struct {
 			// Ctx is the ctx argument value.
@@ -306,6 +770,13 @@ type ServiceMock struct {
 			// Before is the before argument value.
 			Before *string
 		}
+		// ConfirmAccountDeletion holds details about calls to the ConfirmAccountDeletion method.
+		ConfirmAccountDeletion []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+		}
 		// CreateComment holds details about calls to the CreateComment method.
 		CreateComment []struct {
 			// Ctx is the ctx argument value.
@@ -315,6 +786,27 @@ type ServiceMock struct {
 			// Content is the content argument value.
 			Content string
 		}
+		// CreateMute holds details about calls to the CreateMute method.
+		CreateMute []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.CreateMute
+		}
+		// CreateOAuthApp holds details about calls to the CreateOAuthApp method.
+		CreateOAuthApp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.CreateOAuthApp
+		}
+		// CreatePersonalAccessToken holds details about calls to the CreatePersonalAccessToken method.
+		CreatePersonalAccessToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.CreatePersonalAccessToken
+		}
 		// CreateTimelineItem holds details about calls to the CreateTimelineItem method.
 		CreateTimelineItem []struct {
 			// Ctx is the ctx argument value.
@@ -328,6 +820,20 @@ type ServiceMock struct {
 			// Media is the media argument value.
 			Media []io.ReadSeeker
 		}
+		// CreateUserList holds details about calls to the CreateUserList method.
+		CreateUserList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.CreateUserList
+		}
+		// DataExportFile holds details about calls to the DataExportFile method.
+		DataExportFile []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Token is the token argument value.
+			Token string
+		}
 		// DeleteComment holds details about calls to the DeleteComment method.
 		DeleteComment []struct {
 			// Ctx is the ctx argument value.
@@ -335,6 +841,27 @@ type ServiceMock struct {
 			// CommentID is the commentID argument value.
 			CommentID string
 		}
+		// DeleteMute holds details about calls to the DeleteMute method.
+		DeleteMute []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MuteID is the muteID argument value.
+			MuteID string
+		}
+		// DeleteOAuthApp holds details about calls to the DeleteOAuthApp method.
+		DeleteOAuthApp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AppID is the appID argument value.
+			AppID string
+		}
+		// DeletePasskey holds details about calls to the DeletePasskey method.
+		DeletePasskey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PasskeyID is the passkeyID argument value.
+			PasskeyID string
+		}
 		// DeletePost holds details about calls to the DeletePost method.
 		DeletePost []struct {
 			// Ctx is the ctx argument value.
@@ -349,6 +876,20 @@ type ServiceMock struct {
 			// TimelineItemID is the timelineItemID argument value.
 			TimelineItemID string
 		}
+		// DeleteUserList holds details about calls to the DeleteUserList method.
+		DeleteUserList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+		}
+		// DenyFollowRequest holds details about calls to the DenyFollowRequest method.
+		DenyFollowRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
 		// DevLogin holds details about calls to the DevLogin method.
 		DevLogin []struct {
 			// Ctx is the ctx argument value.
@@ -356,6 +897,50 @@ type ServiceMock struct {
 			// Email is the email argument value.
 			Email string
 		}
+		// DisableTOTP holds details about calls to the DisableTOTP method.
+		DisableTOTP []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+		}
+		// EnableTOTP holds details about calls to the EnableTOTP method.
+		EnableTOTP []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+		}
+		// ExchangeOAuthCode holds details about calls to the ExchangeOAuthCode method.
+		ExchangeOAuthCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.ExchangeOAuthCode
+		}
+		// FinishPasskeyLogin holds details about calls to the FinishPasskeyLogin method.
+		FinishPasskeyLogin []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.FinishPasskeyLogin
+		}
+		// FinishPasskeyRegistration holds details about calls to the FinishPasskeyRegistration method.
+		FinishPasskeyRegistration []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.FinishPasskeyRegistration
+		}
+		// FollowRequests holds details about calls to the FollowRequests method.
+		FollowRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// First is the first argument value.
+			First uint64
+			// After is the after argument value.
+			After *string
+		}
 		// Followees holds details about calls to the Followees method.
 		Followees []struct {
 			// Ctx is the ctx argument value.
@@ -383,20 +968,73 @@ type ServiceMock struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
-		// LoginFromProvider holds details about calls to the LoginFromProvider method.
-		LoginFromProvider []struct {
+		// Identities holds details about calls to the Identities method.
+		Identities []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Name is the name argument value.
-			Name string
-			// User is the user argument value.
-			User nakama.ProvidedUser
 		}
-		// MarkNotificationAsRead holds details about calls to the MarkNotificationAsRead method.
-		MarkNotificationAsRead []struct {
+		// IntrospectOAuthToken holds details about calls to the IntrospectOAuthToken method.
+		IntrospectOAuthToken []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// NotificationID is the notificationID argument value.
+			// ClientID is the clientID argument value.
+			ClientID string
+			// ClientSecret is the clientSecret argument value.
+			ClientSecret string
+			// Token is the token argument value.
+			Token string
+		}
+		// LinkIdentity holds details about calls to the LinkIdentity method.
+		LinkIdentity []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Provider is the provider argument value.
+			Provider string
+			// User is the user argument value.
+			User nakama.ProvidedUser
+		}
+		// ListTimeline holds details about calls to the ListTimeline method.
+		ListTimeline []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+			// Last is the last argument value.
+			Last uint64
+			// Before is the before argument value.
+			Before *string
+		}
+		// ListTimelineStream holds details about calls to the ListTimelineStream method.
+		ListTimelineStream []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+		}
+		// LoginFromProvider holds details about calls to the LoginFromProvider method.
+		LoginFromProvider []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+			// User is the user argument value.
+			User nakama.ProvidedUser
+		}
+		// Logout holds details about calls to the Logout method.
+		Logout []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// LogoutEverywhere holds details about calls to the LogoutEverywhere method.
+		LogoutEverywhere []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// MarkNotificationAsRead holds details about calls to the MarkNotificationAsRead method.
+		MarkNotificationAsRead []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// NotificationID is the notificationID argument value.
 			NotificationID string
 		}
 		// MarkNotificationsAsRead holds details about calls to the MarkNotificationsAsRead method.
@@ -404,6 +1042,22 @@ type ServiceMock struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// Mutes holds details about calls to the Mutes method.
+		Mutes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// MutualFollowers holds details about calls to the MutualFollowers method.
+		MutualFollowers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// First is the first argument value.
+			First uint64
+			// After is the after argument value.
+			After *string
+		}
 		// NotificationStream holds details about calls to the NotificationStream method.
 		NotificationStream []struct {
 			// Ctx is the ctx argument value.
@@ -418,11 +1072,47 @@ type ServiceMock struct {
 			// Before is the before argument value.
 			Before *string
 		}
+		// OAuthApps holds details about calls to the OAuthApps method.
+		OAuthApps []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// OAuthAuthorization holds details about calls to the OAuthAuthorization method.
+		OAuthAuthorization []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.OAuthAuthorizationRequest
+		}
 		// ParseRedirectURI holds details about calls to the ParseRedirectURI method.
 		ParseRedirectURI []struct {
 			// Rawurl is the rawurl argument value.
 			Rawurl string
 		}
+		// Passkeys holds details about calls to the Passkeys method.
+		Passkeys []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// PersonalAccessTokens holds details about calls to the PersonalAccessTokens method.
+		PersonalAccessTokens []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// PinPost holds details about calls to the PinPost method.
+		PinPost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PostID is the postID argument value.
+			PostID string
+		}
+		// PinnedPosts holds details about calls to the PinnedPosts method.
+		PinnedPosts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
 		// Post holds details about calls to the Post method.
 		Post []struct {
 			// Ctx is the ctx argument value.
@@ -446,6 +1136,114 @@ type ServiceMock struct {
 			// Opts is the opts argument value.
 			Opts []nakama.PostsOpt
 		}
+		// RefreshToken holds details about calls to the RefreshToken method.
+		RefreshToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RefreshToken is the refreshToken argument value.
+			RefreshToken string
+		}
+		// RegenerateRecoveryCodes holds details about calls to the RegenerateRecoveryCodes method.
+		RegenerateRecoveryCodes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+		}
+		// RemoveUserListMember holds details about calls to the RemoveUserListMember method.
+		RemoveUserListMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+			// Username is the username argument value.
+			Username string
+		}
+		// RenamePasskey holds details about calls to the RenamePasskey method.
+		RenamePasskey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PasskeyID is the passkeyID argument value.
+			PasskeyID string
+			// Name is the name argument value.
+			Name string
+		}
+		// ReportComment holds details about calls to the ReportComment method.
+		ReportComment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// CommentID is the commentID argument value.
+			CommentID string
+			// In is the in argument value.
+			In nakama.ReportInput
+		}
+		// ReportPost holds details about calls to the ReportPost method.
+		ReportPost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PostID is the postID argument value.
+			PostID string
+			// In is the in argument value.
+			In nakama.ReportInput
+		}
+		// ReportUser holds details about calls to the ReportUser method.
+		ReportUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// In is the in argument value.
+			In nakama.ReportInput
+		}
+		// Reports holds details about calls to the Reports method.
+		Reports []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// First is the first argument value.
+			First uint64
+			// After is the after argument value.
+			After *string
+		}
+		// RequestAccountDeletion holds details about calls to the RequestAccountDeletion method.
+		RequestAccountDeletion []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RequestDataExport holds details about calls to the RequestDataExport method.
+		RequestDataExport []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ResolveReport holds details about calls to the ResolveReport method.
+		ResolveReport []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ReportID is the reportID argument value.
+			ReportID string
+			// Action is the action argument value.
+			Action string
+		}
+		// RevokeOAuthApp holds details about calls to the RevokeOAuthApp method.
+		RevokeOAuthApp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AppID is the appID argument value.
+			AppID string
+		}
+		// RevokePersonalAccessToken holds details about calls to the RevokePersonalAccessToken method.
+		RevokePersonalAccessToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TokenID is the tokenID argument value.
+			TokenID string
+		}
+		// RevokeSession holds details about calls to the RevokeSession method.
+		RevokeSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SessionID is the sessionID argument value.
+			SessionID string
+		}
 		// SendMagicLink holds details about calls to the SendMagicLink method.
 		SendMagicLink []struct {
 			// Ctx is the ctx argument value.
@@ -453,6 +1251,27 @@ type ServiceMock struct {
 			// In is the in argument value.
 			In nakama.SendMagicLink
 		}
+		// Sessions holds details about calls to the Sessions method.
+		Sessions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// SuggestedUsers holds details about calls to the SuggestedUsers method.
+		SuggestedUsers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// First is the first argument value.
+			First uint64
+		}
+		// SuspendUser holds details about calls to the SuspendUser method.
+		SuspendUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Until is the until argument value.
+			Until *time.Time
+		}
 		// Timeline holds details about calls to the Timeline method.
 		Timeline []struct {
 			// Ctx is the ctx argument value.
@@ -467,6 +1286,13 @@ type ServiceMock struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// ToggleBlock holds details about calls to the ToggleBlock method.
+		ToggleBlock []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
 		// ToggleCommentReaction holds details about calls to the ToggleCommentReaction method.
 		ToggleCommentReaction []struct {
 			// Ctx is the ctx argument value.
@@ -499,10 +1325,31 @@ type ServiceMock struct {
 			// PostID is the postID argument value.
 			PostID string
 		}
-		// Token holds details about calls to the Token method.
-		Token []struct {
+		// TwoFactorStatus holds details about calls to the TwoFactorStatus method.
+		TwoFactorStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// UnlinkIdentity holds details about calls to the UnlinkIdentity method.
+		UnlinkIdentity []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Provider is the provider argument value.
+			Provider string
+		}
+		// UnpinPost holds details about calls to the UnpinPost method.
+		UnpinPost []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PostID is the postID argument value.
+			PostID string
+		}
+		// UnsuspendUser holds details about calls to the UnsuspendUser method.
+		UnsuspendUser []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
+			// Username is the username argument value.
+			Username string
 		}
 		// UpdateAvatar holds details about calls to the UpdateAvatar method.
 		UpdateAvatar []struct {
@@ -525,6 +1372,13 @@ type ServiceMock struct {
 			// R is the r argument value.
 			R io.ReadSeeker
 		}
+		// UpdateMute holds details about calls to the UpdateMute method.
+		UpdateMute []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.UpdateMute
+		}
 		// UpdatePost holds details about calls to the UpdatePost method.
 		UpdatePost []struct {
 			// Ctx is the ctx argument value.
@@ -541,6 +1395,13 @@ type ServiceMock struct {
 			// Params is the params argument value.
 			Params nakama.UpdateUserParams
 		}
+		// UpdateUserList holds details about calls to the UpdateUserList method.
+		UpdateUserList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In nakama.UpdateUserList
+		}
 		// User holds details about calls to the User method.
 		User []struct {
 			// Ctx is the ctx argument value.
@@ -548,6 +1409,31 @@ type ServiceMock struct {
 			// Username is the username argument value.
 			Username string
 		}
+		// UserList holds details about calls to the UserList method.
+		UserList []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+		}
+		// UserListMembers holds details about calls to the UserListMembers method.
+		UserListMembers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ListID is the listID argument value.
+			ListID string
+			// First is the first argument value.
+			First uint64
+			// After is the after argument value.
+			After *string
+		}
+		// UserLists holds details about calls to the UserLists method.
+		UserLists []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
 		// Usernames holds details about calls to the Usernames method.
 		Usernames []struct {
 			// Ctx is the ctx argument value.
@@ -570,6 +1456,17 @@ type ServiceMock struct {
 			// After is the after argument value.
 			After *string
 		}
+		// VerifyLoginCode holds details about calls to the VerifyLoginCode method.
+		VerifyLoginCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+			// Code is the code argument value.
+			Code string
+			// Username is the username argument value.
+			Username *string
+		}
 		// VerifyMagicLink holds details about calls to the VerifyMagicLink method.
 		VerifyMagicLink []struct {
 			// Ctx is the ctx argument value.
@@ -581,47 +1478,167 @@ type ServiceMock struct {
 			// Username is the username argument value.
 			Username *string
 		}
+		// VerifySecondFactor holds details about calls to the VerifySecondFactor method.
+		VerifySecondFactor []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChallengeID is the challengeID argument value.
+			ChallengeID string
+			// Code is the code argument value.
+			Code string
+		}
+	}
+	lockAddUserListMember         sync.RWMutex
+	lockAddWebPushSubscription    sync.RWMutex
+	lockApproveFollowRequest      sync.RWMutex
+	lockAuthFromToken             sync.RWMutex
+	lockAuthUser                  sync.RWMutex
+	lockAuthorizeOAuthApp         sync.RWMutex
+	lockAuthorizedOAuthApps       sync.RWMutex
+	lockBeginPasskeyLogin         sync.RWMutex
+	lockBeginPasskeyRegistration  sync.RWMutex
+	lockBeginTOTPEnrollment       sync.RWMutex
+	lockBlocks                    sync.RWMutex
+	lockCommentStream             sync.RWMutex
+	lockComments                  sync.RWMutex
+	lockConfirmAccountDeletion    sync.RWMutex
+	lockCreateComment             sync.RWMutex
+	lockCreateMute                sync.RWMutex
+	lockCreateOAuthApp            sync.RWMutex
+	lockCreatePersonalAccessToken sync.RWMutex
+	lockCreateTimelineItem        sync.RWMutex
+	lockCreateUserList            sync.RWMutex
+	lockDataExportFile            sync.RWMutex
+	lockDeleteComment             sync.RWMutex
+	lockDeleteMute                sync.RWMutex
+	lockDeleteOAuthApp            sync.RWMutex
+	lockDeletePasskey             sync.RWMutex
+	lockDeletePost                sync.RWMutex
+	lockDeleteTimelineItem        sync.RWMutex
+	lockDeleteUserList            sync.RWMutex
+	lockDenyFollowRequest         sync.RWMutex
+	lockDevLogin                  sync.RWMutex
+	lockDisableTOTP               sync.RWMutex
+	lockEnableTOTP                sync.RWMutex
+	lockExchangeOAuthCode         sync.RWMutex
+	lockFinishPasskeyLogin        sync.RWMutex
+	lockFinishPasskeyRegistration sync.RWMutex
+	lockFollowRequests            sync.RWMutex
+	lockFollowees                 sync.RWMutex
+	lockFollowers                 sync.RWMutex
+	lockHasUnreadNotifications    sync.RWMutex
+	lockIdentities                sync.RWMutex
+	lockIntrospectOAuthToken      sync.RWMutex
+	lockLinkIdentity              sync.RWMutex
+	lockListTimeline              sync.RWMutex
+	lockListTimelineStream        sync.RWMutex
+	lockLoginFromProvider         sync.RWMutex
+	lockLogout                    sync.RWMutex
+	lockLogoutEverywhere          sync.RWMutex
+	lockMarkNotificationAsRead    sync.RWMutex
+	lockMarkNotificationsAsRead   sync.RWMutex
+	lockMutes                     sync.RWMutex
+	lockMutualFollowers           sync.RWMutex
+	lockNotificationStream        sync.RWMutex
+	lockNotifications             sync.RWMutex
+	lockOAuthApps                 sync.RWMutex
+	lockOAuthAuthorization        sync.RWMutex
+	lockParseRedirectURI          sync.RWMutex
+	lockPasskeys                  sync.RWMutex
+	lockPersonalAccessTokens      sync.RWMutex
+	lockPinPost                   sync.RWMutex
+	lockPinnedPosts               sync.RWMutex
+	lockPost                      sync.RWMutex
+	lockPostStream                sync.RWMutex
+	lockPosts                     sync.RWMutex
+	lockRefreshToken              sync.RWMutex
+	lockRegenerateRecoveryCodes   sync.RWMutex
+	lockRemoveUserListMember      sync.RWMutex
+	lockRenamePasskey             sync.RWMutex
+	lockReportComment             sync.RWMutex
+	lockReportPost                sync.RWMutex
+	lockReportUser                sync.RWMutex
+	lockReports                   sync.RWMutex
+	lockRequestAccountDeletion    sync.RWMutex
+	lockRequestDataExport         sync.RWMutex
+	lockResolveReport             sync.RWMutex
+	lockRevokeOAuthApp            sync.RWMutex
+	lockRevokePersonalAccessToken sync.RWMutex
+	lockRevokeSession             sync.RWMutex
+	lockSendMagicLink             sync.RWMutex
+	lockSessions                  sync.RWMutex
+	lockSuggestedUsers            sync.RWMutex
+	lockSuspendUser               sync.RWMutex
+	lockTimeline                  sync.RWMutex
+	lockTimelineItemStream        sync.RWMutex
+	lockToggleBlock               sync.RWMutex
+	lockToggleCommentReaction     sync.RWMutex
+	lockToggleFollow              sync.RWMutex
+	lockTogglePostReaction        sync.RWMutex
+	lockTogglePostSubscription    sync.RWMutex
+	lockTwoFactorStatus           sync.RWMutex
+	lockUnlinkIdentity            sync.RWMutex
+	lockUnpinPost                 sync.RWMutex
+	lockUnsuspendUser             sync.RWMutex
+	lockUpdateAvatar              sync.RWMutex
+	lockUpdateComment             sync.RWMutex
+	lockUpdateCover               sync.RWMutex
+	lockUpdateMute                sync.RWMutex
+	lockUpdatePost                sync.RWMutex
+	lockUpdateUser                sync.RWMutex
+	lockUpdateUserList            sync.RWMutex
+	lockUser                      sync.RWMutex
+	lockUserList                  sync.RWMutex
+	lockUserListMembers           sync.RWMutex
+	lockUserLists                 sync.RWMutex
+	lockUsernames                 sync.RWMutex
+	lockUsers                     sync.RWMutex
+	lockVerifyLoginCode           sync.RWMutex
+	lockVerifyMagicLink           sync.RWMutex
+	lockVerifySecondFactor        sync.RWMutex
+}
+
+// AddUserListMember calls AddUserListMemberFunc.
+func (mock *ServiceMock) AddUserListMember(ctx context.Context, listID string, username string) error {
+	callInfo := struct {
+		Ctx      context.Context
+		ListID   string
+		Username string
+	}{
+		Ctx:      ctx,
+		ListID:   listID,
+		Username: username,
+	}
+	mock.lockAddUserListMember.Lock()
+	mock.calls.AddUserListMember = append(mock.calls.AddUserListMember, callInfo)
+	mock.lockAddUserListMember.Unlock()
+	if mock.AddUserListMemberFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.AddUserListMemberFunc(ctx, listID, username)
+}
+
+// AddUserListMemberCalls gets all the calls that were made to AddUserListMember.
+// Check the length with:
+//
+//	len(mockedService.AddUserListMemberCalls())
+func (mock *ServiceMock) AddUserListMemberCalls() []struct {
+	Ctx      context.Context
+	ListID   string
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ListID   string
+		Username string
 	}
-	lockAddWebPushSubscription  sync.RWMutex
-	lockAuthUser                sync.RWMutex
-	lockAuthUserIDFromToken     sync.RWMutex
-	lockCommentStream           sync.RWMutex
-	lockComments                sync.RWMutex
-	lockCreateComment           sync.RWMutex
-	lockCreateTimelineItem      sync.RWMutex
-	lockDeleteComment           sync.RWMutex
-	lockDeletePost              sync.RWMutex
-	lockDeleteTimelineItem      sync.RWMutex
-	lockDevLogin                sync.RWMutex
-	lockFollowees               sync.RWMutex
-	lockFollowers               sync.RWMutex
-	lockHasUnreadNotifications  sync.RWMutex
-	lockLoginFromProvider       sync.RWMutex
-	lockMarkNotificationAsRead  sync.RWMutex
-	lockMarkNotificationsAsRead sync.RWMutex
-	lockNotificationStream      sync.RWMutex
-	lockNotifications           sync.RWMutex
-	lockParseRedirectURI        sync.RWMutex
-	lockPost                    sync.RWMutex
-	lockPostStream              sync.RWMutex
-	lockPosts                   sync.RWMutex
-	lockSendMagicLink           sync.RWMutex
-	lockTimeline                sync.RWMutex
-	lockTimelineItemStream      sync.RWMutex
-	lockToggleCommentReaction   sync.RWMutex
-	lockToggleFollow            sync.RWMutex
-	lockTogglePostReaction      sync.RWMutex
-	lockTogglePostSubscription  sync.RWMutex
-	lockToken                   sync.RWMutex
-	lockUpdateAvatar            sync.RWMutex
-	lockUpdateComment           sync.RWMutex
-	lockUpdateCover             sync.RWMutex
-	lockUpdatePost              sync.RWMutex
-	lockUpdateUser              sync.RWMutex
-	lockUser                    sync.RWMutex
-	lockUsernames               sync.RWMutex
-	lockUsers                   sync.RWMutex
-	lockVerifyMagicLink         sync.RWMutex
+	mock.lockAddUserListMember.RLock()
+	calls = mock.calls.AddUserListMember
+	mock.lockAddUserListMember.RUnlock()
+	return calls
 }
 
 // AddWebPushSubscription calls AddWebPushSubscriptionFunc.
@@ -663,75 +1680,346 @@ func (mock *ServiceMock) AddWebPushSubscriptionCalls() []struct {
 	return calls
 }
 
-// AuthUser calls AuthUserFunc.
-func (mock *ServiceMock) AuthUser(ctx context.Context) (nakama.User, error) {
+// ApproveFollowRequest calls ApproveFollowRequestFunc.
+func (mock *ServiceMock) ApproveFollowRequest(ctx context.Context, username string) error {
 	callInfo := struct {
-		Ctx context.Context
+		Ctx      context.Context
+		Username string
 	}{
-		Ctx: ctx,
+		Ctx:      ctx,
+		Username: username,
 	}
-	mock.lockAuthUser.Lock()
-	mock.calls.AuthUser = append(mock.calls.AuthUser, callInfo)
-	mock.lockAuthUser.Unlock()
-	if mock.AuthUserFunc == nil {
+	mock.lockApproveFollowRequest.Lock()
+	mock.calls.ApproveFollowRequest = append(mock.calls.ApproveFollowRequest, callInfo)
+	mock.lockApproveFollowRequest.Unlock()
+	if mock.ApproveFollowRequestFunc == nil {
 		var (
-			userOut nakama.User
-			errOut  error
+			errOut error
 		)
-		return userOut, errOut
+		return errOut
 	}
-	return mock.AuthUserFunc(ctx)
+	return mock.ApproveFollowRequestFunc(ctx, username)
 }
 
-// AuthUserCalls gets all the calls that were made to AuthUser.
+// ApproveFollowRequestCalls gets all the calls that were made to ApproveFollowRequest.
 // Check the length with:
 //
-//	len(mockedService.AuthUserCalls())
-func (mock *ServiceMock) AuthUserCalls() []struct {
-	Ctx context.Context
+//	len(mockedService.ApproveFollowRequestCalls())
+func (mock *ServiceMock) ApproveFollowRequestCalls() []struct {
+	Ctx      context.Context
+	Username string
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx      context.Context
+		Username string
 	}
-	mock.lockAuthUser.RLock()
-	calls = mock.calls.AuthUser
-	mock.lockAuthUser.RUnlock()
+	mock.lockApproveFollowRequest.RLock()
+	calls = mock.calls.ApproveFollowRequest
+	mock.lockApproveFollowRequest.RUnlock()
 	return calls
 }
 
-// AuthUserIDFromToken calls AuthUserIDFromTokenFunc.
-func (mock *ServiceMock) AuthUserIDFromToken(token string) (string, error) {
+// AuthFromToken calls AuthFromTokenFunc.
+func (mock *ServiceMock) AuthFromToken(ctx context.Context, token string) (nakama.Auth, error) {
 	callInfo := struct {
+		Ctx   context.Context
 		Token string
 	}{
+		Ctx:   ctx,
 		Token: token,
 	}
-	mock.lockAuthUserIDFromToken.Lock()
-	mock.calls.AuthUserIDFromToken = append(mock.calls.AuthUserIDFromToken, callInfo)
-	mock.lockAuthUserIDFromToken.Unlock()
-	if mock.AuthUserIDFromTokenFunc == nil {
+	mock.lockAuthFromToken.Lock()
+	mock.calls.AuthFromToken = append(mock.calls.AuthFromToken, callInfo)
+	mock.lockAuthFromToken.Unlock()
+	if mock.AuthFromTokenFunc == nil {
 		var (
-			sOut   string
-			errOut error
+			authOut nakama.Auth
+			errOut  error
 		)
-		return sOut, errOut
+		return authOut, errOut
 	}
-	return mock.AuthUserIDFromTokenFunc(token)
+	return mock.AuthFromTokenFunc(ctx, token)
 }
 
-// AuthUserIDFromTokenCalls gets all the calls that were made to AuthUserIDFromToken.
+// AuthFromTokenCalls gets all the calls that were made to AuthFromToken.
 // Check the length with:
 //
-//	len(mockedService.AuthUserIDFromTokenCalls())
-func (mock *ServiceMock) AuthUserIDFromTokenCalls() []struct {
+//	len(mockedService.AuthFromTokenCalls())
+func (mock *ServiceMock) AuthFromTokenCalls() []struct {
+	Ctx   context.Context
 	Token string
 } {
 	var calls []struct {
+		Ctx   context.Context
 		Token string
 	}
-	mock.lockAuthUserIDFromToken.RLock()
-	calls = mock.calls.AuthUserIDFromToken
-	mock.lockAuthUserIDFromToken.RUnlock()
+	mock.lockAuthFromToken.RLock()
+	calls = mock.calls.AuthFromToken
+	mock.lockAuthFromToken.RUnlock()
+	return calls
+}
+
+// AuthUser calls AuthUserFunc.
+func (mock *ServiceMock) AuthUser(ctx context.Context) (nakama.User, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockAuthUser.Lock()
+	mock.calls.AuthUser = append(mock.calls.AuthUser, callInfo)
+	mock.lockAuthUser.Unlock()
+	if mock.AuthUserFunc == nil {
+		var (
+			userOut nakama.User
+			errOut  error
+		)
+		return userOut, errOut
+	}
+	return mock.AuthUserFunc(ctx)
+}
+
+// AuthUserCalls gets all the calls that were made to AuthUser.
+// Check the length with:
+//
+//	len(mockedService.AuthUserCalls())
+func (mock *ServiceMock) AuthUserCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockAuthUser.RLock()
+	calls = mock.calls.AuthUser
+	mock.lockAuthUser.RUnlock()
+	return calls
+}
+
+// AuthorizeOAuthApp calls AuthorizeOAuthAppFunc.
+func (mock *ServiceMock) AuthorizeOAuthApp(ctx context.Context, in nakama.OAuthAuthorizationRequest) (*url.URL, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.OAuthAuthorizationRequest
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockAuthorizeOAuthApp.Lock()
+	mock.calls.AuthorizeOAuthApp = append(mock.calls.AuthorizeOAuthApp, callInfo)
+	mock.lockAuthorizeOAuthApp.Unlock()
+	if mock.AuthorizeOAuthAppFunc == nil {
+		var (
+			uRLOut *url.URL
+			errOut error
+		)
+		return uRLOut, errOut
+	}
+	return mock.AuthorizeOAuthAppFunc(ctx, in)
+}
+
+// AuthorizeOAuthAppCalls gets all the calls that were made to AuthorizeOAuthApp.
+// Check the length with:
+//
+//	len(mockedService.AuthorizeOAuthAppCalls())
+func (mock *ServiceMock) AuthorizeOAuthAppCalls() []struct {
+	Ctx context.Context
+	In  nakama.OAuthAuthorizationRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.OAuthAuthorizationRequest
+	}
+	mock.lockAuthorizeOAuthApp.RLock()
+	calls = mock.calls.AuthorizeOAuthApp
+	mock.lockAuthorizeOAuthApp.RUnlock()
+	return calls
+}
+
+// AuthorizedOAuthApps calls AuthorizedOAuthAppsFunc.
+func (mock *ServiceMock) AuthorizedOAuthApps(ctx context.Context) ([]nakama.AuthorizedOAuthApp, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockAuthorizedOAuthApps.Lock()
+	mock.calls.AuthorizedOAuthApps = append(mock.calls.AuthorizedOAuthApps, callInfo)
+	mock.lockAuthorizedOAuthApps.Unlock()
+	if mock.AuthorizedOAuthAppsFunc == nil {
+		var (
+			authorizedOAuthAppsOut []nakama.AuthorizedOAuthApp
+			errOut                 error
+		)
+		return authorizedOAuthAppsOut, errOut
+	}
+	return mock.AuthorizedOAuthAppsFunc(ctx)
+}
+
+// AuthorizedOAuthAppsCalls gets all the calls that were made to AuthorizedOAuthApps.
+// Check the length with:
+//
+//	len(mockedService.AuthorizedOAuthAppsCalls())
+func (mock *ServiceMock) AuthorizedOAuthAppsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockAuthorizedOAuthApps.RLock()
+	calls = mock.calls.AuthorizedOAuthApps
+	mock.lockAuthorizedOAuthApps.RUnlock()
+	return calls
+}
+
+// BeginPasskeyLogin calls BeginPasskeyLoginFunc.
+func (mock *ServiceMock) BeginPasskeyLogin(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBeginPasskeyLogin.Lock()
+	mock.calls.BeginPasskeyLogin = append(mock.calls.BeginPasskeyLogin, callInfo)
+	mock.lockBeginPasskeyLogin.Unlock()
+	if mock.BeginPasskeyLoginFunc == nil {
+		var (
+			passkeyCeremonyOut nakama.PasskeyCeremony
+			errOut             error
+		)
+		return passkeyCeremonyOut, errOut
+	}
+	return mock.BeginPasskeyLoginFunc(ctx)
+}
+
+// BeginPasskeyLoginCalls gets all the calls that were made to BeginPasskeyLogin.
+// Check the length with:
+//
+//	len(mockedService.BeginPasskeyLoginCalls())
+func (mock *ServiceMock) BeginPasskeyLoginCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBeginPasskeyLogin.RLock()
+	calls = mock.calls.BeginPasskeyLogin
+	mock.lockBeginPasskeyLogin.RUnlock()
+	return calls
+}
+
+// BeginPasskeyRegistration calls BeginPasskeyRegistrationFunc.
+func (mock *ServiceMock) BeginPasskeyRegistration(ctx context.Context) (nakama.PasskeyCeremony, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBeginPasskeyRegistration.Lock()
+	mock.calls.BeginPasskeyRegistration = append(mock.calls.BeginPasskeyRegistration, callInfo)
+	mock.lockBeginPasskeyRegistration.Unlock()
+	if mock.BeginPasskeyRegistrationFunc == nil {
+		var (
+			passkeyCeremonyOut nakama.PasskeyCeremony
+			errOut             error
+		)
+		return passkeyCeremonyOut, errOut
+	}
+	return mock.BeginPasskeyRegistrationFunc(ctx)
+}
+
+// BeginPasskeyRegistrationCalls gets all the calls that were made to BeginPasskeyRegistration.
+// Check the length with:
+//
+//	len(mockedService.BeginPasskeyRegistrationCalls())
+func (mock *ServiceMock) BeginPasskeyRegistrationCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBeginPasskeyRegistration.RLock()
+	calls = mock.calls.BeginPasskeyRegistration
+	mock.lockBeginPasskeyRegistration.RUnlock()
+	return calls
+}
+
+// BeginTOTPEnrollment calls BeginTOTPEnrollmentFunc.
+func (mock *ServiceMock) BeginTOTPEnrollment(ctx context.Context) (nakama.TOTPEnrollment, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBeginTOTPEnrollment.Lock()
+	mock.calls.BeginTOTPEnrollment = append(mock.calls.BeginTOTPEnrollment, callInfo)
+	mock.lockBeginTOTPEnrollment.Unlock()
+	if mock.BeginTOTPEnrollmentFunc == nil {
+		var (
+			tOTPEnrollmentOut nakama.TOTPEnrollment
+			errOut            error
+		)
+		return tOTPEnrollmentOut, errOut
+	}
+	return mock.BeginTOTPEnrollmentFunc(ctx)
+}
+
+// BeginTOTPEnrollmentCalls gets all the calls that were made to BeginTOTPEnrollment.
+// Check the length with:
+//
+//	len(mockedService.BeginTOTPEnrollmentCalls())
+func (mock *ServiceMock) BeginTOTPEnrollmentCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBeginTOTPEnrollment.RLock()
+	calls = mock.calls.BeginTOTPEnrollment
+	mock.lockBeginTOTPEnrollment.RUnlock()
+	return calls
+}
+
+// Blocks calls BlocksFunc.
+func (mock *ServiceMock) Blocks(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		First uint64
+		After *string
+	}{
+		Ctx:   ctx,
+		First: first,
+		After: after,
+	}
+	mock.lockBlocks.Lock()
+	mock.calls.Blocks = append(mock.calls.Blocks, callInfo)
+	mock.lockBlocks.Unlock()
+	if mock.BlocksFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.BlocksFunc(ctx, first, after)
+}
+
+// BlocksCalls gets all the calls that were made to Blocks.
+// Check the length with:
+//
+//	len(mockedService.BlocksCalls())
+func (mock *ServiceMock) BlocksCalls() []struct {
+	Ctx   context.Context
+	First uint64
+	After *string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		First uint64
+		After *string
+	}
+	mock.lockBlocks.RLock()
+	calls = mock.calls.Blocks
+	mock.lockBlocks.RUnlock()
 	return calls
 }
 
@@ -823,6 +2111,46 @@ func (mock *ServiceMock) CommentsCalls() []struct {
 	return calls
 }
 
+// ConfirmAccountDeletion calls ConfirmAccountDeletionFunc.
+func (mock *ServiceMock) ConfirmAccountDeletion(ctx context.Context, code string) (time.Time, error) {
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+	}{
+		Ctx:  ctx,
+		Code: code,
+	}
+	mock.lockConfirmAccountDeletion.Lock()
+	mock.calls.ConfirmAccountDeletion = append(mock.calls.ConfirmAccountDeletion, callInfo)
+	mock.lockConfirmAccountDeletion.Unlock()
+	if mock.ConfirmAccountDeletionFunc == nil {
+		var (
+			timeOut time.Time
+			errOut  error
+		)
+		return timeOut, errOut
+	}
+	return mock.ConfirmAccountDeletionFunc(ctx, code)
+}
+
+// ConfirmAccountDeletionCalls gets all the calls that were made to ConfirmAccountDeletion.
+// Check the length with:
+//
+//	len(mockedService.ConfirmAccountDeletionCalls())
+func (mock *ServiceMock) ConfirmAccountDeletionCalls() []struct {
+	Ctx  context.Context
+	Code string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+	}
+	mock.lockConfirmAccountDeletion.RLock()
+	calls = mock.calls.ConfirmAccountDeletion
+	mock.lockConfirmAccountDeletion.RUnlock()
+	return calls
+}
+
 // CreateComment calls CreateCommentFunc.
 func (mock *ServiceMock) CreateComment(ctx context.Context, postID string, content string) (nakama.Comment, error) {
 	callInfo := struct {
@@ -867,84 +2195,284 @@ func (mock *ServiceMock) CreateCommentCalls() []struct {
 	return calls
 }
 
-// CreateTimelineItem calls CreateTimelineItemFunc.
-func (mock *ServiceMock) CreateTimelineItem(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error) {
+// CreateMute calls CreateMuteFunc.
+func (mock *ServiceMock) CreateMute(ctx context.Context, in nakama.CreateMute) (nakama.Mute, error) {
 	callInfo := struct {
-		Ctx       context.Context
-		Content   string
-		SpoilerOf *string
-		Nsfw      bool
-		Media     []io.ReadSeeker
+		Ctx context.Context
+		In  nakama.CreateMute
 	}{
-		Ctx:       ctx,
-		Content:   content,
-		SpoilerOf: spoilerOf,
-		Nsfw:      nsfw,
-		Media:     media,
+		Ctx: ctx,
+		In:  in,
 	}
-	mock.lockCreateTimelineItem.Lock()
-	mock.calls.CreateTimelineItem = append(mock.calls.CreateTimelineItem, callInfo)
-	mock.lockCreateTimelineItem.Unlock()
-	if mock.CreateTimelineItemFunc == nil {
+	mock.lockCreateMute.Lock()
+	mock.calls.CreateMute = append(mock.calls.CreateMute, callInfo)
+	mock.lockCreateMute.Unlock()
+	if mock.CreateMuteFunc == nil {
 		var (
-			timelineItemOut nakama.TimelineItem
-			errOut          error
+			muteOut nakama.Mute
+			errOut  error
 		)
-		return timelineItemOut, errOut
+		return muteOut, errOut
 	}
-	return mock.CreateTimelineItemFunc(ctx, content, spoilerOf, nsfw, media)
+	return mock.CreateMuteFunc(ctx, in)
 }
 
-// CreateTimelineItemCalls gets all the calls that were made to CreateTimelineItem.
+// CreateMuteCalls gets all the calls that were made to CreateMute.
 // Check the length with:
 //
-//	len(mockedService.CreateTimelineItemCalls())
-func (mock *ServiceMock) CreateTimelineItemCalls() []struct {
-	Ctx       context.Context
-	Content   string
-	SpoilerOf *string
-	Nsfw      bool
-	Media     []io.ReadSeeker
+//	len(mockedService.CreateMuteCalls())
+func (mock *ServiceMock) CreateMuteCalls() []struct {
+	Ctx context.Context
+	In  nakama.CreateMute
 } {
 	var calls []struct {
-		Ctx       context.Context
-		Content   string
-		SpoilerOf *string
-		Nsfw      bool
-		Media     []io.ReadSeeker
+		Ctx context.Context
+		In  nakama.CreateMute
 	}
-	mock.lockCreateTimelineItem.RLock()
-	calls = mock.calls.CreateTimelineItem
-	mock.lockCreateTimelineItem.RUnlock()
+	mock.lockCreateMute.RLock()
+	calls = mock.calls.CreateMute
+	mock.lockCreateMute.RUnlock()
 	return calls
 }
 
-// DeleteComment calls DeleteCommentFunc.
-func (mock *ServiceMock) DeleteComment(ctx context.Context, commentID string) error {
+// CreateOAuthApp calls CreateOAuthAppFunc.
+func (mock *ServiceMock) CreateOAuthApp(ctx context.Context, in nakama.CreateOAuthApp) (nakama.CreatedOAuthApp, error) {
 	callInfo := struct {
-		Ctx       context.Context
-		CommentID string
+		Ctx context.Context
+		In  nakama.CreateOAuthApp
 	}{
-		Ctx:       ctx,
-		CommentID: commentID,
+		Ctx: ctx,
+		In:  in,
 	}
-	mock.lockDeleteComment.Lock()
-	mock.calls.DeleteComment = append(mock.calls.DeleteComment, callInfo)
-	mock.lockDeleteComment.Unlock()
-	if mock.DeleteCommentFunc == nil {
+	mock.lockCreateOAuthApp.Lock()
+	mock.calls.CreateOAuthApp = append(mock.calls.CreateOAuthApp, callInfo)
+	mock.lockCreateOAuthApp.Unlock()
+	if mock.CreateOAuthAppFunc == nil {
 		var (
-			errOut error
+			createdOAuthAppOut nakama.CreatedOAuthApp
+			errOut             error
 		)
-		return errOut
+		return createdOAuthAppOut, errOut
 	}
-	return mock.DeleteCommentFunc(ctx, commentID)
+	return mock.CreateOAuthAppFunc(ctx, in)
 }
 
-// DeleteCommentCalls gets all the calls that were made to DeleteComment.
+// CreateOAuthAppCalls gets all the calls that were made to CreateOAuthApp.
 // Check the length with:
 //
-//	len(mockedService.DeleteCommentCalls())
-func (mock *ServiceMock) DeleteCommentCalls() []struct {
+//	len(mockedService.CreateOAuthAppCalls())
+func (mock *ServiceMock) CreateOAuthAppCalls() []struct {
+	Ctx context.Context
+	In  nakama.CreateOAuthApp
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.CreateOAuthApp
+	}
+	mock.lockCreateOAuthApp.RLock()
+	calls = mock.calls.CreateOAuthApp
+	mock.lockCreateOAuthApp.RUnlock()
+	return calls
+}
+
+// CreatePersonalAccessToken calls CreatePersonalAccessTokenFunc.
+func (mock *ServiceMock) CreatePersonalAccessToken(ctx context.Context, in nakama.CreatePersonalAccessToken) (nakama.CreatedPersonalAccessToken, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.CreatePersonalAccessToken
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockCreatePersonalAccessToken.Lock()
+	mock.calls.CreatePersonalAccessToken = append(mock.calls.CreatePersonalAccessToken, callInfo)
+	mock.lockCreatePersonalAccessToken.Unlock()
+	if mock.CreatePersonalAccessTokenFunc == nil {
+		var (
+			createdPersonalAccessTokenOut nakama.CreatedPersonalAccessToken
+			errOut                        error
+		)
+		return createdPersonalAccessTokenOut, errOut
+	}
+	return mock.CreatePersonalAccessTokenFunc(ctx, in)
+}
+
+// CreatePersonalAccessTokenCalls gets all the calls that were made to CreatePersonalAccessToken.
+// Check the length with:
+//
+//	len(mockedService.CreatePersonalAccessTokenCalls())
+func (mock *ServiceMock) CreatePersonalAccessTokenCalls() []struct {
+	Ctx context.Context
+	In  nakama.CreatePersonalAccessToken
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.CreatePersonalAccessToken
+	}
+	mock.lockCreatePersonalAccessToken.RLock()
+	calls = mock.calls.CreatePersonalAccessToken
+	mock.lockCreatePersonalAccessToken.RUnlock()
+	return calls
+}
+
+// CreateTimelineItem calls CreateTimelineItemFunc.
+func (mock *ServiceMock) CreateTimelineItem(ctx context.Context, content string, spoilerOf *string, nsfw bool, media []io.ReadSeeker) (nakama.TimelineItem, error) {
+	callInfo := struct {
+		Ctx       context.Context
+		Content   string
+		SpoilerOf *string
+		Nsfw      bool
+		Media     []io.ReadSeeker
+	}{
+		Ctx:       ctx,
+		Content:   content,
+		SpoilerOf: spoilerOf,
+		Nsfw:      nsfw,
+		Media:     media,
+	}
+	mock.lockCreateTimelineItem.Lock()
+	mock.calls.CreateTimelineItem = append(mock.calls.CreateTimelineItem, callInfo)
+	mock.lockCreateTimelineItem.Unlock()
+	if mock.CreateTimelineItemFunc == nil {
+		var (
+			timelineItemOut nakama.TimelineItem
+			errOut          error
+		)
+		return timelineItemOut, errOut
+	}
+	return mock.CreateTimelineItemFunc(ctx, content, spoilerOf, nsfw, media)
+}
+
+// CreateTimelineItemCalls gets all the calls that were made to CreateTimelineItem.
+// Check the length with:
+//
+//	len(mockedService.CreateTimelineItemCalls())
+func (mock *ServiceMock) CreateTimelineItemCalls() []struct {
+	Ctx       context.Context
+	Content   string
+	SpoilerOf *string
+	Nsfw      bool
+	Media     []io.ReadSeeker
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Content   string
+		SpoilerOf *string
+		Nsfw      bool
+		Media     []io.ReadSeeker
+	}
+	mock.lockCreateTimelineItem.RLock()
+	calls = mock.calls.CreateTimelineItem
+	mock.lockCreateTimelineItem.RUnlock()
+	return calls
+}
+
+// CreateUserList calls CreateUserListFunc.
+func (mock *ServiceMock) CreateUserList(ctx context.Context, in nakama.CreateUserList) (nakama.UserList, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.CreateUserList
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockCreateUserList.Lock()
+	mock.calls.CreateUserList = append(mock.calls.CreateUserList, callInfo)
+	mock.lockCreateUserList.Unlock()
+	if mock.CreateUserListFunc == nil {
+		var (
+			userListOut nakama.UserList
+			errOut      error
+		)
+		return userListOut, errOut
+	}
+	return mock.CreateUserListFunc(ctx, in)
+}
+
+// CreateUserListCalls gets all the calls that were made to CreateUserList.
+// Check the length with:
+//
+//	len(mockedService.CreateUserListCalls())
+func (mock *ServiceMock) CreateUserListCalls() []struct {
+	Ctx context.Context
+	In  nakama.CreateUserList
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.CreateUserList
+	}
+	mock.lockCreateUserList.RLock()
+	calls = mock.calls.CreateUserList
+	mock.lockCreateUserList.RUnlock()
+	return calls
+}
+
+// DataExportFile calls DataExportFileFunc.
+func (mock *ServiceMock) DataExportFile(ctx context.Context, token string) (*storage.File, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		Token string
+	}{
+		Ctx:   ctx,
+		Token: token,
+	}
+	mock.lockDataExportFile.Lock()
+	mock.calls.DataExportFile = append(mock.calls.DataExportFile, callInfo)
+	mock.lockDataExportFile.Unlock()
+	if mock.DataExportFileFunc == nil {
+		var (
+			fileOut *storage.File
+			errOut  error
+		)
+		return fileOut, errOut
+	}
+	return mock.DataExportFileFunc(ctx, token)
+}
+
+// DataExportFileCalls gets all the calls that were made to DataExportFile.
+// Check the length with:
+//
+//	len(mockedService.DataExportFileCalls())
+func (mock *ServiceMock) DataExportFileCalls() []struct {
+	Ctx   context.Context
+	Token string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Token string
+	}
+	mock.lockDataExportFile.RLock()
+	calls = mock.calls.DataExportFile
+	mock.lockDataExportFile.RUnlock()
+	return calls
+}
+
+// DeleteComment calls DeleteCommentFunc.
+func (mock *ServiceMock) DeleteComment(ctx context.Context, commentID string) error {
+	callInfo := struct {
+		Ctx       context.Context
+		CommentID string
+	}{
+		Ctx:       ctx,
+		CommentID: commentID,
+	}
+	mock.lockDeleteComment.Lock()
+	mock.calls.DeleteComment = append(mock.calls.DeleteComment, callInfo)
+	mock.lockDeleteComment.Unlock()
+	if mock.DeleteCommentFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteCommentFunc(ctx, commentID)
+}
+
+// DeleteCommentCalls gets all the calls that were made to DeleteComment.
+// Check the length with:
+//
+//	len(mockedService.DeleteCommentCalls())
+func (mock *ServiceMock) DeleteCommentCalls() []struct {
 	Ctx       context.Context
 	CommentID string
 } {
@@ -958,6 +2486,123 @@ func (mock *ServiceMock) DeleteCommentCalls() []struct {
 	return calls
 }
 
+// DeleteMute calls DeleteMuteFunc.
+func (mock *ServiceMock) DeleteMute(ctx context.Context, muteID string) error {
+	callInfo := struct {
+		Ctx    context.Context
+		MuteID string
+	}{
+		Ctx:    ctx,
+		MuteID: muteID,
+	}
+	mock.lockDeleteMute.Lock()
+	mock.calls.DeleteMute = append(mock.calls.DeleteMute, callInfo)
+	mock.lockDeleteMute.Unlock()
+	if mock.DeleteMuteFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteMuteFunc(ctx, muteID)
+}
+
+// DeleteMuteCalls gets all the calls that were made to DeleteMute.
+// Check the length with:
+//
+//	len(mockedService.DeleteMuteCalls())
+func (mock *ServiceMock) DeleteMuteCalls() []struct {
+	Ctx    context.Context
+	MuteID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		MuteID string
+	}
+	mock.lockDeleteMute.RLock()
+	calls = mock.calls.DeleteMute
+	mock.lockDeleteMute.RUnlock()
+	return calls
+}
+
+// DeleteOAuthApp calls DeleteOAuthAppFunc.
+func (mock *ServiceMock) DeleteOAuthApp(ctx context.Context, appID string) error {
+	callInfo := struct {
+		Ctx   context.Context
+		AppID string
+	}{
+		Ctx:   ctx,
+		AppID: appID,
+	}
+	mock.lockDeleteOAuthApp.Lock()
+	mock.calls.DeleteOAuthApp = append(mock.calls.DeleteOAuthApp, callInfo)
+	mock.lockDeleteOAuthApp.Unlock()
+	if mock.DeleteOAuthAppFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteOAuthAppFunc(ctx, appID)
+}
+
+// DeleteOAuthAppCalls gets all the calls that were made to DeleteOAuthApp.
+// Check the length with:
+//
+//	len(mockedService.DeleteOAuthAppCalls())
+func (mock *ServiceMock) DeleteOAuthAppCalls() []struct {
+	Ctx   context.Context
+	AppID string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		AppID string
+	}
+	mock.lockDeleteOAuthApp.RLock()
+	calls = mock.calls.DeleteOAuthApp
+	mock.lockDeleteOAuthApp.RUnlock()
+	return calls
+}
+
+// DeletePasskey calls DeletePasskeyFunc.
+func (mock *ServiceMock) DeletePasskey(ctx context.Context, passkeyID string) error {
+	callInfo := struct {
+		Ctx       context.Context
+		PasskeyID string
+	}{
+		Ctx:       ctx,
+		PasskeyID: passkeyID,
+	}
+	mock.lockDeletePasskey.Lock()
+	mock.calls.DeletePasskey = append(mock.calls.DeletePasskey, callInfo)
+	mock.lockDeletePasskey.Unlock()
+	if mock.DeletePasskeyFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeletePasskeyFunc(ctx, passkeyID)
+}
+
+// DeletePasskeyCalls gets all the calls that were made to DeletePasskey.
+// Check the length with:
+//
+//	len(mockedService.DeletePasskeyCalls())
+func (mock *ServiceMock) DeletePasskeyCalls() []struct {
+	Ctx       context.Context
+	PasskeyID string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		PasskeyID string
+	}
+	mock.lockDeletePasskey.RLock()
+	calls = mock.calls.DeletePasskey
+	mock.lockDeletePasskey.RUnlock()
+	return calls
+}
+
 // DeletePost calls DeletePostFunc.
 func (mock *ServiceMock) DeletePost(ctx context.Context, postID string) error {
 	callInfo := struct {
@@ -997,572 +2642,2059 @@ func (mock *ServiceMock) DeletePostCalls() []struct {
 	return calls
 }
 
-// DeleteTimelineItem calls DeleteTimelineItemFunc.
-func (mock *ServiceMock) DeleteTimelineItem(ctx context.Context, timelineItemID string) error {
+// DeleteTimelineItem calls DeleteTimelineItemFunc.
+func (mock *ServiceMock) DeleteTimelineItem(ctx context.Context, timelineItemID string) error {
+	callInfo := struct {
+		Ctx            context.Context
+		TimelineItemID string
+	}{
+		Ctx:            ctx,
+		TimelineItemID: timelineItemID,
+	}
+	mock.lockDeleteTimelineItem.Lock()
+	mock.calls.DeleteTimelineItem = append(mock.calls.DeleteTimelineItem, callInfo)
+	mock.lockDeleteTimelineItem.Unlock()
+	if mock.DeleteTimelineItemFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteTimelineItemFunc(ctx, timelineItemID)
+}
+
+// DeleteTimelineItemCalls gets all the calls that were made to DeleteTimelineItem.
+// Check the length with:
+//
+//	len(mockedService.DeleteTimelineItemCalls())
+func (mock *ServiceMock) DeleteTimelineItemCalls() []struct {
+	Ctx            context.Context
+	TimelineItemID string
+} {
+	var calls []struct {
+		Ctx            context.Context
+		TimelineItemID string
+	}
+	mock.lockDeleteTimelineItem.RLock()
+	calls = mock.calls.DeleteTimelineItem
+	mock.lockDeleteTimelineItem.RUnlock()
+	return calls
+}
+
+// DeleteUserList calls DeleteUserListFunc.
+func (mock *ServiceMock) DeleteUserList(ctx context.Context, listID string) error {
+	callInfo := struct {
+		Ctx    context.Context
+		ListID string
+	}{
+		Ctx:    ctx,
+		ListID: listID,
+	}
+	mock.lockDeleteUserList.Lock()
+	mock.calls.DeleteUserList = append(mock.calls.DeleteUserList, callInfo)
+	mock.lockDeleteUserList.Unlock()
+	if mock.DeleteUserListFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DeleteUserListFunc(ctx, listID)
+}
+
+// DeleteUserListCalls gets all the calls that were made to DeleteUserList.
+// Check the length with:
+//
+//	len(mockedService.DeleteUserListCalls())
+func (mock *ServiceMock) DeleteUserListCalls() []struct {
+	Ctx    context.Context
+	ListID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ListID string
+	}
+	mock.lockDeleteUserList.RLock()
+	calls = mock.calls.DeleteUserList
+	mock.lockDeleteUserList.RUnlock()
+	return calls
+}
+
+// DenyFollowRequest calls DenyFollowRequestFunc.
+func (mock *ServiceMock) DenyFollowRequest(ctx context.Context, username string) error {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockDenyFollowRequest.Lock()
+	mock.calls.DenyFollowRequest = append(mock.calls.DenyFollowRequest, callInfo)
+	mock.lockDenyFollowRequest.Unlock()
+	if mock.DenyFollowRequestFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DenyFollowRequestFunc(ctx, username)
+}
+
+// DenyFollowRequestCalls gets all the calls that were made to DenyFollowRequest.
+// Check the length with:
+//
+//	len(mockedService.DenyFollowRequestCalls())
+func (mock *ServiceMock) DenyFollowRequestCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockDenyFollowRequest.RLock()
+	calls = mock.calls.DenyFollowRequest
+	mock.lockDenyFollowRequest.RUnlock()
+	return calls
+}
+
+// DevLogin calls DevLoginFunc.
+func (mock *ServiceMock) DevLogin(ctx context.Context, email string) (nakama.AuthOutput, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+	}{
+		Ctx:   ctx,
+		Email: email,
+	}
+	mock.lockDevLogin.Lock()
+	mock.calls.DevLogin = append(mock.calls.DevLogin, callInfo)
+	mock.lockDevLogin.Unlock()
+	if mock.DevLoginFunc == nil {
+		var (
+			authOutputOut nakama.AuthOutput
+			errOut        error
+		)
+		return authOutputOut, errOut
+	}
+	return mock.DevLoginFunc(ctx, email)
+}
+
+// DevLoginCalls gets all the calls that were made to DevLogin.
+// Check the length with:
+//
+//	len(mockedService.DevLoginCalls())
+func (mock *ServiceMock) DevLoginCalls() []struct {
+	Ctx   context.Context
+	Email string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+	}
+	mock.lockDevLogin.RLock()
+	calls = mock.calls.DevLogin
+	mock.lockDevLogin.RUnlock()
+	return calls
+}
+
+// DisableTOTP calls DisableTOTPFunc.
+func (mock *ServiceMock) DisableTOTP(ctx context.Context, code string) error {
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+	}{
+		Ctx:  ctx,
+		Code: code,
+	}
+	mock.lockDisableTOTP.Lock()
+	mock.calls.DisableTOTP = append(mock.calls.DisableTOTP, callInfo)
+	mock.lockDisableTOTP.Unlock()
+	if mock.DisableTOTPFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DisableTOTPFunc(ctx, code)
+}
+
+// DisableTOTPCalls gets all the calls that were made to DisableTOTP.
+// Check the length with:
+//
+//	len(mockedService.DisableTOTPCalls())
+func (mock *ServiceMock) DisableTOTPCalls() []struct {
+	Ctx  context.Context
+	Code string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+	}
+	mock.lockDisableTOTP.RLock()
+	calls = mock.calls.DisableTOTP
+	mock.lockDisableTOTP.RUnlock()
+	return calls
+}
+
+// EnableTOTP calls EnableTOTPFunc.
+func (mock *ServiceMock) EnableTOTP(ctx context.Context, code string) ([]string, error) {
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+	}{
+		Ctx:  ctx,
+		Code: code,
+	}
+	mock.lockEnableTOTP.Lock()
+	mock.calls.EnableTOTP = append(mock.calls.EnableTOTP, callInfo)
+	mock.lockEnableTOTP.Unlock()
+	if mock.EnableTOTPFunc == nil {
+		var (
+			stringsOut []string
+			errOut     error
+		)
+		return stringsOut, errOut
+	}
+	return mock.EnableTOTPFunc(ctx, code)
+}
+
+// EnableTOTPCalls gets all the calls that were made to EnableTOTP.
+// Check the length with:
+//
+//	len(mockedService.EnableTOTPCalls())
+func (mock *ServiceMock) EnableTOTPCalls() []struct {
+	Ctx  context.Context
+	Code string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+	}
+	mock.lockEnableTOTP.RLock()
+	calls = mock.calls.EnableTOTP
+	mock.lockEnableTOTP.RUnlock()
+	return calls
+}
+
+// ExchangeOAuthCode calls ExchangeOAuthCodeFunc.
+func (mock *ServiceMock) ExchangeOAuthCode(ctx context.Context, in nakama.ExchangeOAuthCode) (nakama.OAuthToken, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.ExchangeOAuthCode
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockExchangeOAuthCode.Lock()
+	mock.calls.ExchangeOAuthCode = append(mock.calls.ExchangeOAuthCode, callInfo)
+	mock.lockExchangeOAuthCode.Unlock()
+	if mock.ExchangeOAuthCodeFunc == nil {
+		var (
+			oAuthTokenOut nakama.OAuthToken
+			errOut        error
+		)
+		return oAuthTokenOut, errOut
+	}
+	return mock.ExchangeOAuthCodeFunc(ctx, in)
+}
+
+// ExchangeOAuthCodeCalls gets all the calls that were made to ExchangeOAuthCode.
+// Check the length with:
+//
+//	len(mockedService.ExchangeOAuthCodeCalls())
+func (mock *ServiceMock) ExchangeOAuthCodeCalls() []struct {
+	Ctx context.Context
+	In  nakama.ExchangeOAuthCode
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.ExchangeOAuthCode
+	}
+	mock.lockExchangeOAuthCode.RLock()
+	calls = mock.calls.ExchangeOAuthCode
+	mock.lockExchangeOAuthCode.RUnlock()
+	return calls
+}
+
+// FinishPasskeyLogin calls FinishPasskeyLoginFunc.
+func (mock *ServiceMock) FinishPasskeyLogin(ctx context.Context, in nakama.FinishPasskeyLogin) (nakama.AuthOutput, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.FinishPasskeyLogin
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockFinishPasskeyLogin.Lock()
+	mock.calls.FinishPasskeyLogin = append(mock.calls.FinishPasskeyLogin, callInfo)
+	mock.lockFinishPasskeyLogin.Unlock()
+	if mock.FinishPasskeyLoginFunc == nil {
+		var (
+			authOutputOut nakama.AuthOutput
+			errOut        error
+		)
+		return authOutputOut, errOut
+	}
+	return mock.FinishPasskeyLoginFunc(ctx, in)
+}
+
+// FinishPasskeyLoginCalls gets all the calls that were made to FinishPasskeyLogin.
+// Check the length with:
+//
+//	len(mockedService.FinishPasskeyLoginCalls())
+func (mock *ServiceMock) FinishPasskeyLoginCalls() []struct {
+	Ctx context.Context
+	In  nakama.FinishPasskeyLogin
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.FinishPasskeyLogin
+	}
+	mock.lockFinishPasskeyLogin.RLock()
+	calls = mock.calls.FinishPasskeyLogin
+	mock.lockFinishPasskeyLogin.RUnlock()
+	return calls
+}
+
+// FinishPasskeyRegistration calls FinishPasskeyRegistrationFunc.
+func (mock *ServiceMock) FinishPasskeyRegistration(ctx context.Context, in nakama.FinishPasskeyRegistration) (nakama.Passkey, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.FinishPasskeyRegistration
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockFinishPasskeyRegistration.Lock()
+	mock.calls.FinishPasskeyRegistration = append(mock.calls.FinishPasskeyRegistration, callInfo)
+	mock.lockFinishPasskeyRegistration.Unlock()
+	if mock.FinishPasskeyRegistrationFunc == nil {
+		var (
+			passkeyOut nakama.Passkey
+			errOut     error
+		)
+		return passkeyOut, errOut
+	}
+	return mock.FinishPasskeyRegistrationFunc(ctx, in)
+}
+
+// FinishPasskeyRegistrationCalls gets all the calls that were made to FinishPasskeyRegistration.
+// Check the length with:
+//
+//	len(mockedService.FinishPasskeyRegistrationCalls())
+func (mock *ServiceMock) FinishPasskeyRegistrationCalls() []struct {
+	Ctx context.Context
+	In  nakama.FinishPasskeyRegistration
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.FinishPasskeyRegistration
+	}
+	mock.lockFinishPasskeyRegistration.RLock()
+	calls = mock.calls.FinishPasskeyRegistration
+	mock.lockFinishPasskeyRegistration.RUnlock()
+	return calls
+}
+
+// FollowRequests calls FollowRequestsFunc.
+func (mock *ServiceMock) FollowRequests(ctx context.Context, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		First uint64
+		After *string
+	}{
+		Ctx:   ctx,
+		First: first,
+		After: after,
+	}
+	mock.lockFollowRequests.Lock()
+	mock.calls.FollowRequests = append(mock.calls.FollowRequests, callInfo)
+	mock.lockFollowRequests.Unlock()
+	if mock.FollowRequestsFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.FollowRequestsFunc(ctx, first, after)
+}
+
+// FollowRequestsCalls gets all the calls that were made to FollowRequests.
+// Check the length with:
+//
+//	len(mockedService.FollowRequestsCalls())
+func (mock *ServiceMock) FollowRequestsCalls() []struct {
+	Ctx   context.Context
+	First uint64
+	After *string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		First uint64
+		After *string
+	}
+	mock.lockFollowRequests.RLock()
+	calls = mock.calls.FollowRequests
+	mock.lockFollowRequests.RUnlock()
+	return calls
+}
+
+// Followees calls FolloweesFunc.
+func (mock *ServiceMock) Followees(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		First:    first,
+		After:    after,
+	}
+	mock.lockFollowees.Lock()
+	mock.calls.Followees = append(mock.calls.Followees, callInfo)
+	mock.lockFollowees.Unlock()
+	if mock.FolloweesFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.FolloweesFunc(ctx, username, first, after)
+}
+
+// FolloweesCalls gets all the calls that were made to Followees.
+// Check the length with:
+//
+//	len(mockedService.FolloweesCalls())
+func (mock *ServiceMock) FolloweesCalls() []struct {
+	Ctx      context.Context
+	Username string
+	First    uint64
+	After    *string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}
+	mock.lockFollowees.RLock()
+	calls = mock.calls.Followees
+	mock.lockFollowees.RUnlock()
+	return calls
+}
+
+// Followers calls FollowersFunc.
+func (mock *ServiceMock) Followers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		First:    first,
+		After:    after,
+	}
+	mock.lockFollowers.Lock()
+	mock.calls.Followers = append(mock.calls.Followers, callInfo)
+	mock.lockFollowers.Unlock()
+	if mock.FollowersFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.FollowersFunc(ctx, username, first, after)
+}
+
+// FollowersCalls gets all the calls that were made to Followers.
+// Check the length with:
+//
+//	len(mockedService.FollowersCalls())
+func (mock *ServiceMock) FollowersCalls() []struct {
+	Ctx      context.Context
+	Username string
+	First    uint64
+	After    *string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}
+	mock.lockFollowers.RLock()
+	calls = mock.calls.Followers
+	mock.lockFollowers.RUnlock()
+	return calls
+}
+
+// HasUnreadNotifications calls HasUnreadNotificationsFunc.
+func (mock *ServiceMock) HasUnreadNotifications(ctx context.Context) (bool, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockHasUnreadNotifications.Lock()
+	mock.calls.HasUnreadNotifications = append(mock.calls.HasUnreadNotifications, callInfo)
+	mock.lockHasUnreadNotifications.Unlock()
+	if mock.HasUnreadNotificationsFunc == nil {
+		var (
+			bOut   bool
+			errOut error
+		)
+		return bOut, errOut
+	}
+	return mock.HasUnreadNotificationsFunc(ctx)
+}
+
+// HasUnreadNotificationsCalls gets all the calls that were made to HasUnreadNotifications.
+// Check the length with:
+//
+//	len(mockedService.HasUnreadNotificationsCalls())
+func (mock *ServiceMock) HasUnreadNotificationsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockHasUnreadNotifications.RLock()
+	calls = mock.calls.HasUnreadNotifications
+	mock.lockHasUnreadNotifications.RUnlock()
+	return calls
+}
+
+// Identities calls IdentitiesFunc.
+func (mock *ServiceMock) Identities(ctx context.Context) ([]nakama.UserIdentity, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockIdentities.Lock()
+	mock.calls.Identities = append(mock.calls.Identities, callInfo)
+	mock.lockIdentities.Unlock()
+	if mock.IdentitiesFunc == nil {
+		var (
+			userIdentitysOut []nakama.UserIdentity
+			errOut           error
+		)
+		return userIdentitysOut, errOut
+	}
+	return mock.IdentitiesFunc(ctx)
+}
+
+// IdentitiesCalls gets all the calls that were made to Identities.
+// Check the length with:
+//
+//	len(mockedService.IdentitiesCalls())
+func (mock *ServiceMock) IdentitiesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockIdentities.RLock()
+	calls = mock.calls.Identities
+	mock.lockIdentities.RUnlock()
+	return calls
+}
+
+// IntrospectOAuthToken calls IntrospectOAuthTokenFunc.
+func (mock *ServiceMock) IntrospectOAuthToken(ctx context.Context, clientID string, clientSecret string, token string) (nakama.OAuthTokenIntrospection, error) {
+	callInfo := struct {
+		Ctx          context.Context
+		ClientID     string
+		ClientSecret string
+		Token        string
+	}{
+		Ctx:          ctx,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Token:        token,
+	}
+	mock.lockIntrospectOAuthToken.Lock()
+	mock.calls.IntrospectOAuthToken = append(mock.calls.IntrospectOAuthToken, callInfo)
+	mock.lockIntrospectOAuthToken.Unlock()
+	if mock.IntrospectOAuthTokenFunc == nil {
+		var (
+			oAuthTokenIntrospectionOut nakama.OAuthTokenIntrospection
+			errOut                     error
+		)
+		return oAuthTokenIntrospectionOut, errOut
+	}
+	return mock.IntrospectOAuthTokenFunc(ctx, clientID, clientSecret, token)
+}
+
+// IntrospectOAuthTokenCalls gets all the calls that were made to IntrospectOAuthToken.
+// Check the length with:
+//
+//	len(mockedService.IntrospectOAuthTokenCalls())
+func (mock *ServiceMock) IntrospectOAuthTokenCalls() []struct {
+	Ctx          context.Context
+	ClientID     string
+	ClientSecret string
+	Token        string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ClientID     string
+		ClientSecret string
+		Token        string
+	}
+	mock.lockIntrospectOAuthToken.RLock()
+	calls = mock.calls.IntrospectOAuthToken
+	mock.lockIntrospectOAuthToken.RUnlock()
+	return calls
+}
+
+// LinkIdentity calls LinkIdentityFunc.
+func (mock *ServiceMock) LinkIdentity(ctx context.Context, provider string, user nakama.ProvidedUser) error {
+	callInfo := struct {
+		Ctx      context.Context
+		Provider string
+		User     nakama.ProvidedUser
+	}{
+		Ctx:      ctx,
+		Provider: provider,
+		User:     user,
+	}
+	mock.lockLinkIdentity.Lock()
+	mock.calls.LinkIdentity = append(mock.calls.LinkIdentity, callInfo)
+	mock.lockLinkIdentity.Unlock()
+	if mock.LinkIdentityFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.LinkIdentityFunc(ctx, provider, user)
+}
+
+// LinkIdentityCalls gets all the calls that were made to LinkIdentity.
+// Check the length with:
+//
+//	len(mockedService.LinkIdentityCalls())
+func (mock *ServiceMock) LinkIdentityCalls() []struct {
+	Ctx      context.Context
+	Provider string
+	User     nakama.ProvidedUser
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Provider string
+		User     nakama.ProvidedUser
+	}
+	mock.lockLinkIdentity.RLock()
+	calls = mock.calls.LinkIdentity
+	mock.lockLinkIdentity.RUnlock()
+	return calls
+}
+
+// ListTimeline calls ListTimelineFunc.
+func (mock *ServiceMock) ListTimeline(ctx context.Context, listID string, last uint64, before *string) (nakama.Posts, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		ListID string
+		Last   uint64
+		Before *string
+	}{
+		Ctx:    ctx,
+		ListID: listID,
+		Last:   last,
+		Before: before,
+	}
+	mock.lockListTimeline.Lock()
+	mock.calls.ListTimeline = append(mock.calls.ListTimeline, callInfo)
+	mock.lockListTimeline.Unlock()
+	if mock.ListTimelineFunc == nil {
+		var (
+			postsOut nakama.Posts
+			errOut   error
+		)
+		return postsOut, errOut
+	}
+	return mock.ListTimelineFunc(ctx, listID, last, before)
+}
+
+// ListTimelineCalls gets all the calls that were made to ListTimeline.
+// Check the length with:
+//
+//	len(mockedService.ListTimelineCalls())
+func (mock *ServiceMock) ListTimelineCalls() []struct {
+	Ctx    context.Context
+	ListID string
+	Last   uint64
+	Before *string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ListID string
+		Last   uint64
+		Before *string
+	}
+	mock.lockListTimeline.RLock()
+	calls = mock.calls.ListTimeline
+	mock.lockListTimeline.RUnlock()
+	return calls
+}
+
+// ListTimelineStream calls ListTimelineStreamFunc.
+func (mock *ServiceMock) ListTimelineStream(ctx context.Context, listID string) (<-chan nakama.Post, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		ListID string
+	}{
+		Ctx:    ctx,
+		ListID: listID,
+	}
+	mock.lockListTimelineStream.Lock()
+	mock.calls.ListTimelineStream = append(mock.calls.ListTimelineStream, callInfo)
+	mock.lockListTimelineStream.Unlock()
+	if mock.ListTimelineStreamFunc == nil {
+		var (
+			postChOut <-chan nakama.Post
+			errOut    error
+		)
+		return postChOut, errOut
+	}
+	return mock.ListTimelineStreamFunc(ctx, listID)
+}
+
+// ListTimelineStreamCalls gets all the calls that were made to ListTimelineStream.
+// Check the length with:
+//
+//	len(mockedService.ListTimelineStreamCalls())
+func (mock *ServiceMock) ListTimelineStreamCalls() []struct {
+	Ctx    context.Context
+	ListID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ListID string
+	}
+	mock.lockListTimelineStream.RLock()
+	calls = mock.calls.ListTimelineStream
+	mock.lockListTimelineStream.RUnlock()
+	return calls
+}
+
+// LoginFromProvider calls LoginFromProviderFunc.
+func (mock *ServiceMock) LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.AuthOutput, error) {
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+		User nakama.ProvidedUser
+	}{
+		Ctx:  ctx,
+		Name: name,
+		User: user,
+	}
+	mock.lockLoginFromProvider.Lock()
+	mock.calls.LoginFromProvider = append(mock.calls.LoginFromProvider, callInfo)
+	mock.lockLoginFromProvider.Unlock()
+	if mock.LoginFromProviderFunc == nil {
+		var (
+			authOutputOut nakama.AuthOutput
+			errOut        error
+		)
+		return authOutputOut, errOut
+	}
+	return mock.LoginFromProviderFunc(ctx, name, user)
+}
+
+// LoginFromProviderCalls gets all the calls that were made to LoginFromProvider.
+// Check the length with:
+//
+//	len(mockedService.LoginFromProviderCalls())
+func (mock *ServiceMock) LoginFromProviderCalls() []struct {
+	Ctx  context.Context
+	Name string
+	User nakama.ProvidedUser
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+		User nakama.ProvidedUser
+	}
+	mock.lockLoginFromProvider.RLock()
+	calls = mock.calls.LoginFromProvider
+	mock.lockLoginFromProvider.RUnlock()
+	return calls
+}
+
+// Logout calls LogoutFunc.
+func (mock *ServiceMock) Logout(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockLogout.Lock()
+	mock.calls.Logout = append(mock.calls.Logout, callInfo)
+	mock.lockLogout.Unlock()
+	if mock.LogoutFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.LogoutFunc(ctx)
+}
+
+// LogoutCalls gets all the calls that were made to Logout.
+// Check the length with:
+//
+//	len(mockedService.LogoutCalls())
+func (mock *ServiceMock) LogoutCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockLogout.RLock()
+	calls = mock.calls.Logout
+	mock.lockLogout.RUnlock()
+	return calls
+}
+
+// LogoutEverywhere calls LogoutEverywhereFunc.
+func (mock *ServiceMock) LogoutEverywhere(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockLogoutEverywhere.Lock()
+	mock.calls.LogoutEverywhere = append(mock.calls.LogoutEverywhere, callInfo)
+	mock.lockLogoutEverywhere.Unlock()
+	if mock.LogoutEverywhereFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.LogoutEverywhereFunc(ctx)
+}
+
+// LogoutEverywhereCalls gets all the calls that were made to LogoutEverywhere.
+// Check the length with:
+//
+//	len(mockedService.LogoutEverywhereCalls())
+func (mock *ServiceMock) LogoutEverywhereCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockLogoutEverywhere.RLock()
+	calls = mock.calls.LogoutEverywhere
+	mock.lockLogoutEverywhere.RUnlock()
+	return calls
+}
+
+// MarkNotificationAsRead calls MarkNotificationAsReadFunc.
+func (mock *ServiceMock) MarkNotificationAsRead(ctx context.Context, notificationID string) error {
+	callInfo := struct {
+		Ctx            context.Context
+		NotificationID string
+	}{
+		Ctx:            ctx,
+		NotificationID: notificationID,
+	}
+	mock.lockMarkNotificationAsRead.Lock()
+	mock.calls.MarkNotificationAsRead = append(mock.calls.MarkNotificationAsRead, callInfo)
+	mock.lockMarkNotificationAsRead.Unlock()
+	if mock.MarkNotificationAsReadFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.MarkNotificationAsReadFunc(ctx, notificationID)
+}
+
+// MarkNotificationAsReadCalls gets all the calls that were made to MarkNotificationAsRead.
+// Check the length with:
+//
+//	len(mockedService.MarkNotificationAsReadCalls())
+func (mock *ServiceMock) MarkNotificationAsReadCalls() []struct {
+	Ctx            context.Context
+	NotificationID string
+} {
+	var calls []struct {
+		Ctx            context.Context
+		NotificationID string
+	}
+	mock.lockMarkNotificationAsRead.RLock()
+	calls = mock.calls.MarkNotificationAsRead
+	mock.lockMarkNotificationAsRead.RUnlock()
+	return calls
+}
+
+// MarkNotificationsAsRead calls MarkNotificationsAsReadFunc.
+func (mock *ServiceMock) MarkNotificationsAsRead(ctx context.Context) error {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockMarkNotificationsAsRead.Lock()
+	mock.calls.MarkNotificationsAsRead = append(mock.calls.MarkNotificationsAsRead, callInfo)
+	mock.lockMarkNotificationsAsRead.Unlock()
+	if mock.MarkNotificationsAsReadFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.MarkNotificationsAsReadFunc(ctx)
+}
+
+// MarkNotificationsAsReadCalls gets all the calls that were made to MarkNotificationsAsRead.
+// Check the length with:
+//
+//	len(mockedService.MarkNotificationsAsReadCalls())
+func (mock *ServiceMock) MarkNotificationsAsReadCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockMarkNotificationsAsRead.RLock()
+	calls = mock.calls.MarkNotificationsAsRead
+	mock.lockMarkNotificationsAsRead.RUnlock()
+	return calls
+}
+
+// Mutes calls MutesFunc.
+func (mock *ServiceMock) Mutes(ctx context.Context) ([]nakama.Mute, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockMutes.Lock()
+	mock.calls.Mutes = append(mock.calls.Mutes, callInfo)
+	mock.lockMutes.Unlock()
+	if mock.MutesFunc == nil {
+		var (
+			mutesOut []nakama.Mute
+			errOut   error
+		)
+		return mutesOut, errOut
+	}
+	return mock.MutesFunc(ctx)
+}
+
+// MutesCalls gets all the calls that were made to Mutes.
+// Check the length with:
+//
+//	len(mockedService.MutesCalls())
+func (mock *ServiceMock) MutesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockMutes.RLock()
+	calls = mock.calls.Mutes
+	mock.lockMutes.RUnlock()
+	return calls
+}
+
+// MutualFollowers calls MutualFollowersFunc.
+func (mock *ServiceMock) MutualFollowers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		First:    first,
+		After:    after,
+	}
+	mock.lockMutualFollowers.Lock()
+	mock.calls.MutualFollowers = append(mock.calls.MutualFollowers, callInfo)
+	mock.lockMutualFollowers.Unlock()
+	if mock.MutualFollowersFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.MutualFollowersFunc(ctx, username, first, after)
+}
+
+// MutualFollowersCalls gets all the calls that were made to MutualFollowers.
+// Check the length with:
+//
+//	len(mockedService.MutualFollowersCalls())
+func (mock *ServiceMock) MutualFollowersCalls() []struct {
+	Ctx      context.Context
+	Username string
+	First    uint64
+	After    *string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		First    uint64
+		After    *string
+	}
+	mock.lockMutualFollowers.RLock()
+	calls = mock.calls.MutualFollowers
+	mock.lockMutualFollowers.RUnlock()
+	return calls
+}
+
+// NotificationStream calls NotificationStreamFunc.
+func (mock *ServiceMock) NotificationStream(ctx context.Context) (<-chan nakama.Notification, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockNotificationStream.Lock()
+	mock.calls.NotificationStream = append(mock.calls.NotificationStream, callInfo)
+	mock.lockNotificationStream.Unlock()
+	if mock.NotificationStreamFunc == nil {
+		var (
+			notificationChOut <-chan nakama.Notification
+			errOut            error
+		)
+		return notificationChOut, errOut
+	}
+	return mock.NotificationStreamFunc(ctx)
+}
+
+// NotificationStreamCalls gets all the calls that were made to NotificationStream.
+// Check the length with:
+//
+//	len(mockedService.NotificationStreamCalls())
+func (mock *ServiceMock) NotificationStreamCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockNotificationStream.RLock()
+	calls = mock.calls.NotificationStream
+	mock.lockNotificationStream.RUnlock()
+	return calls
+}
+
+// Notifications calls NotificationsFunc.
+func (mock *ServiceMock) Notifications(ctx context.Context, last uint64, before *string) (nakama.Notifications, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		Last   uint64
+		Before *string
+	}{
+		Ctx:    ctx,
+		Last:   last,
+		Before: before,
+	}
+	mock.lockNotifications.Lock()
+	mock.calls.Notifications = append(mock.calls.Notifications, callInfo)
+	mock.lockNotifications.Unlock()
+	if mock.NotificationsFunc == nil {
+		var (
+			notificationsOut nakama.Notifications
+			errOut           error
+		)
+		return notificationsOut, errOut
+	}
+	return mock.NotificationsFunc(ctx, last, before)
+}
+
+// NotificationsCalls gets all the calls that were made to Notifications.
+// Check the length with:
+//
+//	len(mockedService.NotificationsCalls())
+func (mock *ServiceMock) NotificationsCalls() []struct {
+	Ctx    context.Context
+	Last   uint64
+	Before *string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Last   uint64
+		Before *string
+	}
+	mock.lockNotifications.RLock()
+	calls = mock.calls.Notifications
+	mock.lockNotifications.RUnlock()
+	return calls
+}
+
+// OAuthApps calls OAuthAppsFunc.
+func (mock *ServiceMock) OAuthApps(ctx context.Context) ([]nakama.OAuthApp, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockOAuthApps.Lock()
+	mock.calls.OAuthApps = append(mock.calls.OAuthApps, callInfo)
+	mock.lockOAuthApps.Unlock()
+	if mock.OAuthAppsFunc == nil {
+		var (
+			oAuthAppsOut []nakama.OAuthApp
+			errOut       error
+		)
+		return oAuthAppsOut, errOut
+	}
+	return mock.OAuthAppsFunc(ctx)
+}
+
+// OAuthAppsCalls gets all the calls that were made to OAuthApps.
+// Check the length with:
+//
+//	len(mockedService.OAuthAppsCalls())
+func (mock *ServiceMock) OAuthAppsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockOAuthApps.RLock()
+	calls = mock.calls.OAuthApps
+	mock.lockOAuthApps.RUnlock()
+	return calls
+}
+
+// OAuthAuthorization calls OAuthAuthorizationFunc.
+func (mock *ServiceMock) OAuthAuthorization(ctx context.Context, in nakama.OAuthAuthorizationRequest) (nakama.OAuthAuthorization, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.OAuthAuthorizationRequest
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockOAuthAuthorization.Lock()
+	mock.calls.OAuthAuthorization = append(mock.calls.OAuthAuthorization, callInfo)
+	mock.lockOAuthAuthorization.Unlock()
+	if mock.OAuthAuthorizationFunc == nil {
+		var (
+			oAuthAuthorizationOut nakama.OAuthAuthorization
+			errOut                error
+		)
+		return oAuthAuthorizationOut, errOut
+	}
+	return mock.OAuthAuthorizationFunc(ctx, in)
+}
+
+// OAuthAuthorizationCalls gets all the calls that were made to OAuthAuthorization.
+// Check the length with:
+//
+//	len(mockedService.OAuthAuthorizationCalls())
+func (mock *ServiceMock) OAuthAuthorizationCalls() []struct {
+	Ctx context.Context
+	In  nakama.OAuthAuthorizationRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.OAuthAuthorizationRequest
+	}
+	mock.lockOAuthAuthorization.RLock()
+	calls = mock.calls.OAuthAuthorization
+	mock.lockOAuthAuthorization.RUnlock()
+	return calls
+}
+
+// ParseRedirectURI calls ParseRedirectURIFunc.
+func (mock *ServiceMock) ParseRedirectURI(rawurl string) (*url.URL, error) {
+	callInfo := struct {
+		Rawurl string
+	}{
+		Rawurl: rawurl,
+	}
+	mock.lockParseRedirectURI.Lock()
+	mock.calls.ParseRedirectURI = append(mock.calls.ParseRedirectURI, callInfo)
+	mock.lockParseRedirectURI.Unlock()
+	if mock.ParseRedirectURIFunc == nil {
+		var (
+			uRLOut *url.URL
+			errOut error
+		)
+		return uRLOut, errOut
+	}
+	return mock.ParseRedirectURIFunc(rawurl)
+}
+
+// ParseRedirectURICalls gets all the calls that were made to ParseRedirectURI.
+// Check the length with:
+//
+//	len(mockedService.ParseRedirectURICalls())
+func (mock *ServiceMock) ParseRedirectURICalls() []struct {
+	Rawurl string
+} {
+	var calls []struct {
+		Rawurl string
+	}
+	mock.lockParseRedirectURI.RLock()
+	calls = mock.calls.ParseRedirectURI
+	mock.lockParseRedirectURI.RUnlock()
+	return calls
+}
+
+// Passkeys calls PasskeysFunc.
+func (mock *ServiceMock) Passkeys(ctx context.Context) ([]nakama.Passkey, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPasskeys.Lock()
+	mock.calls.Passkeys = append(mock.calls.Passkeys, callInfo)
+	mock.lockPasskeys.Unlock()
+	if mock.PasskeysFunc == nil {
+		var (
+			passkeysOut []nakama.Passkey
+			errOut      error
+		)
+		return passkeysOut, errOut
+	}
+	return mock.PasskeysFunc(ctx)
+}
+
+// PasskeysCalls gets all the calls that were made to Passkeys.
+// Check the length with:
+//
+//	len(mockedService.PasskeysCalls())
+func (mock *ServiceMock) PasskeysCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPasskeys.RLock()
+	calls = mock.calls.Passkeys
+	mock.lockPasskeys.RUnlock()
+	return calls
+}
+
+// PersonalAccessTokens calls PersonalAccessTokensFunc.
+func (mock *ServiceMock) PersonalAccessTokens(ctx context.Context) ([]nakama.PersonalAccessToken, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPersonalAccessTokens.Lock()
+	mock.calls.PersonalAccessTokens = append(mock.calls.PersonalAccessTokens, callInfo)
+	mock.lockPersonalAccessTokens.Unlock()
+	if mock.PersonalAccessTokensFunc == nil {
+		var (
+			personalAccessTokensOut []nakama.PersonalAccessToken
+			errOut                  error
+		)
+		return personalAccessTokensOut, errOut
+	}
+	return mock.PersonalAccessTokensFunc(ctx)
+}
+
+// PersonalAccessTokensCalls gets all the calls that were made to PersonalAccessTokens.
+// Check the length with:
+//
+//	len(mockedService.PersonalAccessTokensCalls())
+func (mock *ServiceMock) PersonalAccessTokensCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPersonalAccessTokens.RLock()
+	calls = mock.calls.PersonalAccessTokens
+	mock.lockPersonalAccessTokens.RUnlock()
+	return calls
+}
+
+// PinPost calls PinPostFunc.
+func (mock *ServiceMock) PinPost(ctx context.Context, postID string) error {
+	callInfo := struct {
+		Ctx    context.Context
+		PostID string
+	}{
+		Ctx:    ctx,
+		PostID: postID,
+	}
+	mock.lockPinPost.Lock()
+	mock.calls.PinPost = append(mock.calls.PinPost, callInfo)
+	mock.lockPinPost.Unlock()
+	if mock.PinPostFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.PinPostFunc(ctx, postID)
+}
+
+// PinPostCalls gets all the calls that were made to PinPost.
+// Check the length with:
+//
+//	len(mockedService.PinPostCalls())
+func (mock *ServiceMock) PinPostCalls() []struct {
+	Ctx    context.Context
+	PostID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		PostID string
+	}
+	mock.lockPinPost.RLock()
+	calls = mock.calls.PinPost
+	mock.lockPinPost.RUnlock()
+	return calls
+}
+
+// PinnedPosts calls PinnedPostsFunc.
+func (mock *ServiceMock) PinnedPosts(ctx context.Context, username string) (nakama.Posts, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockPinnedPosts.Lock()
+	mock.calls.PinnedPosts = append(mock.calls.PinnedPosts, callInfo)
+	mock.lockPinnedPosts.Unlock()
+	if mock.PinnedPostsFunc == nil {
+		var (
+			postsOut nakama.Posts
+			errOut   error
+		)
+		return postsOut, errOut
+	}
+	return mock.PinnedPostsFunc(ctx, username)
+}
+
+// PinnedPostsCalls gets all the calls that were made to PinnedPosts.
+// Check the length with:
+//
+//	len(mockedService.PinnedPostsCalls())
+func (mock *ServiceMock) PinnedPostsCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockPinnedPosts.RLock()
+	calls = mock.calls.PinnedPosts
+	mock.lockPinnedPosts.RUnlock()
+	return calls
+}
+
+// Post calls PostFunc.
+func (mock *ServiceMock) Post(ctx context.Context, postID string) (nakama.Post, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		PostID string
+	}{
+		Ctx:    ctx,
+		PostID: postID,
+	}
+	mock.lockPost.Lock()
+	mock.calls.Post = append(mock.calls.Post, callInfo)
+	mock.lockPost.Unlock()
+	if mock.PostFunc == nil {
+		var (
+			postOut nakama.Post
+			errOut  error
+		)
+		return postOut, errOut
+	}
+	return mock.PostFunc(ctx, postID)
+}
+
+// PostCalls gets all the calls that were made to Post.
+// Check the length with:
+//
+//	len(mockedService.PostCalls())
+func (mock *ServiceMock) PostCalls() []struct {
+	Ctx    context.Context
+	PostID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		PostID string
+	}
+	mock.lockPost.RLock()
+	calls = mock.calls.Post
+	mock.lockPost.RUnlock()
+	return calls
+}
+
+// PostStream calls PostStreamFunc.
+func (mock *ServiceMock) PostStream(ctx context.Context) (<-chan nakama.Post, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPostStream.Lock()
+	mock.calls.PostStream = append(mock.calls.PostStream, callInfo)
+	mock.lockPostStream.Unlock()
+	if mock.PostStreamFunc == nil {
+		var (
+			postChOut <-chan nakama.Post
+			errOut    error
+		)
+		return postChOut, errOut
+	}
+	return mock.PostStreamFunc(ctx)
+}
+
+// PostStreamCalls gets all the calls that were made to PostStream.
+// Check the length with:
+//
+//	len(mockedService.PostStreamCalls())
+func (mock *ServiceMock) PostStreamCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPostStream.RLock()
+	calls = mock.calls.PostStream
+	mock.lockPostStream.RUnlock()
+	return calls
+}
+
+// Posts calls PostsFunc.
+func (mock *ServiceMock) Posts(ctx context.Context, last uint64, before *string, opts ...nakama.PostsOpt) (nakama.Posts, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		Last   uint64
+		Before *string
+		Opts   []nakama.PostsOpt
+	}{
+		Ctx:    ctx,
+		Last:   last,
+		Before: before,
+		Opts:   opts,
+	}
+	mock.lockPosts.Lock()
+	mock.calls.Posts = append(mock.calls.Posts, callInfo)
+	mock.lockPosts.Unlock()
+	if mock.PostsFunc == nil {
+		var (
+			postsOut nakama.Posts
+			errOut   error
+		)
+		return postsOut, errOut
+	}
+	return mock.PostsFunc(ctx, last, before, opts...)
+}
+
+// PostsCalls gets all the calls that were made to Posts.
+// Check the length with:
+//
+//	len(mockedService.PostsCalls())
+func (mock *ServiceMock) PostsCalls() []struct {
+	Ctx    context.Context
+	Last   uint64
+	Before *string
+	Opts   []nakama.PostsOpt
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Last   uint64
+		Before *string
+		Opts   []nakama.PostsOpt
+	}
+	mock.lockPosts.RLock()
+	calls = mock.calls.Posts
+	mock.lockPosts.RUnlock()
+	return calls
+}
+
+// RefreshToken calls RefreshTokenFunc.
+func (mock *ServiceMock) RefreshToken(ctx context.Context, refreshToken string) (nakama.TokenOutput, error) {
 	callInfo := struct {
-		Ctx            context.Context
-		TimelineItemID string
+		Ctx          context.Context
+		RefreshToken string
 	}{
-		Ctx:            ctx,
-		TimelineItemID: timelineItemID,
+		Ctx:          ctx,
+		RefreshToken: refreshToken,
 	}
-	mock.lockDeleteTimelineItem.Lock()
-	mock.calls.DeleteTimelineItem = append(mock.calls.DeleteTimelineItem, callInfo)
-	mock.lockDeleteTimelineItem.Unlock()
-	if mock.DeleteTimelineItemFunc == nil {
+	mock.lockRefreshToken.Lock()
+	mock.calls.RefreshToken = append(mock.calls.RefreshToken, callInfo)
+	mock.lockRefreshToken.Unlock()
+	if mock.RefreshTokenFunc == nil {
 		var (
-			errOut error
+			tokenOutputOut nakama.TokenOutput
+			errOut         error
 		)
-		return errOut
+		return tokenOutputOut, errOut
 	}
-	return mock.DeleteTimelineItemFunc(ctx, timelineItemID)
+	return mock.RefreshTokenFunc(ctx, refreshToken)
 }
 
-// DeleteTimelineItemCalls gets all the calls that were made to DeleteTimelineItem.
+// RefreshTokenCalls gets all the calls that were made to RefreshToken.
 // Check the length with:
 //
-//	len(mockedService.DeleteTimelineItemCalls())
-func (mock *ServiceMock) DeleteTimelineItemCalls() []struct {
-	Ctx            context.Context
-	TimelineItemID string
+//	len(mockedService.RefreshTokenCalls())
+func (mock *ServiceMock) RefreshTokenCalls() []struct {
+	Ctx          context.Context
+	RefreshToken string
 } {
 	var calls []struct {
-		Ctx            context.Context
-		TimelineItemID string
+		Ctx          context.Context
+		RefreshToken string
 	}
-	mock.lockDeleteTimelineItem.RLock()
-	calls = mock.calls.DeleteTimelineItem
-	mock.lockDeleteTimelineItem.RUnlock()
+	mock.lockRefreshToken.RLock()
+	calls = mock.calls.RefreshToken
+	mock.lockRefreshToken.RUnlock()
 	return calls
 }
 
-// DevLogin calls DevLoginFunc.
-func (mock *ServiceMock) DevLogin(ctx context.Context, email string) (nakama.AuthOutput, error) {
+// RegenerateRecoveryCodes calls RegenerateRecoveryCodesFunc.
+func (mock *ServiceMock) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
 	callInfo := struct {
-		Ctx   context.Context
-		Email string
+		Ctx  context.Context
+		Code string
 	}{
-		Ctx:   ctx,
-		Email: email,
+		Ctx:  ctx,
+		Code: code,
 	}
-	mock.lockDevLogin.Lock()
-	mock.calls.DevLogin = append(mock.calls.DevLogin, callInfo)
-	mock.lockDevLogin.Unlock()
-	if mock.DevLoginFunc == nil {
+	mock.lockRegenerateRecoveryCodes.Lock()
+	mock.calls.RegenerateRecoveryCodes = append(mock.calls.RegenerateRecoveryCodes, callInfo)
+	mock.lockRegenerateRecoveryCodes.Unlock()
+	if mock.RegenerateRecoveryCodesFunc == nil {
 		var (
-			authOutputOut nakama.AuthOutput
-			errOut        error
+			stringsOut []string
+			errOut     error
 		)
-		return authOutputOut, errOut
+		return stringsOut, errOut
 	}
-	return mock.DevLoginFunc(ctx, email)
+	return mock.RegenerateRecoveryCodesFunc(ctx, code)
 }
 
-// DevLoginCalls gets all the calls that were made to DevLogin.
+// RegenerateRecoveryCodesCalls gets all the calls that were made to RegenerateRecoveryCodes.
 // Check the length with:
 //
-//	len(mockedService.DevLoginCalls())
-func (mock *ServiceMock) DevLoginCalls() []struct {
-	Ctx   context.Context
-	Email string
+//	len(mockedService.RegenerateRecoveryCodesCalls())
+func (mock *ServiceMock) RegenerateRecoveryCodesCalls() []struct {
+	Ctx  context.Context
+	Code string
 } {
 	var calls []struct {
-		Ctx   context.Context
-		Email string
+		Ctx  context.Context
+		Code string
 	}
-	mock.lockDevLogin.RLock()
-	calls = mock.calls.DevLogin
-	mock.lockDevLogin.RUnlock()
+	mock.lockRegenerateRecoveryCodes.RLock()
+	calls = mock.calls.RegenerateRecoveryCodes
+	mock.lockRegenerateRecoveryCodes.RUnlock()
 	return calls
 }
 
-// Followees calls FolloweesFunc.
-func (mock *ServiceMock) Followees(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+// RemoveUserListMember calls RemoveUserListMemberFunc.
+func (mock *ServiceMock) RemoveUserListMember(ctx context.Context, listID string, username string) error {
 	callInfo := struct {
 		Ctx      context.Context
+		ListID   string
 		Username string
-		First    uint64
-		After    *string
 	}{
 		Ctx:      ctx,
+		ListID:   listID,
 		Username: username,
-		First:    first,
-		After:    after,
 	}
-	mock.lockFollowees.Lock()
-	mock.calls.Followees = append(mock.calls.Followees, callInfo)
-	mock.lockFollowees.Unlock()
-	if mock.FolloweesFunc == nil {
+	mock.lockRemoveUserListMember.Lock()
+	mock.calls.RemoveUserListMember = append(mock.calls.RemoveUserListMember, callInfo)
+	mock.lockRemoveUserListMember.Unlock()
+	if mock.RemoveUserListMemberFunc == nil {
 		var (
-			userProfilesOut nakama.UserProfiles
-			errOut          error
+			errOut error
 		)
-		return userProfilesOut, errOut
+		return errOut
 	}
-	return mock.FolloweesFunc(ctx, username, first, after)
+	return mock.RemoveUserListMemberFunc(ctx, listID, username)
 }
 
-// FolloweesCalls gets all the calls that were made to Followees.
+// RemoveUserListMemberCalls gets all the calls that were made to RemoveUserListMember.
 // Check the length with:
 //
-//	len(mockedService.FolloweesCalls())
-func (mock *ServiceMock) FolloweesCalls() []struct {
+//	len(mockedService.RemoveUserListMemberCalls())
+func (mock *ServiceMock) RemoveUserListMemberCalls() []struct {
 	Ctx      context.Context
+	ListID   string
 	Username string
-	First    uint64
-	After    *string
 } {
 	var calls []struct {
 		Ctx      context.Context
+		ListID   string
 		Username string
-		First    uint64
-		After    *string
 	}
-	mock.lockFollowees.RLock()
-	calls = mock.calls.Followees
-	mock.lockFollowees.RUnlock()
+	mock.lockRemoveUserListMember.RLock()
+	calls = mock.calls.RemoveUserListMember
+	mock.lockRemoveUserListMember.RUnlock()
 	return calls
 }
 
-// Followers calls FollowersFunc.
-func (mock *ServiceMock) Followers(ctx context.Context, username string, first uint64, after *string) (nakama.UserProfiles, error) {
+// RenamePasskey calls RenamePasskeyFunc.
+func (mock *ServiceMock) RenamePasskey(ctx context.Context, passkeyID string, name string) error {
 	callInfo := struct {
-		Ctx      context.Context
-		Username string
-		First    uint64
-		After    *string
+		Ctx       context.Context
+		PasskeyID string
+		Name      string
 	}{
-		Ctx:      ctx,
-		Username: username,
-		First:    first,
-		After:    after,
+		Ctx:       ctx,
+		PasskeyID: passkeyID,
+		Name:      name,
 	}
-	mock.lockFollowers.Lock()
-	mock.calls.Followers = append(mock.calls.Followers, callInfo)
-	mock.lockFollowers.Unlock()
-	if mock.FollowersFunc == nil {
+	mock.lockRenamePasskey.Lock()
+	mock.calls.RenamePasskey = append(mock.calls.RenamePasskey, callInfo)
+	mock.lockRenamePasskey.Unlock()
+	if mock.RenamePasskeyFunc == nil {
 		var (
-			userProfilesOut nakama.UserProfiles
-			errOut          error
+			errOut error
 		)
-		return userProfilesOut, errOut
+		return errOut
 	}
-	return mock.FollowersFunc(ctx, username, first, after)
+	return mock.RenamePasskeyFunc(ctx, passkeyID, name)
 }
 
-// FollowersCalls gets all the calls that were made to Followers.
+// RenamePasskeyCalls gets all the calls that were made to RenamePasskey.
 // Check the length with:
 //
-//	len(mockedService.FollowersCalls())
-func (mock *ServiceMock) FollowersCalls() []struct {
-	Ctx      context.Context
-	Username string
-	First    uint64
-	After    *string
+//	len(mockedService.RenamePasskeyCalls())
+func (mock *ServiceMock) RenamePasskeyCalls() []struct {
+	Ctx       context.Context
+	PasskeyID string
+	Name      string
 } {
 	var calls []struct {
-		Ctx      context.Context
-		Username string
-		First    uint64
-		After    *string
+		Ctx       context.Context
+		PasskeyID string
+		Name      string
 	}
-	mock.lockFollowers.RLock()
-	calls = mock.calls.Followers
-	mock.lockFollowers.RUnlock()
+	mock.lockRenamePasskey.RLock()
+	calls = mock.calls.RenamePasskey
+	mock.lockRenamePasskey.RUnlock()
 	return calls
 }
 
-// HasUnreadNotifications calls HasUnreadNotificationsFunc.
-func (mock *ServiceMock) HasUnreadNotifications(ctx context.Context) (bool, error) {
+// ReportComment calls ReportCommentFunc.
+func (mock *ServiceMock) ReportComment(ctx context.Context, commentID string, in nakama.ReportInput) error {
 	callInfo := struct {
-		Ctx context.Context
+		Ctx       context.Context
+		CommentID string
+		In        nakama.ReportInput
 	}{
-		Ctx: ctx,
+		Ctx:       ctx,
+		CommentID: commentID,
+		In:        in,
 	}
-	mock.lockHasUnreadNotifications.Lock()
-	mock.calls.HasUnreadNotifications = append(mock.calls.HasUnreadNotifications, callInfo)
-	mock.lockHasUnreadNotifications.Unlock()
-	if mock.HasUnreadNotificationsFunc == nil {
+	mock.lockReportComment.Lock()
+	mock.calls.ReportComment = append(mock.calls.ReportComment, callInfo)
+	mock.lockReportComment.Unlock()
+	if mock.ReportCommentFunc == nil {
 		var (
-			bOut   bool
 			errOut error
 		)
-		return bOut, errOut
+		return errOut
 	}
-	return mock.HasUnreadNotificationsFunc(ctx)
+	return mock.ReportCommentFunc(ctx, commentID, in)
 }
 
-// HasUnreadNotificationsCalls gets all the calls that were made to HasUnreadNotifications.
+// ReportCommentCalls gets all the calls that were made to ReportComment.
 // Check the length with:
 //
-//	len(mockedService.HasUnreadNotificationsCalls())
-func (mock *ServiceMock) HasUnreadNotificationsCalls() []struct {
-	Ctx context.Context
+//	len(mockedService.ReportCommentCalls())
+func (mock *ServiceMock) ReportCommentCalls() []struct {
+	Ctx       context.Context
+	CommentID string
+	In        nakama.ReportInput
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx       context.Context
+		CommentID string
+		In        nakama.ReportInput
 	}
-	mock.lockHasUnreadNotifications.RLock()
-	calls = mock.calls.HasUnreadNotifications
-	mock.lockHasUnreadNotifications.RUnlock()
+	mock.lockReportComment.RLock()
+	calls = mock.calls.ReportComment
+	mock.lockReportComment.RUnlock()
 	return calls
 }
 
-// LoginFromProvider calls LoginFromProviderFunc.
-func (mock *ServiceMock) LoginFromProvider(ctx context.Context, name string, user nakama.ProvidedUser) (nakama.User, error) {
+// ReportPost calls ReportPostFunc.
+func (mock *ServiceMock) ReportPost(ctx context.Context, postID string, in nakama.ReportInput) error {
 	callInfo := struct {
-		Ctx  context.Context
-		Name string
-		User nakama.ProvidedUser
+		Ctx    context.Context
+		PostID string
+		In     nakama.ReportInput
 	}{
-		Ctx:  ctx,
-		Name: name,
-		User: user,
+		Ctx:    ctx,
+		PostID: postID,
+		In:     in,
 	}
-	mock.lockLoginFromProvider.Lock()
-	mock.calls.LoginFromProvider = append(mock.calls.LoginFromProvider, callInfo)
-	mock.lockLoginFromProvider.Unlock()
-	if mock.LoginFromProviderFunc == nil {
+	mock.lockReportPost.Lock()
+	mock.calls.ReportPost = append(mock.calls.ReportPost, callInfo)
+	mock.lockReportPost.Unlock()
+	if mock.ReportPostFunc == nil {
 		var (
-			userOut nakama.User
-			errOut  error
+			errOut error
 		)
-		return userOut, errOut
+		return errOut
 	}
-	return mock.LoginFromProviderFunc(ctx, name, user)
+	return mock.ReportPostFunc(ctx, postID, in)
 }
 
-// LoginFromProviderCalls gets all the calls that were made to LoginFromProvider.
+// ReportPostCalls gets all the calls that were made to ReportPost.
 // Check the length with:
 //
-//	len(mockedService.LoginFromProviderCalls())
-func (mock *ServiceMock) LoginFromProviderCalls() []struct {
-	Ctx  context.Context
-	Name string
-	User nakama.ProvidedUser
+//	len(mockedService.ReportPostCalls())
+func (mock *ServiceMock) ReportPostCalls() []struct {
+	Ctx    context.Context
+	PostID string
+	In     nakama.ReportInput
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Name string
-		User nakama.ProvidedUser
+		Ctx    context.Context
+		PostID string
+		In     nakama.ReportInput
 	}
-	mock.lockLoginFromProvider.RLock()
-	calls = mock.calls.LoginFromProvider
-	mock.lockLoginFromProvider.RUnlock()
+	mock.lockReportPost.RLock()
+	calls = mock.calls.ReportPost
+	mock.lockReportPost.RUnlock()
 	return calls
 }
 
-// MarkNotificationAsRead calls MarkNotificationAsReadFunc.
-func (mock *ServiceMock) MarkNotificationAsRead(ctx context.Context, notificationID string) error {
+// ReportUser calls ReportUserFunc.
+func (mock *ServiceMock) ReportUser(ctx context.Context, username string, in nakama.ReportInput) error {
 	callInfo := struct {
-		Ctx            context.Context
-		NotificationID string
+		Ctx      context.Context
+		Username string
+		In       nakama.ReportInput
 	}{
-		Ctx:            ctx,
-		NotificationID: notificationID,
+		Ctx:      ctx,
+		Username: username,
+		In:       in,
 	}
-	mock.lockMarkNotificationAsRead.Lock()
-	mock.calls.MarkNotificationAsRead = append(mock.calls.MarkNotificationAsRead, callInfo)
-	mock.lockMarkNotificationAsRead.Unlock()
-	if mock.MarkNotificationAsReadFunc == nil {
+	mock.lockReportUser.Lock()
+	mock.calls.ReportUser = append(mock.calls.ReportUser, callInfo)
+	mock.lockReportUser.Unlock()
+	if mock.ReportUserFunc == nil {
 		var (
 			errOut error
 		)
 		return errOut
 	}
-	return mock.MarkNotificationAsReadFunc(ctx, notificationID)
+	return mock.ReportUserFunc(ctx, username, in)
 }
 
-// MarkNotificationAsReadCalls gets all the calls that were made to MarkNotificationAsRead.
+// ReportUserCalls gets all the calls that were made to ReportUser.
 // Check the length with:
 //
-//	len(mockedService.MarkNotificationAsReadCalls())
-func (mock *ServiceMock) MarkNotificationAsReadCalls() []struct {
-	Ctx            context.Context
-	NotificationID string
+//	len(mockedService.ReportUserCalls())
+func (mock *ServiceMock) ReportUserCalls() []struct {
+	Ctx      context.Context
+	Username string
+	In       nakama.ReportInput
 } {
 	var calls []struct {
-		Ctx            context.Context
-		NotificationID string
+		Ctx      context.Context
+		Username string
+		In       nakama.ReportInput
 	}
-	mock.lockMarkNotificationAsRead.RLock()
-	calls = mock.calls.MarkNotificationAsRead
-	mock.lockMarkNotificationAsRead.RUnlock()
+	mock.lockReportUser.RLock()
+	calls = mock.calls.ReportUser
+	mock.lockReportUser.RUnlock()
 	return calls
 }
 
-// MarkNotificationsAsRead calls MarkNotificationsAsReadFunc.
-func (mock *ServiceMock) MarkNotificationsAsRead(ctx context.Context) error {
+// Reports calls ReportsFunc.
+func (mock *ServiceMock) Reports(ctx context.Context, first uint64, after *string) (nakama.Reports, error) {
 	callInfo := struct {
-		Ctx context.Context
+		Ctx   context.Context
+		First uint64
+		After *string
 	}{
-		Ctx: ctx,
+		Ctx:   ctx,
+		First: first,
+		After: after,
 	}
-	mock.lockMarkNotificationsAsRead.Lock()
-	mock.calls.MarkNotificationsAsRead = append(mock.calls.MarkNotificationsAsRead, callInfo)
-	mock.lockMarkNotificationsAsRead.Unlock()
-	if mock.MarkNotificationsAsReadFunc == nil {
+	mock.lockReports.Lock()
+	mock.calls.Reports = append(mock.calls.Reports, callInfo)
+	mock.lockReports.Unlock()
+	if mock.ReportsFunc == nil {
 		var (
-			errOut error
+			reportsOut nakama.Reports
+			errOut     error
 		)
-		return errOut
+		return reportsOut, errOut
 	}
-	return mock.MarkNotificationsAsReadFunc(ctx)
+	return mock.ReportsFunc(ctx, first, after)
 }
 
-// MarkNotificationsAsReadCalls gets all the calls that were made to MarkNotificationsAsRead.
+// ReportsCalls gets all the calls that were made to Reports.
 // Check the length with:
 //
-//	len(mockedService.MarkNotificationsAsReadCalls())
-func (mock *ServiceMock) MarkNotificationsAsReadCalls() []struct {
-	Ctx context.Context
+//	len(mockedService.ReportsCalls())
+func (mock *ServiceMock) ReportsCalls() []struct {
+	Ctx   context.Context
+	First uint64
+	After *string
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx   context.Context
+		First uint64
+		After *string
 	}
-	mock.lockMarkNotificationsAsRead.RLock()
-	calls = mock.calls.MarkNotificationsAsRead
-	mock.lockMarkNotificationsAsRead.RUnlock()
+	mock.lockReports.RLock()
+	calls = mock.calls.Reports
+	mock.lockReports.RUnlock()
 	return calls
 }
 
-// NotificationStream calls NotificationStreamFunc.
-func (mock *ServiceMock) NotificationStream(ctx context.Context) (<-chan nakama.Notification, error) {
+// RequestAccountDeletion calls RequestAccountDeletionFunc.
+func (mock *ServiceMock) RequestAccountDeletion(ctx context.Context) error {
 	callInfo := struct {
 		Ctx context.Context
 	}{
 		Ctx: ctx,
 	}
-	mock.lockNotificationStream.Lock()
-	mock.calls.NotificationStream = append(mock.calls.NotificationStream, callInfo)
-	mock.lockNotificationStream.Unlock()
-	if mock.NotificationStreamFunc == nil {
+	mock.lockRequestAccountDeletion.Lock()
+	mock.calls.RequestAccountDeletion = append(mock.calls.RequestAccountDeletion, callInfo)
+	mock.lockRequestAccountDeletion.Unlock()
+	if mock.RequestAccountDeletionFunc == nil {
 		var (
-			notificationChOut <-chan nakama.Notification
-			errOut            error
+			errOut error
 		)
-		return notificationChOut, errOut
+		return errOut
 	}
-	return mock.NotificationStreamFunc(ctx)
+	return mock.RequestAccountDeletionFunc(ctx)
 }
 
-// NotificationStreamCalls gets all the calls that were made to NotificationStream.
+// RequestAccountDeletionCalls gets all the calls that were made to RequestAccountDeletion.
 // Check the length with:
 //
-//	len(mockedService.NotificationStreamCalls())
-func (mock *ServiceMock) NotificationStreamCalls() []struct {
+//	len(mockedService.RequestAccountDeletionCalls())
+func (mock *ServiceMock) RequestAccountDeletionCalls() []struct {
 	Ctx context.Context
 } {
 	var calls []struct {
 		Ctx context.Context
 	}
-	mock.lockNotificationStream.RLock()
-	calls = mock.calls.NotificationStream
-	mock.lockNotificationStream.RUnlock()
+	mock.lockRequestAccountDeletion.RLock()
+	calls = mock.calls.RequestAccountDeletion
+	mock.lockRequestAccountDeletion.RUnlock()
 	return calls
 }
 
-// Notifications calls NotificationsFunc.
-func (mock *ServiceMock) Notifications(ctx context.Context, last uint64, before *string) (nakama.Notifications, error) {
+// RequestDataExport calls RequestDataExportFunc.
+func (mock *ServiceMock) RequestDataExport(ctx context.Context) (nakama.DataExport, error) {
 	callInfo := struct {
-		Ctx    context.Context
-		Last   uint64
-		Before *string
+		Ctx context.Context
 	}{
-		Ctx:    ctx,
-		Last:   last,
-		Before: before,
+		Ctx: ctx,
 	}
-	mock.lockNotifications.Lock()
-	mock.calls.Notifications = append(mock.calls.Notifications, callInfo)
-	mock.lockNotifications.Unlock()
-	if mock.NotificationsFunc == nil {
+	mock.lockRequestDataExport.Lock()
+	mock.calls.RequestDataExport = append(mock.calls.RequestDataExport, callInfo)
+	mock.lockRequestDataExport.Unlock()
+	if mock.RequestDataExportFunc == nil {
 		var (
-			notificationsOut nakama.Notifications
-			errOut           error
+			dataExportOut nakama.DataExport
+			errOut        error
 		)
-		return notificationsOut, errOut
+		return dataExportOut, errOut
 	}
-	return mock.NotificationsFunc(ctx, last, before)
+	return mock.RequestDataExportFunc(ctx)
 }
 
-// NotificationsCalls gets all the calls that were made to Notifications.
+// RequestDataExportCalls gets all the calls that were made to RequestDataExport.
 // Check the length with:
 //
-//	len(mockedService.NotificationsCalls())
-func (mock *ServiceMock) NotificationsCalls() []struct {
-	Ctx    context.Context
-	Last   uint64
-	Before *string
+//	len(mockedService.RequestDataExportCalls())
+func (mock *ServiceMock) RequestDataExportCalls() []struct {
+	Ctx context.Context
 } {
 	var calls []struct {
-		Ctx    context.Context
-		Last   uint64
-		Before *string
+		Ctx context.Context
 	}
-	mock.lockNotifications.RLock()
-	calls = mock.calls.Notifications
-	mock.lockNotifications.RUnlock()
+	mock.lockRequestDataExport.RLock()
+	calls = mock.calls.RequestDataExport
+	mock.lockRequestDataExport.RUnlock()
 	return calls
 }
 
-// ParseRedirectURI calls ParseRedirectURIFunc.
-func (mock *ServiceMock) ParseRedirectURI(rawurl string) (*url.URL, error) {
+// ResolveReport calls ResolveReportFunc.
+func (mock *ServiceMock) ResolveReport(ctx context.Context, reportID string, action string) error {
 	callInfo := struct {
-		Rawurl string
+		Ctx      context.Context
+		ReportID string
+		Action   string
 	}{
-		Rawurl: rawurl,
+		Ctx:      ctx,
+		ReportID: reportID,
+		Action:   action,
 	}
-	mock.lockParseRedirectURI.Lock()
-	mock.calls.ParseRedirectURI = append(mock.calls.ParseRedirectURI, callInfo)
-	mock.lockParseRedirectURI.Unlock()
-	if mock.ParseRedirectURIFunc == nil {
+	mock.lockResolveReport.Lock()
+	mock.calls.ResolveReport = append(mock.calls.ResolveReport, callInfo)
+	mock.lockResolveReport.Unlock()
+	if mock.ResolveReportFunc == nil {
 		var (
-			uRLOut *url.URL
 			errOut error
 		)
-		return uRLOut, errOut
+		return errOut
 	}
-	return mock.ParseRedirectURIFunc(rawurl)
+	return mock.ResolveReportFunc(ctx, reportID, action)
 }
 
-// ParseRedirectURICalls gets all the calls that were made to ParseRedirectURI.
+// ResolveReportCalls gets all the calls that were made to ResolveReport.
 // Check the length with:
 //
-//	len(mockedService.ParseRedirectURICalls())
-func (mock *ServiceMock) ParseRedirectURICalls() []struct {
-	Rawurl string
+//	len(mockedService.ResolveReportCalls())
+func (mock *ServiceMock) ResolveReportCalls() []struct {
+	Ctx      context.Context
+	ReportID string
+	Action   string
 } {
 	var calls []struct {
-		Rawurl string
+		Ctx      context.Context
+		ReportID string
+		Action   string
 	}
-	mock.lockParseRedirectURI.RLock()
-	calls = mock.calls.ParseRedirectURI
-	mock.lockParseRedirectURI.RUnlock()
+	mock.lockResolveReport.RLock()
+	calls = mock.calls.ResolveReport
+	mock.lockResolveReport.RUnlock()
 	return calls
 }
 
-// Post calls PostFunc.
-func (mock *ServiceMock) Post(ctx context.Context, postID string) (nakama.Post, error) {
+// RevokeOAuthApp calls RevokeOAuthAppFunc.
+func (mock *ServiceMock) RevokeOAuthApp(ctx context.Context, appID string) error {
 	callInfo := struct {
-		Ctx    context.Context
-		PostID string
+		Ctx   context.Context
+		AppID string
 	}{
-		Ctx:    ctx,
-		PostID: postID,
+		Ctx:   ctx,
+		AppID: appID,
 	}
-	mock.lockPost.Lock()
-	mock.calls.Post = append(mock.calls.Post, callInfo)
-	mock.lockPost.Unlock()
-	if mock.PostFunc == nil {
+	mock.lockRevokeOAuthApp.Lock()
+	mock.calls.RevokeOAuthApp = append(mock.calls.RevokeOAuthApp, callInfo)
+	mock.lockRevokeOAuthApp.Unlock()
+	if mock.RevokeOAuthAppFunc == nil {
 		var (
-			postOut nakama.Post
-			errOut  error
+			errOut error
 		)
-		return postOut, errOut
+		return errOut
 	}
-	return mock.PostFunc(ctx, postID)
+	return mock.RevokeOAuthAppFunc(ctx, appID)
 }
 
-// PostCalls gets all the calls that were made to Post.
+// RevokeOAuthAppCalls gets all the calls that were made to RevokeOAuthApp.
 // Check the length with:
 //
-//	len(mockedService.PostCalls())
-func (mock *ServiceMock) PostCalls() []struct {
-	Ctx    context.Context
-	PostID string
+//	len(mockedService.RevokeOAuthAppCalls())
+func (mock *ServiceMock) RevokeOAuthAppCalls() []struct {
+	Ctx   context.Context
+	AppID string
 } {
 	var calls []struct {
-		Ctx    context.Context
-		PostID string
+		Ctx   context.Context
+		AppID string
 	}
-	mock.lockPost.RLock()
-	calls = mock.calls.Post
-	mock.lockPost.RUnlock()
+	mock.lockRevokeOAuthApp.RLock()
+	calls = mock.calls.RevokeOAuthApp
+	mock.lockRevokeOAuthApp.RUnlock()
 	return calls
 }
 
-// PostStream calls PostStreamFunc.
-func (mock *ServiceMock) PostStream(ctx context.Context) (<-chan nakama.Post, error) {
+// RevokePersonalAccessToken calls RevokePersonalAccessTokenFunc.
+func (mock *ServiceMock) RevokePersonalAccessToken(ctx context.Context, tokenID string) error {
 	callInfo := struct {
-		Ctx context.Context
+		Ctx     context.Context
+		TokenID string
 	}{
-		Ctx: ctx,
+		Ctx:     ctx,
+		TokenID: tokenID,
 	}
-	mock.lockPostStream.Lock()
-	mock.calls.PostStream = append(mock.calls.PostStream, callInfo)
-	mock.lockPostStream.Unlock()
-	if mock.PostStreamFunc == nil {
+	mock.lockRevokePersonalAccessToken.Lock()
+	mock.calls.RevokePersonalAccessToken = append(mock.calls.RevokePersonalAccessToken, callInfo)
+	mock.lockRevokePersonalAccessToken.Unlock()
+	if mock.RevokePersonalAccessTokenFunc == nil {
 		var (
-			postChOut <-chan nakama.Post
-			errOut    error
+			errOut error
 		)
-		return postChOut, errOut
+		return errOut
 	}
-	return mock.PostStreamFunc(ctx)
+	return mock.RevokePersonalAccessTokenFunc(ctx, tokenID)
 }
 
-// PostStreamCalls gets all the calls that were made to PostStream.
+// RevokePersonalAccessTokenCalls gets all the calls that were made to RevokePersonalAccessToken.
 // Check the length with:
 //
-//	len(mockedService.PostStreamCalls())
-func (mock *ServiceMock) PostStreamCalls() []struct {
-	Ctx context.Context
+//	len(mockedService.RevokePersonalAccessTokenCalls())
+func (mock *ServiceMock) RevokePersonalAccessTokenCalls() []struct {
+	Ctx     context.Context
+	TokenID string
 } {
 	var calls []struct {
-		Ctx context.Context
+		Ctx     context.Context
+		TokenID string
 	}
-	mock.lockPostStream.RLock()
-	calls = mock.calls.PostStream
-	mock.lockPostStream.RUnlock()
+	mock.lockRevokePersonalAccessToken.RLock()
+	calls = mock.calls.RevokePersonalAccessToken
+	mock.lockRevokePersonalAccessToken.RUnlock()
 	return calls
 }
 
-// Posts calls PostsFunc.
-func (mock *ServiceMock) Posts(ctx context.Context, last uint64, before *string, opts ...nakama.PostsOpt) (nakama.Posts, error) {
+// RevokeSession calls RevokeSessionFunc.
+func (mock *ServiceMock) RevokeSession(ctx context.Context, sessionID string) error {
 	callInfo := struct {
-		Ctx    context.Context
-		Last   uint64
-		Before *string
-		Opts   []nakama.PostsOpt
+		Ctx       context.Context
+		SessionID string
 	}{
-		Ctx:    ctx,
-		Last:   last,
-		Before: before,
-		Opts:   opts,
+		Ctx:       ctx,
+		SessionID: sessionID,
 	}
-	mock.lockPosts.Lock()
-	mock.calls.Posts = append(mock.calls.Posts, callInfo)
-	mock.lockPosts.Unlock()
-	if mock.PostsFunc == nil {
+	mock.lockRevokeSession.Lock()
+	mock.calls.RevokeSession = append(mock.calls.RevokeSession, callInfo)
+	mock.lockRevokeSession.Unlock()
+	if mock.RevokeSessionFunc == nil {
 		var (
-			postsOut nakama.Posts
-			errOut   error
+			errOut error
 		)
-		return postsOut, errOut
+		return errOut
 	}
-	return mock.PostsFunc(ctx, last, before, opts...)
+	return mock.RevokeSessionFunc(ctx, sessionID)
 }
 
-// PostsCalls gets all the calls that were made to Posts.
+// RevokeSessionCalls gets all the calls that were made to RevokeSession.
 // Check the length with:
 //
-//	len(mockedService.PostsCalls())
-func (mock *ServiceMock) PostsCalls() []struct {
-	Ctx    context.Context
-	Last   uint64
-	Before *string
-	Opts   []nakama.PostsOpt
+//	len(mockedService.RevokeSessionCalls())
+func (mock *ServiceMock) RevokeSessionCalls() []struct {
+	Ctx       context.Context
+	SessionID string
 } {
 	var calls []struct {
-		Ctx    context.Context
-		Last   uint64
-		Before *string
-		Opts   []nakama.PostsOpt
+		Ctx       context.Context
+		SessionID string
 	}
-	mock.lockPosts.RLock()
-	calls = mock.calls.Posts
-	mock.lockPosts.RUnlock()
+	mock.lockRevokeSession.RLock()
+	calls = mock.calls.RevokeSession
+	mock.lockRevokeSession.RUnlock()
 	return calls
 }
 
@@ -1605,6 +4737,125 @@ func (mock *ServiceMock) SendMagicLinkCalls() []struct {
 	return calls
 }
 
+// Sessions calls SessionsFunc.
+func (mock *ServiceMock) Sessions(ctx context.Context) ([]nakama.Session, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockSessions.Lock()
+	mock.calls.Sessions = append(mock.calls.Sessions, callInfo)
+	mock.lockSessions.Unlock()
+	if mock.SessionsFunc == nil {
+		var (
+			sessionsOut []nakama.Session
+			errOut      error
+		)
+		return sessionsOut, errOut
+	}
+	return mock.SessionsFunc(ctx)
+}
+
+// SessionsCalls gets all the calls that were made to Sessions.
+// Check the length with:
+//
+//	len(mockedService.SessionsCalls())
+func (mock *ServiceMock) SessionsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockSessions.RLock()
+	calls = mock.calls.Sessions
+	mock.lockSessions.RUnlock()
+	return calls
+}
+
+// SuggestedUsers calls SuggestedUsersFunc.
+func (mock *ServiceMock) SuggestedUsers(ctx context.Context, first uint64) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		First uint64
+	}{
+		Ctx:   ctx,
+		First: first,
+	}
+	mock.lockSuggestedUsers.Lock()
+	mock.calls.SuggestedUsers = append(mock.calls.SuggestedUsers, callInfo)
+	mock.lockSuggestedUsers.Unlock()
+	if mock.SuggestedUsersFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.SuggestedUsersFunc(ctx, first)
+}
+
+// SuggestedUsersCalls gets all the calls that were made to SuggestedUsers.
+// Check the length with:
+//
+//	len(mockedService.SuggestedUsersCalls())
+func (mock *ServiceMock) SuggestedUsersCalls() []struct {
+	Ctx   context.Context
+	First uint64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		First uint64
+	}
+	mock.lockSuggestedUsers.RLock()
+	calls = mock.calls.SuggestedUsers
+	mock.lockSuggestedUsers.RUnlock()
+	return calls
+}
+
+// SuspendUser calls SuspendUserFunc.
+func (mock *ServiceMock) SuspendUser(ctx context.Context, username string, until *time.Time) error {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Until    *time.Time
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Until:    until,
+	}
+	mock.lockSuspendUser.Lock()
+	mock.calls.SuspendUser = append(mock.calls.SuspendUser, callInfo)
+	mock.lockSuspendUser.Unlock()
+	if mock.SuspendUserFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SuspendUserFunc(ctx, username, until)
+}
+
+// SuspendUserCalls gets all the calls that were made to SuspendUser.
+// Check the length with:
+//
+//	len(mockedService.SuspendUserCalls())
+func (mock *ServiceMock) SuspendUserCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Until    *time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Until    *time.Time
+	}
+	mock.lockSuspendUser.RLock()
+	calls = mock.calls.SuspendUser
+	mock.lockSuspendUser.RUnlock()
+	return calls
+}
+
 // Timeline calls TimelineFunc.
 func (mock *ServiceMock) Timeline(ctx context.Context, last uint64, before *string) (nakama.Timeline, error) {
 	callInfo := struct {
@@ -1679,9 +4930,49 @@ func (mock *ServiceMock) TimelineItemStreamCalls() []struct {
 	var calls []struct {
 		Ctx context.Context
 	}
-	mock.lockTimelineItemStream.RLock()
-	calls = mock.calls.TimelineItemStream
-	mock.lockTimelineItemStream.RUnlock()
+	mock.lockTimelineItemStream.RLock()
+	calls = mock.calls.TimelineItemStream
+	mock.lockTimelineItemStream.RUnlock()
+	return calls
+}
+
+// ToggleBlock calls ToggleBlockFunc.
+func (mock *ServiceMock) ToggleBlock(ctx context.Context, username string) (nakama.ToggleBlockOutput, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockToggleBlock.Lock()
+	mock.calls.ToggleBlock = append(mock.calls.ToggleBlock, callInfo)
+	mock.lockToggleBlock.Unlock()
+	if mock.ToggleBlockFunc == nil {
+		var (
+			toggleBlockOutputOut nakama.ToggleBlockOutput
+			errOut               error
+		)
+		return toggleBlockOutputOut, errOut
+	}
+	return mock.ToggleBlockFunc(ctx, username)
+}
+
+// ToggleBlockCalls gets all the calls that were made to ToggleBlock.
+// Check the length with:
+//
+//	len(mockedService.ToggleBlockCalls())
+func (mock *ServiceMock) ToggleBlockCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockToggleBlock.RLock()
+	calls = mock.calls.ToggleBlock
+	mock.lockToggleBlock.RUnlock()
 	return calls
 }
 
@@ -1853,39 +5144,156 @@ func (mock *ServiceMock) TogglePostSubscriptionCalls() []struct {
 	return calls
 }
 
-// Token calls TokenFunc.
-func (mock *ServiceMock) Token(ctx context.Context) (nakama.TokenOutput, error) {
+// TwoFactorStatus calls TwoFactorStatusFunc.
+func (mock *ServiceMock) TwoFactorStatus(ctx context.Context) (nakama.TwoFactorStatus, error) {
 	callInfo := struct {
 		Ctx context.Context
 	}{
 		Ctx: ctx,
 	}
-	mock.lockToken.Lock()
-	mock.calls.Token = append(mock.calls.Token, callInfo)
-	mock.lockToken.Unlock()
-	if mock.TokenFunc == nil {
+	mock.lockTwoFactorStatus.Lock()
+	mock.calls.TwoFactorStatus = append(mock.calls.TwoFactorStatus, callInfo)
+	mock.lockTwoFactorStatus.Unlock()
+	if mock.TwoFactorStatusFunc == nil {
 		var (
-			tokenOutputOut nakama.TokenOutput
-			errOut         error
+			twoFactorStatusOut nakama.TwoFactorStatus
+			errOut             error
 		)
-		return tokenOutputOut, errOut
+		return twoFactorStatusOut, errOut
 	}
-	return mock.TokenFunc(ctx)
+	return mock.TwoFactorStatusFunc(ctx)
 }
 
-// TokenCalls gets all the calls that were made to Token.
+// TwoFactorStatusCalls gets all the calls that were made to TwoFactorStatus.
 // Check the length with:
 //
-//	len(mockedService.TokenCalls())
-func (mock *ServiceMock) TokenCalls() []struct {
+//	len(mockedService.TwoFactorStatusCalls())
+func (mock *ServiceMock) TwoFactorStatusCalls() []struct {
 	Ctx context.Context
 } {
 	var calls []struct {
 		Ctx context.Context
 	}
-	mock.lockToken.RLock()
-	calls = mock.calls.Token
-	mock.lockToken.RUnlock()
+	mock.lockTwoFactorStatus.RLock()
+	calls = mock.calls.TwoFactorStatus
+	mock.lockTwoFactorStatus.RUnlock()
+	return calls
+}
+
+// UnlinkIdentity calls UnlinkIdentityFunc.
+func (mock *ServiceMock) UnlinkIdentity(ctx context.Context, provider string) error {
+	callInfo := struct {
+		Ctx      context.Context
+		Provider string
+	}{
+		Ctx:      ctx,
+		Provider: provider,
+	}
+	mock.lockUnlinkIdentity.Lock()
+	mock.calls.UnlinkIdentity = append(mock.calls.UnlinkIdentity, callInfo)
+	mock.lockUnlinkIdentity.Unlock()
+	if mock.UnlinkIdentityFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UnlinkIdentityFunc(ctx, provider)
+}
+
+// UnlinkIdentityCalls gets all the calls that were made to UnlinkIdentity.
+// Check the length with:
+//
+//	len(mockedService.UnlinkIdentityCalls())
+func (mock *ServiceMock) UnlinkIdentityCalls() []struct {
+	Ctx      context.Context
+	Provider string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Provider string
+	}
+	mock.lockUnlinkIdentity.RLock()
+	calls = mock.calls.UnlinkIdentity
+	mock.lockUnlinkIdentity.RUnlock()
+	return calls
+}
+
+// UnpinPost calls UnpinPostFunc.
+func (mock *ServiceMock) UnpinPost(ctx context.Context, postID string) error {
+	callInfo := struct {
+		Ctx    context.Context
+		PostID string
+	}{
+		Ctx:    ctx,
+		PostID: postID,
+	}
+	mock.lockUnpinPost.Lock()
+	mock.calls.UnpinPost = append(mock.calls.UnpinPost, callInfo)
+	mock.lockUnpinPost.Unlock()
+	if mock.UnpinPostFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UnpinPostFunc(ctx, postID)
+}
+
+// UnpinPostCalls gets all the calls that were made to UnpinPost.
+// Check the length with:
+//
+//	len(mockedService.UnpinPostCalls())
+func (mock *ServiceMock) UnpinPostCalls() []struct {
+	Ctx    context.Context
+	PostID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		PostID string
+	}
+	mock.lockUnpinPost.RLock()
+	calls = mock.calls.UnpinPost
+	mock.lockUnpinPost.RUnlock()
+	return calls
+}
+
+// UnsuspendUser calls UnsuspendUserFunc.
+func (mock *ServiceMock) UnsuspendUser(ctx context.Context, username string) error {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockUnsuspendUser.Lock()
+	mock.calls.UnsuspendUser = append(mock.calls.UnsuspendUser, callInfo)
+	mock.lockUnsuspendUser.Unlock()
+	if mock.UnsuspendUserFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.UnsuspendUserFunc(ctx, username)
+}
+
+// UnsuspendUserCalls gets all the calls that were made to UnsuspendUser.
+// Check the length with:
+//
+//	len(mockedService.UnsuspendUserCalls())
+func (mock *ServiceMock) UnsuspendUserCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockUnsuspendUser.RLock()
+	calls = mock.calls.UnsuspendUser
+	mock.lockUnsuspendUser.RUnlock()
 	return calls
 }
 
@@ -2009,6 +5417,46 @@ func (mock *ServiceMock) UpdateCoverCalls() []struct {
 	return calls
 }
 
+// UpdateMute calls UpdateMuteFunc.
+func (mock *ServiceMock) UpdateMute(ctx context.Context, in nakama.UpdateMute) (nakama.Mute, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.UpdateMute
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockUpdateMute.Lock()
+	mock.calls.UpdateMute = append(mock.calls.UpdateMute, callInfo)
+	mock.lockUpdateMute.Unlock()
+	if mock.UpdateMuteFunc == nil {
+		var (
+			muteOut nakama.Mute
+			errOut  error
+		)
+		return muteOut, errOut
+	}
+	return mock.UpdateMuteFunc(ctx, in)
+}
+
+// UpdateMuteCalls gets all the calls that were made to UpdateMute.
+// Check the length with:
+//
+//	len(mockedService.UpdateMuteCalls())
+func (mock *ServiceMock) UpdateMuteCalls() []struct {
+	Ctx context.Context
+	In  nakama.UpdateMute
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.UpdateMute
+	}
+	mock.lockUpdateMute.RLock()
+	calls = mock.calls.UpdateMute
+	mock.lockUpdateMute.RUnlock()
+	return calls
+}
+
 // UpdatePost calls UpdatePostFunc.
 func (mock *ServiceMock) UpdatePost(ctx context.Context, postID string, in nakama.UpdatePost) (nakama.UpdatedPost, error) {
 	callInfo := struct {
@@ -2092,6 +5540,46 @@ func (mock *ServiceMock) UpdateUserCalls() []struct {
 	return calls
 }
 
+// UpdateUserList calls UpdateUserListFunc.
+func (mock *ServiceMock) UpdateUserList(ctx context.Context, in nakama.UpdateUserList) (nakama.UserList, error) {
+	callInfo := struct {
+		Ctx context.Context
+		In  nakama.UpdateUserList
+	}{
+		Ctx: ctx,
+		In:  in,
+	}
+	mock.lockUpdateUserList.Lock()
+	mock.calls.UpdateUserList = append(mock.calls.UpdateUserList, callInfo)
+	mock.lockUpdateUserList.Unlock()
+	if mock.UpdateUserListFunc == nil {
+		var (
+			userListOut nakama.UserList
+			errOut      error
+		)
+		return userListOut, errOut
+	}
+	return mock.UpdateUserListFunc(ctx, in)
+}
+
+// UpdateUserListCalls gets all the calls that were made to UpdateUserList.
+// Check the length with:
+//
+//	len(mockedService.UpdateUserListCalls())
+func (mock *ServiceMock) UpdateUserListCalls() []struct {
+	Ctx context.Context
+	In  nakama.UpdateUserList
+} {
+	var calls []struct {
+		Ctx context.Context
+		In  nakama.UpdateUserList
+	}
+	mock.lockUpdateUserList.RLock()
+	calls = mock.calls.UpdateUserList
+	mock.lockUpdateUserList.RUnlock()
+	return calls
+}
+
 // User calls UserFunc.
 func (mock *ServiceMock) User(ctx context.Context, username string) (nakama.UserProfile, error) {
 	callInfo := struct {
@@ -2132,6 +5620,134 @@ func (mock *ServiceMock) UserCalls() []struct {
 	return calls
 }
 
+// UserList calls UserListFunc.
+func (mock *ServiceMock) UserList(ctx context.Context, listID string) (nakama.UserList, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		ListID string
+	}{
+		Ctx:    ctx,
+		ListID: listID,
+	}
+	mock.lockUserList.Lock()
+	mock.calls.UserList = append(mock.calls.UserList, callInfo)
+	mock.lockUserList.Unlock()
+	if mock.UserListFunc == nil {
+		var (
+			userListOut nakama.UserList
+			errOut      error
+		)
+		return userListOut, errOut
+	}
+	return mock.UserListFunc(ctx, listID)
+}
+
+// UserListCalls gets all the calls that were made to UserList.
+// Check the length with:
+//
+//	len(mockedService.UserListCalls())
+func (mock *ServiceMock) UserListCalls() []struct {
+	Ctx    context.Context
+	ListID string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ListID string
+	}
+	mock.lockUserList.RLock()
+	calls = mock.calls.UserList
+	mock.lockUserList.RUnlock()
+	return calls
+}
+
+// UserListMembers calls UserListMembersFunc.
+func (mock *ServiceMock) UserListMembers(ctx context.Context, listID string, first uint64, after *string) (nakama.UserProfiles, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		ListID string
+		First  uint64
+		After  *string
+	}{
+		Ctx:    ctx,
+		ListID: listID,
+		First:  first,
+		After:  after,
+	}
+	mock.lockUserListMembers.Lock()
+	mock.calls.UserListMembers = append(mock.calls.UserListMembers, callInfo)
+	mock.lockUserListMembers.Unlock()
+	if mock.UserListMembersFunc == nil {
+		var (
+			userProfilesOut nakama.UserProfiles
+			errOut          error
+		)
+		return userProfilesOut, errOut
+	}
+	return mock.UserListMembersFunc(ctx, listID, first, after)
+}
+
+// UserListMembersCalls gets all the calls that were made to UserListMembers.
+// Check the length with:
+//
+//	len(mockedService.UserListMembersCalls())
+func (mock *ServiceMock) UserListMembersCalls() []struct {
+	Ctx    context.Context
+	ListID string
+	First  uint64
+	After  *string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ListID string
+		First  uint64
+		After  *string
+	}
+	mock.lockUserListMembers.RLock()
+	calls = mock.calls.UserListMembers
+	mock.lockUserListMembers.RUnlock()
+	return calls
+}
+
+// UserLists calls UserListsFunc.
+func (mock *ServiceMock) UserLists(ctx context.Context, username string) ([]nakama.UserList, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockUserLists.Lock()
+	mock.calls.UserLists = append(mock.calls.UserLists, callInfo)
+	mock.lockUserLists.Unlock()
+	if mock.UserListsFunc == nil {
+		var (
+			userListsOut []nakama.UserList
+			errOut       error
+		)
+		return userListsOut, errOut
+	}
+	return mock.UserListsFunc(ctx, username)
+}
+
+// UserListsCalls gets all the calls that were made to UserLists.
+// Check the length with:
+//
+//	len(mockedService.UserListsCalls())
+func (mock *ServiceMock) UserListsCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockUserLists.RLock()
+	calls = mock.calls.UserLists
+	mock.lockUserLists.RUnlock()
+	return calls
+}
+
 // Usernames calls UsernamesFunc.
 func (mock *ServiceMock) Usernames(ctx context.Context, startingWith string, first uint64, after *string) (nakama.Usernames, error) {
 	callInfo := struct {
@@ -2228,6 +5844,54 @@ func (mock *ServiceMock) UsersCalls() []struct {
 	return calls
 }
 
+// VerifyLoginCode calls VerifyLoginCodeFunc.
+func (mock *ServiceMock) VerifyLoginCode(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error) {
+	callInfo := struct {
+		Ctx      context.Context
+		Email    string
+		Code     string
+		Username *string
+	}{
+		Ctx:      ctx,
+		Email:    email,
+		Code:     code,
+		Username: username,
+	}
+	mock.lockVerifyLoginCode.Lock()
+	mock.calls.VerifyLoginCode = append(mock.calls.VerifyLoginCode, callInfo)
+	mock.lockVerifyLoginCode.Unlock()
+	if mock.VerifyLoginCodeFunc == nil {
+		var (
+			authOutputOut nakama.AuthOutput
+			errOut        error
+		)
+		return authOutputOut, errOut
+	}
+	return mock.VerifyLoginCodeFunc(ctx, email, code, username)
+}
+
+// VerifyLoginCodeCalls gets all the calls that were made to VerifyLoginCode.
+// Check the length with:
+//
+//	len(mockedService.VerifyLoginCodeCalls())
+func (mock *ServiceMock) VerifyLoginCodeCalls() []struct {
+	Ctx      context.Context
+	Email    string
+	Code     string
+	Username *string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Email    string
+		Code     string
+		Username *string
+	}
+	mock.lockVerifyLoginCode.RLock()
+	calls = mock.calls.VerifyLoginCode
+	mock.lockVerifyLoginCode.RUnlock()
+	return calls
+}
+
 // VerifyMagicLink calls VerifyMagicLinkFunc.
 func (mock *ServiceMock) VerifyMagicLink(ctx context.Context, email string, code string, username *string) (nakama.AuthOutput, error) {
 	callInfo := struct {
@@ -2275,3 +5939,47 @@ func (mock *ServiceMock) VerifyMagicLinkCalls() []struct {
 	mock.lockVerifyMagicLink.RUnlock()
 	return calls
 }
+
+// VerifySecondFactor calls VerifySecondFactorFunc.
+func (mock *ServiceMock) VerifySecondFactor(ctx context.Context, challengeID string, code string) (nakama.AuthOutput, error) {
+	callInfo := struct {
+		Ctx         context.Context
+		ChallengeID string
+		Code        string
+	}{
+		Ctx:         ctx,
+		ChallengeID: challengeID,
+		Code:        code,
+	}
+	mock.lockVerifySecondFactor.Lock()
+	mock.calls.VerifySecondFactor = append(mock.calls.VerifySecondFactor, callInfo)
+	mock.lockVerifySecondFactor.Unlock()
+	if mock.VerifySecondFactorFunc == nil {
+		var (
+			authOutputOut nakama.AuthOutput
+			errOut        error
+		)
+		return authOutputOut, errOut
+	}
+	return mock.VerifySecondFactorFunc(ctx, challengeID, code)
+}
+
+// VerifySecondFactorCalls gets all the calls that were made to VerifySecondFactor.
+// Check the length with:
+//
+//	len(mockedService.VerifySecondFactorCalls())
+func (mock *ServiceMock) VerifySecondFactorCalls() []struct {
+	Ctx         context.Context
+	ChallengeID string
+	Code        string
+} {
+	var calls []struct {
+		Ctx         context.Context
+		ChallengeID string
+		Code        string
+	}
+	mock.lockVerifySecondFactor.RLock()
+	calls = mock.calls.VerifySecondFactor
+	mock.lockVerifySecondFactor.RUnlock()
+	return calls
+}
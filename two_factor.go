@@ -0,0 +1,557 @@
+package nakama
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+const (
+	totpPeriod              = 30
+	totpDigits              = 6
+	totpSecretLen           = 20
+	totpIssuer              = "Nakama"
+	recoveryCodesCount      = 10
+	recoveryCodeLen         = 10
+	secondFactorTTL         = time.Minute * 5
+	secondFactorMaxAttempts = 5
+)
+
+var (
+	// ErrInvalidSecondFactorCode denotes a code that is neither a valid TOTP code
+	// nor an unused recovery code.
+	ErrInvalidSecondFactorCode = InvalidArgumentError("invalid second factor code")
+	// ErrInvalidSecondFactorChallengeID denotes an invalid second factor challenge ID; that is not uuid.
+	ErrInvalidSecondFactorChallengeID = InvalidArgumentError("invalid second factor challenge ID")
+	// ErrSecondFactorChallengeNotFound denotes a not found second factor challenge.
+	// Either it never existed, it was already completed, it expired,
+	// or it had too many failed attempts.
+	ErrSecondFactorChallengeNotFound = NotFoundError("second factor challenge not found")
+	// ErrSecondFactorLocked denotes that the user had too many failed second factor attempts
+	// and has to wait before trying again.
+	ErrSecondFactorLocked = ResourceExhaustedError("second factor locked")
+	// ErrTOTPEnabled denotes that TOTP is already enabled for the user.
+	ErrTOTPEnabled = AlreadyExistsError("totp already enabled")
+	// ErrTOTPNotEnabled denotes that TOTP is not enabled for the user.
+	ErrTOTPNotEnabled = NotFoundError("totp not enabled")
+	// ErrTOTPEnrollmentNotFound denotes that there is no pending TOTP enrollment
+	// to confirm.
+	ErrTOTPEnrollmentNotFound = NotFoundError("totp enrollment not found")
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPEnrollment holds the secret of a pending TOTP enrollment.
+// URI is an otpauth:// provisioning URI meant to be shown as a QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorStatus of the authenticated user.
+type TwoFactorStatus struct {
+	TOTPEnabled       bool `json:"totpEnabled"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+// TwoFactorStatus of the authenticated user.
+func (s *Service) TwoFactorStatus(ctx context.Context) (TwoFactorStatus, error) {
+	var out TwoFactorStatus
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM totp_secrets WHERE user_id = $1 AND enabled_at IS NOT NULL
+			)
+			, (SELECT count(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL)`
+	row := s.DB.QueryRowContext(ctx, query, uid)
+	err := row.Scan(&out.TOTPEnabled, &out.RecoveryCodesLeft)
+	if err != nil {
+		return out, fmt.Errorf("could not sql query select two factor status: %w", err)
+	}
+
+	return out, nil
+}
+
+// BeginTOTPEnrollment generates a new TOTP secret for the authenticated user.
+// It is not enabled until confirmed with EnableTOTP.
+func (s *Service) BeginTOTPEnrollment(ctx context.Context) (TOTPEnrollment, error) {
+	var out TOTPEnrollment
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	secret := make([]byte, totpSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return out, fmt.Errorf("could not generate totp secret: %w", err)
+	}
+
+	out.Secret = totpEncoding.EncodeToString(secret)
+
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var username string
+		query := "SELECT username FROM users WHERE id = $1"
+		err := tx.QueryRowContext(ctx, query, uid).Scan(&username)
+		if err == sql.ErrNoRows {
+			return ErrUserGone
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select totp user: %w", err)
+		}
+
+		query = `
+			UPSERT INTO totp_secrets (user_id, secret, enabled_at, last_used_step, created_at)
+			SELECT $1, $2, NULL, 0, now()
+			WHERE NOT EXISTS (
+				SELECT 1 FROM totp_secrets WHERE user_id = $1 AND enabled_at IS NOT NULL
+			)`
+		res, err := tx.ExecContext(ctx, query, uid, out.Secret)
+		if err != nil {
+			return fmt.Errorf("could not sql upsert totp secret: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get upserted totp secret rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return ErrTOTPEnabled
+		}
+
+		out.URI = totpURI(username, out.Secret)
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// EnableTOTP confirms the pending TOTP enrollment of the authenticated user
+// with a code from the authenticator app.
+// It returns a fresh set of single-use recovery codes
+// that are not retrievable again.
+func (s *Service) EnableTOTP(ctx context.Context, code string) ([]string, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	code = strings.TrimSpace(code)
+
+	var codes []string
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var secret string
+		var enabledAt sql.NullTime
+		query := "SELECT secret, enabled_at FROM totp_secrets WHERE user_id = $1 FOR UPDATE"
+		err := tx.QueryRowContext(ctx, query, uid).Scan(&secret, &enabledAt)
+		if err == sql.ErrNoRows {
+			return ErrTOTPEnrollmentNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select totp secret: %w", err)
+		}
+
+		if enabledAt.Valid {
+			return ErrTOTPEnabled
+		}
+
+		step, ok := validateTOTP(secret, code, time.Now(), 0)
+		if !ok {
+			return ErrInvalidSecondFactorCode
+		}
+
+		query = "UPDATE totp_secrets SET enabled_at = now(), last_used_step = $1 WHERE user_id = $2"
+		_, err = tx.ExecContext(ctx, query, step, uid)
+		if err != nil {
+			return fmt.Errorf("could not sql update totp secret enabled: %w", err)
+		}
+
+		codes, err = replaceRecoveryCodes(ctx, tx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP for the authenticated user.
+// It requires a fresh TOTP or recovery code.
+func (s *Service) DisableTOTP(ctx context.Context, code string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	var valid bool
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var err error
+		valid, err = checkSecondFactor(ctx, tx, uid, code)
+		if err != nil || !valid {
+			return err
+		}
+
+		query := "DELETE FROM totp_secrets WHERE user_id = $1"
+		_, err = tx.ExecContext(ctx, query, uid)
+		if err != nil {
+			return fmt.Errorf("could not sql delete totp secret: %w", err)
+		}
+
+		query = "DELETE FROM recovery_codes WHERE user_id = $1"
+		_, err = tx.ExecContext(ctx, query, uid)
+		if err != nil {
+			return fmt.Errorf("could not sql delete recovery codes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidSecondFactorCode
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes for the authenticated user.
+// Previous recovery codes stop working.
+// It requires a fresh TOTP or recovery code.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var valid bool
+	var codes []string
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var err error
+		valid, err = checkSecondFactor(ctx, tx, uid, code)
+		if err != nil || !valid {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(ctx, tx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		return nil, ErrInvalidSecondFactorCode
+	}
+
+	return codes, nil
+}
+
+// VerifySecondFactor completes a login that required a second factor.
+// The code can be either a TOTP code or a recovery code.
+func (s *Service) VerifySecondFactor(ctx context.Context, challengeID, code string) (AuthOutput, error) {
+	var out AuthOutput
+
+	if !reUUID.MatchString(challengeID) {
+		return out, ErrInvalidSecondFactorChallengeID
+	}
+
+	var valid bool
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var uid string
+		var attempts int
+		var createdAt time.Time
+		query := "SELECT user_id, attempts, created_at FROM second_factor_challenges WHERE id = $1 FOR UPDATE"
+		row := tx.QueryRowContext(ctx, query, challengeID)
+		err := row.Scan(&uid, &attempts, &createdAt)
+		if err == sql.ErrNoRows {
+			return ErrSecondFactorChallengeNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select second factor challenge: %w", err)
+		}
+
+		if time.Since(createdAt) >= secondFactorTTL {
+			return ErrSecondFactorChallengeNotFound
+		}
+
+		valid, err = checkSecondFactor(ctx, tx, uid, code)
+		if err != nil {
+			return err
+		}
+
+		if !valid && attempts+1 < secondFactorMaxAttempts {
+			query := "UPDATE second_factor_challenges SET attempts = attempts + 1 WHERE id = $1"
+			_, err := tx.ExecContext(ctx, query, challengeID)
+			if err != nil {
+				return fmt.Errorf("could not sql update second factor challenge attempts: %w", err)
+			}
+
+			return nil
+		}
+
+		query = "DELETE FROM second_factor_challenges WHERE id = $1"
+		_, err = tx.ExecContext(ctx, query, challengeID)
+		if err != nil {
+			return fmt.Errorf("could not sql delete second factor challenge: %w", err)
+		}
+
+		out.User.ID = uid
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	if !valid {
+		return out, ErrInvalidSecondFactorCode
+	}
+
+	out.User, err = s.userByID(ctx, out.User.ID)
+	if err != nil {
+		return out, err
+	}
+
+	out.TokenOutput, err = s.issueToken(ctx, out.User.ID)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// createSecondFactorChallenge if the given user has TOTP enabled.
+func (s *Service) createSecondFactorChallenge(ctx context.Context, userID string) (string, bool, error) {
+	var challengeID string
+	query := `
+		INSERT INTO second_factor_challenges (user_id)
+		SELECT user_id FROM totp_secrets WHERE user_id = $1 AND enabled_at IS NOT NULL
+		RETURNING id`
+	err := s.DB.QueryRowContext(ctx, query, userID).Scan(&challengeID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("could not sql insert second factor challenge: %w", err)
+	}
+
+	return challengeID, true, nil
+}
+
+// checkSecondFactor checks the given code against the user TOTP secret,
+// or else against its unused recovery codes.
+// Either gets consumed so it cannot be used again.
+// Failed attempts are counted per user, so after too many of them
+// every check fails with ErrSecondFactorLocked for a while,
+// even with the right code.
+// Callers must commit the transaction when the code is not valid
+// so the failed attempt gets recorded.
+func checkSecondFactor(ctx context.Context, tx *sql.Tx, userID, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	var secret string
+	var lastUsedStep int64
+	var failedAttempts int
+	var lastFailedAt sql.NullTime
+	query := `
+		SELECT secret, last_used_step, failed_attempts, last_failed_at
+		FROM totp_secrets
+		WHERE user_id = $1 AND enabled_at IS NOT NULL
+		FOR UPDATE`
+	row := tx.QueryRowContext(ctx, query, userID)
+	err := row.Scan(&secret, &lastUsedStep, &failedAttempts, &lastFailedAt)
+	if err == sql.ErrNoRows {
+		return false, ErrTOTPNotEnabled
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select totp secret: %w", err)
+	}
+
+	now := time.Now()
+	if lastFailedAt.Valid && now.Sub(lastFailedAt.Time) >= secondFactorTTL {
+		failedAttempts = 0
+	}
+
+	if failedAttempts >= secondFactorMaxAttempts {
+		return false, ErrSecondFactorLocked
+	}
+
+	valid, err := consumeSecondFactor(ctx, tx, userID, secret, lastUsedStep, code, now)
+	if err != nil {
+		return false, err
+	}
+
+	if valid {
+		failedAttempts = 0
+	} else {
+		failedAttempts++
+	}
+
+	query = "UPDATE totp_secrets SET failed_attempts = $1, last_failed_at = $2 WHERE user_id = $3"
+	_, err = tx.ExecContext(ctx, query, failedAttempts, sql.NullTime{Time: now, Valid: !valid}, userID)
+	if err != nil {
+		return false, fmt.Errorf("could not sql update totp failed attempts: %w", err)
+	}
+
+	return valid, nil
+}
+
+func consumeSecondFactor(ctx context.Context, tx *sql.Tx, userID, secret string, lastUsedStep int64, code string, now time.Time) (bool, error) {
+	if step, ok := validateTOTP(secret, code, now, lastUsedStep); ok {
+		query := "UPDATE totp_secrets SET last_used_step = $1 WHERE user_id = $2"
+		_, err := tx.ExecContext(ctx, query, step, userID)
+		if err != nil {
+			return false, fmt.Errorf("could not sql update totp last used step: %w", err)
+		}
+
+		return true, nil
+	}
+
+	code = normalizeRecoveryCode(code)
+	if len(code) != recoveryCodeLen {
+		return false, nil
+	}
+
+	hash := sha256.Sum256([]byte(code))
+	query := "UPDATE recovery_codes SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL"
+	res, err := tx.ExecContext(ctx, query, userID, hash[:])
+	if err != nil {
+		return false, fmt.Errorf("could not sql update recovery code usage: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not get updated recovery code rows affected: %w", err)
+	}
+
+	return n != 0, nil
+}
+
+// replaceRecoveryCodes of the given user with new ones.
+// Only their hashes get stored.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID string) ([]string, error) {
+	query := "DELETE FROM recovery_codes WHERE user_id = $1"
+	_, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not sql delete recovery codes: %w", err)
+	}
+
+	codes := make([]string, recoveryCodesCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeLen)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("could not generate recovery code: %w", err)
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(b))[:recoveryCodeLen]
+		hash := sha256.Sum256([]byte(code))
+		query := "INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+		_, err := tx.ExecContext(ctx, query, userID, hash[:])
+		if err != nil {
+			return nil, fmt.Errorf("could not sql insert recovery code: %w", err)
+		}
+
+		codes[i] = code[:recoveryCodeLen/2] + "-" + code[recoveryCodeLen/2:]
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode so it can be typed with or without the dash
+// and in any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return code
+}
+
+func totpURI(username, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", totpIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", strconv.Itoa(totpDigits))
+	q.Set("period", strconv.Itoa(totpPeriod))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + username,
+		RawQuery: q.Encode(),
+	}).String()
+}
+
+// validateTOTP checks the code against the time steps around now
+// to allow for some clock drift.
+// Steps not after lastUsedStep are rejected so a code cannot be replayed.
+func validateTOTP(secret, code string, now time.Time, lastUsedStep int64) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - 1; step <= current+1; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode as described in RFC 6238 using HMAC-SHA1.
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, bin%mod)
+}
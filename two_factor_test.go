@@ -0,0 +1,112 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+// Test vectors from RFC 6238 appendix B, truncated to 6 digits.
+func Test_totpCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tt := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tc := range tt {
+		got := totpCode(key, tc.unix/totpPeriod)
+		testutil.WantEq(t, tc.want, got, "totp code")
+	}
+}
+
+func Test_validateTOTP(t *testing.T) {
+	key := []byte("12345678901234567890")
+	secret := totpEncoding.EncodeToString(key)
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	t.Run("current_step", func(t *testing.T) {
+		step, ok := validateTOTP(secret, totpCode(key, current), now, 0)
+		testutil.WantEq(t, true, ok, "ok")
+		testutil.WantEq(t, current, step, "step")
+	})
+
+	t.Run("clock_drift", func(t *testing.T) {
+		step, ok := validateTOTP(secret, totpCode(key, current-1), now, 0)
+		testutil.WantEq(t, true, ok, "ok")
+		testutil.WantEq(t, current-1, step, "step")
+	})
+
+	t.Run("too_old", func(t *testing.T) {
+		_, ok := validateTOTP(secret, totpCode(key, current-2), now, 0)
+		testutil.WantEq(t, false, ok, "ok")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		_, ok := validateTOTP(secret, totpCode(key, current), now, current)
+		testutil.WantEq(t, false, ok, "ok")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, ok := validateTOTP(secret, "nope", now, 0)
+		testutil.WantEq(t, false, ok, "ok")
+	})
+}
+
+func Test_normalizeRecoveryCode(t *testing.T) {
+	testutil.WantEq(t, "abcdefghij", normalizeRecoveryCode("ABCDE-fghij"), "recovery code")
+	testutil.WantEq(t, "abcdefghij", normalizeRecoveryCode("abcde fghij"), "recovery code")
+}
+
+func TestService_DisableTOTP(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.DisableTOTP(context.Background(), "123456")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping second factor lockout integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		key := []byte("12345678901234567890")
+		u := createTestUser(t)
+		_, err := testDB.ExecContext(ctx, "INSERT INTO totp_secrets (user_id, secret, enabled_at) VALUES ($1, $2, now())", u.ID, totpEncoding.EncodeToString(key))
+		testutil.WantEq(t, nil, err, "insert totp secret error")
+
+		ctx = context.WithValue(ctx, KeyAuthUserID, u.ID)
+		for i := 0; i < secondFactorMaxAttempts; i++ {
+			_, err = svc.RegenerateRecoveryCodes(ctx, "000000")
+			testutil.WantEq(t, ErrInvalidSecondFactorCode, err, "wrong code error")
+		}
+
+		code := totpCode(key, time.Now().Unix()/totpPeriod)
+		err = svc.DisableTOTP(ctx, code)
+		testutil.WantEq(t, ErrSecondFactorLocked, err, "right code after lockout error")
+
+		var enabled bool
+		err = testDB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM totp_secrets WHERE user_id = $1)", u.ID).Scan(&enabled)
+		testutil.WantEq(t, nil, err, "select totp secret error")
+		testutil.WantEq(t, true, enabled, "totp still enabled")
+
+		_, err = testDB.ExecContext(ctx, "UPDATE totp_secrets SET last_failed_at = now() - INTERVAL '1 hour' WHERE user_id = $1", u.ID)
+		testutil.WantEq(t, nil, err, "expire lockout error")
+
+		err = svc.DisableTOTP(ctx, code)
+		testutil.WantEq(t, nil, err, "right code after lockout expired error")
+	})
+}
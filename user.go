@@ -1,23 +1,19 @@
 package nakama
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
 	"io"
 	"regexp"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 	"github.com/disintegration/imaging"
 	gonanoid "github.com/matoous/go-nanoid/v2"
-
-	"github.com/nakamauwu/nakama/storage"
 )
 
 const (
@@ -44,7 +40,8 @@ var (
 	ErrInvalidUsername = InvalidArgumentError("invalid username")
 	// ErrEmailTaken denotes an email already taken.
 	ErrEmailTaken = AlreadyExistsError("email taken")
-	// ErrUsernameTaken denotes a username already taken.
+	// ErrUsernameTaken denotes a username already taken,
+	// or released by someone else too recently.
 	ErrUsernameTaken = AlreadyExistsError("username taken")
 	// ErrUserNotFound denotes a not found user.
 	ErrUserNotFound = NotFoundError("user not found")
@@ -71,27 +68,39 @@ type User struct {
 	ID        string  `json:"id,omitempty"`
 	Username  string  `json:"username"`
 	AvatarURL *string `json:"avatarURL"`
+	// Role is only set for the authenticated user.
+	Role Role `json:"role,omitempty"`
 }
 
 // UserProfile model.
 type UserProfile struct {
 	User
-	Email          string  `json:"email,omitempty"`
-	CoverURL       *string `json:"coverURL"`
-	Bio            *string `json:"bio"`
-	Waifu          *string `json:"waifu"`
-	Husbando       *string `json:"husbando"`
-	FollowersCount int     `json:"followersCount"`
-	FolloweesCount int     `json:"followeesCount"`
-	Me             bool    `json:"me"`
-	Following      bool    `json:"following"`
-	Followeed      bool    `json:"followeed"`
+	Email                string  `json:"email,omitempty"`
+	CoverURL             *string `json:"coverURL"`
+	Bio                  *string `json:"bio"`
+	Waifu                *string `json:"waifu"`
+	Husbando             *string `json:"husbando"`
+	FollowersCount       int     `json:"followersCount"`
+	FolloweesCount       int     `json:"followeesCount"`
+	Me                   bool    `json:"me"`
+	Following            bool    `json:"following"`
+	Followeed            bool    `json:"followeed"`
+	Blocking             bool    `json:"blocking"`
+	Private              bool    `json:"private"`
+	FollowRequested      bool    `json:"followRequested"`
+	RenamedFrom          string  `json:"renamedFrom,omitempty"`
+	MutualFollowersCount int     `json:"mutualFollowersCount,omitempty"`
+	MutualFollowers      []User  `json:"mutualFollowers,omitempty"`
+
+	// searchRank is set when searching, for the end cursor.
+	searchRank *float64
 }
 
 // ToggleFollowOutput response.
 type ToggleFollowOutput struct {
-	Following      bool `json:"following"`
-	FollowersCount int  `json:"followersCount"`
+	Following       bool `json:"following"`
+	FollowRequested bool `json:"followRequested"`
+	FollowersCount  int  `json:"followersCount"`
 }
 
 type UserProfiles []UserProfile
@@ -102,48 +111,100 @@ func (uu UserProfiles) EndCursor() *string {
 	}
 
 	last := uu[len(uu)-1]
+	if last.searchRank != nil {
+		return ptrString(encodeRankCursor(last.Username, *last.searchRank))
+	}
+
 	return ptrString(encodeSimpleCursor(last.Username))
 }
 
-// Users in ascending order with forward pagination and filtered by username.
+// Users in ascending order with forward pagination.
+// When searching, users are matched by username, bio, waifu and husbando,
+// and sorted by relevance instead; boosting those followed by
+// the people the authenticated user follows.
+// Suspended accounts are left out.
 func (s *Service) Users(ctx context.Context, search string, first uint64, after *string) (UserProfiles, error) {
 	search = strings.TrimSpace(search)
 	first = normalizePageSize(first)
 
 	var afterUsername string
+	var afterRank float64
 	if after != nil {
 		var err error
-		afterUsername, err = decodeSimpleCursor(*after)
+		if search != "" {
+			afterUsername, afterRank, err = decodeRankCursor(*after)
+		} else {
+			afterUsername, err = decodeSimpleCursor(*after)
+		}
 		if err != nil || !ValidUsername(afterUsername) {
 			return nil, ErrInvalidCursor
 		}
 	}
 
+	var tsquery string
+	if search != "" {
+		tsquery = userSearchQuery(search)
+		if tsquery == "" {
+			return nil, nil
+		}
+	}
+
 	uid, auth := ctx.Value(KeyAuthUserID).(string)
 	query, args, err := buildQuery(`
-		SELECT id, email, username, avatar, cover, bio, waifu, husbando, followers_count, followees_count
+		{{ if .tsquery }}
+		WITH ranked_users AS (
+			SELECT users.*
+			, ts_rank(users.search_vector, to_tsquery('simple', @tsquery))
+				+ CASE WHEN lower(users.username) = lower(@search) THEN 1.0 ELSE 0.0 END
+				{{ if .auth }}
+				+ ln(1 + (
+					SELECT count(*) FROM follows AS followees
+					INNER JOIN follows AS followees_follows
+						ON followees_follows.follower_id = followees.followee_id
+					WHERE followees.follower_id = @uid
+						AND followees_follows.followee_id = users.id
+				)::FLOAT) / 10
+				{{ end }}
+				AS rank
+			FROM users
+			WHERE users.search_vector @@ to_tsquery('simple', @tsquery)
+			AND NOT `+userSuspended+`
+			{{ if .auth }}AND `+notBlocked("users.id")+`{{ end }}
+		)
+		{{ end }}
+		SELECT id, email, username, avatar, cover, bio, waifu, husbando, followers_count, followees_count, private
 		{{ if .auth }}
 		, followers.follower_id IS NOT NULL AS following
 		, followees.followee_id IS NOT NULL AS followeed
 		{{ end }}
-		FROM users
+		{{ if .tsquery }}, users.rank{{ end }}
+		FROM {{ if .tsquery }}ranked_users AS users{{ else }}users{{ end }}
 		{{ if .auth }}
 		LEFT JOIN follows AS followers
 			ON followers.follower_id = @uid AND followers.followee_id = users.id
 		LEFT JOIN follows AS followees
 			ON followees.follower_id = users.id AND followees.followee_id = @uid
 		{{ end }}
-		{{ if or .search .afterUsername }}WHERE{{ end }}
-		{{ if .search }}username ILIKE '%' || @search || '%'{{ end }}
-		{{ if and .search .afterUsername }}AND{{ end }}
-		{{ if .afterUsername }}username > @afterUsername{{ end }}
-		ORDER BY username ASC
+		{{ if .tsquery }}
+			{{ if .afterUsername }}
+			WHERE users.rank < @afterRank
+				OR (users.rank = @afterRank AND users.username > @afterUsername)
+			{{ end }}
+			ORDER BY users.rank DESC, users.username ASC
+		{{ else }}
+			WHERE NOT `+userSuspended+`
+			{{ if .auth }}AND `+notBlocked("users.id")+`{{ end }}
+			{{ if .afterUsername }}AND username > @afterUsername{{ end }}
+			ORDER BY username ASC
+		{{ end }}
 		LIMIT @first`, map[string]interface{}{
 		"auth":          auth,
 		"uid":           uid,
 		"search":        search,
+		"tsquery":       tsquery,
 		"first":         first,
 		"afterUsername": afterUsername,
+		"afterRank":     afterRank,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not build users sql query: %w", err)
@@ -170,10 +231,15 @@ func (s *Service) Users(ctx context.Context, search string, first uint64, after
 			&u.Husbando,
 			&u.FollowersCount,
 			&u.FolloweesCount,
+			&u.Private,
 		}
 		if auth {
 			dest = append(dest, &u.Following, &u.Followeed)
 		}
+		if tsquery != "" {
+			u.searchRank = new(float64)
+			dest = append(dest, u.searchRank)
+		}
 		if err = rows.Scan(dest...); err != nil {
 			return nil, fmt.Errorf("could not scan user: %w", err)
 		}
@@ -195,6 +261,19 @@ func (s *Service) Users(ctx context.Context, search string, first uint64, after
 	return uu, nil
 }
 
+// userSearchQuery turns a search into a tsquery
+// that matches every word in it as a prefix.
+// It is empty when the search has no words.
+func userSearchQuery(search string) string {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
 type Usernames []string
 
 func (uu Usernames) EndCursor() *string {
@@ -227,7 +306,7 @@ func (s *Service) Usernames(ctx context.Context, startingWith string, first uint
 	query, args, err := buildQuery(`
 		SELECT username FROM users
 		WHERE username ILIKE @startingWith || '%'
-		{{ if .auth }}AND users.id != @uid{{ end }}
+		{{ if .auth }}AND users.id != @uid AND `+notBlocked("users.id")+`{{ end }}
 		{{ if .afterUsername }}AND username > @afterUsername{{ end }}
 		ORDER BY username ASC
 		LIMIT @first`, map[string]interface{}{
@@ -285,6 +364,8 @@ func (s *Service) userByID(ctx context.Context, id string) (User, error) {
 }
 
 // User with the given username.
+// An old username of a user that changed it resolves to them,
+// with RenamedFrom set so the caller can redirect to the current one.
 func (s *Service) User(ctx context.Context, username string) (UserProfile, error) {
 	var u UserProfile
 
@@ -293,12 +374,36 @@ func (s *Service) User(ctx context.Context, username string) (UserProfile, error
 		return u, ErrInvalidUsername
 	}
 
+	u, err := s.user(ctx, username)
+	if err != ErrUserNotFound {
+		return u, err
+	}
+
+	current, err := renamedUsername(ctx, s.DB, username)
+	if err != nil {
+		return u, err
+	}
+
+	u, err = s.user(ctx, current)
+	if err != nil {
+		return u, err
+	}
+
+	u.RenamedFrom = username
+	return u, nil
+}
+
+func (s *Service) user(ctx context.Context, username string) (UserProfile, error) {
+	var u UserProfile
+
 	uid, auth := ctx.Value(KeyAuthUserID).(string)
 	query, args, err := buildQuery(`
-		SELECT id, email, avatar, cover, bio, waifu, husbando, followers_count, followees_count
+		SELECT id, email, avatar, cover, bio, waifu, husbando, followers_count, followees_count, private
 		{{if .auth}}
 		, followers.follower_id IS NOT NULL AS following
 		, followees.followee_id IS NOT NULL AS followeed
+		, blocks.blocked_id IS NOT NULL AS blocking
+		, follow_requests.follower_id IS NOT NULL AS follow_requested
 		{{end}}
 		FROM users
 		{{if .auth}}
@@ -306,6 +411,10 @@ func (s *Service) User(ctx context.Context, username string) (UserProfile, error
 			ON followers.follower_id = @uid AND followers.followee_id = users.id
 		LEFT JOIN follows AS followees
 			ON followees.follower_id = users.id AND followees.followee_id = @uid
+		LEFT JOIN blocks
+			ON blocks.blocker_id = @uid AND blocks.blocked_id = users.id
+		LEFT JOIN follow_requests
+			ON follow_requests.follower_id = @uid AND follow_requests.followee_id = users.id
 		{{end}}
 		WHERE username = @username`, map[string]interface{}{
 		"auth":     auth,
@@ -317,9 +426,9 @@ func (s *Service) User(ctx context.Context, username string) (UserProfile, error
 	}
 
 	var avatar, cover sql.NullString
-	dest := []interface{}{&u.ID, &u.Email, &avatar, &cover, &u.Bio, &u.Waifu, &u.Husbando, &u.FollowersCount, &u.FolloweesCount}
+	dest := []interface{}{&u.ID, &u.Email, &avatar, &cover, &u.Bio, &u.Waifu, &u.Husbando, &u.FollowersCount, &u.FolloweesCount, &u.Private}
 	if auth {
-		dest = append(dest, &u.Following, &u.Followeed)
+		dest = append(dest, &u.Following, &u.Followeed, &u.Blocking, &u.FollowRequested)
 	}
 	err = s.DB.QueryRowContext(ctx, query, args...).Scan(dest...)
 	if err == sql.ErrNoRows {
@@ -332,6 +441,12 @@ func (s *Service) User(ctx context.Context, username string) (UserProfile, error
 
 	u.Username = username
 	u.Me = auth && uid == u.ID
+	if auth && !u.Me {
+		u.MutualFollowersCount, u.MutualFollowers, err = s.mutualFollowersSummary(ctx, uid, u.ID)
+		if err != nil {
+			return u, err
+		}
+	}
 	if !u.Me {
 		u.ID = ""
 		u.Email = ""
@@ -346,8 +461,13 @@ type UpdateUserParams struct {
 	Bio      *string `json:"bio"`
 	Waifu    *string `json:"waifu"`
 	Husbando *string `json:"husbando"`
+	Private  *bool   `json:"private"`
 }
 
+// UpdateUser of the authenticated user.
+// The old username is kept in the history to resolve it to the user,
+// and nobody else can claim it for a while.
+// Making the account public approves all pending follow requests.
 func (s *Service) UpdateUser(ctx context.Context, params UpdateUserParams) error {
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
 	if !ok {
@@ -382,26 +502,58 @@ func (s *Service) UpdateUser(ctx context.Context, params UpdateUserParams) error
 		}
 	}
 
-	query := `
-		UPDATE users SET
-			username = COALESCE($1, username)
-			, bio = $2
-			, waifu = $3
-			, husbando = $4
-		WHERE id = $5`
-	_, err := s.DB.ExecContext(ctx, query, params.Username, params.Bio, params.Waifu, params.Husbando, uid)
-	if isUniqueViolation(err) {
-		return ErrUsernameTaken
-	}
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		if params.Username != nil {
+			var oldUsername string
+			query := "SELECT username FROM users WHERE id = $1"
+			if err := tx.QueryRowContext(ctx, query, uid).Scan(&oldUsername); err != nil {
+				return fmt.Errorf("could not sql query select old username: %w", err)
+			}
 
-	if err != nil {
-		return fmt.Errorf("could not sql update user: %w", err)
-	}
+			if oldUsername != *params.Username {
+				reserved, err := usernameReserved(ctx, tx, *params.Username, uid)
+				if err != nil {
+					return err
+				}
 
-	return nil
+				if reserved {
+					return ErrUsernameTaken
+				}
+
+				if err := releaseUsername(ctx, tx, oldUsername, uid); err != nil {
+					return err
+				}
+			}
+		}
+
+		query := `
+			UPDATE users SET
+				username = COALESCE($1, username)
+				, bio = $2
+				, waifu = $3
+				, husbando = $4
+				, private = COALESCE($5, private)
+			WHERE id = $6`
+		_, err := tx.ExecContext(ctx, query, params.Username, params.Bio, params.Waifu, params.Husbando, params.Private, uid)
+		if isUniqueViolation(err) {
+			return ErrUsernameTaken
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql update user: %w", err)
+		}
+
+		if params.Private != nil && !*params.Private {
+			return approveFollowRequests(ctx, tx, uid)
+		}
+
+		return nil
+	})
 }
 
 // UpdateAvatar of the authenticated user returning the new avatar URL.
+// Animated GIF and WebP avatars keep their frames, and a static poster
+// of the first frame is stored next to them; see posterFileName.
 // Please limit the reader before hand using MaxAvatarBytes.
 func (s *Service) UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, error) {
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
@@ -414,28 +566,20 @@ func (s *Service) UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, er
 		return "", fmt.Errorf("update avatar: detect content type: %w", err)
 	}
 
-	if ct != "image/png" && ct != "image/jpeg" {
-		return "", ErrUnsupportedAvatarFormat
-	}
-
-	img, err := imaging.Decode(io.LimitReader(r, MaxAvatarBytes), imaging.AutoOrientation(true))
-	if err == image.ErrFormat {
+	if !supportedImageType(ct) {
 		return "", ErrUnsupportedAvatarFormat
 	}
 
+	b, err := io.ReadAll(io.LimitReader(r, MaxAvatarBytes))
 	if err != nil {
 		return "", fmt.Errorf("could not read avatar: %w", err)
 	}
 
-	buf := &bytes.Buffer{}
-	img = imaging.Fill(img, 400, 400, imaging.Center, imaging.CatmullRom)
-	if ct == "image/png" {
-		err = png.Encode(buf, img)
-	} else {
-		err = jpeg.Encode(buf, img, nil)
-	}
+	img, err := encodeImage(b, ct, MaxAvatarBytes, func(img image.Image) image.Image {
+		return imaging.Fill(img, 400, 400, imaging.Center, imaging.CatmullRom)
+	}, ErrUnsupportedAvatarFormat)
 	if err != nil {
-		return "", fmt.Errorf("could not resize avatar: %w", err)
+		return "", err
 	}
 
 	avatarFileName, err := gonanoid.New()
@@ -443,13 +587,9 @@ func (s *Service) UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, er
 		return "", fmt.Errorf("could not generate avatar filename: %w", err)
 	}
 
-	if ct == "image/png" {
-		avatarFileName += ".png"
-	} else {
-		avatarFileName += ".jpg"
-	}
+	avatarFileName += img.ext
 
-	err = s.Store.Store(ctx, AvatarsBucket, avatarFileName, buf.Bytes(), storage.StoreWithContentType(ct))
+	err = s.storeImage(ctx, AvatarsBucket, avatarFileName, img)
 	if err != nil {
 		return "", fmt.Errorf("could not store avatar file: %w", err)
 	}
@@ -462,29 +602,20 @@ func (s *Service) UpdateAvatar(ctx context.Context, r io.ReadSeeker) (string, er
 	row := s.DB.QueryRowContext(ctx, query, avatarFileName, uid)
 	err = row.Scan(&oldAvatar)
 	if err != nil {
-		defer func() {
-			err := s.Store.Delete(context.Background(), AvatarsBucket, avatarFileName)
-			if err != nil {
-				_ = s.Logger.Log("error", fmt.Errorf("could not delete avatar file after user update fail: %w", err))
-			}
-		}()
-
+		defer s.deleteFile(AvatarsBucket, avatarFileName)
 		return "", fmt.Errorf("could not update avatar: %w", err)
 	}
 
 	if oldAvatar.Valid {
-		defer func() {
-			err := s.Store.Delete(context.Background(), AvatarsBucket, oldAvatar.String)
-			if err != nil {
-				_ = s.Logger.Log("error", fmt.Errorf("could not delete old avatar: %w", err))
-			}
-		}()
+		defer s.deleteFile(AvatarsBucket, oldAvatar.String)
 	}
 
 	return s.AvatarURLPrefix + avatarFileName, nil
 }
 
 // UpdateCover of the authenticated user returning the new cover URL.
+// Animated GIF and WebP covers keep their frames, and a static poster
+// of the first frame is stored next to them; see posterFileName.
 // Please limit the reader before hand using MaxCoverBytes.
 func (s *Service) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, error) {
 	uid, ok := ctx.Value(KeyAuthUserID).(string)
@@ -497,28 +628,20 @@ func (s *Service) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, err
 		return "", fmt.Errorf("update cover: detect content type: %w", err)
 	}
 
-	if ct != "image/png" && ct != "image/jpeg" {
-		return "", ErrUnsupportedAvatarFormat
-	}
-
-	img, err := imaging.Decode(io.LimitReader(r, MaxCoverBytes), imaging.AutoOrientation(true))
-	if err == image.ErrFormat {
+	if !supportedImageType(ct) {
 		return "", ErrUnsupportedCoverFormat
 	}
 
+	b, err := io.ReadAll(io.LimitReader(r, MaxCoverBytes))
 	if err != nil {
 		return "", fmt.Errorf("could not read cover: %w", err)
 	}
 
-	buf := &bytes.Buffer{}
-	img = imaging.CropCenter(img, 2560, 423)
-	if ct == "image/png" {
-		err = png.Encode(buf, img)
-	} else {
-		err = jpeg.Encode(buf, img, nil)
-	}
+	img, err := encodeImage(b, ct, MaxCoverBytes, func(img image.Image) image.Image {
+		return imaging.CropCenter(img, 2560, 423)
+	}, ErrUnsupportedCoverFormat)
 	if err != nil {
-		return "", fmt.Errorf("could not resize cover: %w", err)
+		return "", err
 	}
 
 	coverFileName, err := gonanoid.New()
@@ -526,13 +649,9 @@ func (s *Service) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, err
 		return "", fmt.Errorf("could not generate cover filename: %w", err)
 	}
 
-	if ct == "image/png" {
-		coverFileName += ".png"
-	} else {
-		coverFileName += ".jpg"
-	}
+	coverFileName += img.ext
 
-	err = s.Store.Store(ctx, CoversBucket, coverFileName, buf.Bytes(), storage.StoreWithContentType(ct))
+	err = s.storeImage(ctx, CoversBucket, coverFileName, img)
 	if err != nil {
 		return "", fmt.Errorf("could not store cover file: %w", err)
 	}
@@ -545,29 +664,20 @@ func (s *Service) UpdateCover(ctx context.Context, r io.ReadSeeker) (string, err
 	row := s.DB.QueryRowContext(ctx, query, coverFileName, uid)
 	err = row.Scan(&oldCover)
 	if err != nil {
-		defer func() {
-			err := s.Store.Delete(context.Background(), CoversBucket, coverFileName)
-			if err != nil {
-				_ = s.Logger.Log("error", fmt.Errorf("could not delete cover file after user update fail: %w", err))
-			}
-		}()
-
+		defer s.deleteFile(CoversBucket, coverFileName)
 		return "", fmt.Errorf("could not update cover: %w", err)
 	}
 
 	if oldCover.Valid {
-		defer func() {
-			err := s.Store.Delete(context.Background(), CoversBucket, oldCover.String)
-			if err != nil {
-				_ = s.Logger.Log("error", fmt.Errorf("could not delete old cover: %w", err))
-			}
-		}()
+		defer s.deleteFile(CoversBucket, oldCover.String)
 	}
 
 	return s.CoverURLPrefix + coverFileName, nil
 }
 
 // ToggleFollow between two users.
+// Following a private account sends a follow request instead,
+// and toggling again cancels it.
 func (s *Service) ToggleFollow(ctx context.Context, username string) (ToggleFollowOutput, error) {
 	var out ToggleFollowOutput
 	followerID, ok := ctx.Value(KeyAuthUserID).(string)
@@ -581,9 +691,14 @@ func (s *Service) ToggleFollow(ctx context.Context, username string) (ToggleFoll
 	}
 
 	var followeeID string
+	var followed, requested bool
 	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
-		query := "SELECT id FROM users WHERE username = $1"
-		err := tx.QueryRowContext(ctx, query, username).Scan(&followeeID)
+		out = ToggleFollowOutput{}
+		followed, requested = false, false
+
+		var private bool
+		query := "SELECT id, private FROM users WHERE username = $1"
+		err := tx.QueryRowContext(ctx, query, username).Scan(&followeeID, &private)
 		if err == sql.ErrNoRows {
 			return ErrUserNotFound
 		}
@@ -596,17 +711,18 @@ func (s *Service) ToggleFollow(ctx context.Context, username string) (ToggleFoll
 			return ErrForbiddenFollow
 		}
 
+		var following bool
 		query = `
 			SELECT EXISTS (
 				SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2
 			)`
 		row := tx.QueryRowContext(ctx, query, followerID, followeeID)
-		err = row.Scan(&out.Following)
+		err = row.Scan(&following)
 		if err != nil {
 			return fmt.Errorf("could not query select existence of follow: %w", err)
 		}
 
-		if out.Following {
+		if following {
 			query = "DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2"
 			_, err = tx.ExecContext(ctx, query, followerID, followeeID)
 			if err != nil {
@@ -625,40 +741,91 @@ func (s *Service) ToggleFollow(ctx context.Context, username string) (ToggleFoll
 				Scan(&out.FollowersCount); err != nil {
 				return fmt.Errorf("could not decrement followers count: %w", err)
 			}
-		} else {
-			query = "INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2)"
-			_, err = tx.ExecContext(ctx, query, followerID, followeeID)
-			if err != nil {
-				return fmt.Errorf("could not insert follow: %w", err)
+
+			return nil
+		}
+
+		query = "DELETE FROM follow_requests WHERE follower_id = $1 AND followee_id = $2"
+		res, err := tx.ExecContext(ctx, query, followerID, followeeID)
+		if err != nil {
+			return fmt.Errorf("could not delete follow request: %w", err)
+		}
+
+		canceled, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get deleted follow request rows affected: %w", err)
+		}
+
+		if canceled != 0 {
+			query = "SELECT followers_count FROM users WHERE id = $1"
+			if err = tx.QueryRowContext(ctx, query, followeeID).Scan(&out.FollowersCount); err != nil {
+				return fmt.Errorf("could not query select followers count: %w", err)
 			}
 
-			query = "UPDATE users SET followees_count = followees_count + 1 WHERE id = $1"
-			if _, err = tx.ExecContext(ctx, query, followerID); err != nil {
-				return fmt.Errorf("could not increment followees count: %w", err)
+			return nil
+		}
+
+		isBlocked, err := blocked(ctx, tx, followerID, followeeID)
+		if err != nil {
+			return err
+		}
+
+		if isBlocked {
+			return ErrBlocked
+		}
+
+		if private {
+			query = "INSERT INTO follow_requests (follower_id, followee_id) VALUES ($1, $2)"
+			if _, err = tx.ExecContext(ctx, query, followerID, followeeID); err != nil {
+				return fmt.Errorf("could not insert follow request: %w", err)
 			}
 
-			query = `
-				UPDATE users SET followers_count = followers_count + 1 WHERE id = $1
-				RETURNING followers_count`
-			row = tx.QueryRowContext(ctx, query, followeeID)
-			err = row.Scan(&out.FollowersCount)
-			if err != nil {
-				return fmt.Errorf("could not increment followers count: %w", err)
+			query = "SELECT followers_count FROM users WHERE id = $1"
+			if err = tx.QueryRowContext(ctx, query, followeeID).Scan(&out.FollowersCount); err != nil {
+				return fmt.Errorf("could not query select followers count: %w", err)
 			}
+
+			out.FollowRequested = true
+			requested = true
+			return nil
+		}
+
+		query = "INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2)"
+		_, err = tx.ExecContext(ctx, query, followerID, followeeID)
+		if err != nil {
+			return fmt.Errorf("could not insert follow: %w", err)
 		}
 
+		query = "UPDATE users SET followees_count = followees_count + 1 WHERE id = $1"
+		if _, err = tx.ExecContext(ctx, query, followerID); err != nil {
+			return fmt.Errorf("could not increment followees count: %w", err)
+		}
+
+		query = `
+			UPDATE users SET followers_count = followers_count + 1 WHERE id = $1
+			RETURNING followers_count`
+		row = tx.QueryRowContext(ctx, query, followeeID)
+		err = row.Scan(&out.FollowersCount)
+		if err != nil {
+			return fmt.Errorf("could not increment followers count: %w", err)
+		}
+
+		out.Following = true
+		followed = true
 		return nil
 	})
 	if err != nil {
 		return out, err
 	}
 
-	out.Following = !out.Following
-
-	if out.Following {
+	if followed {
 		go s.notifyFollow(followerID, followeeID)
 	}
 
+	if requested {
+		go s.notifyFollowRequest("follow_request", followeeID, followerID)
+	}
+
 	return out, nil
 }
 
@@ -688,6 +855,7 @@ func (s *Service) Followers(ctx context.Context, username string, first uint64,
 		, users.cover
 		, users.followers_count
 		, users.followees_count
+		, users.private
 		{{ if .auth }}
 		, followers.follower_id IS NOT NULL AS following
 		, followees.followee_id IS NOT NULL AS followeed
@@ -733,6 +901,7 @@ func (s *Service) Followers(ctx context.Context, username string, first uint64,
 			&cover,
 			&u.FollowersCount,
 			&u.FolloweesCount,
+			&u.Private,
 		}
 		if auth {
 			dest = append(dest, &u.Following, &u.Followeed)
@@ -784,6 +953,7 @@ func (s *Service) Followees(ctx context.Context, username string, first uint64,
 		, users.cover
 		, users.followers_count
 		, users.followees_count
+		, users.private
 		{{ if .auth }}
 		, followers.follower_id IS NOT NULL AS following
 		, followees.followee_id IS NOT NULL AS followeed
@@ -829,6 +999,7 @@ func (s *Service) Followees(ctx context.Context, username string, first uint64,
 			&cover,
 			&u.FollowersCount,
 			&u.FolloweesCount,
+			&u.Private,
 		}
 		if auth {
 			dest = append(dest, &u.Following, &u.Followeed)
@@ -0,0 +1,599 @@
+package nakama
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+)
+
+const (
+	// userListNameMaxLength is how many characters a list name can have.
+	userListNameMaxLength = 50
+	// userListsMax is how many lists a user can have.
+	userListsMax = 50
+)
+
+var (
+	// ErrInvalidUserListID denotes an invalid user list ID; that is not uuid.
+	ErrInvalidUserListID = InvalidArgumentError("invalid user list ID")
+	// ErrInvalidUserListName denotes an empty user list name,
+	// or one that exceeds the max allowed characters (50).
+	ErrInvalidUserListName = InvalidArgumentError("invalid user list name")
+	// ErrUserListNotFound denotes a not found user list,
+	// or a private one from someone else.
+	ErrUserListNotFound = NotFoundError("user list not found")
+	// ErrUserListMemberNotFound denotes a user that is not a member of the list.
+	ErrUserListMemberNotFound = NotFoundError("user list member not found")
+	// ErrTooManyUserLists denotes that the user reached the max allowed lists (50).
+	ErrTooManyUserLists = ResourceExhaustedError("too many user lists")
+)
+
+// UserList is a curated list of users with its own timeline.
+// Members don't have to be followed.
+// Private lists are only visible to their owner.
+type UserList struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"-"`
+	Name         string    `json:"name"`
+	Private      bool      `json:"private"`
+	MembersCount int       `json:"membersCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+	User         *User     `json:"user,omitempty"`
+	Mine         bool      `json:"mine"`
+}
+
+type CreateUserList struct {
+	Name    string `json:"name"`
+	Private bool   `json:"private"`
+}
+
+type UpdateUserList struct {
+	ID      string  `json:"-"`
+	Name    *string `json:"name"`
+	Private *bool   `json:"private"`
+}
+
+// CreateUserList for the authenticated user.
+func (s *Service) CreateUserList(ctx context.Context, in CreateUserList) (UserList, error) {
+	var out UserList
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	in.Name = smartTrim(in.Name)
+	if !validUserListName(in.Name) {
+		return out, ErrInvalidUserListName
+	}
+
+	err := crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var count int
+		query := "SELECT count(*) FROM user_lists WHERE user_id = $1"
+		if err := tx.QueryRowContext(ctx, query, uid).Scan(&count); err != nil {
+			return fmt.Errorf("could not sql count user lists: %w", err)
+		}
+
+		if count >= userListsMax {
+			return ErrTooManyUserLists
+		}
+
+		query = "INSERT INTO user_lists (user_id, name, private) VALUES ($1, $2, $3) RETURNING id, created_at"
+		err := tx.QueryRowContext(ctx, query, uid, in.Name, in.Private).Scan(&out.ID, &out.CreatedAt)
+		if isForeignKeyViolation(err) {
+			return ErrUserGone
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql insert user list: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return out, err
+	}
+
+	out.UserID = uid
+	out.Name = in.Name
+	out.Private = in.Private
+	out.Mine = true
+
+	return out, nil
+}
+
+// UserLists from the user with the given username. Newest first.
+// Private ones are included only for the owner.
+func (s *Service) UserLists(ctx context.Context, username string) ([]UserList, error) {
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	uid, _ := ctx.Value(KeyAuthUserID).(string)
+	query := `
+		SELECT user_lists.id, user_lists.user_id, user_lists.name, user_lists.private
+		, user_lists.members_count, user_lists.created_at
+		FROM user_lists
+		INNER JOIN users ON user_lists.user_id = users.id
+		WHERE users.username = $1
+			AND (NOT user_lists.private OR user_lists.user_id = $2::UUID)
+		ORDER BY user_lists.created_at DESC`
+	rows, err := s.DB.QueryContext(ctx, query, username, sql.NullString{String: uid, Valid: uid != ""})
+	if err != nil {
+		return nil, fmt.Errorf("could not sql query select user lists: %w", err)
+	}
+
+	defer rows.Close()
+
+	var ll []UserList
+	for rows.Next() {
+		var l UserList
+		err := rows.Scan(&l.ID, &l.UserID, &l.Name, &l.Private, &l.MembersCount, &l.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not sql scan user list: %w", err)
+		}
+
+		l.Mine = l.UserID == uid
+		ll = append(ll, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate user list rows: %w", err)
+	}
+
+	return ll, nil
+}
+
+// UserList with the given ID, along with its owner.
+func (s *Service) UserList(ctx context.Context, listID string) (UserList, error) {
+	if !reUUID.MatchString(listID) {
+		return UserList{}, ErrInvalidUserListID
+	}
+
+	uid, _ := ctx.Value(KeyAuthUserID).(string)
+	return s.userList(ctx, listID, uid)
+}
+
+// UpdateUserList from the authenticated user.
+func (s *Service) UpdateUserList(ctx context.Context, in UpdateUserList) (UserList, error) {
+	var out UserList
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return out, ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(in.ID) {
+		return out, ErrInvalidUserListID
+	}
+
+	if in.Name != nil {
+		*in.Name = smartTrim(*in.Name)
+		if !validUserListName(*in.Name) {
+			return out, ErrInvalidUserListName
+		}
+	}
+
+	query := `
+		UPDATE user_lists SET
+			name = COALESCE($1, name)
+			, private = COALESCE($2, private)
+		WHERE id = $3 AND user_id = $4`
+	res, err := s.DB.ExecContext(ctx, query, in.Name, in.Private, in.ID, uid)
+	if err != nil {
+		return out, fmt.Errorf("could not sql update user list: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return out, fmt.Errorf("could not get updated user list rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return out, ErrUserListNotFound
+	}
+
+	return s.userList(ctx, in.ID, uid)
+}
+
+// DeleteUserList from the authenticated user.
+func (s *Service) DeleteUserList(ctx context.Context, listID string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(listID) {
+		return ErrInvalidUserListID
+	}
+
+	query := "DELETE FROM user_lists WHERE id = $1 AND user_id = $2"
+	res, err := s.DB.ExecContext(ctx, query, listID, uid)
+	if err != nil {
+		return fmt.Errorf("could not sql delete user list: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get deleted user list rows affected: %w", err)
+	}
+
+	if n == 0 {
+		return ErrUserListNotFound
+	}
+
+	return nil
+}
+
+// AddUserListMember adds the user with the given username
+// to a list from the authenticated user.
+// Adding somebody that is already a member does nothing.
+func (s *Service) AddUserListMember(ctx context.Context, listID, username string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(listID) {
+		return ErrInvalidUserListID
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		if err := ownUserList(ctx, tx, listID, uid); err != nil {
+			return err
+		}
+
+		var memberID string
+		query := "SELECT id FROM users WHERE username = $1"
+		err := tx.QueryRowContext(ctx, query, username).Scan(&memberID)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not sql query select user list member id: %w", err)
+		}
+
+		isBlocked, err := blocked(ctx, tx, uid, memberID)
+		if err != nil {
+			return err
+		}
+
+		if isBlocked {
+			return ErrBlocked
+		}
+
+		query = "INSERT INTO user_list_members (list_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		res, err := tx.ExecContext(ctx, query, listID, memberID)
+		if err != nil {
+			return fmt.Errorf("could not sql insert user list member: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get inserted user list member rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		query = "UPDATE user_lists SET members_count = members_count + 1 WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, listID); err != nil {
+			return fmt.Errorf("could not increment user list members count: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RemoveUserListMember removes the user with the given username
+// from a list of the authenticated user.
+func (s *Service) RemoveUserListMember(ctx context.Context, listID, username string) error {
+	uid, ok := ctx.Value(KeyAuthUserID).(string)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if !reUUID.MatchString(listID) {
+		return ErrInvalidUserListID
+	}
+
+	username = strings.TrimSpace(username)
+	if !ValidUsername(username) {
+		return ErrInvalidUsername
+	}
+
+	return crdb.ExecuteTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		if err := ownUserList(ctx, tx, listID, uid); err != nil {
+			return err
+		}
+
+		query := `
+			DELETE FROM user_list_members
+			WHERE list_id = $1 AND user_id = (SELECT id FROM users WHERE username = $2)`
+		res, err := tx.ExecContext(ctx, query, listID, username)
+		if err != nil {
+			return fmt.Errorf("could not sql delete user list member: %w", err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("could not get deleted user list member rows affected: %w", err)
+		}
+
+		if n == 0 {
+			return ErrUserListMemberNotFound
+		}
+
+		query = "UPDATE user_lists SET members_count = members_count - 1 WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, listID); err != nil {
+			return fmt.Errorf("could not decrement user list members count: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UserListMembers in ascending order with forward pagination.
+func (s *Service) UserListMembers(ctx context.Context, listID string, first uint64, after *string) (UserProfiles, error) {
+	if !reUUID.MatchString(listID) {
+		return nil, ErrInvalidUserListID
+	}
+
+	var afterUsername string
+	if after != nil {
+		var err error
+		afterUsername, err = decodeSimpleCursor(*after)
+		if err != nil || !ValidUsername(afterUsername) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
+	if _, err := s.userList(ctx, listID, uid); err != nil {
+		return nil, err
+	}
+
+	first = normalizePageSize(first)
+	query, args, err := buildQuery(`
+		SELECT users.id
+		, users.username
+		, users.avatar
+		, users.cover
+		, users.followers_count
+		, users.followees_count
+		, users.private
+		{{ if .auth }}
+		, followers.follower_id IS NOT NULL AS following
+		, followees.followee_id IS NOT NULL AS followeed
+		{{ end }}
+		FROM user_list_members
+		INNER JOIN users ON user_list_members.user_id = users.id
+		{{ if .auth }}
+		LEFT JOIN follows AS followers
+			ON followers.follower_id = @uid AND followers.followee_id = users.id
+		LEFT JOIN follows AS followees
+			ON followees.follower_id = users.id AND followees.followee_id = @uid
+		{{ end }}
+		WHERE user_list_members.list_id = @listID
+		{{ if .afterUsername }}AND users.username > @afterUsername{{ end }}
+		ORDER BY users.username ASC
+		LIMIT @first`, map[string]interface{}{
+		"auth":          auth,
+		"uid":           uid,
+		"listID":        listID,
+		"first":         first,
+		"afterUsername": afterUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build user list members sql query: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query select user list members: %w", err)
+	}
+
+	defer rows.Close()
+
+	var uu UserProfiles
+	for rows.Next() {
+		var u UserProfile
+		var avatar, cover sql.NullString
+		dest := []interface{}{
+			&u.ID,
+			&u.Username,
+			&avatar,
+			&cover,
+			&u.FollowersCount,
+			&u.FolloweesCount,
+			&u.Private,
+		}
+		if auth {
+			dest = append(dest, &u.Following, &u.Followeed)
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("could not scan user list member: %w", err)
+		}
+
+		u.Me = auth && uid == u.ID
+		u.ID = ""
+		u.AvatarURL = s.avatarURL(avatar)
+		u.CoverURL = s.coverURL(cover)
+		uu = append(uu, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not iterate user list member rows: %w", err)
+	}
+
+	return uu, nil
+}
+
+// ListTimeline with the posts from the members of the given list
+// in descending order and with backward pagination.
+// Same cursors as Timeline.
+func (s *Service) ListTimeline(ctx context.Context, listID string, last uint64, before *string) (Posts, error) {
+	if !reUUID.MatchString(listID) {
+		return nil, ErrInvalidUserListID
+	}
+
+	uid, _ := ctx.Value(KeyAuthUserID).(string)
+	if _, err := s.userList(ctx, listID, uid); err != nil {
+		return nil, err
+	}
+
+	return s.Posts(ctx, last, before, postsFromList(listID))
+}
+
+// ListTimelineStream to receive posts from the members of the given list in realtime.
+func (s *Service) ListTimelineStream(ctx context.Context, listID string) (<-chan Post, error) {
+	if !reUUID.MatchString(listID) {
+		return nil, ErrInvalidUserListID
+	}
+
+	uid, auth := ctx.Value(KeyAuthUserID).(string)
+	if _, err := s.userList(ctx, listID, uid); err != nil {
+		return nil, err
+	}
+
+	pp := make(chan Post)
+	unsub, err := s.PubSub.Sub(listTimelineTopic(listID), func(data []byte) {
+		go func(r io.Reader) {
+			var p Post
+			err := gob.NewDecoder(r).Decode(&p)
+			if err != nil {
+				_ = s.Logger.Log("error", fmt.Errorf("could not gob decode list timeline post: %w", err))
+				return
+			}
+
+			ok, err := s.streamablePost(ctx, uid, auth, p)
+			if err != nil {
+				_ = s.Logger.Log("error", err)
+				return
+			}
+
+			if ok {
+				pp <- p
+			}
+		}(bytes.NewReader(data))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not subscribe to list timeline: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := unsub(); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not unsubcribe from list timeline: %w", err))
+			// don't return
+		}
+
+		close(pp)
+	}()
+
+	return pp, nil
+}
+
+// userList with the given ID as seen by the given user.
+// Private lists from somebody else are not found.
+func (s *Service) userList(ctx context.Context, listID, uid string) (UserList, error) {
+	var l UserList
+	var u User
+	var avatar sql.NullString
+	query := `
+		SELECT user_lists.id, user_lists.user_id, user_lists.name, user_lists.private
+		, user_lists.members_count, user_lists.created_at
+		, users.username, users.avatar
+		FROM user_lists
+		INNER JOIN users ON user_lists.user_id = users.id
+		WHERE user_lists.id = $1`
+	row := s.DB.QueryRowContext(ctx, query, listID)
+	err := row.Scan(&l.ID, &l.UserID, &l.Name, &l.Private, &l.MembersCount, &l.CreatedAt, &u.Username, &avatar)
+	if err == sql.ErrNoRows {
+		return l, ErrUserListNotFound
+	}
+
+	if err != nil {
+		return l, fmt.Errorf("could not sql query select user list: %w", err)
+	}
+
+	l.Mine = l.UserID == uid
+	if l.Private && !l.Mine {
+		return UserList{}, ErrUserListNotFound
+	}
+
+	u.AvatarURL = s.avatarURL(avatar)
+	l.User = &u
+
+	return l, nil
+}
+
+// ownUserList checks the list with the given ID belongs to the given user.
+func ownUserList(ctx context.Context, db queryRower, listID, userID string) error {
+	var ok bool
+	query := "SELECT EXISTS (SELECT 1 FROM user_lists WHERE id = $1 AND user_id = $2)"
+	if err := db.QueryRowContext(ctx, query, listID, userID).Scan(&ok); err != nil {
+		return fmt.Errorf("could not sql query select user list ownership: %w", err)
+	}
+
+	if !ok {
+		return ErrUserListNotFound
+	}
+
+	return nil
+}
+
+// fanoutListPost publishes the post to the timelines of the lists its author is in.
+func (s *Service) fanoutListPost(p Post) {
+	query := "SELECT list_id FROM user_list_members WHERE user_id = $1"
+	rows, err := s.DB.Query(query, p.UserID)
+	if err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not sql query select post user lists: %w", err))
+		return
+	}
+
+	defer rows.Close()
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(p); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not gob encode list timeline post: %w", err))
+		return
+	}
+
+	for rows.Next() {
+		var listID string
+		if err = rows.Scan(&listID); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not sql scan post user list: %w", err))
+			return
+		}
+
+		if err := s.PubSub.Pub(listTimelineTopic(listID), b.Bytes()); err != nil {
+			_ = s.Logger.Log("error", fmt.Errorf("could not publish list timeline post: %w", err))
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		_ = s.Logger.Log("error", fmt.Errorf("could not iterate post user list rows: %w", err))
+		return
+	}
+}
+
+func listTimelineTopic(listID string) string { return "list_timeline_" + listID }
+
+func validUserListName(s string) bool {
+	return s != "" && utf8.RuneCountInString(s) <= userListNameMaxLength
+}
@@ -0,0 +1,95 @@
+package nakama
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_CreateUserList(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := svc.CreateUserList(context.Background(), CreateUserList{Name: "friends"})
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_name", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		for _, name := range []string{"", "   ", strings.Repeat("a", userListNameMaxLength+1)} {
+			_, err := svc.CreateUserList(ctx, CreateUserList{Name: name})
+			testutil.WantEq(t, ErrInvalidUserListName, err, "error")
+		}
+	})
+}
+
+func TestService_AddUserListMember(t *testing.T) {
+	svc := &Service{}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := svc.AddUserListMember(context.Background(), "00000000-0000-0000-0000-000000000001", "someone")
+		testutil.WantEq(t, ErrUnauthenticated, err, "error")
+	})
+
+	t.Run("invalid_list_id", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.AddUserListMember(ctx, "nope", "someone")
+		testutil.WantEq(t, ErrInvalidUserListID, err, "error")
+	})
+
+	t.Run("invalid_username", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), KeyAuthUserID, "00000000-0000-0000-0000-000000000001")
+		err := svc.AddUserListMember(ctx, "00000000-0000-0000-0000-000000000001", "@nope")
+		testutil.WantEq(t, ErrInvalidUsername, err, "error")
+	})
+
+	t.Run("list_timeline", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping user list integration test in short mode")
+		}
+
+		ctx := context.Background()
+		svc := &Service{DB: testDB}
+
+		owner := createTestUser(t)
+		member := createTestUser(t)
+		other := createTestUser(t)
+
+		_, err := testDB.ExecContext(ctx, `
+			INSERT INTO posts (user_id, content) VALUES ($1, 'from member'), ($2, 'from other')`,
+			member.ID, other.ID)
+		testutil.WantEq(t, nil, err, "insert posts error")
+
+		ownerCtx := context.WithValue(ctx, KeyAuthUserID, owner.ID)
+		l, err := svc.CreateUserList(ownerCtx, CreateUserList{Name: "friends", Private: true})
+		testutil.WantEq(t, nil, err, "create user list error")
+
+		err = svc.AddUserListMember(ownerCtx, l.ID, member.Username)
+		testutil.WantEq(t, nil, err, "add user list member error")
+
+		// adding twice does nothing.
+		err = svc.AddUserListMember(ownerCtx, l.ID, member.Username)
+		testutil.WantEq(t, nil, err, "add user list member again error")
+
+		l, err = svc.UserList(ownerCtx, l.ID)
+		testutil.WantEq(t, nil, err, "user list error")
+		testutil.WantEq(t, 1, l.MembersCount, "members count")
+
+		pp, err := svc.ListTimeline(ownerCtx, l.ID, 0, nil)
+		testutil.WantEq(t, nil, err, "list timeline error")
+		testutil.WantEq(t, 1, len(pp), "list timeline length")
+		testutil.WantEq(t, "from member", pp[0].Content, "list timeline post content")
+
+		otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+		_, err = svc.ListTimeline(otherCtx, l.ID, 0, nil)
+		testutil.WantEq(t, ErrUserListNotFound, err, "private list timeline error")
+
+		err = svc.RemoveUserListMember(ownerCtx, l.ID, member.Username)
+		testutil.WantEq(t, nil, err, "remove user list member error")
+
+		err = svc.RemoveUserListMember(ownerCtx, l.ID, member.Username)
+		testutil.WantEq(t, ErrUserListMemberNotFound, err, "remove user list member again error")
+	})
+}
@@ -0,0 +1,79 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func Test_userSearchQuery(t *testing.T) {
+	tt := []struct {
+		search string
+		want   string
+	}{
+		{search: "", want: ""},
+		{search: "!?", want: ""},
+		{search: "John", want: "john:*"},
+		{search: "john_doe", want: "john:* & doe:*"},
+		{search: "  rem & ram | emilia:* ", want: "rem:* & ram:* & emilia:*"},
+		{search: "トトロ", want: "トトロ:*"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.search, func(t *testing.T) {
+			got := userSearchQuery(tc.search)
+			testutil.WantEq(t, tc.want, got, "tsquery")
+		})
+	}
+}
+
+func TestService_Users(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping users search integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	word := "r" + testutil.RandStr(t, 8)
+	insertUser := func(username string) {
+		t.Helper()
+		_, err := testDB.ExecContext(ctx, "INSERT INTO users (email, username) VALUES ($1, $2)", username+"@example.org", username)
+		testutil.WantEq(t, nil, err, "insert user error")
+	}
+
+	// equal ranks for the ones only matching by a word of their username,
+	// sorted by username then.
+	insertUser(word + "_c")
+	insertUser(word)
+	insertUser(word + "_a")
+	insertUser(word + "_b")
+
+	// bio matches weight less than username ones.
+	bioUser := createTestUser(t)
+	_, err := testDB.ExecContext(ctx, "UPDATE users SET bio = $1 WHERE id = $2", "i like "+word, bioUser.ID)
+	testutil.WantEq(t, nil, err, "update bio error")
+
+	want := []string{word, word + "_a", word + "_b", word + "_c", bioUser.Username}
+
+	var got []string
+	var after *string
+	for range len(want) {
+		uu, err := svc.Users(ctx, word, 2, after)
+		testutil.WantEq(t, nil, err, "users error")
+
+		if len(uu) == 0 {
+			break
+		}
+
+		for _, u := range uu {
+			got = append(got, u.Username)
+		}
+		after = uu.EndCursor()
+	}
+
+	testutil.WantEq(t, want, got, "usernames")
+
+	_, err = svc.Users(ctx, word, 2, ptrString(encodeSimpleCursor(word)))
+	testutil.WantEq(t, ErrInvalidCursor, err, "simple cursor while searching error")
+}
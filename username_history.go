@@ -0,0 +1,64 @@
+package nakama
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// usernameCooldown is how long a released username stays reserved
+// before somebody else can claim it.
+const usernameCooldown = time.Hour * 24 * 30
+
+// releaseUsername records that the given user stopped using the username,
+// so old links to it can still be resolved.
+func releaseUsername(ctx context.Context, tx *sql.Tx, username, userID string) error {
+	query := "INSERT INTO username_history (username, user_id) VALUES ($1, $2)"
+	if _, err := tx.ExecContext(ctx, query, username, userID); err != nil {
+		return fmt.Errorf("could not sql insert username history: %w", err)
+	}
+
+	return nil
+}
+
+// usernameReserved tells whether the username was released by someone else
+// than the given user less than usernameCooldown ago.
+// An empty userID stands for a new user.
+func usernameReserved(ctx context.Context, db queryRower, username, userID string) (bool, error) {
+	var reserved bool
+	query := `SELECT EXISTS (
+		SELECT 1 FROM username_history
+		WHERE username = $1
+			AND ($2::UUID IS NULL OR user_id != $2)
+			AND released_at > $3
+	)`
+	err := db.QueryRowContext(ctx, query, username, sql.NullString{String: userID, Valid: userID != ""}, time.Now().Add(-usernameCooldown)).Scan(&reserved)
+	if err != nil {
+		return false, fmt.Errorf("could not sql query select username reservation: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// renamedUsername gives the current username of whoever released
+// the given username the latest.
+func renamedUsername(ctx context.Context, db queryRower, username string) (string, error) {
+	var current string
+	query := `
+		SELECT users.username FROM username_history
+		INNER JOIN users ON username_history.user_id = users.id
+		WHERE username_history.username = $1
+		ORDER BY username_history.released_at DESC
+		LIMIT 1`
+	err := db.QueryRowContext(ctx, query, username).Scan(&current)
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("could not sql query select renamed username: %w", err)
+	}
+
+	return current, nil
+}
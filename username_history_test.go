@@ -0,0 +1,45 @@
+package nakama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nakamauwu/nakama/testutil"
+)
+
+func TestService_User_renamed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping username history integration test in short mode")
+	}
+
+	ctx := context.Background()
+	svc := &Service{DB: testDB}
+
+	renamed := createTestUser(t)
+	other := createTestUser(t)
+
+	renamedCtx := context.WithValue(ctx, KeyAuthUserID, renamed.ID)
+	newUsername := "test_" + testutil.RandStr(t, 8)
+	err := svc.UpdateUser(renamedCtx, UpdateUserParams{Username: &newUsername})
+	testutil.WantEq(t, nil, err, "rename error")
+
+	u, err := svc.User(ctx, renamed.Username)
+	testutil.WantEq(t, nil, err, "old username user error")
+	testutil.WantEq(t, newUsername, u.Username, "canonical username")
+	testutil.WantEq(t, renamed.Username, u.RenamedFrom, "renamed from")
+
+	u, err = svc.User(ctx, newUsername)
+	testutil.WantEq(t, nil, err, "new username user error")
+	testutil.WantEq(t, "", u.RenamedFrom, "renamed from")
+
+	otherCtx := context.WithValue(ctx, KeyAuthUserID, other.ID)
+	err = svc.UpdateUser(otherCtx, UpdateUserParams{Username: &renamed.Username})
+	testutil.WantEq(t, ErrUsernameTaken, err, "claim released username error")
+
+	err = svc.UpdateUser(renamedCtx, UpdateUserParams{Username: &renamed.Username})
+	testutil.WantEq(t, nil, err, "reclaim own username error")
+
+	u, err = svc.User(ctx, renamed.Username)
+	testutil.WantEq(t, nil, err, "reclaimed username user error")
+	testutil.WantEq(t, "", u.RenamedFrom, "renamed from")
+}
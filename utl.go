@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -178,6 +179,32 @@ func decodeSimpleCursor(s string) (string, error) {
 	return string(b), nil
 }
 
+// encodeRankCursor for results sorted by a relevance rank.
+// The rank goes within so the next page doesn't have to recompute it.
+func encodeRankCursor(key string, rank float64) string {
+	s := fmt.Sprintf("%s,%s", key, strconv.FormatFloat(rank, 'g', -1, 64))
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func decodeRankCursor(s string) (string, float64, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not base64 decode cursor: %w", err)
+	}
+
+	key, rawRank, ok := strings.Cut(string(b), ",")
+	if !ok {
+		return "", 0, errors.New("expected cursor to have two items split by comma")
+	}
+
+	rank, err := strconv.ParseFloat(rawRank, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not parse cursor rank: %w", err)
+	}
+
+	return key, rank, nil
+}
+
 func detectContentType(r io.ReadSeeker) (string, error) {
 	// http.DetectContentType uses at most 512 bytes to make its decision.
 	h := make([]byte, 512)
@@ -203,3 +230,10 @@ func detectContentType(r io.ReadSeeker) (string, error) {
 func ptrString(v string) *string {
 	return &v
 }
+
+func emptyStrPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -65,3 +65,19 @@ func Test_collectTags(t *testing.T) {
 		})
 	}
 }
+
+func Test_decodeRankCursor(t *testing.T) {
+	rank := 1.0 + 0.1/3
+	username, gotRank, err := decodeRankCursor(encodeRankCursor("john", rank))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if username != "john" || gotRank != rank {
+		t.Errorf("want (%q, %v); got (%q, %v)", "john", rank, username, gotRank)
+	}
+
+	if _, _, err := decodeRankCursor(encodeSimpleCursor("john")); err == nil {
+		t.Error("want error decoding a cursor without rank")
+	}
+}
@@ -15,6 +15,8 @@ export function getLocalAuth() {
         || auth === null
         || typeof auth.token !== "string"
         || typeof auth.expiresAt !== "string"
+        || typeof auth.refreshToken !== "string"
+        || typeof auth.refreshTokenExpiresAt !== "string"
         || typeof auth.user !== "object"
         || typeof auth.user === null
         || typeof auth.user.id !== "string"
@@ -23,20 +25,23 @@ export function getLocalAuth() {
         return null
     }
 
-    let expiresAt
+    let expiresAt, refreshTokenExpiresAt
     try {
         expiresAt = new Date(auth.expiresAt)
+        refreshTokenExpiresAt = new Date(auth.refreshTokenExpiresAt)
     } catch (_) {
         return null
     }
 
-    if (isNaN(expiresAt.valueOf()) || expiresAt < new Date()) {
+    if (isNaN(expiresAt.valueOf()) || isNaN(refreshTokenExpiresAt.valueOf()) || refreshTokenExpiresAt < new Date()) {
         return null
     }
 
     return {
         token: auth.token,
         expiresAt,
+        refreshToken: auth.refreshToken,
+        refreshTokenExpiresAt,
         user: {
             id: auth.user.id,
             username: auth.user.username,
@@ -3,6 +3,7 @@ import { html } from "lit"
 import { translate } from "lit-translate"
 import { setLocalAuth } from "../auth.js"
 import { authStore, useStore } from "../ctx.js"
+import { request } from "../http.js"
 import { navigate } from "../router.js"
 
 export default function () {
@@ -14,6 +15,9 @@ function AccessCallbackPage() {
     const [err, setErr] = useState(/** @type {Error|null} */(null))
     const [retryEndpoint, setRetryEndpoint] = useState(/** @type {URL|null} */(null))
     const [username, setUsername] = useState("")
+    const [secondFactorChallengeID, setSecondFactorChallengeID] = useState(/** @type {string|null} */(null))
+    const [secondFactorCode, setSecondFactorCode] = useState("")
+    const [verifyingSecondFactor, setVerifyingSecondFactor] = useState(false)
 
     const onUsernameFormSubmit = ev => {
         ev.preventDefault()
@@ -30,6 +34,33 @@ function AccessCallbackPage() {
         setUsername(ev.currentTarget.value)
     }
 
+    const onSecondFactorFormSubmit = ev => {
+        ev.preventDefault()
+
+        if (secondFactorChallengeID === null) {
+            return
+        }
+
+        setVerifyingSecondFactor(true)
+        verifySecondFactor(secondFactorChallengeID, secondFactorCode).then(auth => {
+            setLocalAuth(auth)
+            setAuth(auth)
+            navigate("/", true)
+        }, err => {
+            console.error("could not verify second factor:", err)
+            setErr(err)
+            if (err.name === "SecondFactorChallengeNotFoundError") {
+                setSecondFactorChallengeID(null)
+            }
+        }).finally(() => {
+            setVerifyingSecondFactor(false)
+        })
+    }
+
+    const onSecondFactorCodeInput = ev => {
+        setSecondFactorCode(ev.currentTarget.value)
+    }
+
     useEffect(() => {
         const data = new URLSearchParams(location.hash.substr(1))
         if (data.has("error")) {
@@ -50,7 +81,12 @@ function AccessCallbackPage() {
             return
         }
 
-        if (!data.has("token") || !data.has("expires_at") || !data.has("user.id") || !data.has("user.username")) {
+        if (data.has("second_factor_challenge_id")) {
+            setSecondFactorChallengeID(decodeURIComponent(data.get("second_factor_challenge_id")))
+            return
+        }
+
+        if (!data.has("token") || !data.has("expires_at") || !data.has("refresh_token") || !data.has("refresh_token_expires_at") || !data.has("user.id") || !data.has("user.username")) {
             const err = new Error("missing auth data")
             err.name = "MissingAuthDataError"
             setErr(err)
@@ -60,6 +96,8 @@ function AccessCallbackPage() {
         const auth = {
             token: decodeURIComponent(data.get("token")),
             expiresAt: new Date(decodeURIComponent(data.get("expires_at"))),
+            refreshToken: decodeURIComponent(data.get("refresh_token")),
+            refreshTokenExpiresAt: new Date(decodeURIComponent(data.get("refresh_token_expires_at"))),
             user: {
                 id: decodeURIComponent(data.get("user.id")),
                 username: decodeURIComponent(data.get("user.username")),
@@ -81,6 +119,12 @@ function AccessCallbackPage() {
                     <a href="/">${translate("accessCallbackPage.goHome")}</a>
                 ` : null}
             ` : null}
+            ${secondFactorChallengeID !== null ? html`
+                <form class="second-factor-form" @submit=${onSecondFactorFormSubmit}>
+                    <input type="text" name="code" placeholder="${translate("accessCallbackPage.secondFactorCodePlaceholder")}" autocomplete="one-time-code" required autofocus .value=${secondFactorCode} .disabled=${verifyingSecondFactor} @input=${onSecondFactorCodeInput}>
+                    <button .disabled=${verifyingSecondFactor}>${translate("accessCallbackPage.verifySecondFactorBtn")}</button>
+                </form>
+            ` : null}
             ${retryEndpoint !== null ? html`
                 <form class="username-form" @submit=${onUsernameFormSubmit}>
                     <input type="text" name="username" placeholder="${translate("accessCallbackPage.usernamePlaceholder")}" pattern="^[a-zA-Z][a-zA-Z0-9_-]{0,17}$" autofocus .value=${username} @input=${onUsernameInput}>
@@ -93,6 +137,16 @@ function AccessCallbackPage() {
 
 customElements.define("access-callback-page", component(AccessCallbackPage, { useShadowDOM: false }))
 
+function verifySecondFactor(challengeID, code) {
+    return request("POST", "/api/verify_second_factor", { body: { challengeID, code } })
+        .then(resp => resp.body)
+        .then(auth => {
+            auth.expiresAt = new Date(auth.expiresAt)
+            auth.refreshTokenExpiresAt = new Date(auth.refreshTokenExpiresAt)
+            return auth
+        })
+}
+
 function isRetriableError(err) {
     return err.name === "UserNotFoundError" || err.name === "InvalidUsernameError" || err.name === "UsernameTakenError"
 }
@@ -23,6 +23,7 @@ function LoginForm() {
     const [email, setEmail] = useState(inLocalhost ? "shinji@example.org" : "")
     const [fetching, setFetching] = useState(false)
     const [toast, setToast] = useState(null)
+    const [codeSentTo, setCodeSentTo] = useState(/** @type {string|null} */(null))
 
     const onSubmit = ev => {
         ev.preventDefault()
@@ -30,6 +31,7 @@ function LoginForm() {
         setFetching(true)
 
         const promise = inLocalhost ? devLogin(email) : sendMagicLink(email)
+        const sentTo = email
         promise.then(auth => {
             setEmail("")
 
@@ -39,6 +41,7 @@ function LoginForm() {
                 return
             }
 
+            setCodeSentTo(sentTo)
             setToast({
                 type: "success",
                 content: getTranslation("loginForm.success"),
@@ -70,6 +73,7 @@ function LoginForm() {
                 @input=${onEmailInput}>
             <button .disabled=${fetching}>${translate("loginForm.btn")}</button>
         </form>
+        ${codeSentTo !== null ? html`<login-code-form .email=${codeSentTo}></login-code-form>` : null}
         <h3>${translate("loginForm.subheading")}</h3>
         <div class="oauth-providers">
             <a class="btn"
@@ -115,6 +119,79 @@ function LoginForm() {
 
 customElements.define("login-form", component(LoginForm, { useShadowDOM: false }))
 
+function LoginCodeForm({ email }) {
+    const [, setAuth] = useStore(authStore)
+    const [code, setCode] = useState("")
+    const [username, setUsername] = useState("")
+    const [needsUsername, setNeedsUsername] = useState(false)
+    const [fetching, setFetching] = useState(false)
+    const [toast, setToast] = useState(null)
+
+    const onSubmit = ev => {
+        ev.preventDefault()
+
+        setFetching(true)
+        verifyLoginCode(email, code, needsUsername ? username : undefined).then(auth => {
+            if (auth.secondFactorRequired) {
+                location.assign("/access-callback#second_factor_challenge_id=" + encodeURIComponent(auth.secondFactorChallengeID))
+                return
+            }
+
+            setLocalAuth(auth)
+            setAuth(auth)
+        }, err => {
+            if (err.name === "UserNotFoundError") {
+                setNeedsUsername(true)
+                return
+            }
+
+            const msg = getTranslation("loginCodeForm.err") + getTranslation(err.name)
+            console.error(msg)
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setFetching(false)
+        })
+    }
+
+    const onCodeInput = ev => {
+        setCode(ev.currentTarget.value)
+    }
+
+    const onUsernameInput = ev => {
+        setUsername(ev.currentTarget.value)
+    }
+
+    return html`
+        <form class="access-form" @submit=${onSubmit}>
+            <input type="text"
+                name="code"
+                inputmode="numeric"
+                pattern="^[0-9]{6}$"
+                autocomplete="one-time-code"
+                required
+                placeholder="${translate("loginCodeForm.placeholder")}"
+                .value=${code}
+                .disabled=${fetching}
+                @input=${onCodeInput}>
+            ${needsUsername ? html`
+                <input type="text"
+                    name="username"
+                    pattern="^[a-zA-Z][a-zA-Z0-9_-]{0,17}$"
+                    required
+                    autofocus
+                    placeholder="${translate("accessCallbackPage.usernamePlaceholder")}"
+                    .value=${username}
+                    .disabled=${fetching}
+                    @input=${onUsernameInput}>
+            ` : null}
+            <button .disabled=${fetching}>${translate("loginCodeForm.btn")}</button>
+        </form>
+        ${toast !== null ? html`<toast-item .toast=${toast}></toast-item>` : null}
+    `
+}
+
+customElements.define("login-code-form", component(LoginCodeForm, { useShadowDOM: false }))
+
 /**
  * @param {string} email
  */
@@ -122,6 +199,15 @@ function devLogin(email) {
     return request("POST", "/api/dev_login", { body: { email } }).then(resp => resp.body)
 }
 
+/**
+ * @param {string} email
+ * @param {string} code
+ * @param {string=} username
+ */
+function verifyLoginCode(email, code, username) {
+    return request("POST", "/api/verify_login_code", { body: { email, code, username } }).then(resp => resp.body)
+}
+
 function sendMagicLink(email, redirectURI = location.origin + "/access-callback") {
     return request("POST", "/api/send_magic_link", { body: { email, redirectURI } })
 }
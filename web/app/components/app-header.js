@@ -201,11 +201,11 @@ function notificationBody(n) {
             case 0:
                 return "Someone"
             case 1:
-                return aa[0]
+                return aa[0].username
             case 2:
-                return `${aa[0]} and ${aa[1]}`
+                return `${aa[0].username} and ${aa[1].username}`
             default:
-                return `${aa[0]} and ${aa.length - 1} others`
+                return `${aa[0].username} and ${aa.length - 1} others`
         }
     }
 
@@ -233,7 +233,7 @@ function notificationPathname(n) {
     }
 
     if (n.type === "follow") {
-        return "/@" + encodeURIComponent(n.actors[0])
+        return "/@" + encodeURIComponent(n.actors[0].username)
     }
 
     return "/notifications"
@@ -1,8 +1,9 @@
 import { html } from "lit"
+import { motionSafeURL } from "../utils.js"
 
 export function Avatar(user) {
     return user.avatarURL !== null ? html`
-        <img class="avatar" src="${user.avatarURL}" alt="">
+        <img class="avatar" src="${motionSafeURL(user.avatarURL)}" alt="">
     ` : html`
         <span class="avatar" data-initial="${user.username[0]}"></span>
     `
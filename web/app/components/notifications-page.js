@@ -266,18 +266,18 @@ function NotificationItem({ notification: initialNotification }) {
 
     const getActors = () => {
         const aa = notification.actors
+        const link = u => html`<a href="/@${encodeURIComponent(u.username)}">${u.username}</a>`
         switch (aa.length) {
             case 0:
                 return "Someone"
             case 1:
-                return html`<a href="/@${aa[0]}">${aa[0]}</a>`
+                return link(aa[0])
             case 2:
-                return html`<a href="/@${aa[0]}">${aa[0]}</a> and <a href="/@${aa[1]}">${aa[1]}</a>`
+                return html`${link(aa[0])} and ${link(aa[1])}`
             default:
                 return notification.type === "follow"
-                    ? html`${repeat(aa.slice(0, aa.length - 1), u => u, (u, i) => html`${i > 0 ? ", " : ""}<a href="/@${u}">${u}</a>`)} and <a
-    href="/@${aa[aa.length - 1]}">${aa[aa.length - 1]}</a>`
-                    : html`<a href="/@${aa[0]}">${aa[0]}</a> and ${aa.length - 1} others`
+                    ? html`${repeat(aa.slice(0, aa.length - 1), u => u.username, (u, i) => html`${i > 0 ? ", " : ""}${link(u)}`)} and ${link(aa[aa.length - 1])}`
+                    : html`${link(aa[0])} and ${aa.length - 1} others`
         }
     }
 
@@ -285,12 +285,24 @@ function NotificationItem({ notification: initialNotification }) {
         switch (notification.type) {
             case "follow":
                 return "followed you"
+            case "follow_request":
+                return "requested to follow you"
+            case "follow_request_approved":
+                return "approved your follow request"
+            case "follow_request_denied":
+                return "denied your follow request"
             case "comment":
                 return html`commented in a <a href="/posts/${notification.postID}">post</a>`
             case "post_mention":
                 return html`mentioned you in a <a href="/posts/${notification.postID}">post</a>`
             case "comment_mention":
                 return html`mentioned you in a <a href="/posts/${notification.postID}">comment</a>`
+            case "report":
+                return notification.postID
+                    ? html`reported content in a <a href="/posts/${notification.postID}">post</a>`
+                    : "reported a user"
+            case "report_resolved":
+                return "reviewed your report"
             default:
                 return "did something"
         }
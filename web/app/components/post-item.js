@@ -8,7 +8,7 @@ import { unsafeHTML } from "lit/directives/unsafe-html.js"
 import mediumZoom from "medium-zoom"
 import { authStore, useStore } from "../ctx.js"
 import { request } from "../http.js"
-import { collectMediaURLs, linkify } from "../utils.js"
+import { collectMediaURLs, linkify, motionSafeURL } from "../utils.js"
 import { Avatar } from "./avatar.js"
 import "./relative-datetime.js"
 import "./toast-item.js"
@@ -24,6 +24,7 @@ function PostItem({ post: initialPost, type }) {
     const [mediaURLs, setMediaURLs] = useState([])
     const [showMenu, setShowMenu] = useState(false)
     const [togglingPostSubscription, setTogglingPostSubscription] = useState(false)
+    const [togglingPin, setTogglingPin] = useState(false)
     const [updating, setUpdating] = useState(false)
     const [removingFromTimeline, setRemovingFromTimeline] = useState(false)
     const [deleting, setDeleting] = useState(false)
@@ -58,6 +59,23 @@ function PostItem({ post: initialPost, type }) {
         })
     }
 
+    const onPinToggleBtnClick = () => {
+        setTogglingPin(true)
+        const pinned = !post.pinned
+        togglePin(post.id, pinned).then(() => {
+            setPost(p => ({
+                ...p,
+                pinned,
+            }))
+        }, err => {
+            const msg = getTranslation("postItem.errPin") + " " + getTranslation(err.name)
+            console.error(msg)
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setTogglingPin(false)
+        })
+    }
+
     const onUpdateBtnClick = () => {
         setUpdating(true)
 
@@ -151,7 +169,7 @@ function PostItem({ post: initialPost, type }) {
         const urls = []
         if ("mediaURLs" in post) {
             for (const mediaURL of post.mediaURLs) {
-                urls.push(new URL(mediaURL, location.origin))
+                urls.push(new URL(motionSafeURL(mediaURL), location.origin))
             }
         }
         urls.push(...collectMediaURLs(post.content))
@@ -170,6 +188,9 @@ function PostItem({ post: initialPost, type }) {
                     <span class="username">${post.user.username}</span>
                 </a>
                 <div class="post-meta">
+                    ${post.pinned && type === "post" ? html`
+                        <span class="post-pinned">${translate("postItem.pinned")}</span>
+                    ` : null}
                     ${type === "comment" ? html`
                         <relative-datetime class="post-ts" .datetime=${post.createdAt}></relative-datetime>
                     ` : html`
@@ -213,6 +234,14 @@ function PostItem({ post: initialPost, type }) {
                                         </button>
                                     </li>
                                 ` : null}
+                                ${post.mine && type !== "comment" ? html`
+                                    <li class="post-menu-item" role="none">
+                                        <button class="post-menu-btn" role="menuitem" tabindex="-1" .disabled=${togglingPin} @click=${onPinToggleBtnClick} @blur=${onMenuWrapperBlur}>
+                                            <svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><g data-name="Layer 2"><g data-name="pin"><rect width="24" height="24" opacity="0"/><path d="M12 2a8 8 0 0 0-8 7.92c0 5.48 7.05 11.58 7.35 11.84a1 1 0 0 0 1.3 0C13 21.5 20 15.4 20 9.92A8 8 0 0 0 12 2zm0 17.65c-1.67-1.59-6-6-6-9.73a6 6 0 0 1 12 0c0 3.7-4.33 8.14-6 9.73z"/><path d="M12 6a3.5 3.5 0 1 0 3.5 3.5A3.5 3.5 0 0 0 12 6zm0 5a1.5 1.5 0 1 1 1.5-1.5A1.5 1.5 0 0 1 12 11z"/></g></g></svg>
+                                            <span>${post.pinned ? translate("postItem.menu.unpin") : translate("postItem.menu.pin")}</span>
+                                        </button>
+                                    </li>
+                                ` : null}
                                 ${post.mine && postCanBeUpdated ? html`
                                     <li class="post-menu-item" role="none">
                                         <button class="post-menu-btn" role="menuitem" tabindex="-1" .disabled=${updating} @click=${onUpdateBtnClick} @blur=${onMenuWrapperBlur}>
@@ -842,6 +871,10 @@ function ZoomableImg({ src, width = undefined, height = undefined }) {
 // @ts-ignore
 customElements.define("zoomable-img", component(ZoomableImg, { useShadowDOM: false }))
 
+function togglePin(postID, pinned) {
+    return request(pinned ? "PUT" : "DELETE", `/api/posts/${encodeURIComponent(postID)}/pin`)
+}
+
 function togglePostSubscription(postID) {
     return request("POST", `/api/posts/${encodeURIComponent(postID)}/toggle_subscription`)
         .then(resp => resp.body)
@@ -0,0 +1,50 @@
+import { component, useEffect, useState } from "haunted"
+import { html } from "lit"
+import { translate } from "lit-translate"
+import { repeat } from "lit/directives/repeat.js"
+import { request } from "../http.js"
+import "./user-item.js"
+
+const pageSize = 5
+
+function SuggestedUsers() {
+    const [users, setUsers] = useState(/** @type {import("../types.js").UserProfile[]} */ ([]))
+    const [err, setErr] = useState(null)
+
+    useEffect(() => {
+        fetchSuggestedUsers().then(setUsers, err => {
+            console.error("could not fetch suggested users:", err)
+            setErr(err)
+        })
+    }, [])
+
+    if (err !== null) {
+        return html`
+            <p class="error" role="alert">${translate("suggestedUsers.err")} ${translate(err.name)}</p>
+        `
+    }
+
+    if (users.length === 0) {
+        return null
+    }
+
+    return html`
+        <section class="suggested-users">
+            <h2>${translate("suggestedUsers.title")}</h2>
+            <div class="users">
+                ${repeat(users, u => u.username, u => html`<user-item .user=${u}></user-item>`)}
+            </div>
+        </section>
+    `
+}
+
+// @ts-ignore
+customElements.define("suggested-users", component(SuggestedUsers, { useShadowDOM: false }))
+
+/**
+ * @returns {Promise<import("../types.js").UserProfile[]>}
+ */
+function fetchSuggestedUsers(first = pageSize) {
+    return request("GET", "/api/suggested_users?first=" + encodeURIComponent(first))
+        .then(resp => resp.body)
+}
@@ -45,7 +45,7 @@ function UserFollowBtn({ user: initialUser }) {
             ` : html`
                 <svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><g data-name="Layer 2"><g data-name="person-add"><rect width="24" height="24" opacity="0"/><path d="M21 6h-1V5a1 1 0 0 0-2 0v1h-1a1 1 0 0 0 0 2h1v1a1 1 0 0 0 2 0V8h1a1 1 0 0 0 0-2z"/><path d="M10 11a4 4 0 1 0-4-4 4 4 0 0 0 4 4zm0-6a2 2 0 1 1-2 2 2 2 0 0 1 2-2z"/><path d="M10 13a7 7 0 0 0-7 7 1 1 0 0 0 2 0 5 5 0 0 1 10 0 1 1 0 0 0 2 0 7 7 0 0 0-7-7z"/></g></g></svg>
             `}
-            <span>${user.following ? "Following" : "Follow"}</span>
+            <span>${user.following ? "Following" : user.followRequested ? "Requested" : "Follow"}</span>
         </button>
         ${toast !== null ? html`<toast-item .toast=${toast}></toast-item>` : null}
     `
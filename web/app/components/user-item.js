@@ -2,6 +2,7 @@ import { component, useEffect, useState } from "haunted"
 import { html } from "lit"
 import { ifDefined } from "lit/directives/if-defined.js"
 import { authStore, useStore } from "../ctx.js"
+import { motionSafeURL } from "../utils.js"
 import { Avatar } from "./avatar.js"
 import "./user-follow-btn.js"
 import "./user-follow-counts.js"
@@ -23,7 +24,7 @@ function UserItem({ user: initialUser }) {
     }, [initialUser])
 
     return html`
-        <article class="user-item" style="${ifDefined(user.coverURL !== null ? `--cover-url: url('${user.coverURL}');` : undefined)}">
+        <article class="user-item" style="${ifDefined(user.coverURL !== null ? `--cover-url: url('${motionSafeURL(user.coverURL)}');` : undefined)}">
             <a href="/@${user.username}" class="user-info">
                 ${Avatar(user)}
                 <div class="user-text">
@@ -8,7 +8,7 @@ import { setLocalAuth } from "../auth.js"
 import { authStore, useStore } from "../ctx.js"
 import { request } from "../http.js"
 import { navigate } from "../router.js"
-import { linkify } from "../utils.js"
+import { linkify, motionSafeURL } from "../utils.js"
 import { Avatar } from "./avatar.js"
 import "./intersectable-comp.js"
 import "./post-item.js"
@@ -26,6 +26,7 @@ function UserPage({ username }) {
     const [_, setAuth] = useStore(authStore)
     const [user, setUser] = useState(null)
     const [posts, setPosts] = useState([])
+    const [pinnedPosts, setPinnedPosts] = useState([])
     const [postsEndCursor, setPostsEndCursor] = useState(null)
     const [fetching, setFetching] = useState(user === null)
     const [err, setErr] = useState(null)
@@ -37,6 +38,7 @@ function UserPage({ username }) {
     const onPostDeleted = ev => {
         const payload = ev.detail
         setPosts(pp => pp.filter(p => p.id !== payload.id))
+        setPinnedPosts(pp => pp.filter(p => p.id !== payload.id))
     }
 
     const onUserUpdated = ev => {
@@ -108,13 +110,24 @@ function UserPage({ username }) {
         Promise.all([
             fetchUser(username),
             fetchPosts(username),
-        ]).then(([user, { items: posts, endCursor }]) => {
+            fetchPinnedPosts(username),
+        ]).then(([user, { items: posts, endCursor }, pinnedPosts]) => {
+            if (user.username !== username) {
+                navigate("/@" + encodeURIComponent(user.username), true)
+                return
+            }
+
             for (let i = 0; i < posts.length; i++) {
                 posts[i].user = user
             }
 
+            for (let i = 0; i < pinnedPosts.length; i++) {
+                pinnedPosts[i].user = user
+            }
+
             setUser(user)
             setPosts(posts)
+            setPinnedPosts(pinnedPosts)
             setPostsEndCursor(endCursor)
 
             if (posts.length < pageSize) {
@@ -136,7 +149,7 @@ function UserPage({ username }) {
 
     return html`
         <main class="user-page">
-            <div class="user-profile-wrapper" style="${ifDefined(err === null && !fetching && user.coverURL !== null ? `--cover-url: url('${user.coverURL}');` : undefined)}">
+            <div class="user-profile-wrapper" style="${ifDefined(err === null && !fetching && user.coverURL !== null ? `--cover-url: url('${motionSafeURL(user.coverURL)}');` : undefined)}">
                 <div class="container">
                     ${err !== null ? html`
                     <p class="error" role="alert">Could not fetch user: ${err.message}</p>
@@ -154,6 +167,12 @@ function UserPage({ username }) {
                 ` : fetching ? html`
                 <p class="loader" aria-busy="true" aria-live="polite">Loading posts... please wait.</p>
                 ` : html`
+                ${pinnedPosts.length !== 0 ? html`
+                <div class="posts pinned-posts">
+                    ${repeat(pinnedPosts, p => p.id, p => html`<post-item .post=${p} .type=${"post"}
+                        @resource-deleted=${onPostDeleted}></post-item>`)}
+                </div>
+                ` : null}
                 ${posts.length === 0 ? html`
                 <p>0 posts</p>
                 ` : html`
@@ -186,6 +205,7 @@ function UserProfile({ user: initialUser }) {
     const [bio, setBio] = useState(user.bio ?? "")
     const [waifu, setWaifu] = useState(user.waifu ?? "")
     const [husbando, setHusbando] = useState(user.husbando ?? "")
+    const [privateAccount, setPrivateAccount] = useState(user.private ?? false)
     const settingsDialogRef = /** @type {import("lit/directives/ref.js").Ref<HTMLDialogElement>} */(createRef())
     const avatarInputRef = /** @type {import("lit/directives/ref.js").Ref<HTMLInputElement>} */(createRef())
     const coverInputRef = /** @type {import("lit/directives/ref.js").Ref<HTMLInputElement>} */(createRef())
@@ -193,6 +213,14 @@ function UserProfile({ user: initialUser }) {
     const [updatingUser, setUpdatingUser] = useState(false)
     const [updatingAvatar, setUpdatingAvatar] = useState(false)
     const [updatingCover, setUpdatingCover] = useState(false)
+    const [requestingDataExport, setRequestingDataExport] = useState(false)
+    const [requestingAccountDeletion, setRequestingAccountDeletion] = useState(false)
+    const [togglingBlock, setTogglingBlock] = useState(false)
+    const [mutes, setMutes] = useState(/** @type {any[]|null} */ (null))
+    const [muteType, setMuteType] = useState("word")
+    const [muteValue, setMuteValue] = useState("")
+    const [creatingMute, setCreatingMute] = useState(false)
+    const [followRequests, setFollowRequests] = useState(/** @type {import("../types.js").UserProfile[]|null} */ (null))
     const [theme, setTheme] = useState(() => {
         const value = localStorage.getItem("color-scheme")
         return value !== null ? value : "default"
@@ -219,10 +247,97 @@ function UserProfile({ user: initialUser }) {
         }))
     }
 
+    const onBlockBtnClick = () => {
+        if (!user.blocking && !confirm(`Block ${user.username}? You will unfollow each other and stop seeing each other's posts.`)) {
+            return
+        }
+
+        setTogglingBlock(true)
+        toggleBlock(user.username).then(out => {
+            setUser(u => out.blocking ? ({
+                ...u,
+                ...out,
+                following: false,
+                followeed: false,
+                followersCount: u.following ? u.followersCount - 1 : u.followersCount,
+                followeesCount: u.followeed ? u.followeesCount - 1 : u.followeesCount,
+            }) : ({
+                ...u,
+                ...out,
+            }))
+        }, err => {
+            const msg = "could not toggle block: " + err.message
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setTogglingBlock(false)
+        })
+    }
+
     const onSettingsBtnClick = () => {
         if (settingsDialogRef.value !== undefined) {
             settingsDialogRef.value.showModal()
         }
+
+        fetchMutes().then(setMutes, err => {
+            const msg = "could not fetch mutes: " + err.message
+            setToast({ type: "error", content: msg })
+        })
+
+        fetchFollowRequests().then(page => setFollowRequests(page.items), err => {
+            const msg = "could not fetch follow requests: " + err.message
+            setToast({ type: "error", content: msg })
+        })
+    }
+
+    const onApproveFollowRequestBtnClick = follower => {
+        approveFollowRequest(follower.username).then(() => {
+            setFollowRequests(uu => uu.filter(u => u.username !== follower.username))
+            setUser(u => ({ ...u, followersCount: u.followersCount + 1 }))
+        }, err => {
+            const msg = "could not approve follow request: " + err.message
+            setToast({ type: "error", content: msg })
+        })
+    }
+
+    const onDenyFollowRequestBtnClick = follower => {
+        denyFollowRequest(follower.username).then(() => {
+            setFollowRequests(uu => uu.filter(u => u.username !== follower.username))
+        }, err => {
+            const msg = "could not deny follow request: " + err.message
+            setToast({ type: "error", content: msg })
+        })
+    }
+
+    const onMuteTypeChange = ev => {
+        setMuteType(ev.currentTarget.value)
+    }
+
+    const onMuteValueInput = ev => {
+        setMuteValue(ev.currentTarget.value)
+    }
+
+    const onMuteFormSubmit = ev => {
+        ev.preventDefault()
+
+        setCreatingMute(true)
+        createMute({ type: muteType, value: muteValue }).then(mute => {
+            setMutes(mm => [mute, ...(mm === null ? [] : mm)])
+            setMuteValue("")
+        }, err => {
+            const msg = "could not mute: " + err.message
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setCreatingMute(false)
+        })
+    }
+
+    const onUnmuteBtnClick = mute => {
+        deleteMute(mute.id).then(() => {
+            setMutes(mm => mm.filter(m => m.id !== mute.id))
+        }, err => {
+            const msg = "could not unmute: " + err.message
+            setToast({ type: "error", content: msg })
+        })
     }
 
     const onEmailInput = ev => {
@@ -245,6 +360,10 @@ function UserProfile({ user: initialUser }) {
         setHusbando(ev.currentTarget.value)
     }
 
+    const onPrivateAccountChange = ev => {
+        setPrivateAccount(ev.currentTarget.checked)
+    }
+
     const onEmailFormSubmit = ev => {
         ev.preventDefault()
         if (email === user.email) {
@@ -271,7 +390,7 @@ function UserProfile({ user: initialUser }) {
     const onUserFormSubmit = ev => {
         ev.preventDefault()
 
-        const payload = { username, bio, waifu, husbando }
+        const payload = { username, bio, waifu, husbando, private: privateAccount }
         for (const [k, v] of Object.entries(payload)) {
             if (v === "") {
                 payload[k] = null
@@ -454,6 +573,34 @@ function UserProfile({ user: initialUser }) {
         document.firstElementChild.setAttribute("color-scheme", value)
     }
 
+    const onDataExportBtnClick = () => {
+        setRequestingDataExport(true)
+        requestDataExport().then(() => {
+            setToast({ type: "success", content: "you will get an email when your data export is ready" })
+        }, err => {
+            const msg = "could not request data export: " + err.message
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setRequestingDataExport(false)
+        })
+    }
+
+    const onDeleteAccountBtnClick = () => {
+        if (!confirm("We will send you an email to confirm the deletion of your account. Continue?")) {
+            return
+        }
+
+        setRequestingAccountDeletion(true)
+        requestAccountDeletion().then(() => {
+            setToast({ type: "success", content: "account deletion email sended" })
+        }, err => {
+            const msg = "could not request account deletion: " + err.message
+            setToast({ type: "error", content: msg })
+        }).finally(() => {
+            setRequestingAccountDeletion(false)
+        })
+    }
+
     const onSettingsDialogCloseBtnClick = () => {
         settingsDialogRef.value.close()
     }
@@ -488,9 +635,12 @@ function UserProfile({ user: initialUser }) {
         <div class="user-profile">
             <div class="user-details-wrapper">
                 <div>
-                    <h1>${user.username}</h1>
+                    <h1>${user.username}${user.private ? html` <small class="private-badge">Private</small>` : null}</h1>
                     <user-follow-counts .user=${user}></user-follow-counts>
                 </div>
+                ${user.mutualFollowersCount > 0 ? html`
+                    <p class="mutual-followers">Followed by ${MutualFollowers(user)}</p>
+                ` : null}
                 <div class="user-details">
                     ${user.bio !== null && user.bio !== "" ? html`
                         <p>${unsafeHTML(linkify(user.bio))}</p>
@@ -527,8 +677,13 @@ function UserProfile({ user: initialUser }) {
                 </button>
                 <logout-btn></logout-btn>
                 ` : auth !== null ? html`
+                ${!user.blocking ? html`
                 <user-follow-btn .user=${user} @follow-toggle=${onFollowToggle}></user-follow-btn>
                 ` : null}
+                <button .disabled=${togglingBlock} @click=${onBlockBtnClick}>
+                    <span>${user.blocking ? "Unblock" : "Block"}</span>
+                </button>
+                ` : null}
             </div>
         </div>
         <dialog class="user-settings-dialog" ${ref(settingsDialogRef)} @close=${onSettingsDialogClose}>
@@ -577,6 +732,13 @@ function UserProfile({ user: initialUser }) {
                             .disabled=${updatingUser}
                             @input=${onUserHusbandoInput}>
                     </div>
+                    <label class="private-account-label">
+                        <input type="checkbox" name="private"
+                            .checked=${privateAccount}
+                            .disabled=${updatingUser}
+                            @change=${onPrivateAccountChange}>
+                        <span>Private account. Only approved followers see your posts.</span>
+                    </label>
                     <button .disabled=${updatingUser}>Update</button>
                 </form>
 
@@ -600,7 +762,7 @@ function UserProfile({ user: initialUser }) {
                         <div @dblclick=${onAvatarDblClick}>
                             ${Avatar(user)}
                         </div>
-                        <input type="file" name="avatar" accept="image/png,image/jpeg" required hidden
+                        <input type="file" name="avatar" accept="image/png,image/jpeg,image/gif,image/webp" required hidden
                             .disabled=${updatingAvatar} ${ref(avatarInputRef)} @change=${onAvatarInputChange}>
                         <button .disabled=${updatingAvatar} @click=${onAvatarBtnClick}>Update</button>
                     </div>
@@ -609,9 +771,9 @@ function UserProfile({ user: initialUser }) {
                     <legend>Cover</legend>
                     <div class="cover-grp">
                         ${user.coverURL !== null ? html`
-                            <img src="${user.coverURL}" @dblclick=${onCoverDblClick}>
+                            <img src="${motionSafeURL(user.coverURL)}" @dblclick=${onCoverDblClick}>
                         ` : null}
-                        <input type="file" name="cover" accept="image/png,image/jpeg" required hidden
+                        <input type="file" name="cover" accept="image/png,image/jpeg,image/gif,image/webp" required hidden
                             .disabled=${updatingCover} ${ref(coverInputRef)} @change=${onCoverInputChange}>
                         <button .disabled=${updatingCover} @click=${onCoverBtnClick}>Update</button>
                     </div>
@@ -631,9 +793,60 @@ function UserProfile({ user: initialUser }) {
                         <span>Light</span>
                     </label>
                 </fieldset>
+                ${followRequests !== null && followRequests.length !== 0 ? html`
+                    <fieldset class="follow-requests-fieldset">
+                        <legend>Follow requests</legend>
+                        <ul class="follow-requests">
+                            ${repeat(followRequests, u => u.username, u => html`
+                                <li>
+                                    <a href="/@${encodeURIComponent(u.username)}">${u.username}</a>
+                                    <button @click=${() => onApproveFollowRequestBtnClick(u)}>Approve</button>
+                                    <button @click=${() => onDenyFollowRequestBtnClick(u)}>Deny</button>
+                                </li>
+                            `)}
+                        </ul>
+                    </fieldset>
+                ` : null}
+                <fieldset class="mutes-fieldset">
+                    <legend>Mutes</legend>
+                    <p>Hide posts and notifications from users, or with words or hashtags, without blocking anyone.</p>
+                    <form class="mute-form" @submit=${onMuteFormSubmit}>
+                        <select name="type" aria-label="Mute type" .value=${muteType} .disabled=${creatingMute} @change=${onMuteTypeChange}>
+                            <option value="word">Word</option>
+                            <option value="tag">Hashtag</option>
+                            <option value="user">User</option>
+                        </select>
+                        <input type="text" name="value" placeholder="What to mute" aria-label="What to mute" required maxlength="100" autocomplete="off"
+                            .value=${muteValue}
+                            .disabled=${creatingMute}
+                            @input=${onMuteValueInput}>
+                        <button .disabled=${creatingMute}>Mute</button>
+                    </form>
+                    ${mutes !== null && mutes.length !== 0 ? html`
+                        <ul class="mutes">
+                            ${repeat(mutes, m => m.id, m => html`
+                                <li>
+                                    <span>${m.type === "tag" ? "#" : m.type === "user" ? "@" : ""}${m.value}</span>
+                                    <button @click=${() => onUnmuteBtnClick(m)}>Unmute</button>
+                                </li>
+                            `)}
+                        </ul>
+                    ` : null}
+                </fieldset>
+                <fieldset class="data-export-fieldset">
+                    <legend>Your data</legend>
+                    <p>Download a copy of your profile, posts, comments, reactions, follows, notifications and media.</p>
+                    <button .disabled=${requestingDataExport} @click=${onDataExportBtnClick}>Export data</button>
+                </fieldset>
+                <fieldset class="account-deletion-fieldset">
+                    <legend>Danger zone</legend>
+                    <p>Your account is deleted after 30 days. Login before then to cancel.</p>
+                    <button .disabled=${requestingAccountDeletion} @click=${onDeleteAccountBtnClick}>Delete account</button>
+                </fieldset>
             </div>
-            ${toast !== null ? html`<toast-item .toast=${toast}></toast-item>` : null}
+            ${toast !== null && user.me ? html`<toast-item .toast=${toast}></toast-item>` : null}
         </dialog>
+        ${toast !== null && !user.me ? html`<toast-item .toast=${toast}></toast-item>` : null}
     `
 }
 
@@ -644,9 +857,13 @@ function LogoutBtn() {
     const [, setAuth] = useStore(authStore)
 
     const onClick = () => {
-        localStorage.removeItem("auth")
-        setAuth(null)
-        navigate("/")
+        request("POST", "/api/logout").catch(err => {
+            console.error("could not logout:", err)
+        }).finally(() => {
+            localStorage.removeItem("auth")
+            setAuth(null)
+            navigate("/")
+        })
     }
 
     return html`
@@ -668,6 +885,15 @@ function LogoutBtn() {
 
 customElements.define("logout-btn", component(LogoutBtn, { useShadowDOM: false }))
 
+/**
+ * @param {import("../types.js").UserProfile} user
+ */
+function MutualFollowers(user) {
+    const uu = user.mutualFollowers
+    const others = user.mutualFollowersCount - uu.length
+    return html`${repeat(uu, u => u.username, (u, i) => html`${i > 0 ? (others === 0 && i === uu.length - 1 ? " and " : ", ") : ""}<a href="/@${encodeURIComponent(u.username)}">${u.username}</a>`)}${others > 0 ? html` and ${others} ${others === 1 ? "other" : "others"}` : null} you follow`
+}
+
 /**
  * @param {string} username
  */
@@ -679,6 +905,11 @@ function fetchUser(username) {
 /**
  * @param {string} username
  */
+function fetchPinnedPosts(username) {
+    return request("GET", `/api/users/${encodeURIComponent(username)}/pinned_posts`)
+        .then(resp => resp.body)
+}
+
 function fetchPosts(username, before = "", last = pageSize) {
     return request("GET", `/api/users/${encodeURIComponent(username)}/posts?last=${encodeURIComponent(last)}&before=${encodeURIComponent(before)}`)
         .then(resp => resp.body)
@@ -692,10 +923,10 @@ function fetchPosts(username, before = "", last = pageSize) {
 }
 
 /**
- * @param {{username?:string,bio?:string,waifu?:string,husbando?:string}} payload
+ * @param {{username?:string,bio?:string,waifu?:string,husbando?:string,private?:boolean}} payload
  */
-function updateUser({ username, bio, waifu, husbando }) {
-    return request("PATCH", "/api/auth_user", { body: { username, bio, waifu, husbando } })
+function updateUser({ username, bio, waifu, husbando, private: privateAccount }) {
+    return request("PATCH", "/api/auth_user", { body: { username, bio, waifu, husbando, private: privateAccount } })
 }
 
 /**
@@ -715,3 +946,44 @@ function updateCover(cover) {
         .then(resp => resp.body)
         .then(coverURL => ({ coverURL }))
 }
+
+function requestDataExport() {
+    return request("POST", "/api/auth_user/data_exports")
+        .then(resp => resp.body)
+}
+
+function requestAccountDeletion() {
+    return request("POST", "/api/auth_user/deletion_request")
+}
+
+function toggleBlock(username) {
+    return request("POST", `/api/users/${encodeURIComponent(username)}/toggle_block`)
+        .then(resp => resp.body)
+}
+
+function fetchMutes() {
+    return request("GET", "/api/auth_user/mutes")
+        .then(resp => resp.body)
+}
+
+function createMute({ type, value }) {
+    return request("POST", "/api/auth_user/mutes", { body: { type, value } })
+        .then(resp => resp.body)
+}
+
+function deleteMute(muteID) {
+    return request("DELETE", `/api/auth_user/mutes/${encodeURIComponent(muteID)}`)
+}
+
+function fetchFollowRequests() {
+    return request("GET", "/api/auth_user/follow_requests")
+        .then(resp => resp.body)
+}
+
+function approveFollowRequest(username) {
+    return request("POST", `/api/auth_user/follow_requests/${encodeURIComponent(username)}/approval`)
+}
+
+function denyFollowRequest(username) {
+    return request("DELETE", `/api/auth_user/follow_requests/${encodeURIComponent(username)}`)
+}
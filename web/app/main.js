@@ -2,10 +2,10 @@ import { component, render, useEffect, useState } from "haunted"
 import { html } from "lit"
 import { registerTranslateConfig, translate, use as useLang } from "lit-translate"
 import { until } from "lit/directives/until.js"
-import { setLocalAuth } from "./auth.js"
+import { getLocalAuth, setLocalAuth } from "./auth.js"
 import "./components/app-header.js"
 import { authStore, useStore } from "./ctx.js"
-import { request } from "./http.js"
+import { handleResponse, request } from "./http.js"
 import { createRouter, hijackClicks } from "./router.js"
 
 const router = createRouter()
@@ -86,25 +86,30 @@ function RouterView({ router }) {
 // @ts-ignore
 customElements.define("router-view", component(RouterView, { useShadowDOM: false }))
 
-const oneDayInMs = 1000 * 60 * 60 * 24
-const sevenDaysInMs = 1000 * 60 * 60 * 24
+const oneMinuteInMs = 1000 * 60
+const twoMinutesInMs = 1000 * 60 * 2
 
 function NakamaApp() {
     const [auth, setAuth] = useStore(authStore)
 
     const tryRefreshAuth = () => {
-        if (auth === null) {
+        // another tab could have refreshed it already.
+        const localAuth = getLocalAuth()
+        if (localAuth === null) {
             return
         }
 
-        const inSevenDays = new Date()
-        inSevenDays.setTime(inSevenDays.getTime() + sevenDaysInMs)
+        const inTwoMinutes = new Date()
+        inTwoMinutes.setTime(inTwoMinutes.getTime() + twoMinutesInMs)
 
-        if (auth.expiresAt >= inSevenDays) {
+        if (localAuth.expiresAt >= inTwoMinutes) {
+            if (auth !== null && localAuth.token !== auth.token) {
+                setAuth(localAuth)
+            }
             return
         }
 
-        fetchToken().then(payload => {
+        fetchToken(localAuth.refreshToken).then(payload => {
             setAuth(auth => {
                 const newAuth = {
                     ...auth,
@@ -115,6 +120,12 @@ function NakamaApp() {
             })
         }, err => {
             console.error("could not refresh auth:", err)
+            if (err.name === "InvalidRefreshTokenError"
+                || err.name === "RefreshTokenReusedError"
+                || err.name === "ExpiredTokenError") {
+                setLocalAuth(null)
+                setAuth(null)
+            }
         })
     }
 
@@ -127,7 +138,7 @@ function NakamaApp() {
 
         const id = setInterval(() => {
             tryRefreshAuth()
-        }, oneDayInMs)
+        }, oneMinuteInMs)
 
         return () => {
             clearInterval(id)
@@ -152,11 +163,19 @@ useLang(lang).then(() => {
     render(html`<nakama-app></nakama-app>`, document.body)
 })
 
-function fetchToken() {
-    return request("GET", "/api/token")
+// fetchToken exchanges the refresh token for a new pair of tokens.
+// It does not go through request() since the current access token
+// may already be expired.
+function fetchToken(refreshToken) {
+    return fetch("/api/token", {
+        method: "POST",
+        credentials: "include",
+        body: JSON.stringify({ refreshToken }),
+    }).then(handleResponse)
         .then(resp => resp.body)
         .then(auth => {
             auth.expiresAt = new Date(auth.expiresAt)
+            auth.refreshTokenExpiresAt = new Date(auth.refreshTokenExpiresAt)
             return auth
         })
 }
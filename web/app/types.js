@@ -3,6 +3,7 @@
  * @prop {string=} id
  * @prop {string} username
  * @prop {string=} avatarURL
+ * @prop {"user"|"moderator"|"admin"=} role Only set for the authenticated user.
  */
 
 /**
@@ -16,12 +17,20 @@
  * @prop {boolean} me
  * @prop {boolean} following
  * @prop {boolean} followeed
+ * @prop {boolean} blocking
+ * @prop {boolean} private
+ * @prop {boolean} followRequested
+ * @prop {string=} renamedFrom
+ * @prop {number=} mutualFollowersCount
+ * @prop {User[]=} mutualFollowers
  */
 
 /**
  * @typedef DevLoginOutput
  * @prop {string} token
  * @prop {string|Date} expiresAt
+ * @prop {string} refreshToken
+ * @prop {string|Date} refreshTokenExpiresAt
  * @prop {User} user
  */
 
@@ -45,6 +54,7 @@
  * @prop {boolean} mine
  * @prop {boolean} liked
  * @prop {boolean} subscribed
+ * @prop {boolean} pinned
  */
 
 /**
@@ -92,6 +102,17 @@
  * @prop {string|null} endCursor
  */
 
+/**
+ * @typedef UserList
+ * @prop {string} id
+ * @prop {string} name
+ * @prop {boolean} private
+ * @prop {number} membersCount
+ * @prop {string|Date} createdAt
+ * @prop {User=} user
+ * @prop {boolean} mine
+ */
+
 /**
  * @typedef Comment
  * @prop {string} id
@@ -121,11 +142,26 @@
  * @prop {boolean} subscribed
  */
 
+/**
+ * @typedef Report
+ * @prop {string} id
+ * @prop {"post"|"comment"|"user"} targetType
+ * @prop {string} targetID
+ * @prop {string=} postID
+ * @prop {string=} content
+ * @prop {User} author
+ * @prop {string[]} reasons
+ * @prop {string[]} details
+ * @prop {number} reportersCount
+ * @prop {string|Date} createdAt
+ * @prop {string|Date} lastReportedAt
+ */
+
 /**
  * @typedef Notification
  * @prop {string} id
- * @prop {string[]} actors
- * @prop {"follow"|"comment"|"post_mention"|"comment_mention"} type
+ * @prop {User[]} actors
+ * @prop {"follow"|"follow_request"|"follow_request_approved"|"follow_request_denied"|"comment"|"post_mention"|"comment_mention"|"report"|"report_resolved"} type
  * @prop {string=} postID
  * @prop {boolean} read
  * @prop {string|Date} issuedAt
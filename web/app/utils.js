@@ -43,3 +43,17 @@ export function collectMediaURLs(s) {
     }
     return out
 }
+
+const reducedMotion = matchMedia("(prefers-reduced-motion: reduce)")
+
+/**
+ * Animated avatars, covers and media come along a static poster of their first frame.
+ * motionSafeURL swaps them with it for people that prefer reduced motion.
+ * @param {string} url
+ */
+export function motionSafeURL(url) {
+    if (!reducedMotion.matches) {
+        return url
+    }
+    return url.replace(/\.(gif|webp)$/, ".poster.png")
+}
@@ -108,7 +108,7 @@ function notificationPathname(n) {
     }
 
     if (n.type === "follow") {
-        return "/@" + encodeURIComponent(n.actors[0])
+        return "/@" + encodeURIComponent(n.actors[0].username)
     }
 
     return "/notifications"
@@ -140,6 +140,10 @@ function notificationTitle(n) {
             return "New post mention"
         case "comment_mention":
             return "New comment mention"
+        case "report":
+            return "New report"
+        case "report_resolved":
+            return "Report reviewed"
     }
     return "New notification"
 }
@@ -151,12 +155,12 @@ function notificationBody(n) {
             case 0:
                 return "Someone"
             case 1:
-                return aa[0]
+                return aa[0].username
             case 2:
-                return `${aa[0]} and ${aa[1]}`
+                return `${aa[0].username} and ${aa[1].username}`
         }
 
-        return `${aa[0]} and ${aa.length - 1} others`
+        return `${aa[0].username} and ${aa.length - 1} others`
     }
 
     const getAction = () => {
@@ -169,6 +173,10 @@ function notificationBody(n) {
                 return "mentioned you in a post"
             case "comment_mention":
                 return "mentioned you in a comment"
+            case "report":
+                return n.postID ? "reported content in a post" : "reported a user"
+            case "report_resolved":
+                return "reviewed your report"
         }
         return "did something"
     }